@@ -40,8 +40,10 @@ var (
 )
 
 type AgentToolOptions struct {
-	fullChatHistoryAsInput bool
-	agentInputSchema       *schema.ParamsOneOf
+	fullChatHistoryAsInput      bool
+	includeLastAssistantMessage bool
+	agentInputSchema            *schema.ParamsOneOf
+	contextMessageTemplates     *ContextMessageTemplates
 }
 
 type AgentToolOption func(*AgentToolOptions)
@@ -53,6 +55,26 @@ func WithFullChatHistoryAsInput() AgentToolOption {
 	}
 }
 
+// WithIncludeLastAssistantMessage keeps the last assistant message, i.e. the one calling the agent tool,
+// in the chat history used as input by WithFullChatHistoryAsInput, which drops it by default.
+func WithIncludeLastAssistantMessage() AgentToolOption {
+	return func(options *AgentToolOptions) {
+		options.includeLastAssistantMessage = true
+	}
+}
+
+// WithContextMessageTemplates sets the templates rewriting the messages of the calling agent in the chat history
+// used as input by WithFullChatHistoryAsInput, e.g. to change their wording.
+// e.g.
+//
+//	templates, err := adk.NewContextMessageTemplates("", "[{{.AgentName}}] used {{.ToolName}}({{.Arguments}}).", "")
+//	agentTool := adk.NewAgentTool(ctx, agent, adk.WithFullChatHistoryAsInput(), adk.WithContextMessageTemplates(templates))
+func WithContextMessageTemplates(templates *ContextMessageTemplates) AgentToolOption {
+	return func(options *AgentToolOptions) {
+		options.contextMessageTemplates = templates
+	}
+}
+
 // WithAgentInputSchema sets a custom input schema for the agent tool.
 func WithAgentInputSchema(schema *schema.ParamsOneOf) AgentToolOption {
 	return func(options *AgentToolOptions) {
@@ -93,17 +115,21 @@ func NewAgentTool(_ context.Context, agent Agent, options ...AgentToolOption) to
 	}
 
 	return &agentTool{
-		agent:                  agent,
-		fullChatHistoryAsInput: opts.fullChatHistoryAsInput,
-		inputSchema:            opts.agentInputSchema,
+		agent:                       agent,
+		fullChatHistoryAsInput:      opts.fullChatHistoryAsInput,
+		includeLastAssistantMessage: opts.includeLastAssistantMessage,
+		inputSchema:                 opts.agentInputSchema,
+		contextMessageTemplates:     opts.contextMessageTemplates,
 	}
 }
 
 type agentTool struct {
 	agent Agent
 
-	fullChatHistoryAsInput bool
-	inputSchema            *schema.ParamsOneOf
+	fullChatHistoryAsInput      bool
+	includeLastAssistantMessage bool
+	inputSchema                 *schema.ParamsOneOf
+	contextMessageTemplates     *ContextMessageTemplates
 }
 
 func (at *agentTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
@@ -130,7 +156,7 @@ func (at *agentTool) InvokableRun(ctx context.Context, argumentsInJSON string, o
 		ms = newBridgeStore()
 		var input []Message
 		if at.fullChatHistoryAsInput {
-			input, err = getReactChatHistory(ctx, at.agent.Name(ctx))
+			input, err = getReactChatHistory(ctx, at.agent.Name(ctx), at.includeLastAssistantMessage, at.contextMessageTemplates)
 			if err != nil {
 				return "", err
 			}
@@ -277,12 +303,21 @@ func getEmitGeneratorAndEnableStreaming(opts []tool.Option) (*AsyncGenerator[*Ag
 	return o.generator, o.enableStreaming
 }
 
-func getReactChatHistory(ctx context.Context, destAgentName string) ([]Message, error) {
+func getReactChatHistory(ctx context.Context, destAgentName string, includeLastAssistantMessage bool,
+	templates *ContextMessageTemplates) ([]Message, error) {
+	if templates == nil {
+		templates = defaultContextMessageTemplates
+	}
+
 	var messages []Message
 	var agentName string
 	err := compose.ProcessState(ctx, func(ctx context.Context, st *State) error {
-		messages = make([]Message, len(st.Messages)-1)
-		copy(messages, st.Messages[:len(st.Messages)-1]) // remove the last assistant message, which is the tool call message
+		n := len(st.Messages)
+		if !includeLastAssistantMessage {
+			n-- // remove the last assistant message, which is the tool call message
+		}
+		messages = make([]Message, n)
+		copy(messages, st.Messages[:n])
 		agentName = st.AgentName
 		return nil
 	})
@@ -296,13 +331,33 @@ func getReactChatHistory(ctx context.Context, destAgentName string) ([]Message,
 		}
 
 		if msg.Role == schema.Assistant || msg.Role == schema.Tool {
-			msg = rewriteMessage(msg, agentName)
+			var rErr error
+			if msg, rErr = templates.rewrite(msg, agentName); rErr != nil {
+				return nil, fmt.Errorf("failed to rewrite context message: %w", rErr)
+			}
 		}
 
 		history = append(history, msg)
 	}
 
-	return history, err
+	return dedupContextMessages(history), err
+}
+
+// dedupContextMessages removes context messages identical to the message right before them,
+// which accumulate when history carrying context messages is forwarded again in nested transfers.
+func dedupContextMessages(messages []Message) []Message {
+	deduped := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if n := len(deduped); n > 0 && isContextMessage(msg) && sameMessage(deduped[n-1], msg) {
+			continue
+		}
+		deduped = append(deduped, msg)
+	}
+	return deduped
+}
+
+func sameMessage(a, b Message) bool {
+	return a.Role == b.Role && a.Content == b.Content && len(a.MultiContent) == 0 && len(b.MultiContent) == 0
 }
 
 func newInvokableAgentToolRunner(agent Agent, store compose.CheckPointStore, enableStreaming bool) *Runner {
@@ -279,7 +279,7 @@ func TestGetReactHistory(t *testing.T) {
 		}
 	}))
 	assert.NoError(t, g.AddLambdaNode("1", compose.InvokableLambda(func(ctx context.Context, input string) (output []Message, err error) {
-		return getReactChatHistory(ctx, "DestAgentName")
+		return getReactChatHistory(ctx, "DestAgentName", false, nil)
 	})))
 	assert.NoError(t, g.AddEdge(compose.START, "1"))
 	assert.NoError(t, g.AddEdge("1", compose.END))
@@ -298,6 +298,83 @@ func TestGetReactHistory(t *testing.T) {
 	}, result)
 }
 
+func TestGetReactHistoryDedupContextMessages(t *testing.T) {
+	g := compose.NewGraph[string, []Message](compose.WithGenLocalState(func(ctx context.Context) (state *State) {
+		return &State{
+			Messages: []Message{
+				// history forwarded by an outer transfer from MyAgent
+				schema.UserMessage("user query"),
+				schema.UserMessage("For context: [MyAgent] said: let me check."),
+				schema.AssistantMessage("let me check", nil),
+				schema.UserMessage("For context: [MyAgent] said: let me check."),
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "tool call id 1", Function: schema.FunctionCall{Name: "tool1", Arguments: "arguments1"}}}),
+			},
+			AgentName: "MyAgent",
+		}
+	}))
+	assert.NoError(t, g.AddLambdaNode("1", compose.InvokableLambda(func(ctx context.Context, input string) (output []Message, err error) {
+		return getReactChatHistory(ctx, "DestAgentName", false, nil)
+	})))
+	assert.NoError(t, g.AddEdge(compose.START, "1"))
+	assert.NoError(t, g.AddEdge("1", compose.END))
+
+	ctx := context.Background()
+	runner, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	result, err := runner.Invoke(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Message{
+		schema.UserMessage("user query"),
+		schema.UserMessage("For context: [MyAgent] said: let me check."),
+		schema.UserMessage("For context: [MyAgent] called tool: `transfer_to_agent` with arguments: DestAgentName."),
+		schema.UserMessage("For context: [MyAgent] `transfer_to_agent` tool returned result: successfully transferred to agent [DestAgentName]."),
+	}, result)
+}
+
+func TestGetReactHistoryContextMessageTemplates(t *testing.T) {
+	_, err := NewContextMessageTemplates("{{.AgentName", "", "")
+	assert.ErrorContains(t, err, "invalid said context message template")
+	_, err = NewContextMessageTemplates("", "{{.Tool}}", "")
+	assert.ErrorContains(t, err, "invalid tool call context message template")
+
+	templates, err := NewContextMessageTemplates(
+		"<{{.AgentName}}> {{.Content}}",
+		"<{{.AgentName}}> {{.ToolName}}({{.Arguments}})",
+		"<{{.AgentName}}> {{.ToolName}} => {{.Result}}",
+	)
+	assert.NoError(t, err)
+
+	g := compose.NewGraph[string, []Message](compose.WithGenLocalState(func(ctx context.Context) (state *State) {
+		return &State{
+			Messages: []Message{
+				schema.UserMessage("user query"),
+				schema.AssistantMessage("let me check", []schema.ToolCall{{ID: "tool call id 1", Function: schema.FunctionCall{Name: "tool1", Arguments: "arguments1"}}}),
+				schema.ToolMessage("tool result 1", "tool call id 1", schema.WithToolName("tool1")),
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "tool call id 2", Function: schema.FunctionCall{Name: "tool2", Arguments: "arguments2"}}}),
+			},
+			AgentName: "MyAgent",
+		}
+	}))
+	assert.NoError(t, g.AddLambdaNode("1", compose.InvokableLambda(func(ctx context.Context, input string) (output []Message, err error) {
+		return getReactChatHistory(ctx, "DestAgentName", false, templates)
+	})))
+	assert.NoError(t, g.AddEdge(compose.START, "1"))
+	assert.NoError(t, g.AddEdge("1", compose.END))
+
+	ctx := context.Background()
+	runner, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	result, err := runner.Invoke(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Message{
+		schema.UserMessage("user query"),
+		schema.UserMessage("For context: <MyAgent> let me check <MyAgent> tool1(arguments1)"),
+		schema.UserMessage("For context: <MyAgent> tool1 => tool result 1"),
+		schema.UserMessage("For context: <MyAgent> transfer_to_agent(DestAgentName)"),
+		schema.UserMessage("For context: <MyAgent> transfer_to_agent => successfully transferred to agent [DestAgentName]"),
+	}, result)
+}
+
 // mockAgentWithInputCapture implements the Agent interface for testing and captures the input it receives
 type mockAgentWithInputCapture struct {
 	name          string
@@ -402,6 +479,55 @@ func TestAgentToolWithOptions(t *testing.T) {
 		assert.Equal(t, "For context: [react-agent] `transfer_to_agent` tool returned result: successfully transferred to agent [test-agent].", mockAgent.capturedInput[3].Content)
 	})
 
+	t.Run("WithIncludeLastAssistantMessage", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockAgent := newMockAgentWithInputCapture("test-agent", "a test agent", []*AgentEvent{
+			{
+				AgentName: "test-agent",
+				Output: &AgentOutput{
+					MessageOutput: &MessageVariant{
+						Message: schema.AssistantMessage("done", nil),
+						Role:    schema.Assistant,
+					},
+				},
+			},
+		})
+
+		agentTool := NewAgentTool(ctx, mockAgent, WithFullChatHistoryAsInput(), WithIncludeLastAssistantMessage())
+
+		g := compose.NewGraph[string, string](compose.WithGenLocalState(func(ctx context.Context) (state *State) {
+			return &State{
+				AgentName: "react-agent",
+				Messages: []Message{
+					schema.UserMessage("first user message"),
+					schema.AssistantMessage("let me ask test-agent", []schema.ToolCall{{
+						ID:       "call-1",
+						Function: schema.FunctionCall{Name: "test-agent", Arguments: `{"request":"help"}`},
+					}}),
+				},
+			}
+		}))
+		assert.NoError(t, g.AddLambdaNode("1", compose.InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+			_, err = agentTool.(tool.InvokableTool).InvokableRun(ctx, `{"request":"help"}`)
+			return "done", err
+		})))
+		assert.NoError(t, g.AddEdge(compose.START, "1"))
+		assert.NoError(t, g.AddEdge("1", compose.END))
+		runner, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		_, err = runner.Invoke(ctx, "")
+		assert.NoError(t, err)
+
+		// the tool call message is kept, followed by the transfer messages
+		assert.Len(t, mockAgent.capturedInput, 4)
+		assert.Equal(t, "first user message", mockAgent.capturedInput[0].Content)
+		assert.Equal(t, "For context: [react-agent] said: let me ask test-agent. "+
+			"[react-agent] called tool: `test-agent` with arguments: {\"request\":\"help\"}.", mockAgent.capturedInput[1].Content)
+		assert.Equal(t, "For context: [react-agent] called tool: `transfer_to_agent` with arguments: test-agent.", mockAgent.capturedInput[2].Content)
+		assert.Equal(t, "For context: [react-agent] `transfer_to_agent` tool returned result: successfully transferred to agent [test-agent].", mockAgent.capturedInput[3].Content)
+	})
+
 	// Test Case 2: WithAgentInputSchema
 	t.Run("WithAgentInputSchema", func(t *testing.T) {
 		ctx := context.Background()
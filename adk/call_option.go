@@ -16,11 +16,17 @@
 
 package adk
 
+import "io"
+
 type options struct {
 	sharedParentSession  bool
 	sessionValues        map[string]any
 	checkPointID         *string
+	conversationID       *string
 	skipTransferMessages bool
+	initialMessages      []Message
+	appendedMessages     []Message
+	trajectoryWriter     io.Writer
 }
 
 // AgentRunOption is the call option for adk Agent.
@@ -58,6 +64,39 @@ func WithSkipTransferMessages() AgentRunOption {
 	})
 }
 
+// WithInitialMessages seeds a fresh conversation with msgs, replacing the messages passed to Runner.Run or Runner.Query.
+// It can be designated to the agent behind an agent tool to replace the input the tool would derive for it,
+// e.g. the full chat history under WithFullChatHistoryAsInput.
+// If used more than once, the last one wins.
+func WithInitialMessages(msgs ...Message) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.initialMessages = msgs
+	})
+}
+
+// WithAppendedMessages appends msgs to the conversation of the run, after the messages passed to Runner.Run
+// or Runner.Query, or after the messages set by WithInitialMessages if it is also used.
+// If used more than once, the messages are appended in the order of the options.
+func WithAppendedMessages(msgs ...Message) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.appendedMessages = append(o.appendedMessages, msgs...)
+	})
+}
+
+// inputMessages returns the messages a run starts with, given the messages it was called with.
+func (o *options) inputMessages(messages []Message) []Message {
+	if o.initialMessages == nil && len(o.appendedMessages) == 0 {
+		return messages
+	}
+	base := messages
+	if o.initialMessages != nil {
+		base = o.initialMessages
+	}
+	ret := make([]Message, 0, len(base)+len(o.appendedMessages))
+	ret = append(ret, base...)
+	return append(ret, o.appendedMessages...)
+}
+
 func withSharedParentSession() AgentRunOption {
 	return WrapImplSpecificOptFn(func(o *options) {
 		o.sharedParentSession = true
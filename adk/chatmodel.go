@@ -109,6 +109,22 @@ type ToolsConfig struct {
 	//   - Interrupted: Propagated via CompositeInterrupt to allow proper interrupt/resume
 	//   - Exit, TransferToAgent, BreakLoop: Ignored outside the agent tool
 	EmitInternalEvents bool
+
+	// ToolPriority orders the tools given to the model by name, as their order may affect its choices,
+	// e.g. to keep a tool ending the run first whatever the middlewares add. Tools are sorted by descending priority,
+	// 0 if missing, those with equal priorities keeping their order: the tools of ToolsNodeConfig, then the AdditionalTools
+	// of the middlewares in order, then the transfer and exit tools.
+	// e.g.
+	//
+	//	ToolPriority: map[string]int{"finish": 100, adk.TransferToAgentToolName: 90, "debug_dump": -1}
+	ToolPriority map[string]int
+
+	// HiddenTools specifies tools, typically internal ones such as a todo list tool, whose events
+	// are hidden from the end-user, while still being recorded in the session history for the agents.
+	// These are the events of the tool results, and the non-streaming assistant messages
+	// only made of calls to these tools. Events carrying an action or an error, and the result of a tool
+	// returning directly, are still emitted.
+	HiddenTools map[string]bool
 }
 
 // GenModelInput transforms agent instructions and input into a format suitable for the model.
@@ -148,25 +164,75 @@ type ChatModelAgentState struct {
 	Messages []Message
 }
 
+// MessageTransform transforms a single message before a ChatModel invocation.
+// It returns the message to keep, either msg itself or a modified copy which must not share mutable fields with msg,
+// or nil to drop msg, in which case the MessageTransforms of later middlewares are skipped for it.
+// idx and total locate msg in the message list as it was before the pass, i.e. regardless of messages
+// dropped by the MessageTransforms of earlier middlewares in the same pass.
+type MessageTransform func(ctx context.Context, msg Message, idx, total int) (Message, error)
+
+// ConditionalInstruction is a part of the system instruction that only applies if some tools are available.
+type ConditionalInstruction struct {
+	// Instruction is concatenated as is, so it should carry its own leading separator if needed.
+	Instruction string
+	// RequiredTools are the names of the tools, from ToolsConfig or the AdditionalTools of any middleware,
+	// all of which must be available for Instruction to be included.
+	RequiredTools []string
+}
+
 // AgentMiddleware provides hooks to customize agent behavior at various stages of execution.
 type AgentMiddleware struct {
+	// Name identifies the middleware, e.g. in the spans of ChatModelAgentConfig.MiddlewareTracer.
+	// Optional. Defaults to "middleware_<index>".
+	Name string
+
 	// AdditionalInstruction adds supplementary text to the agent's system instruction.
 	// This instruction is concatenated with the base instruction before each chat model call.
 	AdditionalInstruction string
 
+	// ConditionalInstructions add supplementary text to the agent's system instruction depending on the tools
+	// available to the agent, e.g. instructions for a tool that is only present with some configurations.
+	// Those whose RequiredTools are all available follow AdditionalInstruction, in order.
+	ConditionalInstructions []ConditionalInstruction
+
 	// AdditionalTools adds supplementary tools to the agent's available toolset.
 	// These tools are combined with the tools configured for the agent.
 	AdditionalTools []tool.BaseTool
 
+	// AdditionalReturnDirectly specifies tools, typically ones from AdditionalTools, that cause the agent
+	// to return immediately when called. It is merged into ToolsConfig.ReturnDirectly.
+	AdditionalReturnDirectly map[string]bool
+
+	// SetsReturnDirectly declares that WrapToolCall may call SetReturnDirectly to end the run with a tool result.
+	// Optional. SetReturnDirectly only takes effect for agents with such a middleware or with ReturnDirectly tools.
+	SetsReturnDirectly bool
+
+	// AdditionalToolPriority specifies the priorities of tools, typically ones from AdditionalTools, in the order
+	// of the tools given to the model. It is merged into ToolsConfig.ToolPriority, see there.
+	AdditionalToolPriority map[string]int
+
 	// BeforeChatModel is called before each ChatModel invocation, allowing modification of the agent state.
 	BeforeChatModel func(context.Context, *ChatModelAgentState) error
 
+	// MessageTransform is called for each message before each ChatModel invocation, ahead of this middleware's
+	// BeforeChatModel, allowing a message to be kept, modified or dropped.
+	// The MessageTransforms of consecutive middlewares are applied in a single pass over the messages,
+	// rather than one pass per middleware as with BeforeChatModel.
+	MessageTransform MessageTransform
+
 	// AfterChatModel is called after each ChatModel invocation, allowing modification of the agent state.
 	AfterChatModel func(context.Context, *ChatModelAgentState) error
 
 	// WrapToolCall wraps tool calls with custom middleware logic.
 	// Each middleware contains Invokable and/or Streamable functions for tool calls.
 	WrapToolCall compose.ToolMiddleware
+
+	// ShouldApply decides whether the middleware applies to a run of the agent, evaluated at the start of each run
+	// and not again when the run is resumed after an interrupt, so that one agent can serve runs with and without the middleware, e.g. a lightweight and a full mode.
+	// When it doesn't apply, its instructions and AdditionalTools are left out of the run, and its hooks are skipped.
+	// Run options can be passed to it as session values, e.g. set by WithSessionValues.
+	// Optional. If nil, the middleware applies to all runs.
+	ShouldApply func(ctx context.Context, input *AgentInput) bool
 }
 
 type ChatModelAgentConfig struct {
@@ -212,6 +278,25 @@ type ChatModelAgentConfig struct {
 	// based on the configured policy.
 	// Optional. If nil, no retry will be performed.
 	ModelRetryConfig *ModelRetryConfig
+
+	// MiddlewareTracer creates spans around the BeforeChatModel, MessageTransform, AfterChatModel
+	// and WrapToolCall phases of Middlewares, tagged with the middleware and tool names.
+	// Optional. If nil, no span will be created.
+	MiddlewareTracer MiddlewareTracer
+
+	// PromptCaching marks the system messages generated by GenModelInput, which carry the Instruction
+	// composed with the AdditionalInstruction of Middlewares, as cacheable via schema.SetCacheControl,
+	// and passes model.WithPromptCaching to the ChatModel.
+	// Optional. Defaults to false.
+	PromptCaching bool
+
+	// ThinkBeforeToolCall makes each ChatModel generation run in two phases: a free-form generation with
+	// tools forbidden, for the model to reason, then a generation forced to call tools, given the reasoning
+	// as an assistant message. The reasoning is stored in the ReasoningContent of the generated message.
+	// As the model can't answer without calling a tool, the agent should have a tool ending the run,
+	// e.g. Exit or a tool in ToolsConfig.ReturnDirectly.
+	// Optional. Defaults to false.
+	ThinkBeforeToolCall bool
 }
 
 type ChatModelAgent struct {
@@ -238,6 +323,17 @@ type ChatModelAgent struct {
 
 	modelRetryConfig *ModelRetryConfig
 
+	promptCaching bool
+
+	// setsReturnDirectly is set if some middlewares may call SetReturnDirectly.
+	setsReturnDirectly bool
+
+	// middlewares, baseInstruction and configuredToolCount are kept to select the middlewares applying to a run,
+	// only if some middlewares have a ShouldApply.
+	middlewares         []AgentMiddleware
+	baseInstruction     string
+	configuredToolCount int
+
 	// runner
 	once   sync.Once
 	run    runFunc
@@ -247,7 +343,7 @@ type ChatModelAgent struct {
 type runFunc func(ctx context.Context, input *AgentInput, generator *AsyncGenerator[*AgentEvent], store *bridgeStore, opts ...compose.Option)
 
 // NewChatModelAgent constructs a chat model-backed agent with the provided config.
-func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatModelAgent, error) {
+func NewChatModelAgent(ctx context.Context, config *ChatModelAgentConfig) (*ChatModelAgent, error) {
 	if config.Name == "" {
 		return nil, errors.New("agent 'Name' is required")
 	}
@@ -258,37 +354,90 @@ func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatMo
 		return nil, errors.New("agent 'Model' is required")
 	}
 
+	cm := config.Model
+	if config.ThinkBeforeToolCall {
+		cm = newThinkThenToolChatModel(cm)
+	}
+
 	genInput := defaultGenModelInput
 	if config.GenModelInput != nil {
 		genInput = config.GenModelInput
 	}
+	if config.PromptCaching {
+		genInput = cacheSystemMessages(genInput)
+	}
 
 	beforeChatModels := make([]func(context.Context, *ChatModelAgentState) error, 0)
 	afterChatModels := make([]func(context.Context, *ChatModelAgentState) error, 0)
-	sb := &strings.Builder{}
-	sb.WriteString(config.Instruction)
+	var (
+		transforms     []MessageTransform
+		transformNames []string
+	)
+	tracer := config.MiddlewareTracer
+	flushTransforms := func() {
+		if len(transforms) == 0 {
+			return
+		}
+		beforeChatModels = append(beforeChatModels, traceStateHandler(tracer, MiddlewarePhaseMessageTransform,
+			strings.Join(transformNames, ","), transformMessages(transforms)))
+		transforms, transformNames = nil, nil
+	}
 	tc := config.ToolsConfig
-	for _, m := range config.Middlewares {
-		sb.WriteString("\n")
-		sb.WriteString(m.AdditionalInstruction)
+	conditional := false
+	setsReturnDirectly := false
+	for i, m := range config.Middlewares {
+		name := middlewareName(m, i)
+		if m.ShouldApply != nil {
+			conditional = true
+			m = guardMiddleware(i, m)
+		}
 		tc.Tools = append(tc.Tools, m.AdditionalTools...)
 
+		setsReturnDirectly = setsReturnDirectly || m.SetsReturnDirectly
+
+		if len(m.AdditionalReturnDirectly) > 0 {
+			returnDirectly := copyMap(tc.ReturnDirectly)
+			for toolName, rd := range m.AdditionalReturnDirectly {
+				returnDirectly[toolName] = rd
+			}
+			tc.ReturnDirectly = returnDirectly
+		}
+
+		if len(m.AdditionalToolPriority) > 0 {
+			toolPriority := copyMap(tc.ToolPriority)
+			for toolName, p := range m.AdditionalToolPriority {
+				toolPriority[toolName] = p
+			}
+			tc.ToolPriority = toolPriority
+		}
+
 		if m.WrapToolCall.Invokable != nil || m.WrapToolCall.Streamable != nil {
-			tc.ToolCallMiddlewares = append(tc.ToolCallMiddlewares, m.WrapToolCall)
+			tc.ToolCallMiddlewares = append(tc.ToolCallMiddlewares, traceToolMiddleware(tracer, name, m.WrapToolCall))
+		}
+		if m.MessageTransform != nil {
+			transforms = append(transforms, m.MessageTransform)
+			transformNames = append(transformNames, name)
 		}
 		if m.BeforeChatModel != nil {
-			beforeChatModels = append(beforeChatModels, m.BeforeChatModel)
+			flushTransforms()
+			beforeChatModels = append(beforeChatModels, traceStateHandler(tracer, MiddlewarePhaseBeforeChatModel, name, m.BeforeChatModel))
 		}
 		if m.AfterChatModel != nil {
-			afterChatModels = append(afterChatModels, m.AfterChatModel)
+			afterChatModels = append(afterChatModels, traceStateHandler(tracer, MiddlewarePhaseAfterChatModel, name, m.AfterChatModel))
 		}
 	}
+	flushTransforms()
+
+	instruction, err := buildInstruction(ctx, config.Instruction, config.Middlewares, tc.Tools)
+	if err != nil {
+		return nil, err
+	}
 
-	return &ChatModelAgent{
+	a := &ChatModelAgent{
 		name:             config.Name,
 		description:      config.Description,
-		instruction:      sb.String(),
-		model:            config.Model,
+		instruction:      instruction,
+		model:            cm,
 		toolsConfig:      tc,
 		genModelInput:    genInput,
 		exit:             config.Exit,
@@ -297,7 +446,98 @@ func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatMo
 		beforeChatModels: beforeChatModels,
 		afterChatModels:  afterChatModels,
 		modelRetryConfig: config.ModelRetryConfig,
-	}, nil
+		promptCaching:    config.PromptCaching,
+
+		setsReturnDirectly: setsReturnDirectly,
+	}
+	if conditional {
+		a.middlewares = config.Middlewares
+		a.baseInstruction = config.Instruction
+		a.configuredToolCount = len(config.ToolsConfig.Tools)
+	}
+	return a, nil
+}
+
+// buildInstruction concatenates the instruction with the additional and applicable conditional instructions of middlewares.
+func buildInstruction(ctx context.Context, instruction string, middlewares []AgentMiddleware, tools []tool.BaseTool) (string, error) {
+	var available map[string]bool
+	for _, m := range middlewares {
+		if len(m.ConditionalInstructions) > 0 {
+			available = make(map[string]bool, len(tools))
+			break
+		}
+	}
+	if available != nil {
+		for _, t := range tools {
+			info, err := t.Info(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to get tool info: %w", err)
+			}
+			available[info.Name] = true
+		}
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(instruction)
+	for _, m := range middlewares {
+		sb.WriteString("\n")
+		sb.WriteString(m.AdditionalInstruction)
+	conditions:
+		for _, ci := range m.ConditionalInstructions {
+			for _, name := range ci.RequiredTools {
+				if !available[name] {
+					continue conditions
+				}
+			}
+			sb.WriteString(ci.Instruction)
+		}
+	}
+	return sb.String(), nil
+}
+
+// cacheSystemMessages wraps genInput to mark the system messages it generates as cacheable.
+// The messages are copied, so that messages shared with the input aren't altered.
+func cacheSystemMessages(genInput GenModelInput) GenModelInput {
+	return func(ctx context.Context, instruction string, input *AgentInput) ([]Message, error) {
+		msgs, err := genInput(ctx, instruction, input)
+		if err != nil {
+			return nil, err
+		}
+		for i, msg := range msgs {
+			if msg == nil || msg.Role != schema.System {
+				continue
+			}
+			cp := *msg
+			schema.SetCacheControl(&cp, &schema.CacheControl{})
+			msgs[i] = &cp
+		}
+		return msgs, nil
+	}
+}
+
+// transformMessages applies transforms to state.Messages in a single pass.
+func transformMessages(transforms []MessageTransform) func(context.Context, *ChatModelAgentState) error {
+	return func(ctx context.Context, state *ChatModelAgentState) error {
+		total := len(state.Messages)
+		messages := make([]Message, 0, total)
+		for idx, msg := range state.Messages {
+			var err error
+			for _, t := range transforms {
+				msg, err = t(ctx, msg, idx, total)
+				if err != nil {
+					return err
+				}
+				if msg == nil {
+					break
+				}
+			}
+			if msg != nil {
+				messages = append(messages, msg)
+			}
+		}
+		state.Messages = messages
+		return nil
+	}
 }
 
 const (
@@ -442,6 +682,20 @@ type cbHandler struct {
 	addr                    Address
 
 	modelRetryConfigs *ModelRetryConfig
+	hiddenTools       map[string]bool
+}
+
+// onlyCallsHiddenTools reports whether msg has no other content than calls to hidden tools.
+func (h *cbHandler) onlyCallsHiddenTools(msg Message) bool {
+	if msg == nil || len(msg.ToolCalls) == 0 || msg.Content != "" || msg.ReasoningContent != "" {
+		return false
+	}
+	for _, tc := range msg.ToolCalls {
+		if !h.hiddenTools[tc.Function.Name] {
+			return false
+		}
+	}
+	return true
 }
 
 func (h *cbHandler) onChatModelEnd(ctx context.Context,
@@ -452,6 +706,7 @@ func (h *cbHandler) onChatModelEnd(ctx context.Context,
 	}
 
 	event := EventFromMessage(output.Message, nil, schema.Assistant, "")
+	event.hidden = h.onlyCallsHiddenTools(output.Message)
 	h.Send(event)
 	return ctx
 }
@@ -555,7 +810,8 @@ func genReactCallbacks(ctx context.Context, agentName string,
 	generator *AsyncGenerator[*AgentEvent],
 	enableStreaming bool,
 	store *bridgeStore,
-	modelRetryConfigs *ModelRetryConfig) compose.Option {
+	modelRetryConfigs *ModelRetryConfig,
+	hiddenTools map[string]bool) compose.Option {
 
 	h := &cbHandler{
 		ctx:               ctx,
@@ -564,7 +820,8 @@ func genReactCallbacks(ctx context.Context, agentName string,
 		agentName:         agentName,
 		store:             store,
 		enableStreaming:   enableStreaming,
-		modelRetryConfigs: modelRetryConfigs}
+		modelRetryConfigs: modelRetryConfigs,
+		hiddenTools:       hiddenTools}
 
 	cmHandler := &ub.ModelCallbackHandler{
 		OnEnd:                 h.onChatModelEnd,
@@ -587,8 +844,10 @@ func genReactCallbacks(ctx context.Context, agentName string,
 
 			returnDirectlyID, hasReturnDirectly := getReturnDirectlyToolCallID(toolCtx)
 			if hasReturnDirectly && returnDirectlyID == callID {
+				// the result returned directly is the output of the agent, which is never hidden
 				h.returnDirectlyToolEvent.Store(event)
 			} else {
+				event.hidden = hiddenTools[toolName]
 				h.Send(event)
 			}
 		}
@@ -606,6 +865,7 @@ func genReactCallbacks(ctx context.Context, agentName string,
 			if hasReturnDirectly && returnDirectlyID == callID {
 				h.returnDirectlyToolEvent.Store(event)
 			} else {
+				event.hidden = hiddenTools[toolName]
 				h.Send(event)
 			}
 		}
@@ -719,6 +979,7 @@ type ChatModelAgentResumeData struct {
 func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 	a.once.Do(func() {
 		instruction := a.instruction
+		var transferInstruction string
 		toolsNodeConf := a.toolsConfig.ToolsNodeConfig
 		returnDirectly := copyMap(a.toolsConfig.ReturnDirectly)
 
@@ -728,7 +989,7 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 		}
 
 		if len(transferToAgents) > 0 {
-			transferInstruction := genTransferToAgentInstruction(ctx, transferToAgents)
+			transferInstruction = genTransferToAgentInstruction(ctx, transferToAgents)
 			instruction = concatInstructions(instruction, transferInstruction)
 
 			toolsNodeConf.Tools = append(toolsNodeConf.Tools, &transferToAgent{})
@@ -745,6 +1006,18 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 			returnDirectly[exitInfo.Name] = true
 		}
 
+		// selectMiddlewares returns the instruction of a run and the options for the middlewares applying to it
+		selectMiddlewares := func(ctx context.Context, input *AgentInput) (context.Context, string, []compose.Option, error) {
+			ctx, sel, err := a.selectMiddlewares(ctx, input, toolsNodeConf.Tools)
+			if err != nil || sel == nil {
+				return ctx, instruction, nil, err
+			}
+			if transferInstruction == "" {
+				return ctx, sel.instruction, sel.options, nil
+			}
+			return ctx, concatInstructions(sel.instruction, transferInstruction), sel.options, nil
+		}
+
 		if len(toolsNodeConf.Tools) == 0 {
 			var chatModel model.ToolCallingChatModel = a.model
 			if a.modelRetryConfig != nil {
@@ -753,6 +1026,12 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 
 			a.run = func(ctx context.Context, input *AgentInput, generator *AsyncGenerator[*AgentEvent],
 				store *bridgeStore, opts ...compose.Option) {
+				ctx, instruction, selOpts, err := selectMiddlewares(ctx, input)
+				if err != nil {
+					generator.Send(&AgentEvent{Err: err})
+					return
+				}
+
 				r, err := compose.NewChain[*AgentInput, Message](compose.WithGenLocalState(func(ctx context.Context) (state *ChatModelAgentState) {
 					return &ChatModelAgentState{}
 				})).
@@ -797,6 +1076,7 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 				callOpt := genNoToolsCallbacks(generator, a.modelRetryConfig)
 				var runOpts []compose.Option
 				runOpts = append(runOpts, opts...)
+				runOpts = append(runOpts, selOpts...)
 				runOpts = append(runOpts, callOpt)
 
 				var msg Message
@@ -829,6 +1109,8 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 			model:               a.model,
 			toolsConfig:         &toolsNodeConf,
 			toolsReturnDirectly: returnDirectly,
+			setsReturnDirectly:  a.setsReturnDirectly,
+			toolPriority:        a.toolsConfig.ToolPriority,
 			agentName:           a.name,
 			maxIterations:       a.maxIterations,
 			beforeChatModel:     a.beforeChatModels,
@@ -844,6 +1126,12 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 
 		a.run = func(ctx context.Context, input *AgentInput, generator *AsyncGenerator[*AgentEvent], store *bridgeStore,
 			opts ...compose.Option) {
+			ctx, instruction, selOpts, err_ := selectMiddlewares(ctx, input)
+			if err_ != nil {
+				generator.Send(&AgentEvent{Err: err_})
+				return
+			}
+
 			var compileOptions []compose.GraphCompileOption
 			compileOptions = append(compileOptions,
 				compose.WithGraphName(a.name),
@@ -865,9 +1153,11 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 				return
 			}
 
-			callOpt := genReactCallbacks(ctx, a.name, generator, input.EnableStreaming, store, a.modelRetryConfig)
+			callOpt := genReactCallbacks(ctx, a.name, generator, input.EnableStreaming, store, a.modelRetryConfig,
+				a.toolsConfig.HiddenTools)
 			var runOpts []compose.Option
 			runOpts = append(runOpts, opts...)
+			runOpts = append(runOpts, selOpts...)
 			runOpts = append(runOpts, callOpt)
 			if a.toolsConfig.EmitInternalEvents {
 				runOpts = append(runOpts, compose.WithToolsNodeOption(compose.WithToolOption(withAgentToolEventGenerator(generator))))
@@ -907,7 +1197,7 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 func (a *ChatModelAgent) Run(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	run := a.buildRunFunc(ctx)
 
-	co := getComposeOptions(opts)
+	co := a.composeOptions(opts)
 	co = append(co, compose.WithCheckPointID(bridgeCheckpointID))
 
 	iterator, generator := NewAsyncIteratorPair[*AgentEvent]()
@@ -931,7 +1221,7 @@ func (a *ChatModelAgent) Run(ctx context.Context, input *AgentInput, opts ...Age
 func (a *ChatModelAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	run := a.buildRunFunc(ctx)
 
-	co := getComposeOptions(opts)
+	co := a.composeOptions(opts)
 	co = append(co, compose.WithCheckPointID(bridgeCheckpointID))
 
 	if info.InterruptState == nil {
@@ -975,13 +1265,21 @@ func (a *ChatModelAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...A
 			generator.Close()
 		}()
 
-		run(ctx, &AgentInput{EnableStreaming: info.EnableStreaming}, generator,
+		run(withMiddlewareResume(ctx), &AgentInput{EnableStreaming: info.EnableStreaming}, generator,
 			newResumeBridgeStore(stateByte), co...)
 	}()
 
 	return iterator
 }
 
+func (a *ChatModelAgent) composeOptions(opts []AgentRunOption) []compose.Option {
+	co := getComposeOptions(opts)
+	if a.promptCaching {
+		co = append(co, compose.WithChatModelOption(model.WithPromptCaching()))
+	}
+	return co
+}
+
 func getComposeOptions(opts []AgentRunOption) []compose.Option {
 	o := GetImplSpecificOptions[chatModelAgentRunOptions](nil, opts...)
 	var co []compose.Option
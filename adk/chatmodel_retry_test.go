@@ -990,3 +990,99 @@ func TestSequentialWorkflow_NoRetryConfig_StreamError_StopsFlow(t *testing.T) {
 	assert.Equal(t, 0, len(capturingModel.capturedInputs), "Agent B should NOT be called due to error")
 	assert.Equal(t, int32(1), atomic.LoadInt32(&noRetryModel.callCount), "Model should only be called once (no retry)")
 }
+
+type retryAfterError struct {
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return "rate limited"
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func TestChatModelAgentRetry_RetryAfter(t *testing.T) {
+	ctx := context.Background()
+	retryAfter := 200 * time.Millisecond
+
+	run := func(t *testing.T, config *ModelRetryConfig) time.Duration {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		var callCount int32
+		var firstCall, secondCall time.Time
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				if atomic.AddInt32(&callCount, 1) == 1 {
+					firstCall = time.Now()
+					return nil, &retryAfterError{retryAfter: retryAfter}
+				}
+				secondCall = time.Now()
+				return schema.AssistantMessage("Success", nil), nil
+			}).Times(2)
+
+		config.MaxRetries = 1
+		config.BackoffFunc = func(ctx context.Context, attempt int) time.Duration {
+			return time.Millisecond
+		}
+		agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:             "RetryTestAgent",
+			Description:      "Test agent for retry functionality",
+			Instruction:      "You are a helpful assistant.",
+			Model:            cm,
+			ModelRetryConfig: config,
+		})
+		assert.NoError(t, err)
+
+		iterator := agent.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("Hello")}})
+		event, ok := iterator.Next()
+		assert.True(t, ok)
+		assert.Nil(t, event.Err)
+		_, ok = iterator.Next()
+		assert.False(t, ok)
+
+		return secondCall.Sub(firstCall)
+	}
+
+	t.Run("waits the retry-after of the error", func(t *testing.T) {
+		assert.GreaterOrEqual(t, run(t, &ModelRetryConfig{}), retryAfter)
+	})
+
+	t.Run("retry-after is capped", func(t *testing.T) {
+		assert.Less(t, run(t, &ModelRetryConfig{MaxRetryAfter: 10 * time.Millisecond}), retryAfter)
+	})
+
+	t.Run("retry-after is disabled", func(t *testing.T) {
+		assert.Less(t, run(t, &ModelRetryConfig{DisableRetryAfter: true}), retryAfter)
+	})
+
+	t.Run("retry-after is capped by default", func(t *testing.T) {
+		d := retryDelay(ctx, &ModelRetryConfig{}, defaultBackoff, 1, &retryAfterError{retryAfter: time.Hour})
+		assert.Equal(t, defaultMaxRetryAfter, d)
+	})
+}
+
+func TestChatModelRetry_ContextDoneWhileWaiting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &retryAfterError{retryAfter: time.Hour}).Times(1)
+	cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &retryAfterError{retryAfter: time.Hour}).Times(1)
+
+	rm := newRetryChatModel(cm, &ModelRetryConfig{MaxRetries: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := rm.Generate(ctx, []*schema.Message{schema.UserMessage("Hello")})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = rm.Stream(ctx, []*schema.Message{schema.UserMessage("Hello")})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
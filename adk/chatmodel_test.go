@@ -19,12 +19,14 @@ package adk
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
@@ -530,6 +532,65 @@ func TestConcurrentSameToolSendToolGenActionUsesToolCallID(t *testing.T) {
 	assert.True(t, seen["B"])
 }
 
+func TestHiddenToolEventsKeepActions(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, toolCalls []schema.ToolCall, tc ToolsConfig, final bool) []*AgentEvent {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("", toolCalls), nil).Times(1)
+		if final {
+			cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(schema.AssistantMessage("done", nil), nil).Times(1)
+		}
+
+		agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "TestAgent",
+			Description: "Agent with hidden tools",
+			Model:       cm,
+			ToolsConfig: tc,
+		})
+		assert.NoError(t, err)
+
+		var events []*AgentEvent
+		iter := NewRunner(ctx, RunnerConfig{Agent: agent}).Query(ctx, "go")
+		for {
+			e, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, e.Err)
+			events = append(events, e)
+		}
+		return events
+	}
+
+	t.Run("action of a hidden tool", func(t *testing.T) {
+		events := run(t, []schema.ToolCall{{ID: "id1", Function: schema.FunctionCall{Name: "action_tool", Arguments: "A"}}},
+			ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{actionTool{}}},
+				HiddenTools:     map[string]bool{"action_tool": true},
+			}, true)
+		// the tool call message is hidden, while the tool result carrying the action isn't
+		assert.Len(t, events, 2)
+		assert.Equal(t, "A", events[0].Action.CustomizedAction)
+		assert.Equal(t, "done", events[1].Output.MessageOutput.Message.Content)
+	})
+
+	t.Run("hidden tool returning directly", func(t *testing.T) {
+		events := run(t, []schema.ToolCall{{ID: "id1", Function: schema.FunctionCall{Name: "tool1"}}},
+			ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&myTool{name: "tool1", desc: "tool1"}}},
+				ReturnDirectly:  map[string]bool{"tool1": true},
+				HiddenTools:     map[string]bool{"tool1": true},
+			}, false)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "success", events[0].Output.MessageOutput.Message.Content)
+	})
+}
+
 type myTool struct {
 	name     string
 	desc     string
@@ -1183,3 +1244,430 @@ func (s *simpleToolForMiddlewareTest) InvokableRun(_ context.Context, _ string,
 func (s *simpleToolForMiddlewareTest) StreamableRun(_ context.Context, _ string, _ ...tool.Option) (*schema.StreamReader[string], error) {
 	return schema.StreamReaderFromArray([]string{s.result}), nil
 }
+
+func TestChatModelAgentMessageTransform(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var modelInput []Message
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			modelInput = input
+			return schema.AssistantMessage("done", nil), nil
+		}).Times(1)
+
+	redactPasses := 0
+	redaction := AgentMiddleware{
+		MessageTransform: func(ctx context.Context, msg Message, idx, total int) (Message, error) {
+			if idx == 0 {
+				redactPasses++
+			}
+			if !strings.Contains(msg.Content, "secret") {
+				return msg, nil
+			}
+			cp := *msg
+			cp.Content = strings.ReplaceAll(msg.Content, "secret", "***")
+			return &cp, nil
+		},
+	}
+	// keeps the system message and the last 2 messages
+	windowing := AgentMiddleware{
+		MessageTransform: func(ctx context.Context, msg Message, idx, total int) (Message, error) {
+			// redaction has already been applied to the message
+			assert.NotContains(t, msg.Content, "secret")
+			if msg.Role == schema.System || idx >= total-2 {
+				return msg, nil
+			}
+			return nil, nil
+		},
+	}
+	var seenByBeforeChatModel []string
+	after := AgentMiddleware{
+		BeforeChatModel: func(ctx context.Context, state *ChatModelAgentState) error {
+			for _, m := range state.Messages {
+				seenByBeforeChatModel = append(seenByBeforeChatModel, m.Content)
+			}
+			return nil
+		},
+	}
+
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent for message transforms",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		Middlewares: []AgentMiddleware{redaction, windowing, after},
+	})
+	assert.NoError(t, err)
+
+	history := []Message{
+		schema.UserMessage("first"),
+		schema.AssistantMessage("second", nil),
+		schema.UserMessage("my secret is 42"),
+		schema.AssistantMessage("noted", nil),
+	}
+	iter := NewRunner(ctx, RunnerConfig{Agent: agent}).Run(ctx, history)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	var contents []string
+	for _, m := range modelInput {
+		contents = append(contents, m.Content)
+	}
+	assert.Len(t, contents, 3)
+	assert.Equal(t, schema.System, modelInput[0].Role)
+	assert.Equal(t, []string{"my *** is 42", "noted"}, contents[1:])
+	assert.Equal(t, contents, seenByBeforeChatModel)
+	assert.Equal(t, 1, redactPasses)
+	assert.Equal(t, "my secret is 42", history[2].Content)
+}
+
+func TestChatModelAgentPromptCaching(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+
+	user := schema.UserMessage("hi")
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Len(t, input, 2)
+			assert.Equal(t, schema.System, input[0].Role)
+			assert.Equal(t, "You are a helpful assistant.\nUse the tools wisely.", input[0].Content)
+			assert.NotNil(t, schema.GetCacheControl(input[0]))
+			assert.Nil(t, schema.GetCacheControl(input[1]))
+			assert.True(t, model.GetCommonOptions(nil, opts...).PromptCaching)
+			return schema.AssistantMessage("hello", nil), nil
+		}).Times(1)
+
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:          "TestAgent",
+		Description:   "Test agent for unit testing",
+		Instruction:   "You are a helpful assistant.",
+		Model:         cm,
+		Middlewares:   []AgentMiddleware{{AdditionalInstruction: "Use the tools wisely."}},
+		PromptCaching: true,
+	})
+	assert.NoError(t, err)
+
+	iter := agent.Run(ctx, &AgentInput{Messages: []Message{user}})
+	event, ok := iter.Next()
+	assert.True(t, ok)
+	assert.NoError(t, event.Err)
+	_, ok = iter.Next()
+	assert.False(t, ok)
+
+	assert.Nil(t, user.Extra)
+}
+
+func TestChatModelAgentThinkBeforeToolCall(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	gomock.InOrder(
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				assert.Len(t, input, 2)
+				assert.Equal(t, schema.ToolChoiceForbidden, *model.GetCommonOptions(nil, opts...).ToolChoice)
+				return schema.AssistantMessage("The task is done, so I should exit.", nil), nil
+			}),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				assert.Len(t, input, 3)
+				assert.Equal(t, schema.AssistantMessage("The task is done, so I should exit.", nil), input[2])
+				assert.Equal(t, schema.ToolChoiceForced, *model.GetCommonOptions(nil, opts...).ToolChoice)
+				return schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       "tool-call-1",
+					Function: schema.FunctionCall{Name: "exit", Arguments: `{"final_result": "done"}`},
+				}}), nil
+			}),
+	)
+
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:                "TestAgent",
+		Description:         "Test agent thinking before calling tools",
+		Instruction:         "You are a helpful assistant.",
+		Model:               cm,
+		Exit:                &ExitTool{},
+		ThinkBeforeToolCall: true,
+	})
+	assert.NoError(t, err)
+
+	iter := agent.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("finish the task")}})
+	event, ok := iter.Next()
+	assert.True(t, ok)
+	assert.NoError(t, event.Err)
+	msg := event.Output.MessageOutput.Message
+	assert.Equal(t, "The task is done, so I should exit.", msg.ReasoningContent)
+	assert.Len(t, msg.ToolCalls, 1)
+
+	event, ok = iter.Next()
+	assert.True(t, ok)
+	assert.True(t, event.Action.Exit)
+	_, ok = iter.Next()
+	assert.False(t, ok)
+}
+
+func TestBuildInstruction(t *testing.T) {
+	ctx := context.Background()
+	middlewares := []AgentMiddleware{
+		{
+			AdditionalInstruction: "first",
+			ConditionalInstructions: []ConditionalInstruction{
+				{Instruction: " with test_tool", RequiredTools: []string{"test_tool"}},
+				{Instruction: " with missing_tool", RequiredTools: []string{"test_tool", "missing_tool"}},
+			},
+		},
+		{AdditionalInstruction: "second"},
+	}
+
+	instruction, err := buildInstruction(ctx, "base", middlewares, []tool.BaseTool{&fakeToolForTest{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "base\nfirst with test_tool\nsecond", instruction)
+
+	instruction, err = buildInstruction(ctx, "base", middlewares, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "base\nfirst\nsecond", instruction)
+}
+
+func TestChatModelAgentConditionalMiddleware(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	type call struct {
+		system string
+		tools  []*schema.ToolInfo
+	}
+	var calls []call
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			calls = append(calls, call{system: input[0].Content, tools: model.GetCommonOptions(nil, opts...).Tools})
+			if strings.Contains(input[0].Content, "deep_search") && input[len(input)-1].Role == schema.User {
+				return schema.AssistantMessage("", []schema.ToolCall{
+					{ID: "call-1", Function: schema.FunctionCall{Name: "deep_search", Arguments: "{}"}},
+				}), nil
+			}
+			return schema.AssistantMessage("done", nil), nil
+		}).AnyTimes()
+
+	hookCalls := 0
+	research := AgentMiddleware{
+		AdditionalInstruction: "Use deep_search for research.",
+		AdditionalTools:       []tool.BaseTool{&simpleTool{name: "deep_search", result: "found"}},
+		BeforeChatModel: func(ctx context.Context, state *ChatModelAgentState) error {
+			hookCalls++
+			return nil
+		},
+		ShouldApply: func(ctx context.Context, input *AgentInput) bool {
+			mode, _ := GetSessionValue(ctx, "mode")
+			return mode == "full"
+		},
+	}
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with a conditional middleware",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		ToolsConfig: ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{
+				Tools: []tool.BaseTool{&simpleTool{name: "echo", result: "echo"}},
+			},
+		},
+		Middlewares: []AgentMiddleware{research},
+	})
+	assert.NoError(t, err)
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent})
+
+	t.Run("full mode", func(t *testing.T) {
+		calls, hookCalls = nil, 0
+		var toolResults []string
+		iter := runner.Query(ctx, "research eino", WithSessionValues(map[string]any{"mode": "full"}))
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			if event.Output != nil && event.Output.MessageOutput.Role == schema.Tool {
+				toolResults = append(toolResults, event.Output.MessageOutput.Message.Content)
+			}
+		}
+
+		assert.Equal(t, []string{"found"}, toolResults)
+		assert.Len(t, calls, 2)
+		assert.Contains(t, calls[0].system, "Use deep_search for research.")
+		// the tools bound to the model are used as is
+		assert.Nil(t, calls[0].tools)
+		assert.Equal(t, 2, hookCalls)
+	})
+
+	t.Run("lightweight mode", func(t *testing.T) {
+		calls, hookCalls = nil, 0
+		output, err := runner.RunToCompletion(ctx, []Message{schema.UserMessage("research eino")})
+		assert.NoError(t, err)
+		assert.Equal(t, "done", output.MessageOutput.Message.Content)
+
+		assert.Len(t, calls, 1)
+		assert.Equal(t, "You are a helpful assistant.", calls[0].system)
+		assert.Len(t, calls[0].tools, 1)
+		assert.Equal(t, "echo", calls[0].tools[0].Name)
+		assert.Zero(t, hookCalls)
+	})
+}
+
+type approvalTool struct{}
+
+func (approvalTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "deploy", Desc: "deploy after an approval"}, nil
+}
+
+func (approvalTool) InvokableRun(ctx context.Context, _ string, _ ...tool.Option) (string, error) {
+	if wasInterrupted, _, _ := tool.GetInterruptState[any](ctx); !wasInterrupted {
+		return "", tool.Interrupt(ctx, "approve the deployment")
+	}
+	return "deployed", nil
+}
+
+func TestChatModelAgentConditionalMiddlewareResume(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	var systems []string
+	var sessionValues map[string]any
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			systems = append(systems, input[0].Content)
+			sessionValues = GetSessionValues(ctx)
+			if strings.Contains(input[0].Content, "deploy") && input[len(input)-1].Role == schema.User {
+				return schema.AssistantMessage("", []schema.ToolCall{
+					{ID: "call-1", Function: schema.FunctionCall{Name: "deploy", Arguments: "{}"}},
+				}), nil
+			}
+			return schema.AssistantMessage("done", nil), nil
+		}).AnyTimes()
+
+	applies := true
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with a conditional middleware",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		Middlewares: []AgentMiddleware{{
+			AdditionalInstruction: "Use deploy to deploy.",
+			AdditionalTools:       []tool.BaseTool{approvalTool{}},
+			ShouldApply: func(ctx context.Context, input *AgentInput) bool {
+				return applies
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent, CheckPointStore: newMyStore()})
+
+	iter := runner.Query(ctx, "deploy eino", WithCheckPointID("1"))
+	var interrupted bool
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Action != nil && event.Action.Interrupted != nil {
+			interrupted = true
+		}
+	}
+	assert.True(t, interrupted)
+
+	// the middleware selected by the interrupted run is kept by the resumed one
+	applies = false
+	iter, err = runner.Resume(ctx, "1")
+	assert.NoError(t, err)
+	var toolResults []string
+	var final string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Output == nil {
+			continue
+		}
+		if msg := event.Output.MessageOutput.Message; msg.Role == schema.Tool {
+			toolResults = append(toolResults, msg.Content)
+		} else {
+			final = msg.Content
+		}
+	}
+	assert.Equal(t, []string{"deployed"}, toolResults)
+	assert.Equal(t, "done", final)
+	assert.Len(t, systems, 2)
+	assert.Contains(t, systems[1], "Use deploy to deploy.")
+	// the selection is kept apart from the session values
+	assert.Empty(t, sessionValues)
+
+	// a new run evaluates ShouldApply again
+	systems = nil
+	output, err := runner.RunToCompletion(ctx, []Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", output.MessageOutput.Message.Content)
+	assert.Equal(t, []string{"You are a helpful assistant."}, systems)
+}
+
+func TestChatModelAgentToolPriority(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	var boundTools []string
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).DoAndReturn(func(infos []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+		boundTools = nil
+		for _, info := range infos {
+			boundTools = append(boundTools, info.Name)
+		}
+		return cm, nil
+	}).Times(1)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("done", nil), nil).Times(1)
+
+	middleware := func(names ...string) AgentMiddleware {
+		var tools []tool.BaseTool
+		for _, name := range names {
+			tools = append(tools, &simpleTool{name: name})
+		}
+		return AgentMiddleware{AdditionalTools: tools}
+	}
+	filesystem := middleware("ls", "read_file")
+	todos := middleware("write_todos")
+	todos.AdditionalToolPriority = map[string]int{"write_todos": 10}
+	finish := middleware("finish", "debug_dump")
+
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent ordering its tools",
+		Model:       cm,
+		ToolsConfig: ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{
+				Tools: []tool.BaseTool{&simpleTool{name: "search"}},
+			},
+			ToolPriority: map[string]int{"finish": 100, "debug_dump": -1},
+		},
+		Middlewares: []AgentMiddleware{filesystem, todos, finish},
+	})
+	assert.NoError(t, err)
+
+	_, err = RunToCompletion(ctx, agent, &AgentInput{Messages: []Message{schema.UserMessage("hi")}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"finish", "write_todos", "search", "ls", "read_file", "debug_dump"}, boundTools)
+}
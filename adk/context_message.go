@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// DefaultContextSaidTemplate is the default template of the text content of an assistant message of another agent.
+	DefaultContextSaidTemplate = "[{{.AgentName}}] said: {{.Content}}."
+	// DefaultContextToolCallTemplate is the default template of a tool call of another agent.
+	DefaultContextToolCallTemplate = "[{{.AgentName}}] called tool: `{{.ToolName}}` with arguments: {{.Arguments}}."
+	// DefaultContextToolResultTemplate is the default template of a tool result of another agent.
+	DefaultContextToolResultTemplate = "[{{.AgentName}}] `{{.ToolName}}` tool returned result: {{.Result}}."
+)
+
+// ContextMessageTemplates are the Go templates rewriting the messages of another agent into the user messages
+// giving context to an agent, e.g. the chat history passed to an agent tool by WithFullChatHistoryAsInput,
+// or the history of an agent after a transfer, see WithTransferContextMessageTemplates.
+// A rewritten message is "For context:" followed by a sentence per text content, tool call and tool result of the
+// original message, each rendered by its template with a ContextMessageData.
+type ContextMessageTemplates struct {
+	said       *template.Template
+	toolCall   *template.Template
+	toolResult *template.Template
+}
+
+// ContextMessageData is the data the ContextMessageTemplates are rendered with.
+type ContextMessageData struct {
+	// AgentName is the name of the agent the message is from.
+	AgentName string
+	// Content is the text content of the assistant message, set for the said template.
+	Content string
+	// ToolName is the name of the tool called or returning, set for the tool call and tool result templates.
+	ToolName string
+	// Arguments are the arguments of the tool call, set for the tool call template.
+	Arguments string
+	// Result is the result of the tool, set for the tool result template.
+	Result string
+}
+
+var defaultContextMessageTemplates = mustNewContextMessageTemplates()
+
+// NewContextMessageTemplates parses and validates the templates of the text contents, tool calls and tool results of
+// the messages of other agents, an empty template being replaced by its default, e.g. DefaultContextSaidTemplate.
+// e.g.
+//
+//	templates, err := adk.NewContextMessageTemplates("", "[{{.AgentName}}] used {{.ToolName}}({{.Arguments}}).", "")
+func NewContextMessageTemplates(said, toolCall, toolResult string) (*ContextMessageTemplates, error) {
+	var err error
+	t := &ContextMessageTemplates{}
+	if t.said, err = parseContextTemplate("said", said, DefaultContextSaidTemplate); err != nil {
+		return nil, err
+	}
+	if t.toolCall, err = parseContextTemplate("tool call", toolCall, DefaultContextToolCallTemplate); err != nil {
+		return nil, err
+	}
+	if t.toolResult, err = parseContextTemplate("tool result", toolResult, DefaultContextToolResultTemplate); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func mustNewContextMessageTemplates() *ContextMessageTemplates {
+	t, err := NewContextMessageTemplates("", "", "")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseContextTemplate(name, text, defaultText string) (*template.Template, error) {
+	if text == "" {
+		text = defaultText
+	}
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s context message template: %w", name, err)
+	}
+	// rendering catches the references to fields ContextMessageData doesn't have
+	if err = t.Execute(&strings.Builder{}, &ContextMessageData{}); err != nil {
+		return nil, fmt.Errorf("invalid %s context message template: %w", name, err)
+	}
+	return t, nil
+}
+
+// rewrite rewrites a message of agentName into a context message.
+func (t *ContextMessageTemplates) rewrite(msg Message, agentName string) (Message, error) {
+	var sb strings.Builder
+	sb.WriteString(contextMessagePrefix)
+	write := func(tmpl *template.Template, data *ContextMessageData) error {
+		sb.WriteString(" ")
+		return tmpl.Execute(&sb, data)
+	}
+
+	if msg.Role == schema.Assistant {
+		if msg.Content != "" {
+			if err := write(t.said, &ContextMessageData{AgentName: agentName, Content: msg.Content}); err != nil {
+				return nil, err
+			}
+		}
+		for i := range msg.ToolCalls {
+			f := msg.ToolCalls[i].Function
+			if err := write(t.toolCall, &ContextMessageData{AgentName: agentName, ToolName: f.Name, Arguments: f.Arguments}); err != nil {
+				return nil, err
+			}
+		}
+	} else if msg.Role == schema.Tool && msg.Content != "" {
+		if err := write(t.toolResult, &ContextMessageData{AgentName: agentName, ToolName: msg.ToolName, Result: msg.Content}); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema.UserMessage(sb.String()), nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// WithConversationID persists the conversation of the run in the CheckPointStore of the Runner under id,
+// so that later runs with the same id continue it, e.g. from a Runner created afresh after the service restarted.
+// The history of the previous runs of the conversation, i.e. their input messages and the messages of their events
+// along with the agents emitting them, precedes the messages of the run in the input of each agent,
+// rewritten as the events of the run are, and their session values are restored before the ones of WithSessionValues.
+// The conversation is saved under id at the end of each run that neither fails nor is canceled.
+// An interrupted run saves its checkpoint under id instead, overriding WithCheckPointID: resuming it with
+// Runner.Resume or Runner.ResumeWithParams and id continues the conversation, which Run refuses to do until then.
+// The types of the session values must be registered by schema.RegisterName to be persisted.
+func WithConversationID(id string) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.conversationID = &id
+	})
+}
+
+// loadConversation returns the run context saved at the end of the last run of the conversation id,
+// or nil if the conversation has none.
+func (r *Runner) loadConversation(ctx context.Context, id string) (*runContext, error) {
+	if r.store == nil {
+		return nil, errors.New("failed to load conversation: store is nil")
+	}
+
+	data, existed, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation from store: %w", err)
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	s := &serialization{}
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(s); err != nil {
+		return nil, fmt.Errorf("failed to decode conversation: %w", err)
+	}
+	if len(s.InterruptID2Address) > 0 {
+		return nil, fmt.Errorf("conversation[%s] is interrupted, resume it first", id)
+	}
+	return s.RunCtx, nil
+}
+
+// saveConversation saves the conversation id at the end of the run of ctx: its history is the one of the previous runs,
+// followed by the input messages of the run and the messages of its events, and its session values are the ones of the run.
+func (r *Runner) saveConversation(ctx context.Context, id string) error {
+	runCtx := getRunCtx(ctx)
+
+	history := append([]*HistoryEntry{}, runCtx.History...)
+	for _, m := range runCtx.RootInput.Messages {
+		history = append(history, &HistoryEntry{IsUserInput: true, Message: m})
+	}
+	for _, event := range runCtx.Session.getEvents() {
+		msg, err := getMessageFromWrappedEvent(event)
+		if err != nil {
+			var retryErr *WillRetryError
+			if errors.As(err, &retryErr) {
+				continue
+			}
+			return err
+		}
+		if msg != nil {
+			history = append(history, &HistoryEntry{AgentName: event.AgentName, Message: msg})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err := gob.NewEncoder(buf).Encode(&serialization{
+		RunCtx: &runContext{
+			Session: &runSession{Values: GetSessionValues(ctx)},
+			History: history,
+		},
+		EnableStreaming: r.enableStreaming,
+		ConversationID:  id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation: %w", err)
+	}
+	return r.store.Set(ctx, id, buf.Bytes())
+}
@@ -17,8 +17,12 @@
 package adk
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
 	"errors"
+	"fmt"
+	"reflect"
 	"runtime/debug"
 	"sync"
 
@@ -34,23 +38,57 @@ type deterministicTransferState struct {
 	EventList []*agentEventWrapper
 }
 
+// SessionValuesSerializer marshals session values, so that an isolated session can be given a copy of them
+// rather than a shared reference. See DeterministicTransferConfig.SessionValuesSerializer.
+type SessionValuesSerializer interface {
+	Marshal(values map[string]any) ([]byte, error)
+	Unmarshal(data []byte) (map[string]any, error)
+}
+
+// NewGobSessionValuesSerializer returns a SessionValuesSerializer using gob,
+// the concrete types of the values must be registered by schema.Register, as for checkpoints.
+func NewGobSessionValuesSerializer() SessionValuesSerializer {
+	return gobSessionValuesSerializer{}
+}
+
+type gobSessionValuesSerializer struct{}
+
+func (gobSessionValuesSerializer) Marshal(values map[string]any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobSessionValuesSerializer) Unmarshal(data []byte) (map[string]any, error) {
+	var values map[string]any
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // AgentWithDeterministicTransferTo wraps an agent to transfer to given agents deterministically.
 func AgentWithDeterministicTransferTo(_ context.Context, config *DeterministicTransferConfig) Agent {
 	if ra, ok := config.Agent.(ResumableAgent); ok {
 		return &resumableAgentWithDeterministicTransferTo{
-			agent:        ra,
-			toAgentNames: config.ToAgentNames,
+			agent:            ra,
+			toAgentNames:     config.ToAgentNames,
+			valuesSerializer: config.SessionValuesSerializer,
 		}
 	}
 	return &agentWithDeterministicTransferTo{
-		agent:        config.Agent,
-		toAgentNames: config.ToAgentNames,
+		agent:            config.Agent,
+		toAgentNames:     config.ToAgentNames,
+		valuesSerializer: config.SessionValuesSerializer,
 	}
 }
 
 type agentWithDeterministicTransferTo struct {
-	agent        Agent
-	toAgentNames []string
+	agent            Agent
+	toAgentNames     []string
+	valuesSerializer SessionValuesSerializer
 }
 
 func (a *agentWithDeterministicTransferTo) Description(ctx context.Context) string {
@@ -65,7 +103,7 @@ func (a *agentWithDeterministicTransferTo) Run(ctx context.Context,
 	input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 
 	if fa, ok := a.agent.(*flowAgent); ok {
-		return runFlowAgentWithIsolatedSession(ctx, fa, input, a.toAgentNames, options...)
+		return runFlowAgentWithIsolatedSession(ctx, fa, input, a.toAgentNames, a.valuesSerializer, options...)
 	}
 
 	aIter := a.agent.Run(ctx, input, options...)
@@ -77,8 +115,9 @@ func (a *agentWithDeterministicTransferTo) Run(ctx context.Context,
 }
 
 type resumableAgentWithDeterministicTransferTo struct {
-	agent        ResumableAgent
-	toAgentNames []string
+	agent            ResumableAgent
+	toAgentNames     []string
+	valuesSerializer SessionValuesSerializer
 }
 
 func (a *resumableAgentWithDeterministicTransferTo) Description(ctx context.Context) string {
@@ -93,7 +132,7 @@ func (a *resumableAgentWithDeterministicTransferTo) Run(ctx context.Context,
 	input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 
 	if fa, ok := a.agent.(*flowAgent); ok {
-		return runFlowAgentWithIsolatedSession(ctx, fa, input, a.toAgentNames, options...)
+		return runFlowAgentWithIsolatedSession(ctx, fa, input, a.toAgentNames, a.valuesSerializer, options...)
 	}
 
 	aIter := a.agent.Run(ctx, input, options...)
@@ -106,7 +145,7 @@ func (a *resumableAgentWithDeterministicTransferTo) Run(ctx context.Context,
 
 func (a *resumableAgentWithDeterministicTransferTo) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	if fa, ok := a.agent.(*flowAgent); ok {
-		return resumeFlowAgentWithIsolatedSession(ctx, fa, info, a.toAgentNames, opts...)
+		return resumeFlowAgentWithIsolatedSession(ctx, fa, info, a.toAgentNames, a.valuesSerializer, opts...)
 	}
 
 	aIter := a.agent.Resume(ctx, info, opts...)
@@ -117,6 +156,9 @@ func (a *resumableAgentWithDeterministicTransferTo) Resume(ctx context.Context,
 	return iterator
 }
 
+// forwardEventsAndAppendTransfer forwards the events of a non-flow agent, and appends the transfers once the agent
+// finishes without interrupting or exiting. As no transfer is sent by an interrupted run, the wrapper keeps no
+// interrupt state of its own: the resumed run appends the transfers, exactly once.
 func forwardEventsAndAppendTransfer(iter *AsyncIterator[*AgentEvent],
 	generator *AsyncGenerator[*AgentEvent], toAgentNames []string) {
 
@@ -145,20 +187,14 @@ func forwardEventsAndAppendTransfer(iter *AsyncIterator[*AgentEvent],
 }
 
 func runFlowAgentWithIsolatedSession(ctx context.Context, fa *flowAgent, input *AgentInput,
-	toAgentNames []string, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	toAgentNames []string, serializer SessionValuesSerializer, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 
 	parentSession := getSession(ctx)
 	parentRunCtx := getRunCtx(ctx)
 
-	isolatedSession := &runSession{
-		Values:    parentSession.Values,
-		valuesMtx: parentSession.valuesMtx,
-	}
-	if isolatedSession.valuesMtx == nil {
-		isolatedSession.valuesMtx = &sync.Mutex{}
-	}
-	if isolatedSession.Values == nil {
-		isolatedSession.Values = make(map[string]any)
+	isolatedSession, forkedValues, err := newIsolatedSession(parentSession, serializer)
+	if err != nil {
+		return genErrorIter(err)
 	}
 
 	ctx = setRunCtx(ctx, &runContext{
@@ -170,13 +206,13 @@ func runFlowAgentWithIsolatedSession(ctx context.Context, fa *flowAgent, input *
 	iter := fa.Run(ctx, input, options...)
 
 	iterator, generator := NewAsyncIteratorPair[*AgentEvent]()
-	go handleFlowAgentEvents(ctx, iter, generator, isolatedSession, parentSession, toAgentNames)
+	go handleFlowAgentEvents(ctx, iter, generator, isolatedSession, parentSession, forkedValues, toAgentNames, serializer)
 
 	return iterator
 }
 
 func resumeFlowAgentWithIsolatedSession(ctx context.Context, fa *flowAgent, info *ResumeInfo,
-	toAgentNames []string, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	toAgentNames []string, serializer SessionValuesSerializer, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 
 	state, ok := info.InterruptState.(*deterministicTransferState)
 	if !ok || state == nil {
@@ -186,17 +222,11 @@ func resumeFlowAgentWithIsolatedSession(ctx context.Context, fa *flowAgent, info
 	parentSession := getSession(ctx)
 	parentRunCtx := getRunCtx(ctx)
 
-	isolatedSession := &runSession{
-		Values:    parentSession.Values,
-		valuesMtx: parentSession.valuesMtx,
-		Events:    state.EventList,
-	}
-	if isolatedSession.valuesMtx == nil {
-		isolatedSession.valuesMtx = &sync.Mutex{}
-	}
-	if isolatedSession.Values == nil {
-		isolatedSession.Values = make(map[string]any)
+	isolatedSession, forkedValues, err := newIsolatedSession(parentSession, serializer)
+	if err != nil {
+		return genErrorIter(err)
 	}
+	isolatedSession.Events = state.EventList
 
 	ctx = setRunCtx(ctx, &runContext{
 		Session:   isolatedSession,
@@ -207,13 +237,84 @@ func resumeFlowAgentWithIsolatedSession(ctx context.Context, fa *flowAgent, info
 	iter := fa.Resume(ctx, info, opts...)
 
 	iterator, generator := NewAsyncIteratorPair[*AgentEvent]()
-	go handleFlowAgentEvents(ctx, iter, generator, isolatedSession, parentSession, toAgentNames)
+	go handleFlowAgentEvents(ctx, iter, generator, isolatedSession, parentSession, forkedValues, toAgentNames, serializer)
 
 	return iterator
 }
 
+// newIsolatedSession returns a session sharing the values of parentSession,
+// or holding a serialized copy of them if serializer is set.
+// In the latter case it also returns another copy of the values as they were at the fork,
+// against which the changes of the isolated session are found when writing them back.
+func newIsolatedSession(parentSession *runSession, serializer SessionValuesSerializer) (*runSession, map[string]any, error) {
+	if serializer != nil {
+		data, err := serializer.Marshal(parentSession.getValues())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to copy session values to isolated session: %w", err)
+		}
+		values, err := unmarshalSessionValues(data, serializer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to copy session values to isolated session: %w", err)
+		}
+		forkedValues, err := unmarshalSessionValues(data, serializer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to copy session values to isolated session: %w", err)
+		}
+		return &runSession{Values: values, valuesMtx: &sync.Mutex{}, State: parentSession.State,
+			maxEvents: parentSession.maxEvents}, forkedValues, nil
+	}
+
+	isolatedSession := &runSession{
+		Values:    parentSession.Values,
+		valuesMtx: parentSession.valuesMtx,
+		State:     parentSession.State,
+		maxEvents: parentSession.maxEvents,
+	}
+	if isolatedSession.valuesMtx == nil {
+		isolatedSession.valuesMtx = &sync.Mutex{}
+	}
+	if isolatedSession.Values == nil {
+		isolatedSession.Values = make(map[string]any)
+	}
+	if isolatedSession.State == nil {
+		isolatedSession.State = &runState{}
+	}
+	return isolatedSession, nil, nil
+}
+
+func copySessionValues(values map[string]any, serializer SessionValuesSerializer) (map[string]any, error) {
+	data, err := serializer.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalSessionValues(data, serializer)
+}
+
+func unmarshalSessionValues(data []byte, serializer SessionValuesSerializer) (map[string]any, error) {
+	values, err := serializer.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = make(map[string]any)
+	}
+	return values, nil
+}
+
+// changedSessionValues returns the values that are not in forkedValues or differ from them.
+func changedSessionValues(values, forkedValues map[string]any) map[string]any {
+	changed := make(map[string]any)
+	for k, v := range values {
+		if forked, ok := forkedValues[k]; !ok || !reflect.DeepEqual(forked, v) {
+			changed[k] = v
+		}
+	}
+	return changed
+}
+
 func handleFlowAgentEvents(ctx context.Context, iter *AsyncIterator[*AgentEvent],
-	generator *AsyncGenerator[*AgentEvent], isolatedSession, parentSession *runSession, toAgentNames []string) {
+	generator *AsyncGenerator[*AgentEvent], isolatedSession, parentSession *runSession, forkedValues map[string]any,
+	toAgentNames []string, serializer SessionValuesSerializer) {
 
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
@@ -246,6 +347,17 @@ func handleFlowAgentEvents(ctx context.Context, iter *AsyncIterator[*AgentEvent]
 		lastEvent = event
 	}
 
+	if serializer != nil && parentSession != nil {
+		// write the values changed by the isolated session back, so that the parent and a resumed run see them,
+		// while the values the parent set meanwhile are kept
+		values, err := copySessionValues(isolatedSession.getValues(), serializer)
+		if err != nil {
+			generator.Send(&AgentEvent{Err: fmt.Errorf("failed to write back session values of isolated session: %w", err)})
+			return
+		}
+		parentSession.addValues(changedSessionValues(values, forkedValues))
+	}
+
 	if lastEvent != nil && lastEvent.Action != nil {
 		if lastEvent.Action.internalInterrupted != nil {
 			events := isolatedSession.getEvents()
@@ -265,8 +377,9 @@ func handleFlowAgentEvents(ctx context.Context, iter *AsyncIterator[*AgentEvent]
 }
 
 func sendTransferEvents(generator *AsyncGenerator[*AgentEvent], toAgentNames []string) {
-	for _, toAgentName := range toAgentNames {
-		aMsg, tMsg := GenTransferMessages(context.Background(), toAgentName)
+	msgs := GenTransferMessagesBatch(context.Background(), toAgentNames)
+	for i, toAgentName := range toAgentNames {
+		aMsg, tMsg := msgs[2*i], msgs[2*i+1]
 
 		aEvent := EventFromMessage(aMsg, nil, schema.Assistant, "")
 		generator.Send(aEvent)
@@ -18,6 +18,7 @@ package adk
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -642,6 +643,62 @@ func TestDeterministicTransferNonFlowAgent_Resume(t *testing.T) {
 	assert.True(t, sawTransfer, "should see transfer event after resume completion")
 }
 
+func TestDeterministicTransferNonFlowAgent_InterruptResumeTransfersOnce(t *testing.T) {
+	ctx := context.Background()
+
+	agent := &nonFlowTestAgent{
+		name: "test_agent",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				gen.Send(EventFromMessage(schema.AssistantMessage("before interrupt", nil), nil, schema.Assistant, ""))
+				gen.Send(&AgentEvent{
+					Action: &AgentAction{
+						Interrupted: &InterruptInfo{Data: "test interrupt"},
+					},
+				})
+			}()
+			return iter
+		},
+		resumeFn: func(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				gen.Send(EventFromMessage(schema.AssistantMessage("after resume", nil), nil, schema.Assistant, ""))
+			}()
+			return iter
+		},
+	}
+
+	wrapped := AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:        agent,
+		ToAgentNames: []string{"agent_a", "agent_b"},
+	})
+	ra, ok := wrapped.(ResumableAgent)
+	assert.True(t, ok, "wrapped agent should be ResumableAgent")
+
+	collectTransfers := func(iter *AsyncIterator[*AgentEvent]) []string {
+		var transfers []string
+		for {
+			ev, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if ev.Action != nil && ev.Action.TransferToAgent != nil {
+				transfers = append(transfers, ev.Action.TransferToAgent.DestAgentName)
+			}
+		}
+		return transfers
+	}
+
+	transfers := collectTransfers(ra.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("test")}}))
+	assert.Empty(t, transfers, "interrupted run should not send transfers")
+
+	transfers = append(transfers, collectTransfers(ra.Resume(ctx, &ResumeInfo{WasInterrupted: true}))...)
+	assert.Equal(t, []string{"agent_a", "agent_b"}, transfers, "each transfer should be sent exactly once across run and resume")
+}
+
 func TestDeterministicTransferFlowAgent_ResumeWithInvalidState(t *testing.T) {
 	ctx := context.Background()
 
@@ -731,3 +788,266 @@ func TestDeterministicTransferNonResumableAgent(t *testing.T) {
 
 	assert.True(t, sawTransfer, "should see transfer event")
 }
+
+func TestDeterministicTransferSerializedSessionValues(t *testing.T) {
+	ctx := context.Background()
+
+	checked := make(chan struct{})
+	var childSaw any
+	innerAgent := &dtTestAgent{
+		name: "inner",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				childSaw, _ = GetSessionValue(ctx, "k")
+				AddSessionValues(ctx, map[string]any{"k": "child", "new": 1})
+				gen.Send(EventFromMessage(schema.AssistantMessage("from inner", nil), nil, schema.Assistant, ""))
+				<-checked
+			}()
+			return iter
+		},
+	}
+
+	wrapped := AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:                   toFlowAgent(ctx, innerAgent),
+		SessionValuesSerializer: NewGobSessionValuesSerializer(),
+	})
+
+	var duringChild, afterChild map[string]any
+	outerAgent := &dtTestAgent{
+		name: "outer",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			AddSessionValue(ctx, "k", "parent")
+			innerIter := wrapped.Run(ctx, input, options...)
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				for {
+					ev, ok := innerIter.Next()
+					if !ok {
+						break
+					}
+					if duringChild == nil {
+						duringChild = GetSessionValues(ctx)
+						close(checked)
+					}
+					gen.Send(ev)
+				}
+				afterChild = GetSessionValues(ctx)
+			}()
+			return iter
+		},
+	}
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: toFlowAgent(ctx, outerAgent)})
+	iter := runner.Run(ctx, []Message{schema.UserMessage("test")})
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, "parent", childSaw)
+	assert.Equal(t, map[string]any{"k": "parent"}, duringChild)
+	assert.Equal(t, map[string]any{"k": "child", "new": 1}, afterChild)
+}
+
+func TestDeterministicTransferSerializedSessionValuesKeepParentWrites(t *testing.T) {
+	ctx := context.Background()
+
+	updated := make(chan struct{})
+	innerAgent := &dtTestAgent{
+		name: "inner",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				AddSessionValue(ctx, "k", "child")
+				gen.Send(EventFromMessage(schema.AssistantMessage("from inner", nil), nil, schema.Assistant, ""))
+				<-updated
+			}()
+			return iter
+		},
+	}
+
+	wrapped := AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:                   toFlowAgent(ctx, innerAgent),
+		SessionValuesSerializer: NewGobSessionValuesSerializer(),
+	})
+
+	var afterChild map[string]any
+	outerAgent := &dtTestAgent{
+		name: "outer",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			AddSessionValues(ctx, map[string]any{"k": "parent", "p": "before"})
+			innerIter := wrapped.Run(ctx, input, options...)
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				first := true
+				for {
+					ev, ok := innerIter.Next()
+					if !ok {
+						break
+					}
+					if first {
+						first = false
+						AddSessionValue(ctx, "p", "after")
+						close(updated)
+					}
+					gen.Send(ev)
+				}
+				afterChild = GetSessionValues(ctx)
+			}()
+			return iter
+		},
+	}
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: toFlowAgent(ctx, outerAgent)})
+	iter := runner.Run(ctx, []Message{schema.UserMessage("test")})
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, map[string]any{"k": "child", "p": "after"}, afterChild)
+}
+
+func TestDeterministicTransferMaxSessionEventsResume(t *testing.T) {
+	ctx := context.Background()
+	store := newDTTestStore()
+
+	var resumedEvents []string
+	innerAgent := &dtTestAgent{
+		name: "inner",
+		runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				for i := 0; i < 100; i++ {
+					gen.Send(EventFromMessage(schema.AssistantMessage(fmt.Sprintf("event %d", i), nil), nil, schema.Assistant, ""))
+				}
+				gen.Send(Interrupt(ctx, "interrupt_data"))
+			}()
+			return iter
+		},
+		resumeFn: func(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			for _, ev := range getRunCtx(ctx).Session.getEvents() {
+				resumedEvents = append(resumedEvents, ev.Output.MessageOutput.Message.Content)
+			}
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			go func() {
+				defer gen.Close()
+				gen.Send(EventFromMessage(schema.AssistantMessage("after resume", nil), nil, schema.Assistant, ""))
+			}()
+			return iter
+		},
+	}
+
+	wrapped := AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:        toFlowAgent(ctx, innerAgent),
+		ToAgentNames: []string{"next_agent"},
+	})
+	runner := NewRunner(ctx, RunnerConfig{
+		Agent:            wrapped,
+		CheckPointStore:  store,
+		MaxSessionEvents: 3,
+	})
+
+	var interruptID string
+	iter := runner.Run(ctx, []Message{schema.UserMessage("test")}, WithCheckPointID("cp1"))
+	for {
+		ev, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if ev.Action != nil && ev.Action.Interrupted != nil {
+			for _, intCtx := range ev.Action.Interrupted.InterruptContexts {
+				if intCtx.IsRootCause {
+					interruptID = intCtx.ID
+				}
+			}
+		}
+	}
+	assert.NotEmpty(t, interruptID)
+
+	resumeIter, err := runner.ResumeWithParams(ctx, "cp1", &ResumeParams{Targets: map[string]any{interruptID: nil}})
+	assert.NoError(t, err)
+	var contents []string
+	for {
+		ev, ok := resumeIter.Next()
+		if !ok {
+			break
+		}
+		if ev.Output != nil && ev.Output.MessageOutput != nil && ev.Output.MessageOutput.Message != nil {
+			contents = append(contents, ev.Output.MessageOutput.Message.Content)
+		}
+	}
+
+	// only the latest events were retained, and the run resumed from them
+	assert.Equal(t, []string{"event 97", "event 98", "event 99"}, resumedEvents)
+	assert.Contains(t, contents, "after resume")
+}
+
+func TestTransferChain(t *testing.T) {
+	ctx := context.Background()
+
+	outputAgent := func(name string, runFn func(ctx context.Context)) *dtTestAgent {
+		return &dtTestAgent{
+			name: name,
+			runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+				if runFn != nil {
+					runFn(ctx)
+				}
+				iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+				gen.Send(EventFromMessage(schema.AssistantMessage(name+" output", nil), nil, schema.Assistant, ""))
+				gen.Close()
+				return iter
+			},
+		}
+	}
+
+	var chainInC []string
+	var valuesInC map[string]any
+	c := outputAgent("c", func(ctx context.Context) {
+		chainInC = GetTransferChain(ctx)
+		valuesInC = GetSessionValues(ctx)
+	})
+	b, err := SetSubAgents(ctx, AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:        outputAgent("b", nil),
+		ToAgentNames: []string{"c"},
+	}), []Agent{c})
+	assert.NoError(t, err)
+	a, err := SetSubAgents(ctx, AgentWithDeterministicTransferTo(ctx, &DeterministicTransferConfig{
+		Agent:        outputAgent("a", nil),
+		ToAgentNames: []string{"b"},
+	}), []Agent{b})
+	assert.NoError(t, err)
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: a})
+	iter := runner.Run(ctx, []Message{schema.UserMessage("hi")})
+	var outputs []string
+	for {
+		ev, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, ev.Err)
+		if ev.Output != nil && ev.Output.MessageOutput != nil && ev.Output.MessageOutput.Role == schema.Assistant &&
+			ev.Output.MessageOutput.Message.Content != "" {
+			outputs = append(outputs, ev.Output.MessageOutput.Message.Content)
+		}
+	}
+
+	assert.Equal(t, []string{"a output", "b output", "c output"}, outputs)
+	assert.Equal(t, []string{"a", "b", "c"}, chainInC)
+	// the chain is kept apart from the session values
+	assert.Empty(t, valuesInC)
+	assert.Nil(t, GetTransferChain(ctx))
+}
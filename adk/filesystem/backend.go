@@ -19,14 +19,21 @@ package filesystem
 
 import (
 	"context"
+	"errors"
 
 	"github.com/cloudwego/eino/schema"
 )
 
+// ErrFileNotFound is wrapped by the errors of the Backend methods when the file they operate on doesn't exist,
+// so that callers can tell it apart from other failures with errors.Is.
+var ErrFileNotFound = errors.New("file not found")
+
 // FileInfo represents basic file metadata information.
 type FileInfo struct {
 	// Path is the absolute path of the file or directory.
 	Path string
+	// IsDir reports whether Path is a directory.
+	IsDir bool
 }
 
 // GrepMatch represents a single pattern match result.
@@ -35,6 +42,10 @@ type GrepMatch struct {
 	Path string
 	// Line is the 1-based line number of the match.
 	Line int
+	// Column is the 1-based byte offset in the line where the match starts,
+	// which is the first match of the line unless GrepRequest.AllOccurrences is set.
+	// It is 0 if the backend does not report columns.
+	Column int
 	// Content is the full text content of the line containing the match.
 	Content string
 }
@@ -61,6 +72,12 @@ type ReadRequest struct {
 	Limit int
 }
 
+// ReadAllRequest contains parameters for reading the whole content of a file.
+type ReadAllRequest struct {
+	// FilePath is the absolute path to the file to be read. Must start with '/'.
+	FilePath string
+}
+
 // GrepRequest contains parameters for searching file content.
 type GrepRequest struct {
 	// Pattern is the literal string to search for. This is not a regular expression.
@@ -80,6 +97,10 @@ type GrepRequest struct {
 	//   - `?` matches a single character.
 	//   - `[abc]` matches one character from the set.
 	Glob string
+
+	// AllOccurrences makes a line with several matches have a GrepMatch for each of them.
+	// optional, a GrepMatch per matching line by default
+	AllOccurrences bool
 }
 
 // GlobInfoRequest contains parameters for glob pattern matching.
@@ -105,6 +126,27 @@ type WriteRequest struct {
 
 	// Content is the data to be written to the file.
 	Content string
+
+	// IdempotencyKey optionally identifies the write, e.g. by the id of the tool call issuing it.
+	// Backends supporting it treat a write whose key was already applied to the file as successful without writing again,
+	// so that retrying a write which succeeded but failed to report doesn't fail or duplicate its effect.
+	// The keys applied to a file may be forgotten once it's removed.
+	// Backends not supporting it ignore it.
+	IdempotencyKey string
+}
+
+// AppendRequest contains parameters for appending content to a file.
+type AppendRequest struct {
+	// FilePath is the absolute path of the file to append to. Must start with '/'.
+	// The file will be created if it does not exist.
+	FilePath string
+
+	// Content is the data to be appended to the file.
+	Content string
+
+	// IdempotencyKey optionally identifies the append, e.g. by the id of the tool call issuing it.
+	// An append whose key was already applied to the file must not append again, and returns no error.
+	IdempotencyKey string
 }
 
 // EditRequest contains parameters for editing file content.
@@ -142,7 +184,7 @@ type Backend interface {
 	//
 	// Returns:
 	//   - string: The file content read
-	//   - error: Error if file does not exist or read fails
+	//   - error: Error if file does not exist, wrapping ErrFileNotFound, or read fails
 	Read(ctx context.Context, req *ReadRequest) (string, error)
 
 	// GrepRaw searches for content matching the specified pattern in files.
@@ -172,6 +214,93 @@ type Backend interface {
 	Edit(ctx context.Context, req *EditRequest) error
 }
 
+// Pinger is an optional interface for a Backend to check that it's reachable and correctly configured,
+// e.g. that its credentials are valid or its root directory exists.
+// Middlewares call Ping at construction when the Backend implements it, to fail fast on misconfigurations.
+type Pinger interface {
+	// Ping returns an error if the Backend is not usable.
+	Ping(ctx context.Context) error
+}
+
+// RemoveRequest contains parameters for removing files.
+type RemoveRequest struct {
+	// Path is the path of the file to remove, or of the directory to remove with all the files under it.
+	Path string
+}
+
+// RemovableBackend is a Backend which can also remove files.
+type RemovableBackend interface {
+	Backend
+
+	// Remove removes the file or directory at the given path. Removing a path that doesn't exist is not an error.
+	//
+	// Returns:
+	//   - error: Error if the remove operation fails
+	Remove(ctx context.Context, req *RemoveRequest) error
+}
+
+// AppendableBackend is a Backend which can also append to files.
+type AppendableBackend interface {
+	Backend
+
+	// Append appends content to the end of a file, creating it if it doesn't exist.
+	// Implementations must honor AppendRequest.IdempotencyKey, since a retried append isn't otherwise safe.
+	//
+	// Returns:
+	//   - error: Error if the append operation fails
+	Append(ctx context.Context, req *AppendRequest) error
+}
+
+// ReadAllBackend is a Backend which can also read a file whole, without the line limit of Read.
+type ReadAllBackend interface {
+	Backend
+
+	// ReadAll reads the full content of a file, as is, without line numbers.
+	//
+	// Returns:
+	//   - string: The file content
+	//   - error: Error if file does not exist, wrapping ErrFileNotFound, or read fails
+	ReadAll(ctx context.Context, req *ReadAllRequest) (string, error)
+}
+
+// CopyRequest contains parameters for copying a file.
+type CopyRequest struct {
+	// SrcPath is the absolute path of the file to copy. Must start with '/'.
+	SrcPath string
+
+	// DstPath is the absolute path of the copy. Must start with '/'.
+	DstPath string
+
+	// Overwrite allows replacing the file at DstPath if it exists.
+	// If false, the operation fails when DstPath exists.
+	Overwrite bool
+}
+
+// CopyableBackend is a Backend which can also copy files natively.
+type CopyableBackend interface {
+	Backend
+
+	// Copy copies the file at SrcPath to DstPath.
+	//
+	// Returns:
+	//   - error: Error if SrcPath does not exist, DstPath exists without Overwrite, or the copy fails
+	Copy(ctx context.Context, req *CopyRequest) error
+}
+
+// LockableBackend is a Backend which can also lock files, so that the read-modify-write of a file, e.g. applying a patch,
+// isn't interleaved with the ones of concurrent tool calls or agents sharing the Backend.
+type LockableBackend interface {
+	Backend
+
+	// Lock blocks until the exclusive lock of the file at the given path is acquired, or ctx is done.
+	// The lock is advisory: it only excludes the other holders of the lock, not the other methods of the Backend.
+	//
+	// Returns:
+	//   - func(): Releases the lock, which is safe to call more than once
+	//   - error: Error if ctx is done or the lock can't be acquired
+	Lock(ctx context.Context, path string) (unlock func(), err error)
+}
+
 type ExecuteRequest struct {
 	Command string
 }
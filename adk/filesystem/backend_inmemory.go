@@ -29,12 +29,36 @@ import (
 type InMemoryBackend struct {
 	mu    sync.RWMutex
 	files map[string]string // map[filePath]content
+	// appliedKeys holds the idempotency keys of the writes and appends already applied, keyed by file path
+	appliedKeys map[string]*appliedKeys
+
+	locksMu sync.Mutex
+	// locks are the file locks held or waited for, keyed by file path
+	locks map[string]*fileLock
+}
+
+// fileLock is a mutex waitable with a context: holding it is having sent to ch.
+type fileLock struct {
+	ch chan struct{}
+	// refs is the number of holders and waiters, the lock being dropped from the map when it reaches 0
+	refs int
+}
+
+// maxAppliedKeysPerFile caps the idempotency keys remembered for a file, the oldest being forgotten first,
+// as a retry follows shortly the write or append it retries.
+const maxAppliedKeysPerFile = 1024
+
+// appliedKeys are the idempotency keys applied to a file, in the order they were applied.
+type appliedKeys struct {
+	keys  map[string]struct{}
+	order []string
 }
 
 // NewInMemoryBackend creates a new in-memory backend.
 func NewInMemoryBackend() *InMemoryBackend {
 	return &InMemoryBackend{
-		files: make(map[string]string),
+		files:       make(map[string]string),
+		appliedKeys: make(map[string]*appliedKeys),
 	}
 }
 
@@ -77,7 +101,8 @@ func (b *InMemoryBackend) LsInfo(ctx context.Context, req *LsInfoRequest) ([]Fil
 				childPath += parts[0]
 
 				if !seen[childPath] {
-					result = append(result, FileInfo{Path: childPath})
+					// more segments after the first one means the child is a directory
+					result = append(result, FileInfo{Path: childPath, IsDir: len(parts) > 1})
 					seen[childPath] = true
 				}
 			}
@@ -96,7 +121,7 @@ func (b *InMemoryBackend) Read(ctx context.Context, req *ReadRequest) (string, e
 
 	content, exists := b.files[filePath]
 	if !exists {
-		return "", fmt.Errorf("file not found: %s", filePath)
+		return "", fmt.Errorf("%w: %s", ErrFileNotFound, filePath)
 	}
 
 	offset := req.Offset
@@ -108,7 +133,7 @@ func (b *InMemoryBackend) Read(ctx context.Context, req *ReadRequest) (string, e
 		limit = 200
 	}
 
-	lines := strings.Split(content, "\n")
+	lines := splitLines(content)
 	totalLines := len(lines)
 
 	if offset >= totalLines {
@@ -130,6 +155,20 @@ func (b *InMemoryBackend) Read(ctx context.Context, req *ReadRequest) (string, e
 	return sb.String(), nil
 }
 
+// ReadAll reads the full content of a file.
+func (b *InMemoryBackend) ReadAll(ctx context.Context, req *ReadAllRequest) (string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	filePath := normalizePath(req.FilePath)
+
+	content, exists := b.files[filePath]
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrFileNotFound, filePath)
+	}
+	return content, nil
+}
+
 // GrepRaw returns matches for the given pattern.
 func (b *InMemoryBackend) GrepRaw(ctx context.Context, req *GrepRequest) ([]GrepMatch, error) {
 	b.mu.RLock()
@@ -162,12 +201,17 @@ func (b *InMemoryBackend) GrepRaw(ctx context.Context, req *GrepRequest) ([]Grep
 		}
 
 		// Search for pattern in file content
-		lines := strings.Split(content, "\n")
+		lines := splitLines(content)
 		for lineNum, line := range lines {
-			if strings.Contains(line, req.Pattern) {
+			cols := matchColumns(line, req.Pattern)
+			if len(cols) > 0 && !req.AllOccurrences {
+				cols = cols[:1]
+			}
+			for _, col := range cols {
 				matches = append(matches, GrepMatch{
 					Path:    normalizedFilePath,
 					Line:    lineNum + 1, // 1-based line number
+					Column:  col,
 					Content: line,
 				})
 			}
@@ -214,11 +258,139 @@ func (b *InMemoryBackend) Write(ctx context.Context, req *WriteRequest) error {
 	defer b.mu.Unlock()
 
 	filePath := normalizePath(req.FilePath)
+	if b.isApplied(filePath, req.IdempotencyKey) {
+		return nil
+	}
 	if _, ok := b.files[filePath]; ok {
 		return fmt.Errorf("file already exists: %s", filePath)
 	}
 
 	b.files[filePath] = req.Content
+	b.markApplied(filePath, req.IdempotencyKey)
+
+	return nil
+}
+
+// Append appends content to a file, creating it if it doesn't exist.
+// An append whose idempotency key was already applied is skipped.
+func (b *InMemoryBackend) Append(ctx context.Context, req *AppendRequest) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	filePath := normalizePath(req.FilePath)
+	if b.isApplied(filePath, req.IdempotencyKey) {
+		return nil
+	}
+
+	b.files[filePath] += req.Content
+	b.markApplied(filePath, req.IdempotencyKey)
+
+	return nil
+}
+
+// Lock acquires the exclusive lock of the file at the given path, blocking until it's released by its holder or ctx is done.
+func (b *InMemoryBackend) Lock(ctx context.Context, path string) (func(), error) {
+	path = normalizePath(path)
+
+	b.locksMu.Lock()
+	if b.locks == nil {
+		b.locks = make(map[string]*fileLock)
+	}
+	l, ok := b.locks[path]
+	if !ok {
+		l = &fileLock{ch: make(chan struct{}, 1)}
+		b.locks[path] = l
+	}
+	l.refs++
+	b.locksMu.Unlock()
+
+	select {
+	case l.ch <- struct{}{}:
+	case <-ctx.Done():
+		b.releaseLock(path, l)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-l.ch
+			b.releaseLock(path, l)
+		})
+	}, nil
+}
+
+func (b *InMemoryBackend) releaseLock(path string, l *fileLock) {
+	b.locksMu.Lock()
+	defer b.locksMu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(b.locks, path)
+	}
+}
+
+func (b *InMemoryBackend) isApplied(filePath, key string) bool {
+	if key == "" {
+		return false
+	}
+	ak, ok := b.appliedKeys[filePath]
+	if !ok {
+		return false
+	}
+	_, ok = ak.keys[key]
+	return ok
+}
+
+func (b *InMemoryBackend) markApplied(filePath, key string) {
+	if key == "" {
+		return
+	}
+	ak, ok := b.appliedKeys[filePath]
+	if !ok {
+		ak = &appliedKeys{keys: make(map[string]struct{})}
+		b.appliedKeys[filePath] = ak
+	}
+	if len(ak.order) == maxAppliedKeysPerFile {
+		delete(ak.keys, ak.order[0])
+		ak.order = ak.order[1:]
+	}
+	ak.keys[key] = struct{}{}
+	ak.order = append(ak.order, key)
+}
+
+// Remove removes the file at the given path, or all files under it if it's a directory.
+func (b *InMemoryBackend) Remove(ctx context.Context, req *RemoveRequest) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := normalizePath(req.Path)
+	dirPrefix := strings.TrimSuffix(p, "/") + "/"
+	for filePath := range b.files {
+		if filePath == p || strings.HasPrefix(filePath, dirPrefix) {
+			delete(b.files, filePath)
+			// a write or append to the path is applied again once the file is gone
+			delete(b.appliedKeys, filePath)
+		}
+	}
+
+	return nil
+}
+
+// Copy copies the file at SrcPath to DstPath.
+func (b *InMemoryBackend) Copy(ctx context.Context, req *CopyRequest) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	srcPath, dstPath := normalizePath(req.SrcPath), normalizePath(req.DstPath)
+	content, ok := b.files[srcPath]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, srcPath)
+	}
+	if _, ok = b.files[dstPath]; ok && !req.Overwrite {
+		return fmt.Errorf("file already exists: %s", dstPath)
+	}
+
+	b.files[dstPath] = content
 
 	return nil
 }
@@ -232,7 +404,7 @@ func (b *InMemoryBackend) Edit(ctx context.Context, req *EditRequest) error {
 
 	content, exists := b.files[filePath]
 	if !exists {
-		return fmt.Errorf("file not found: %s", filePath)
+		return fmt.Errorf("%w: %s", ErrFileNotFound, filePath)
 	}
 
 	if req.OldString == "" {
@@ -262,6 +434,23 @@ func (b *InMemoryBackend) Edit(ctx context.Context, req *EditRequest) error {
 	return nil
 }
 
+// matchColumns returns the 1-based byte offsets of the non-overlapping occurrences of pattern in line.
+func matchColumns(line, pattern string) []int {
+	if pattern == "" {
+		return []int{1}
+	}
+
+	var cols []int
+	for offset := 0; ; {
+		idx := strings.Index(line[offset:], pattern)
+		if idx < 0 {
+			return cols
+		}
+		cols = append(cols, offset+idx+1)
+		offset += idx + len(pattern)
+	}
+}
+
 // normalizePath normalizes a file path by ensuring it starts with "/" and removing trailing slashes.
 func normalizePath(path string) string {
 	if path == "" {
@@ -275,3 +464,14 @@ func normalizePath(path string) string {
 
 	return filepath.Clean(path)
 }
+
+// splitLines splits content into lines, treating both "\n" and "\r\n" as line endings.
+// A trailing line ending does not start a new line, so "a\nb\n" and "a\r\nb" both have two lines.
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
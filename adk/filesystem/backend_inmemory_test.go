@@ -18,7 +18,9 @@ package filesystem
 
 import (
 	"context"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestInMemoryBackend_WriteAndRead(t *testing.T) {
@@ -73,6 +75,66 @@ func TestInMemoryBackend_WriteAndRead(t *testing.T) {
 	}
 }
 
+func TestInMemoryBackend_LineEndings(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	files := map[string]string{
+		"/crlf.txt":             "line1\r\nline2\r\nline3\r\n",
+		"/no_trailing.txt":      "line1\nline2\nline3",
+		"/trailing_newline.txt": "line1\nline2\nline3\n",
+	}
+	for path, content := range files {
+		if err := backend.Write(ctx, &WriteRequest{FilePath: path, Content: content}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	for path := range files {
+		content, err := backend.Read(ctx, &ReadRequest{FilePath: path, Limit: 100})
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		expected := "     1\tline1\n     2\tline2\n     3\tline3"
+		if content != expected {
+			t.Errorf("Read %s content mismatch. Expected: %q, Got: %q", path, expected, content)
+		}
+
+		content, err = backend.Read(ctx, &ReadRequest{FilePath: path, Offset: 2, Limit: 5})
+		if err != nil {
+			t.Fatalf("Read with offset failed: %v", err)
+		}
+		expected = "     3\tline3"
+		if content != expected {
+			t.Errorf("Read %s with offset content mismatch. Expected: %q, Got: %q", path, expected, content)
+		}
+
+		content, err = backend.Read(ctx, &ReadRequest{FilePath: path, Offset: 3, Limit: 5})
+		if err != nil {
+			t.Fatalf("Read past the end failed: %v", err)
+		}
+		if content != "" {
+			t.Errorf("Read %s past the end expected empty content, got %q", path, content)
+		}
+	}
+
+	matches, err := backend.GrepRaw(ctx, &GrepRequest{Pattern: "line3", Path: "/crlf.txt"})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Line != 3 || matches[0].Content != "line3" {
+		t.Errorf("Unexpected grep matches in CRLF file: %+v", matches)
+	}
+
+	matches, err = backend.GrepRaw(ctx, &GrepRequest{Pattern: "line", Path: "/no_trailing.txt"})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+	if len(matches) != 3 || matches[2].Line != 3 || matches[2].Content != "line3" {
+		t.Errorf("Unexpected grep matches in file without trailing newline: %+v", matches)
+	}
+}
+
 func TestInMemoryBackend_LsInfo(t *testing.T) {
 	backend := NewInMemoryBackend()
 	ctx := context.Background()
@@ -116,6 +178,20 @@ func TestInMemoryBackend_LsInfo(t *testing.T) {
 	if len(infos) != 2 { // file3.txt, subdir
 		t.Errorf("Expected 2 items in /dir1, got %d", len(infos))
 	}
+	for _, info := range infos {
+		switch info.Path {
+		case "/dir1/file3.txt":
+			if info.IsDir {
+				t.Errorf("Expected /dir1/file3.txt to be a file")
+			}
+		case "/dir1/subdir":
+			if !info.IsDir {
+				t.Errorf("Expected /dir1/subdir to be a directory")
+			}
+		default:
+			t.Errorf("Unexpected item %s in /dir1", info.Path)
+		}
+	}
 }
 
 func TestInMemoryBackend_Edit(t *testing.T) {
@@ -187,6 +263,144 @@ func TestInMemoryBackend_Edit(t *testing.T) {
 	}
 }
 
+func TestInMemoryBackend_Remove(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	for _, p := range []string{"/dir/a.txt", "/dir/sub/b.txt", "/dir2/c.txt", "/d.txt"} {
+		if err := backend.Write(ctx, &WriteRequest{FilePath: p, Content: "content"}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// Test Remove - directory, without removing files sharing its name as prefix
+	if err := backend.Remove(ctx, &RemoveRequest{Path: "/dir/"}); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	// Test Remove - single file
+	if err := backend.Remove(ctx, &RemoveRequest{Path: "/d.txt"}); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	// Test Remove - non-existent path
+	if err := backend.Remove(ctx, &RemoveRequest{Path: "/nonexistent"}); err != nil {
+		t.Fatalf("Remove of non-existent path failed: %v", err)
+	}
+
+	infos, err := backend.GlobInfo(ctx, &GlobInfoRequest{Pattern: "**", Path: "/"})
+	if err != nil {
+		t.Fatalf("GlobInfo failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Path != "/dir2/c.txt" {
+		t.Errorf("Expected only /dir2/c.txt to remain, got %v", infos)
+	}
+}
+
+func TestInMemoryBackend_Copy(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	for p, c := range map[string]string{"/a.txt": "a", "/b.txt": "b"} {
+		if err := backend.Write(ctx, &WriteRequest{FilePath: p, Content: c}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// Test Copy - new destination
+	if err := backend.Copy(ctx, &CopyRequest{SrcPath: "/a.txt", DstPath: "/backup/a.txt"}); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if content, _ := backend.ReadAll(ctx, &ReadAllRequest{FilePath: "/backup/a.txt"}); content != "a" {
+		t.Errorf("Expected copied content %q, got %q", "a", content)
+	}
+
+	// Test Copy - existing destination without overwrite
+	if err := backend.Copy(ctx, &CopyRequest{SrcPath: "/a.txt", DstPath: "/b.txt"}); err == nil {
+		t.Error("Expected error for existing destination, got nil")
+	}
+	if content, _ := backend.ReadAll(ctx, &ReadAllRequest{FilePath: "/b.txt"}); content != "b" {
+		t.Errorf("Expected destination to be kept, got %q", content)
+	}
+
+	// Test Copy - existing destination with overwrite
+	if err := backend.Copy(ctx, &CopyRequest{SrcPath: "/a.txt", DstPath: "/b.txt", Overwrite: true}); err != nil {
+		t.Fatalf("Copy with overwrite failed: %v", err)
+	}
+	if content, _ := backend.ReadAll(ctx, &ReadAllRequest{FilePath: "/b.txt"}); content != "a" {
+		t.Errorf("Expected overwritten content %q, got %q", "a", content)
+	}
+
+	// Test Copy - non-existent source
+	if err := backend.Copy(ctx, &CopyRequest{SrcPath: "/nonexistent.txt", DstPath: "/c.txt"}); err == nil {
+		t.Error("Expected error for non-existent source, got nil")
+	}
+}
+
+func TestInMemoryBackend_IdempotentWriteAndAppend(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	// Test Write - a retried write with the same key succeeds without writing again
+	for i := 0; i < 2; i++ {
+		if err := backend.Write(ctx, &WriteRequest{FilePath: "/w.txt", Content: "first", IdempotencyKey: "call_1"}); err != nil {
+			t.Fatalf("Write attempt %d failed: %v", i, err)
+		}
+	}
+	if err := backend.Write(ctx, &WriteRequest{FilePath: "/w.txt", Content: "second", IdempotencyKey: "call_2"}); err == nil {
+		t.Error("Expected error writing an existing file with a new key")
+	}
+
+	// Test Append - the underlying append succeeded, but the caller retries it
+	if err := backend.Append(ctx, &AppendRequest{FilePath: "/log.txt", Content: "line1\n"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := backend.Append(ctx, &AppendRequest{FilePath: "/log.txt", Content: "line2\n", IdempotencyKey: "call_3"}); err != nil {
+			t.Fatalf("Append attempt %d failed: %v", i, err)
+		}
+	}
+	// Test Append - appends without a key are never deduplicated
+	for i := 0; i < 2; i++ {
+		if err := backend.Append(ctx, &AppendRequest{FilePath: "/log.txt", Content: "line3\n"}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	content, err := backend.Read(ctx, &ReadRequest{FilePath: "/log.txt"})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if content != "     1\tline1\n     2\tline2\n     3\tline3\n     4\tline3" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+
+	// Test Write - the keys are scoped by file path, and forgotten once the file is removed
+	if err := backend.Write(ctx, &WriteRequest{FilePath: "/other.txt", Content: "other", IdempotencyKey: "call_1"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := backend.Read(ctx, &ReadRequest{FilePath: "/other.txt"}); err != nil {
+		t.Errorf("Expected /other.txt to be written with a key applied to another file: %v", err)
+	}
+	if err := backend.Remove(ctx, &RemoveRequest{Path: "/w.txt"}); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := backend.Write(ctx, &WriteRequest{FilePath: "/w.txt", Content: "first", IdempotencyKey: "call_1"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := backend.Read(ctx, &ReadRequest{FilePath: "/w.txt"}); err != nil {
+		t.Errorf("Expected /w.txt to be written again after its removal: %v", err)
+	}
+
+	// Test Append - the oldest keys of a file are forgotten beyond the cap
+	for i := 0; i <= maxAppliedKeysPerFile; i++ {
+		if err := backend.Append(ctx, &AppendRequest{FilePath: "/many.txt", Content: "x", IdempotencyKey: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	if n := len(backend.appliedKeys["/many.txt"].keys); n != maxAppliedKeysPerFile {
+		t.Errorf("Expected %d keys remembered, got %d", maxAppliedKeysPerFile, n)
+	}
+}
+
 func TestInMemoryBackend_GrepRaw(t *testing.T) {
 	backend := NewInMemoryBackend()
 	ctx := context.Background()
@@ -247,6 +461,49 @@ func TestInMemoryBackend_GrepRaw(t *testing.T) {
 	}
 }
 
+func TestInMemoryBackend_GrepRawColumn(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	backend.Write(ctx, &WriteRequest{
+		FilePath: "/file.go",
+		Content:  "package main\n\tx := foo(foo)\nfoofoo",
+	})
+
+	matches, err := backend.GrepRaw(ctx, &GrepRequest{Pattern: "foo", AllOccurrences: true})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+
+	// a match not at line start, and several matches per line, including adjacent ones
+	expected := [][2]int{{2, 7}, {2, 11}, {3, 1}, {3, 4}}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(expected), len(matches), matches)
+	}
+	for i, m := range matches {
+		if m.Line != expected[i][0] || m.Column != expected[i][1] {
+			t.Errorf("Match %d: expected line %d column %d, got line %d column %d",
+				i, expected[i][0], expected[i][1], m.Line, m.Column)
+		}
+	}
+
+	// a line with several matches has a single match at the first of them by default
+	matches, err = backend.GrepRaw(ctx, &GrepRequest{Pattern: "foo"})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+	expected = [][2]int{{2, 7}, {3, 1}}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(expected), len(matches), matches)
+	}
+	for i, m := range matches {
+		if m.Line != expected[i][0] || m.Column != expected[i][1] {
+			t.Errorf("Match %d: expected line %d column %d, got line %d column %d",
+				i, expected[i][0], expected[i][1], m.Line, m.Column)
+		}
+	}
+}
+
 func TestInMemoryBackend_GlobInfo(t *testing.T) {
 	backend := NewInMemoryBackend()
 	ctx := context.Background()
@@ -319,3 +576,40 @@ func TestInMemoryBackend_Concurrent(t *testing.T) {
 		<-done
 	}
 }
+
+func TestInMemoryBackend_Lock(t *testing.T) {
+	backend := NewInMemoryBackend()
+	ctx := context.Background()
+
+	unlock, err := backend.Lock(ctx, "/locked.txt")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	// another file isn't locked
+	unlockOther, err := backend.Lock(ctx, "/other.txt")
+	if err != nil {
+		t.Fatalf("Lock of another file failed: %v", err)
+	}
+	unlockOther()
+
+	// the same file is locked until ctx is done
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err = backend.Lock(timeoutCtx, "locked.txt"); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		u, _ := backend.Lock(ctx, "/locked.txt")
+		acquired <- u
+	}()
+	unlock()
+	unlock()
+	(<-acquired)()
+
+	if len(backend.locks) != 0 {
+		t.Errorf("expected released locks to be dropped, got %d", len(backend.locks))
+	}
+}
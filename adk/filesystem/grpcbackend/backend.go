@@ -0,0 +1,288 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package grpcbackend provides a filesystem Backend calling a FileService over gRPC, and the server side of it.
+// It's a module of its own, so that the core eino module doesn't depend on gRPC.
+package grpcbackend
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/adk/filesystem/grpcbackend/grpcpb"
+)
+
+// NewBackend returns a filesystem.Backend calling the FileService served on conn,
+// defined by grpcpb/backend.proto, e.g. by a sandbox written in another language.
+func NewBackend(conn grpc.ClientConnInterface) filesystem.Backend {
+	return &grpcBackend{client: grpcpb.NewFileServiceClient(conn)}
+}
+
+// NewShellBackend is like NewBackend, for a FileService also serving Execute.
+func NewShellBackend(conn grpc.ClientConnInterface) filesystem.ShellBackend {
+	return &grpcShellBackend{grpcBackend{client: grpcpb.NewFileServiceClient(conn)}}
+}
+
+type grpcBackend struct {
+	client grpcpb.FileServiceClient
+}
+
+func (g *grpcBackend) LsInfo(ctx context.Context, req *filesystem.LsInfoRequest) ([]filesystem.FileInfo, error) {
+	resp, err := g.client.LsInfo(ctx, &grpcpb.LsInfoRequest{Path: req.Path})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+	return fromPBFileInfos(resp.Files), nil
+}
+
+func (g *grpcBackend) Read(ctx context.Context, req *filesystem.ReadRequest) (string, error) {
+	resp, err := g.client.Read(ctx, &grpcpb.ReadRequest{
+		FilePath: req.FilePath,
+		Offset:   int64(req.Offset),
+		Limit:    int64(req.Limit),
+	})
+	if err != nil {
+		return "", fromGRPCError(err)
+	}
+	return resp.Content, nil
+}
+
+func (g *grpcBackend) GrepRaw(ctx context.Context, req *filesystem.GrepRequest) ([]filesystem.GrepMatch, error) {
+	resp, err := g.client.GrepRaw(ctx, &grpcpb.GrepRequest{
+		Pattern:        req.Pattern,
+		Path:           req.Path,
+		Glob:           req.Glob,
+		AllOccurrences: req.AllOccurrences,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+	matches := make([]filesystem.GrepMatch, 0, len(resp.Matches))
+	for _, m := range resp.Matches {
+		matches = append(matches, filesystem.GrepMatch{
+			Path:    m.Path,
+			Line:    int(m.Line),
+			Column:  int(m.Column),
+			Content: m.Content,
+		})
+	}
+	return matches, nil
+}
+
+func (g *grpcBackend) GlobInfo(ctx context.Context, req *filesystem.GlobInfoRequest) ([]filesystem.FileInfo, error) {
+	resp, err := g.client.GlobInfo(ctx, &grpcpb.GlobInfoRequest{Pattern: req.Pattern, Path: req.Path})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+	return fromPBFileInfos(resp.Files), nil
+}
+
+func (g *grpcBackend) Write(ctx context.Context, req *filesystem.WriteRequest) error {
+	_, err := g.client.Write(ctx, &grpcpb.WriteRequest{
+		FilePath:       req.FilePath,
+		Content:        req.Content,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	return fromGRPCError(err)
+}
+
+func (g *grpcBackend) Edit(ctx context.Context, req *filesystem.EditRequest) error {
+	_, err := g.client.Edit(ctx, &grpcpb.EditRequest{
+		FilePath:   req.FilePath,
+		OldString:  req.OldString,
+		NewString:  req.NewString,
+		ReplaceAll: req.ReplaceAll,
+	})
+	return fromGRPCError(err)
+}
+
+type grpcShellBackend struct {
+	grpcBackend
+}
+
+func (g *grpcShellBackend) Execute(ctx context.Context, input *filesystem.ExecuteRequest) (*filesystem.ExecuteResponse, error) {
+	resp, err := g.client.Execute(ctx, &grpcpb.ExecuteRequest{Command: input.Command})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+	result := &filesystem.ExecuteResponse{Output: resp.Output, Truncated: resp.Truncated}
+	if resp.ExitCode != nil {
+		code := int(*resp.ExitCode)
+		result.ExitCode = &code
+	}
+	return result, nil
+}
+
+// NewServer returns a FileService serving backend, to be registered by grpcpb.RegisterFileServiceServer.
+// Execute is served if backend implements filesystem.ShellBackend.
+func NewServer(backend filesystem.Backend) grpcpb.FileServiceServer {
+	return &grpcBackendServer{backend: backend}
+}
+
+type grpcBackendServer struct {
+	grpcpb.UnimplementedFileServiceServer
+	backend filesystem.Backend
+}
+
+func (s *grpcBackendServer) LsInfo(ctx context.Context, req *grpcpb.LsInfoRequest) (*grpcpb.LsInfoResponse, error) {
+	infos, err := s.backend.LsInfo(ctx, &filesystem.LsInfoRequest{Path: req.Path})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &grpcpb.LsInfoResponse{Files: toPBFileInfos(infos)}, nil
+}
+
+func (s *grpcBackendServer) Read(ctx context.Context, req *grpcpb.ReadRequest) (*grpcpb.ReadResponse, error) {
+	content, err := s.backend.Read(ctx, &filesystem.ReadRequest{
+		FilePath: req.FilePath,
+		Offset:   int(req.Offset),
+		Limit:    int(req.Limit),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &grpcpb.ReadResponse{Content: content}, nil
+}
+
+func (s *grpcBackendServer) GrepRaw(ctx context.Context, req *grpcpb.GrepRequest) (*grpcpb.GrepResponse, error) {
+	matches, err := s.backend.GrepRaw(ctx, &filesystem.GrepRequest{
+		Pattern:        req.Pattern,
+		Path:           req.Path,
+		Glob:           req.Glob,
+		AllOccurrences: req.AllOccurrences,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &grpcpb.GrepResponse{Matches: make([]*grpcpb.GrepMatch, 0, len(matches))}
+	for _, m := range matches {
+		resp.Matches = append(resp.Matches, &grpcpb.GrepMatch{
+			Path:    m.Path,
+			Line:    int64(m.Line),
+			Column:  int64(m.Column),
+			Content: m.Content,
+		})
+	}
+	return resp, nil
+}
+
+func (s *grpcBackendServer) GlobInfo(ctx context.Context, req *grpcpb.GlobInfoRequest) (*grpcpb.GlobInfoResponse, error) {
+	infos, err := s.backend.GlobInfo(ctx, &filesystem.GlobInfoRequest{Pattern: req.Pattern, Path: req.Path})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &grpcpb.GlobInfoResponse{Files: toPBFileInfos(infos)}, nil
+}
+
+func (s *grpcBackendServer) Write(ctx context.Context, req *grpcpb.WriteRequest) (*grpcpb.WriteResponse, error) {
+	err := s.backend.Write(ctx, &filesystem.WriteRequest{
+		FilePath:       req.FilePath,
+		Content:        req.Content,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &grpcpb.WriteResponse{}, nil
+}
+
+func (s *grpcBackendServer) Edit(ctx context.Context, req *grpcpb.EditRequest) (*grpcpb.EditResponse, error) {
+	err := s.backend.Edit(ctx, &filesystem.EditRequest{
+		FilePath:   req.FilePath,
+		OldString:  req.OldString,
+		NewString:  req.NewString,
+		ReplaceAll: req.ReplaceAll,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &grpcpb.EditResponse{}, nil
+}
+
+func (s *grpcBackendServer) Execute(ctx context.Context, req *grpcpb.ExecuteRequest) (*grpcpb.ExecuteResponse, error) {
+	sb, ok := s.backend.(filesystem.ShellBackend)
+	if !ok {
+		return s.UnimplementedFileServiceServer.Execute(ctx, req)
+	}
+	result, err := sb.Execute(ctx, &filesystem.ExecuteRequest{Command: req.Command})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	resp := &grpcpb.ExecuteResponse{Output: result.Output, Truncated: result.Truncated}
+	if result.ExitCode != nil {
+		code := int64(*result.ExitCode)
+		resp.ExitCode = &code
+	}
+	return resp, nil
+}
+
+func fromPBFileInfos(files []*grpcpb.FileInfo) []filesystem.FileInfo {
+	infos := make([]filesystem.FileInfo, 0, len(files))
+	for _, f := range files {
+		infos = append(infos, filesystem.FileInfo{Path: f.Path, IsDir: f.IsDir})
+	}
+	return infos
+}
+
+func toPBFileInfos(infos []filesystem.FileInfo) []*grpcpb.FileInfo {
+	files := make([]*grpcpb.FileInfo, 0, len(infos))
+	for _, fi := range infos {
+		files = append(files, &grpcpb.FileInfo{Path: fi.Path, IsDir: fi.IsDir})
+	}
+	return files
+}
+
+// toGRPCError returns a backend error with the UNKNOWN code, or NOT_FOUND if it wraps filesystem.ErrFileNotFound,
+// which fromGRPCError turns back into the plain error message.
+func toGRPCError(err error) error {
+	if errors.Is(err, filesystem.ErrFileNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func fromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unknown:
+			return errors.New(st.Message())
+		case codes.NotFound:
+			return &fileNotFoundError{msg: st.Message()}
+		}
+	}
+	return err
+}
+
+// fileNotFoundError is the error of a remote backend reporting a missing file.
+type fileNotFoundError struct {
+	msg string
+}
+
+func (e *fileNotFoundError) Error() string {
+	return e.msg
+}
+
+func (e *fileNotFoundError) Unwrap() error {
+	return filesystem.ErrFileNotFound
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcbackend
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/adk/filesystem/grpcbackend/grpcpb"
+)
+
+type echoShellBackend struct {
+	*filesystem.InMemoryBackend
+}
+
+func (e *echoShellBackend) Execute(_ context.Context, input *filesystem.ExecuteRequest) (*filesystem.ExecuteResponse, error) {
+	code := 0
+	return &filesystem.ExecuteResponse{Output: input.Command, ExitCode: &code}, nil
+}
+
+func newGRPCTestConn(t *testing.T, backend filesystem.Backend) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	grpcpb.RegisterFileServiceServer(srv, NewServer(backend))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestGRPCBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := NewBackend(newGRPCTestConn(t, filesystem.NewInMemoryBackend()))
+
+	if err := backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/dir/a.txt", Content: "hello world\nfoo"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/b.py", Content: "print('hello')"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	content, err := backend.Read(ctx, &filesystem.ReadRequest{FilePath: "/dir/a.txt", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if expected := "     2\tfoo"; content != expected {
+		t.Errorf("Read content mismatch. Expected: %q, Got: %q", expected, content)
+	}
+
+	infos, err := backend.LsInfo(ctx, &filesystem.LsInfoRequest{Path: "/"})
+	if err != nil {
+		t.Fatalf("LsInfo failed: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Errorf("Expected 2 entries, got %d: %v", len(infos), infos)
+	}
+
+	infos, err = backend.GlobInfo(ctx, &filesystem.GlobInfoRequest{Pattern: "*.py", Path: "/"})
+	if err != nil {
+		t.Fatalf("GlobInfo failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Path != "/b.py" {
+		t.Errorf("Expected /b.py, got %v", infos)
+	}
+
+	matches, err := backend.GrepRaw(ctx, &filesystem.GrepRequest{Pattern: "hello"})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("Expected 2 matches, got %d: %v", len(matches), matches)
+	}
+
+	matches, err = backend.GrepRaw(ctx, &filesystem.GrepRequest{Pattern: "l", Path: "/dir", AllOccurrences: true})
+	if err != nil {
+		t.Fatalf("GrepRaw failed: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Errorf("Expected 3 matches, got %d: %v", len(matches), matches)
+	}
+
+	if err = backend.Edit(ctx, &filesystem.EditRequest{FilePath: "/dir/a.txt", OldString: "foo", NewString: "bar"}); err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	content, err = backend.Read(ctx, &filesystem.ReadRequest{FilePath: "/dir/a.txt", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if expected := "     2\tbar"; content != expected {
+		t.Errorf("Read after edit mismatch. Expected: %q, Got: %q", expected, content)
+	}
+
+	// backend errors are passed through as plain errors
+	_, err = backend.Read(ctx, &filesystem.ReadRequest{FilePath: "/nonexistent.txt", Limit: 10})
+	if err == nil {
+		t.Fatal("Expected error for non-existent file, got nil")
+	}
+	if _, ok := status.FromError(err); ok {
+		t.Errorf("Expected plain error, got status error: %v", err)
+	}
+	if !errors.Is(err, filesystem.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got: %v", err)
+	}
+}
+
+func TestGRPCShellBackend(t *testing.T) {
+	ctx := context.Background()
+
+	shell := NewShellBackend(newGRPCTestConn(t, &echoShellBackend{filesystem.NewInMemoryBackend()}))
+	resp, err := shell.Execute(ctx, &filesystem.ExecuteRequest{Command: "echo hi"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if resp.Output != "echo hi" || resp.ExitCode == nil || *resp.ExitCode != 0 {
+		t.Errorf("Unexpected execute response: %+v", resp)
+	}
+
+	noShell := NewShellBackend(newGRPCTestConn(t, filesystem.NewInMemoryBackend()))
+	_, err = noShell.Execute(ctx, &filesystem.ExecuteRequest{Command: "echo hi"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented, got %v", err)
+	}
+}
@@ -45,6 +45,7 @@ type flowAgent struct {
 
 	disallowTransferToParent bool
 	historyRewriter          HistoryRewriter
+	contextMessageTemplates  *ContextMessageTemplates
 
 	checkPointStore compose.CheckPointStore
 }
@@ -56,6 +57,7 @@ func (a *flowAgent) deepCopy() *flowAgent {
 		parentAgent:              a.parentAgent,
 		disallowTransferToParent: a.disallowTransferToParent,
 		historyRewriter:          a.historyRewriter,
+		contextMessageTemplates:  a.contextMessageTemplates,
 		checkPointStore:          a.checkPointStore,
 	}
 
@@ -86,6 +88,19 @@ func WithHistoryRewriter(h HistoryRewriter) AgentOption {
 	}
 }
 
+// WithTransferContextMessageTemplates sets the templates rewriting the messages of the other agents in the history
+// of the agent, e.g. after a transfer to it, unless WithHistoryRewriter replaces the rewriting altogether.
+// Optional. The default templates are used if not set.
+// e.g.
+//
+//	templates, err := adk.NewContextMessageTemplates("", "[{{.AgentName}}] used {{.ToolName}}({{.Arguments}}).", "")
+//	agent = adk.AgentWithOptions(ctx, agent, adk.WithTransferContextMessageTemplates(templates))
+func WithTransferContextMessageTemplates(templates *ContextMessageTemplates) AgentOption {
+	return func(fa *flowAgent) {
+		fa.contextMessageTemplates = templates
+	}
+}
+
 func toFlowAgent(ctx context.Context, agent Agent, opts ...AgentOption) *flowAgent {
 	var fa *flowAgent
 	var ok bool
@@ -98,10 +113,6 @@ func toFlowAgent(ctx context.Context, agent Agent, opts ...AgentOption) *flowAge
 		opt(fa)
 	}
 
-	if fa.historyRewriter == nil {
-		fa.historyRewriter = buildDefaultHistoryRewriter(agent.Name(ctx))
-	}
-
 	return fa
 }
 
@@ -166,32 +177,17 @@ func (a *flowAgent) getAgent(ctx context.Context, name string) *flowAgent {
 	return nil
 }
 
-func rewriteMessage(msg Message, agentName string) Message {
-	var sb strings.Builder
-	sb.WriteString("For context:")
-	if msg.Role == schema.Assistant {
-		if msg.Content != "" {
-			sb.WriteString(fmt.Sprintf(" [%s] said: %s.", agentName, msg.Content))
-		}
-		if len(msg.ToolCalls) > 0 {
-			for i := range msg.ToolCalls {
-				f := msg.ToolCalls[i].Function
-				sb.WriteString(fmt.Sprintf(" [%s] called tool: `%s` with arguments: %s.",
-					agentName, f.Name, f.Arguments))
-			}
-		}
-	} else if msg.Role == schema.Tool && msg.Content != "" {
-		sb.WriteString(fmt.Sprintf(" [%s] `%s` tool returned result: %s.",
-			agentName, msg.ToolName, msg.Content))
-	}
+const contextMessagePrefix = "For context:"
 
-	return schema.UserMessage(sb.String())
+// isContextMessage reports whether msg is a message of another agent rewritten by ContextMessageTemplates.
+func isContextMessage(msg Message) bool {
+	return msg.Role == schema.User && strings.HasPrefix(msg.Content, contextMessagePrefix)
 }
 
-func genMsg(entry *HistoryEntry, agentName string) (Message, error) {
+func genMsg(entry *HistoryEntry, agentName string, templates *ContextMessageTemplates) (Message, error) {
 	msg := entry.Message
 	if entry.AgentName != agentName {
-		msg = rewriteMessage(msg, entry.AgentName)
+		return templates.rewrite(msg, entry.AgentName)
 	}
 
 	return msg, nil
@@ -212,7 +208,8 @@ func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipT
 	input := runCtx.RootInput.deepCopy()
 
 	events := runCtx.Session.getEvents()
-	historyEntries := make([]*HistoryEntry, 0)
+	historyEntries := make([]*HistoryEntry, 0, len(runCtx.History))
+	historyEntries = append(historyEntries, runCtx.History...)
 
 	for _, m := range input.Messages {
 		historyEntries = append(historyEntries, &HistoryEntry{
@@ -254,7 +251,11 @@ func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipT
 		})
 	}
 
-	messages, err := a.historyRewriter(ctx, historyEntries)
+	rewriter := a.historyRewriter
+	if rewriter == nil {
+		rewriter = buildDefaultHistoryRewriter(a.Name(ctx), a.contextMessageTemplates)
+	}
+	messages, err := rewriter(ctx, historyEntries)
 	if err != nil {
 		return nil, err
 	}
@@ -263,14 +264,17 @@ func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipT
 	return input, nil
 }
 
-func buildDefaultHistoryRewriter(agentName string) HistoryRewriter {
+func buildDefaultHistoryRewriter(agentName string, templates *ContextMessageTemplates) HistoryRewriter {
+	if templates == nil {
+		templates = defaultContextMessageTemplates
+	}
 	return func(ctx context.Context, entries []*HistoryEntry) ([]Message, error) {
 		messages := make([]Message, 0, len(entries))
 		var err error
 		for _, entry := range entries {
 			msg := entry.Message
 			if !entry.IsUserInput {
-				msg, err = genMsg(entry, agentName)
+				msg, err = genMsg(entry, agentName, templates)
 				if err != nil {
 					return nil, fmt.Errorf("gen agent input failed: %w", err)
 				}
@@ -357,6 +361,13 @@ func (a *flowAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentR
 type DeterministicTransferConfig struct {
 	Agent        Agent
 	ToAgentNames []string
+
+	// SessionValuesSerializer makes the isolated session of a flow Agent, i.e. one with sub-agents,
+	// get a serialized copy of the parent session values instead of sharing them by reference,
+	// e.g. for the Agent to run in another process. The values of the isolated session are written back
+	// to the parent session, serialized too, when the Agent finishes or interrupts.
+	// Optional. If nil, the session values are shared by reference.
+	SessionValuesSerializer SessionValuesSerializer
 }
 
 func (a *flowAgent) run(
@@ -408,6 +419,11 @@ func (a *flowAgent) run(
 		if exactRunPathMatch(runCtx.RunPath, event.RunPath) {
 			lastAction = event.Action
 		}
+		// hiding only suppresses the messages of hidden tools, the actions and errors still reach the end-user
+		if event.hidden && event.Action == nil && event.Err == nil {
+			setAutomaticClose(event)
+			continue
+		}
 		generator.Send(event)
 	}
 
@@ -435,6 +451,8 @@ func (a *flowAgent) run(
 			return
 		}
 
+		appendTransferChain(ctx, a.Name(ctx), destName)
+
 		subAIter := agentToRun.Run(ctx, nil /*subagents get input from runCtx*/, opts...)
 		for {
 			subEvent, ok_ := subAIter.Next()
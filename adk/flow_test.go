@@ -143,3 +143,62 @@ func TestTransferToAgent(t *testing.T) {
 	_, ok = iterator.Next()
 	assert.False(t, ok)
 }
+
+func TestTransferContextMessageTemplates(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	parentModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	parentModel.EXPECT().WithTools(gomock.Any()).Return(parentModel, nil).AnyTimes()
+	parentModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("over to the child", []schema.ToolCall{{
+			ID:       "tool-call-1",
+			Function: schema.FunctionCall{Name: TransferToAgentToolName, Arguments: `{"agent_name": "ChildAgent"}`},
+		}}), nil).Times(1)
+
+	var childInput []*schema.Message
+	childModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	childModel.EXPECT().WithTools(gomock.Any()).Return(childModel, nil).AnyTimes()
+	childModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...interface{}) (*schema.Message, error) {
+			childInput = input
+			return schema.AssistantMessage("done", nil), nil
+		}).Times(1)
+
+	parentAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ParentAgent",
+		Description: "Parent agent that will transfer to child",
+		Instruction: "You are a parent agent.",
+		Model:       parentModel,
+	})
+	assert.NoError(t, err)
+	childAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ChildAgent",
+		Description: "Child agent that handles specific tasks",
+		Instruction: "You are a child agent.",
+		Model:       childModel,
+	})
+	assert.NoError(t, err)
+
+	templates, err := NewContextMessageTemplates("<{{.AgentName}}> {{.Content}}",
+		"<{{.AgentName}}> {{.ToolName}}({{.Arguments}})", "<{{.AgentName}}> {{.ToolName}} = {{.Result}}")
+	assert.NoError(t, err)
+	flowAgent, err := SetSubAgents(ctx, parentAgent, []Agent{
+		AgentWithOptions(ctx, childAgent, WithTransferContextMessageTemplates(templates)),
+	})
+	assert.NoError(t, err)
+
+	output, err := RunToCompletion(ctx, flowAgent, &AgentInput{Messages: []Message{schema.UserMessage("hi")}})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", output.MessageOutput.Message.Content)
+
+	var contents []string
+	for _, m := range childInput[1:] {
+		contents = append(contents, string(m.Role)+":"+m.Content)
+	}
+	assert.Equal(t, []string{
+		"user:hi",
+		`user:For context: <ParentAgent> over to the child <ParentAgent> transfer_to_agent({"agent_name": "ChildAgent"})`,
+		"user:For context: <ParentAgent> transfer_to_agent = successfully transferred to agent [ChildAgent]",
+	}, contents)
+}
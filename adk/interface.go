@@ -131,6 +131,22 @@ type AgentOutput struct {
 	MessageOutput *MessageVariant
 
 	CustomizedOutput any
+
+	// Artifacts are the binary outputs of the agent, e.g. generated images or files, for the consumer to render or download.
+	// They are kept in the events of the session, and persisted with them on checkpoints,
+	// but are not converted into the messages passed to the models of subsequent agents.
+	Artifacts []Artifact
+}
+
+// Artifact is a binary output of an agent.
+// Either Data holds the content inline, or Path references the content in a filesystem Backend.
+// Prefer Path for large content, as inline Data is persisted with every checkpoint.
+type Artifact struct {
+	Name     string
+	MIMEType string
+
+	Data []byte
+	Path string
 }
 
 // NewTransferToAgentAction creates an action to transfer to the specified agent.
@@ -225,6 +241,55 @@ type AgentEvent struct {
 	Action *AgentAction
 
 	Err error
+
+	// hidden events are recorded in the session but not emitted to the end-user, see ToolsConfig.HiddenTools.
+	hidden bool
+}
+
+// IsError reports whether the event carries an error.
+// An error raised while streaming a message is returned by the Recv of the message stream instead.
+func (e *AgentEvent) IsError() bool {
+	return e.Err != nil
+}
+
+// FinalMessage returns the message of the event if it's an assistant message without tool calls,
+// i.e. the final answer of the agent.
+// It reports false for a streaming message, which it doesn't read: it's up to the caller to consume the stream,
+// e.g. by MessageVariant.GetMessage, which also returns the error the stream may end with.
+// e.g.
+//
+//	for event, ok := iter.Next(); ok; event, ok = iter.Next() {
+//		if msg, ok := event.FinalMessage(); ok {
+//			fmt.Println(msg.Content)
+//		}
+//	}
+func (e *AgentEvent) FinalMessage() (Message, bool) {
+	if e.Output == nil || e.Output.MessageOutput == nil {
+		return nil, false
+	}
+	mv := e.Output.MessageOutput
+	if mv.IsStreaming || mv.Role != "" && mv.Role != schema.Assistant {
+		return nil, false
+	}
+
+	msg := mv.Message
+	if msg == nil || msg.Role != schema.Assistant || len(msg.ToolCalls) > 0 {
+		return nil, false
+	}
+	return msg, true
+}
+
+// IsTransfer returns the name of the agent the event transfers to, if it carries a transfer action.
+func (e *AgentEvent) IsTransfer() (string, bool) {
+	if e.Action == nil || e.Action.TransferToAgent == nil {
+		return "", false
+	}
+	return e.Action.TransferToAgent.DestAgentName, true
+}
+
+// IsExit reports whether the event carries an exit action.
+func (e *AgentEvent) IsExit() bool {
+	return e.Action != nil && e.Action.Exit
 }
 
 type AgentInput struct {
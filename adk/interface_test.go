@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestAgentEventHelpers(t *testing.T) {
+	t.Run("error event", func(t *testing.T) {
+		event := &AgentEvent{Err: errors.New("model failed")}
+		assert.True(t, event.IsError())
+		assert.False(t, event.IsExit())
+		_, ok := event.FinalMessage()
+		assert.False(t, ok)
+		_, ok = event.IsTransfer()
+		assert.False(t, ok)
+	})
+
+	t.Run("transfer event", func(t *testing.T) {
+		event := &AgentEvent{Action: NewTransferToAgentAction("weather")}
+		assert.False(t, event.IsError())
+		dest, ok := event.IsTransfer()
+		assert.True(t, ok)
+		assert.Equal(t, "weather", dest)
+
+		assert.True(t, (&AgentEvent{Action: NewExitAction()}).IsExit())
+	})
+
+	t.Run("message event", func(t *testing.T) {
+		msg, ok := EventFromMessage(schema.AssistantMessage("hello", nil), nil, schema.Assistant, "").FinalMessage()
+		assert.True(t, ok)
+		assert.Equal(t, "hello", msg.Content)
+
+		toolCall := schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "search"}}})
+		_, ok = EventFromMessage(toolCall, nil, schema.Assistant, "").FinalMessage()
+		assert.False(t, ok)
+		_, ok = EventFromMessage(schema.ToolMessage("result", "1"), nil, schema.Tool, "search").FinalMessage()
+		assert.False(t, ok)
+	})
+
+	t.Run("streaming message event", func(t *testing.T) {
+		stream := schema.StreamReaderFromArray([]Message{
+			schema.AssistantMessage("hel", nil),
+			schema.AssistantMessage("lo", nil),
+		})
+		event := EventFromMessage(nil, stream, schema.Assistant, "")
+
+		// the stream is left to the caller
+		_, ok := event.FinalMessage()
+		assert.False(t, ok)
+
+		var frames []string
+		for {
+			frame, err := event.Output.MessageOutput.MessageStream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			frames = append(frames, frame.Content)
+		}
+		assert.Equal(t, []string{"hel", "lo"}, frames)
+	})
+}
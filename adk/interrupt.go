@@ -195,10 +195,12 @@ type serialization struct {
 	EnableStreaming     bool
 	InterruptID2Address map[string]Address
 	InterruptID2State   map[string]core.InterruptState
+	// ConversationID is the id of the conversation of the run, see WithConversationID.
+	ConversationID string
 }
 
 func (r *Runner) loadCheckPoint(ctx context.Context, checkpointID string) (
-	context.Context, *runContext, *ResumeInfo, error) {
+	context.Context, *serialization, *ResumeInfo, error) {
 	data, existed, err := r.store.Get(ctx, checkpointID)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to get checkpoint from store: %w", err)
@@ -214,7 +216,7 @@ func (r *Runner) loadCheckPoint(ctx context.Context, checkpointID string) (
 	}
 	ctx = core.PopulateInterruptState(ctx, s.InterruptID2Address, s.InterruptID2State)
 
-	return ctx, s.RunCtx, &ResumeInfo{
+	return ctx, s, &ResumeInfo{
 		EnableStreaming: s.EnableStreaming,
 		InterruptInfo:   s.Info,
 	}, nil
@@ -225,6 +227,7 @@ func (r *Runner) saveCheckPoint(
 	key string,
 	info *InterruptInfo,
 	is *core.InterruptSignal,
+	conversationID string,
 ) error {
 	runCtx := getRunCtx(ctx)
 
@@ -237,6 +240,7 @@ func (r *Runner) saveCheckPoint(
 		InterruptID2Address: id2Addr,
 		InterruptID2State:   id2State,
 		EnableStreaming:     r.enableStreaming,
+		ConversationID:      conversationID,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to encode checkpoint: %w", err)
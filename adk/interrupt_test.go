@@ -24,10 +24,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -1786,3 +1788,193 @@ func consumeUntilInterrupt(iter *AsyncIterator[*AgentEvent]) (normalEvents []*Ag
 	}
 	return
 }
+
+type sessionValueTool struct{}
+
+func (s *sessionValueTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "greet", Desc: "desc"}, nil
+}
+
+func (s *sessionValueTool) InvokableRun(ctx context.Context, _ string, _ ...tool.Option) (string, error) {
+	if wasInterrupted, _, _ := tool.GetInterruptState[any](ctx); !wasInterrupted {
+		return "", tool.Interrupt(ctx, "confirm greeting")
+	}
+	user, _ := GetSessionValue(ctx, "user")
+	return fmt.Sprintf("hello %v", user), nil
+}
+
+func TestResumeAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	// the store outlives the processes, the agents and runners don't
+	store := newMyStore()
+	newProcessRunner := func(m *myModel) *Runner {
+		a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "name",
+			Description: "description",
+			Instruction: "instruction",
+			Model:       m,
+			ToolsConfig: ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{
+					Tools: []tool.BaseTool{&sessionValueTool{}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		return NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: store})
+	}
+
+	toolCall := schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "greet", Arguments: "{}"}}})
+	runner := newProcessRunner(&myModel{messages: []*schema.Message{toolCall}})
+	_, err := runner.RunToCompletion(ctx, []Message{schema.UserMessage("greet me")},
+		WithCheckPointID("conversation-1"), WithSessionValues(map[string]any{"user": "alice"}))
+	assert.ErrorIs(t, err, ErrRunInterrupted)
+
+	// the restarted process resumes the conversation from the store alone
+	var history []*schema.Message
+	runner = newProcessRunner(&myModel{
+		messages: []*schema.Message{schema.AssistantMessage("done", nil)},
+		validator: func(_ int, messages []*schema.Message) bool {
+			history = messages
+			return true
+		},
+	})
+	iter, err := runner.Resume(ctx, "conversation-1")
+	assert.NoError(t, err)
+	output, err := drainToCompletion(iter)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", output.MessageOutput.Message.Content)
+
+	var contents []string
+	for _, m := range history {
+		contents = append(contents, string(m.Role)+":"+m.Content)
+	}
+	assert.Equal(t, []string{"system:instruction", "user:greet me", "assistant:", "tool:hello alice"}, contents)
+}
+
+func TestConversationWithSubAgents(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	store := newMyStore()
+
+	var parentInput []*schema.Message
+	parentModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	parentModel.EXPECT().WithTools(gomock.Any()).Return(parentModel, nil).AnyTimes()
+	gomock.InOrder(
+		parentModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("over to the child", []schema.ToolCall{{
+				ID:       "tool-call-1",
+				Function: schema.FunctionCall{Name: TransferToAgentToolName, Arguments: `{"agent_name": "ChildAgent"}`},
+			}}), nil),
+		parentModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+				parentInput = input
+				return schema.AssistantMessage("bye", nil), nil
+			}),
+	)
+	childModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	childModel.EXPECT().WithTools(gomock.Any()).Return(childModel, nil).AnyTimes()
+	childModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("done", nil), nil).Times(1)
+
+	parentAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ParentAgent",
+		Description: "Parent agent that will transfer to child",
+		Instruction: "You are a parent agent.",
+		Model:       parentModel,
+	})
+	assert.NoError(t, err)
+	childAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ChildAgent",
+		Description: "Child agent that handles specific tasks",
+		Instruction: "You are a child agent.",
+		Model:       childModel,
+	})
+	assert.NoError(t, err)
+	agent, err := SetSubAgents(ctx, parentAgent, []Agent{childAgent})
+	assert.NoError(t, err)
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent, CheckPointStore: store})
+	output, err := runner.RunToCompletion(ctx, []Message{schema.UserMessage("hi")}, WithConversationID("conversation-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "done", output.MessageOutput.Message.Content)
+
+	runner = NewRunner(ctx, RunnerConfig{Agent: agent, CheckPointStore: store})
+	output, err = runner.RunToCompletion(ctx, []Message{schema.UserMessage("thanks")}, WithConversationID("conversation-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bye", output.MessageOutput.Message.Content)
+
+	// the messages of the child are context for the parent, not its own
+	var contents []string
+	for _, m := range parentInput[1:] {
+		contents = append(contents, string(m.Role)+":"+m.Content)
+	}
+	assert.Equal(t, []string{
+		"user:hi",
+		"assistant:over to the child",
+		"tool:successfully transferred to agent [ChildAgent]",
+		"user:For context: [ChildAgent] said: done.",
+		"user:thanks",
+	}, contents)
+}
+
+func TestConversationAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	// the store outlives the processes, the agents and runners don't
+	store := newMyStore()
+	var history []string
+	newProcessRunner := func(messages ...*schema.Message) *Runner {
+		a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "name",
+			Description: "description",
+			Instruction: "instruction",
+			Model: &myModel{
+				messages: messages,
+				validator: func(_ int, messages []*schema.Message) bool {
+					history = nil
+					for _, m := range messages {
+						history = append(history, string(m.Role)+":"+m.Content)
+					}
+					return true
+				},
+			},
+			ToolsConfig: ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{
+					Tools: []tool.BaseTool{&sessionValueTool{}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		return NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: store})
+	}
+
+	runner := newProcessRunner(schema.AssistantMessage("hi", nil))
+	output, err := runner.RunToCompletion(ctx, []Message{schema.UserMessage("hello")},
+		WithConversationID("conversation-1"), WithSessionValues(map[string]any{"user": "alice"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", output.MessageOutput.Message.Content)
+
+	// the restarted process continues the conversation, up to an interrupt
+	toolCall := schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "greet", Arguments: "{}"}}})
+	runner = newProcessRunner(toolCall)
+	_, err = runner.RunToCompletion(ctx, []Message{schema.UserMessage("greet me")}, WithConversationID("conversation-1"))
+	assert.ErrorIs(t, err, ErrRunInterrupted)
+	assert.Equal(t, []string{"system:instruction", "user:hello", "assistant:hi", "user:greet me"}, history)
+
+	// the interrupted conversation must be resumed before going on
+	runner = newProcessRunner(schema.AssistantMessage("done", nil))
+	_, err = runner.RunToCompletion(ctx, []Message{schema.UserMessage("are you there")}, WithConversationID("conversation-1"))
+	assert.ErrorContains(t, err, "conversation[conversation-1] is interrupted")
+	iter, err := runner.Resume(ctx, "conversation-1")
+	assert.NoError(t, err)
+	output, err = drainToCompletion(iter)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", output.MessageOutput.Message.Content)
+	assert.Equal(t, []string{"system:instruction", "user:hello", "assistant:hi", "user:greet me", "assistant:", "tool:hello alice"}, history)
+
+	// the resumed run saved the conversation again
+	runner = newProcessRunner(schema.AssistantMessage("bye", nil))
+	_, err = runner.RunToCompletion(ctx, []Message{schema.UserMessage("bye")}, WithConversationID("conversation-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system:instruction", "user:hello", "assistant:hi", "user:greet me", "assistant:",
+		"tool:hello alice", "assistant:done", "user:bye"}, history)
+}
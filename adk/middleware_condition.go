@@ -0,0 +1,204 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/internal/core"
+)
+
+type middlewareSelectionKey struct{}
+
+type middlewareResumeKey struct{}
+
+// withMiddlewareResume marks ctx as resuming an interrupted run of a ChatModelAgent.
+func withMiddlewareResume(ctx context.Context) context.Context {
+	return context.WithValue(ctx, middlewareResumeKey{}, true)
+}
+
+// middlewareSelection is the outcome of the ShouldApply of the middlewares of a ChatModelAgent for a run,
+// when some of them don't apply.
+type middlewareSelection struct {
+	// inactive are the indexes of the middlewares not applying to the run.
+	inactive map[int]bool
+	// instruction is the instruction of the agent without the instructions of the inactive middlewares.
+	instruction string
+	// options remove the tools of the inactive middlewares from the run.
+	options []compose.Option
+}
+
+// middlewareActive reports whether the i-th middleware applies to the run of ctx.
+func middlewareActive(ctx context.Context, i int) bool {
+	sel, _ := ctx.Value(middlewareSelectionKey{}).(*middlewareSelection)
+	return sel == nil || !sel.inactive[i]
+}
+
+// guardMiddleware makes the hooks of the i-th middleware m no-ops for the runs it doesn't apply to.
+func guardMiddleware(i int, m AgentMiddleware) AgentMiddleware {
+	if bc := m.BeforeChatModel; bc != nil {
+		m.BeforeChatModel = func(ctx context.Context, state *ChatModelAgentState) error {
+			if !middlewareActive(ctx, i) {
+				return nil
+			}
+			return bc(ctx, state)
+		}
+	}
+	if ac := m.AfterChatModel; ac != nil {
+		m.AfterChatModel = func(ctx context.Context, state *ChatModelAgentState) error {
+			if !middlewareActive(ctx, i) {
+				return nil
+			}
+			return ac(ctx, state)
+		}
+	}
+	if mt := m.MessageTransform; mt != nil {
+		m.MessageTransform = func(ctx context.Context, msg Message, idx, total int) (Message, error) {
+			if !middlewareActive(ctx, i) {
+				return msg, nil
+			}
+			return mt(ctx, msg, idx, total)
+		}
+	}
+	if inv := m.WrapToolCall.Invokable; inv != nil {
+		m.WrapToolCall.Invokable = func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			wrapped := inv(next)
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				if !middlewareActive(ctx, i) {
+					return next(ctx, input)
+				}
+				return wrapped(ctx, input)
+			}
+		}
+	}
+	if str := m.WrapToolCall.Streamable; str != nil {
+		m.WrapToolCall.Streamable = func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			wrapped := str(next)
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				if !middlewareActive(ctx, i) {
+					return next(ctx, input)
+				}
+				return wrapped(ctx, input)
+			}
+		}
+	}
+	return m
+}
+
+// inactiveMiddlewares evaluates the ShouldApply of the middlewares of the agent for a run with input,
+// and returns the indexes of the ones not applying to it.
+// The indexes are stored in the state of the run by the address of the agent, and a resumed run reuses them
+// instead of evaluating ShouldApply again, as the tools of the interrupted run must stay available to it.
+func (a *ChatModelAgent) inactiveMiddlewares(ctx context.Context, input *AgentInput) map[int]bool {
+	if len(a.middlewares) == 0 {
+		return nil
+	}
+
+	var state *runState
+	if session := getSession(ctx); session != nil {
+		state = session.State
+	}
+	addr := core.GetCurrentAddress(ctx).String()
+	if resume, _ := ctx.Value(middlewareResumeKey{}).(bool); resume && state != nil {
+		if indexes, ok := state.getInactiveMiddlewares(addr); ok {
+			inactive := make(map[int]bool, len(indexes))
+			for _, i := range indexes {
+				inactive[i] = true
+			}
+			return inactive
+		}
+	}
+
+	var inactive map[int]bool
+	indexes := []int{}
+	for i, m := range a.middlewares {
+		if m.ShouldApply != nil && !m.ShouldApply(ctx, input) {
+			if inactive == nil {
+				inactive = make(map[int]bool)
+			}
+			inactive[i] = true
+			indexes = append(indexes, i)
+		}
+	}
+	if state != nil {
+		state.setInactiveMiddlewares(addr, indexes)
+	}
+	return inactive
+}
+
+// selectMiddlewares selects the middlewares of the agent applying to a run with input, see inactiveMiddlewares.
+// If some of them don't apply, it returns ctx marking them inactive for the hooks, along with the selection,
+// in which the tools of the inactive middlewares are removed from tools, i.e. all the tools of the agent.
+func (a *ChatModelAgent) selectMiddlewares(ctx context.Context, input *AgentInput, tools []tool.BaseTool) (
+	context.Context, *middlewareSelection, error) {
+
+	inactive := a.inactiveMiddlewares(ctx, input)
+	if resume, _ := ctx.Value(middlewareResumeKey{}).(bool); resume {
+		// the agents run by the tools of the resumed run are not resumed by it
+		ctx = context.WithValue(ctx, middlewareResumeKey{}, false)
+	}
+	if len(inactive) == 0 {
+		return ctx, nil, nil
+	}
+
+	// the AdditionalTools of the middlewares follow the tools of ToolsConfig in tools, in order
+	removed := make(map[int]bool)
+	var active []AgentMiddleware
+	idx := a.configuredToolCount
+	for i, m := range a.middlewares {
+		if !inactive[i] {
+			active = append(active, m)
+			idx += len(m.AdditionalTools)
+			continue
+		}
+		for range m.AdditionalTools {
+			removed[idx] = true
+			idx++
+		}
+	}
+
+	sel := &middlewareSelection{inactive: inactive}
+	if len(removed) > 0 {
+		activeTools := make([]tool.BaseTool, 0, len(tools)-len(removed))
+		for i, t := range tools {
+			if !removed[i] {
+				activeTools = append(activeTools, t)
+			}
+		}
+		infos, err := ToolInfos(ctx, activeTools)
+		if err != nil {
+			return nil, nil, err
+		}
+		sortToolInfos(infos, a.toolsConfig.ToolPriority)
+		sel.options = []compose.Option{
+			compose.WithChatModelOption(model.WithTools(infos)),
+			compose.WithToolsNodeOption(compose.WithToolList(activeTools...)),
+		}
+		tools = activeTools
+	}
+
+	var err error
+	sel.instruction, err = buildInstruction(ctx, a.baseInstruction, active, tools)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return context.WithValue(ctx, middlewareSelectionKey{}, sel), sel, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// MiddlewarePhase is a phase of the agent middlewares traced by MiddlewareTracer.
+type MiddlewarePhase string
+
+const (
+	// MiddlewarePhaseBeforeChatModel is the run of an AgentMiddleware.BeforeChatModel.
+	MiddlewarePhaseBeforeChatModel MiddlewarePhase = "before_chat_model"
+	// MiddlewarePhaseMessageTransform is a pass of the AgentMiddleware.MessageTransform of consecutive middlewares.
+	MiddlewarePhaseMessageTransform MiddlewarePhase = "message_transform"
+	// MiddlewarePhaseAfterChatModel is the run of an AgentMiddleware.AfterChatModel.
+	MiddlewarePhaseAfterChatModel MiddlewarePhase = "after_chat_model"
+	// MiddlewarePhaseToolCall is a tool call through an AgentMiddleware.WrapToolCall, including the inner layers.
+	// For streaming tools, the span ends when the stream is returned, not when it is consumed.
+	MiddlewarePhaseToolCall MiddlewarePhase = "tool_call"
+)
+
+const (
+	// MiddlewareSpanAttrMiddleware is the span attribute holding the AgentMiddleware.Name,
+	// or the comma separated names for MiddlewarePhaseMessageTransform.
+	MiddlewareSpanAttrMiddleware = "middleware"
+	// MiddlewareSpanAttrTool is the span attribute holding the tool name for MiddlewarePhaseToolCall.
+	MiddlewareSpanAttrTool = "tool"
+	// MiddlewareSpanAttrToolCallID is the span attribute holding the tool call id for MiddlewarePhaseToolCall.
+	MiddlewareSpanAttrToolCallID = "tool_call_id"
+)
+
+// MiddlewareTracer creates spans around the phases of agent middlewares, e.g. to find out slow middlewares.
+// Implementations usually adapt a tracing library such as OpenTelemetry.
+type MiddlewareTracer interface {
+	// StartSpan starts a span for phase with attrs, and returns the context for the phase
+	// and the function ending the span with the phase's error, if any.
+	StartSpan(ctx context.Context, phase MiddlewarePhase, attrs map[string]string) (context.Context, func(err error))
+}
+
+func middlewareName(m AgentMiddleware, idx int) string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return fmt.Sprintf("middleware_%d", idx)
+}
+
+func traceStateHandler(tracer MiddlewareTracer, phase MiddlewarePhase, name string,
+	h func(context.Context, *ChatModelAgentState) error) func(context.Context, *ChatModelAgentState) error {
+
+	if tracer == nil {
+		return h
+	}
+	return func(ctx context.Context, state *ChatModelAgentState) error {
+		ctx, end := tracer.StartSpan(ctx, phase, map[string]string{MiddlewareSpanAttrMiddleware: name})
+		err := h(ctx, state)
+		end(err)
+		return err
+	}
+}
+
+func traceToolMiddleware(tracer MiddlewareTracer, name string, m compose.ToolMiddleware) compose.ToolMiddleware {
+	if tracer == nil {
+		return m
+	}
+	attrs := func(input *compose.ToolInput) map[string]string {
+		return map[string]string{
+			MiddlewareSpanAttrMiddleware: name,
+			MiddlewareSpanAttrTool:       input.Name,
+			MiddlewareSpanAttrToolCallID: input.CallID,
+		}
+	}
+
+	ret := compose.ToolMiddleware{}
+	if m.Invokable != nil {
+		ret.Invokable = func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			wrapped := m.Invokable(next)
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				ctx, end := tracer.StartSpan(ctx, MiddlewarePhaseToolCall, attrs(input))
+				output, err := wrapped(ctx, input)
+				end(err)
+				return output, err
+			}
+		}
+	}
+	if m.Streamable != nil {
+		ret.Streamable = func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			wrapped := m.Streamable(next)
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				ctx, end := tracer.StartSpan(ctx, MiddlewarePhaseToolCall, attrs(input))
+				output, err := wrapped(ctx, input)
+				end(err)
+				return output, err
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeMiddlewareTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+func (f *fakeMiddlewareTracer) record(s string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.spans = append(f.spans, s)
+}
+
+func (f *fakeMiddlewareTracer) StartSpan(ctx context.Context, phase MiddlewarePhase, attrs map[string]string) (context.Context, func(err error)) {
+	name := string(phase) + " " + attrs[MiddlewareSpanAttrMiddleware]
+	if t := attrs[MiddlewareSpanAttrTool]; t != "" {
+		name += " " + t + "/" + attrs[MiddlewareSpanAttrToolCallID]
+	}
+	f.record("start " + name)
+	return ctx, func(err error) {
+		f.record("end " + name)
+	}
+}
+
+func TestMiddlewareTracer(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	gomock.InOrder(
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("", []schema.ToolCall{{
+				ID:       "call-1",
+				Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"x"}`},
+			}}), nil),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("done", nil), nil),
+	)
+
+	tracer := &fakeMiddlewareTracer{}
+	agent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent for middleware tracing",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		ToolsConfig: ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}},
+		},
+		Middlewares: []AgentMiddleware{
+			{
+				Name: "skill",
+				BeforeChatModel: func(ctx context.Context, state *ChatModelAgentState) error {
+					tracer.record("run skill")
+					return nil
+				},
+			},
+			{
+				// unnamed
+				WrapToolCall: compose.ToolMiddleware{
+					Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+						return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+							tracer.record("run middleware_1")
+							return next(ctx, input)
+						}
+					},
+				},
+			},
+		},
+		MiddlewareTracer: tracer,
+	})
+	assert.NoError(t, err)
+
+	iter := NewRunner(ctx, RunnerConfig{Agent: agent}).Query(ctx, "hello")
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, []string{
+		"start before_chat_model skill",
+		"run skill",
+		"end before_chat_model skill",
+		"start tool_call middleware_1 test_tool/call-1",
+		"run middleware_1",
+		"end tool_call middleware_1 test_tool/call-1",
+		"start before_chat_model skill",
+		"run skill",
+		"end before_chat_model skill",
+	}, tracer.spans)
+}
@@ -27,10 +27,13 @@ type FileInfo = filesystem.FileInfo
 type GrepMatch = filesystem.GrepMatch
 type LsInfoRequest = filesystem.LsInfoRequest
 type ReadRequest = filesystem.ReadRequest
+type ReadAllRequest = filesystem.ReadAllRequest
 type GrepRequest = filesystem.GrepRequest
 type GlobInfoRequest = filesystem.GlobInfoRequest
 type WriteRequest = filesystem.WriteRequest
 type EditRequest = filesystem.EditRequest
+type AppendRequest = filesystem.AppendRequest
+type CopyRequest = filesystem.CopyRequest
 
 // Backend is a pluggable, unified file backend protocol interface.
 //
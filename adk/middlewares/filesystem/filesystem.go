@@ -21,9 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/adk/filesystem"
@@ -58,9 +61,15 @@ type Config struct {
 	// CustomLsToolDesc overrides the ls tool description used in tool registration
 	// optional, ListFilesToolDesc by default
 	CustomLsToolDesc *string
+	// CustomTreeToolDesc overrides the tree tool description
+	// optional, TreeToolDesc by default
+	CustomTreeToolDesc *string
 	// CustomReadFileToolDesc overrides the read_file tool description
 	// optional, ReadFileToolDesc by default
 	CustomReadFileToolDesc *string
+	// CustomReadWholeFileToolDesc overrides the read_whole_file tool description
+	// optional, ReadWholeFileToolDesc by default
+	CustomReadWholeFileToolDesc *string
 	// CustomGrepToolDesc overrides the grep tool description
 	// optional, GrepToolDesc by default
 	CustomGrepToolDesc *string
@@ -73,9 +82,54 @@ type Config struct {
 	// CustomEditToolDesc overrides the edit_file tool description
 	// optional, EditFileToolDesc by default
 	CustomEditToolDesc *string
+	// CustomCopyFileToolDesc overrides the copy_file tool description
+	// optional, CopyFileToolDesc by default
+	CustomCopyFileToolDesc *string
+	// CustomApplyPatchToolDesc overrides the apply_patch tool description
+	// optional, ApplyPatchToolDesc by default
+	CustomApplyPatchToolDesc *string
 	// CustomExecuteToolDesc overrides the execute tool description
 	// optional, ExecuteToolDesc by default
 	CustomExecuteToolDesc *string
+	// ExecuteTimeout limits how long a single command of the non-streaming execute tool may run.
+	// When exceeded, the context passed to ShellBackend.Execute is canceled and the tool returns a timeout result.
+	// optional, no timeout by default
+	ExecuteTimeout time.Duration
+	// ExecuteStreamCoalesceInterval makes the streaming execute tool buffer the output of a command,
+	// and emit it at most once per interval instead of chunk by chunk, to spare consumers from chatty commands.
+	// optional, chunks are emitted as received by default
+	ExecuteStreamCoalesceInterval time.Duration
+	// ExecuteStreamCoalesceSize makes the streaming execute tool emit the buffered output as soon as it reaches this many bytes.
+	// Used with ExecuteStreamCoalesceInterval, output is emitted on whichever comes first.
+	// optional, chunks are emitted as received by default
+	ExecuteStreamCoalesceSize int
+
+	// MaxListResults caps the number of entries returned by the ls, tree and glob tools.
+	// Entries beyond the cap are dropped and replaced by a "(N more results omitted)" note.
+	// optional, no limit by default
+	MaxListResults int
+	// MaxGrepMatches caps the number of lines returned by the grep tool in content and files_with_matches mode.
+	// Lines beyond the cap are dropped and replaced by a "(N more results omitted)" note.
+	// optional, no limit by default
+	MaxGrepMatches int
+	// MaxFileBytes caps the size of the files the read_whole_file tool returns.
+	// Larger files are refused, and the model is told to page through them with read_file instead.
+	// read_whole_file is only registered if the Backend implements ReadAllBackend.
+	// optional, 65536 by default
+	MaxFileBytes int
+
+	// NotesFilePath enables the add_note and read_notes tools, giving the agent a running notes file at this path:
+	// add_note appends a timestamped note to it, and read_notes returns all the notes in order.
+	// The Backend must implement AppendableBackend, and its Read, or ReadAll, must wrap filesystem.ErrFileNotFound
+	// when the notes file doesn't exist yet. The path is cleaned by Validate.
+	// optional, the notes tools are disabled by default
+	NotesFilePath string
+	// CustomAddNoteToolDesc overrides the add_note tool description
+	// optional, AddNoteToolDesc by default
+	CustomAddNoteToolDesc *string
+	// CustomReadNotesToolDesc overrides the read_notes tool description
+	// optional, ReadNotesToolDesc by default
+	CustomReadNotesToolDesc *string
 }
 
 func (c *Config) Validate() error {
@@ -85,6 +139,16 @@ func (c *Config) Validate() error {
 	if c.Backend == nil {
 		return errors.New("backend should not be nil")
 	}
+	if c.NotesFilePath != "" {
+		if !strings.HasPrefix(c.NotesFilePath, "/") {
+			return fmt.Errorf("notes file path should be absolute: %s", c.NotesFilePath)
+		}
+		// the notes are looked up by the path, however it's spelled
+		c.NotesFilePath = path.Clean(c.NotesFilePath)
+		if _, ok := c.Backend.(filesystem.AppendableBackend); !ok {
+			return errors.New("backend should implement AppendableBackend to enable the notes tools")
+		}
+	}
 	return nil
 }
 
@@ -94,28 +158,28 @@ func NewMiddleware(ctx context.Context, config *Config) (adk.AgentMiddleware, er
 	if err != nil {
 		return adk.AgentMiddleware{}, err
 	}
+	if p, ok := config.Backend.(filesystem.Pinger); ok {
+		if err = p.Ping(ctx); err != nil {
+			return adk.AgentMiddleware{}, fmt.Errorf("failed to ping backend: %w", err)
+		}
+	}
 	ts, err := getFilesystemTools(ctx, config)
 	if err != nil {
 		return adk.AgentMiddleware{}, err
 	}
 
-	var systemPrompt string
+	m := adk.AgentMiddleware{
+		AdditionalTools: ts,
+	}
 	if config.CustomSystemPrompt != nil {
-		systemPrompt = *config.CustomSystemPrompt
+		m.AdditionalInstruction = *config.CustomSystemPrompt
 	} else {
-		systemPrompt = ToolsSystemPrompt
-		_, ok1 := config.Backend.(filesystem.StreamingShellBackend)
-		_, ok2 := config.Backend.(filesystem.ShellBackend)
-		if ok1 || ok2 {
-			systemPrompt += ExecuteToolsSystemPrompt
+		m.AdditionalInstruction, err = toolsSystemPrompt(ctx, ts)
+		if err != nil {
+			return adk.AgentMiddleware{}, err
 		}
 	}
 
-	m := adk.AgentMiddleware{
-		AdditionalInstruction: systemPrompt,
-		AdditionalTools:       ts,
-	}
-
 	if !config.WithoutLargeToolResultOffloading {
 		m.WrapToolCall = newToolResultOffloading(ctx, &toolResultOffloadingConfig{
 			Backend:       config.Backend,
@@ -127,21 +191,65 @@ func NewMiddleware(ctx context.Context, config *Config) (adk.AgentMiddleware, er
 	return m, nil
 }
 
+// toolsSystemPrompt returns ToolsSystemPrompt followed by the prompts of the optional tools among ts, in a fixed order.
+// They're part of AdditionalInstruction, as the tools depend on the backend only.
+func toolsSystemPrompt(ctx context.Context, ts []tool.BaseTool) (string, error) {
+	names := make(map[string]bool, len(ts))
+	for _, t := range ts {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get tool info: %w", err)
+		}
+		names[info.Name] = true
+	}
+
+	prompt := ToolsSystemPrompt
+	for _, p := range []struct {
+		toolName string
+		prompt   string
+	}{
+		{"read_whole_file", ReadWholeFileToolsSystemPrompt},
+		{"copy_file", CopyFileToolsSystemPrompt},
+		{"execute", ExecuteToolsSystemPrompt},
+		{"add_note", NotesToolsSystemPrompt},
+	} {
+		if names[p.toolName] {
+			prompt += p.prompt
+		}
+	}
+	return prompt, nil
+}
+
 func getFilesystemTools(_ context.Context, validatedConfig *Config) ([]tool.BaseTool, error) {
 	var tools []tool.BaseTool
 
-	lsTool, err := newLsTool(validatedConfig.Backend, validatedConfig.CustomLsToolDesc)
+	lsTool, err := newLsTool(validatedConfig.Backend, validatedConfig.CustomLsToolDesc, validatedConfig.MaxListResults)
 	if err != nil {
 		return nil, err
 	}
 	tools = append(tools, lsTool)
 
+	treeTool, err := newTreeTool(validatedConfig.Backend, validatedConfig.CustomTreeToolDesc, validatedConfig.MaxListResults)
+	if err != nil {
+		return nil, err
+	}
+	tools = append(tools, treeTool)
+
 	readTool, err := newReadFileTool(validatedConfig.Backend, validatedConfig.CustomReadFileToolDesc)
 	if err != nil {
 		return nil, err
 	}
 	tools = append(tools, readTool)
 
+	if rb, ok := validatedConfig.Backend.(filesystem.ReadAllBackend); ok {
+		var readWholeTool tool.BaseTool
+		readWholeTool, err = newReadWholeFileTool(rb, validatedConfig.CustomReadWholeFileToolDesc, validatedConfig.MaxFileBytes)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, readWholeTool)
+	}
+
 	writeTool, err := newWriteFileTool(validatedConfig.Backend, validatedConfig.CustomWriteFileToolDesc)
 	if err != nil {
 		return nil, err
@@ -154,13 +262,29 @@ func getFilesystemTools(_ context.Context, validatedConfig *Config) ([]tool.Base
 	}
 	tools = append(tools, editTool)
 
-	globTool, err := newGlobTool(validatedConfig.Backend, validatedConfig.CustomGlobToolDesc)
+	_, canCopy := validatedConfig.Backend.(filesystem.CopyableBackend)
+	if _, canReadAll := validatedConfig.Backend.(filesystem.ReadAllBackend); canCopy || canReadAll {
+		var copyTool tool.BaseTool
+		copyTool, err = newCopyFileTool(validatedConfig.Backend, validatedConfig.CustomCopyFileToolDesc)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, copyTool)
+	}
+
+	applyPatchTool, err := newApplyPatchTool(validatedConfig.Backend, validatedConfig.CustomApplyPatchToolDesc)
+	if err != nil {
+		return nil, err
+	}
+	tools = append(tools, applyPatchTool)
+
+	globTool, err := newGlobTool(validatedConfig.Backend, validatedConfig.CustomGlobToolDesc, validatedConfig.MaxListResults)
 	if err != nil {
 		return nil, err
 	}
 	tools = append(tools, globTool)
 
-	grepTool, err := newGrepTool(validatedConfig.Backend, validatedConfig.CustomGrepToolDesc)
+	grepTool, err := newGrepTool(validatedConfig.Backend, validatedConfig.CustomGrepToolDesc, validatedConfig.MaxGrepMatches)
 	if err != nil {
 		return nil, err
 	}
@@ -168,20 +292,35 @@ func getFilesystemTools(_ context.Context, validatedConfig *Config) ([]tool.Base
 
 	if sb, ok := validatedConfig.Backend.(filesystem.StreamingShellBackend); ok {
 		var executeTool tool.BaseTool
-		executeTool, err = newStreamingExecuteTool(sb, validatedConfig.CustomExecuteToolDesc)
+		executeTool, err = newStreamingExecuteTool(sb, validatedConfig.CustomExecuteToolDesc,
+			validatedConfig.ExecuteStreamCoalesceInterval, validatedConfig.ExecuteStreamCoalesceSize)
 		if err != nil {
 			return nil, err
 		}
 		tools = append(tools, executeTool)
 	} else if sb, ok := validatedConfig.Backend.(filesystem.ShellBackend); ok {
 		var executeTool tool.BaseTool
-		executeTool, err = newExecuteTool(sb, validatedConfig.CustomExecuteToolDesc)
+		executeTool, err = newExecuteTool(sb, validatedConfig.CustomExecuteToolDesc, validatedConfig.ExecuteTimeout)
 		if err != nil {
 			return nil, err
 		}
 		tools = append(tools, executeTool)
 	}
 
+	if validatedConfig.NotesFilePath != "" {
+		var addNoteTool, readNotesTool tool.BaseTool
+		addNoteTool, err = newAddNoteTool(validatedConfig.Backend.(filesystem.AppendableBackend),
+			validatedConfig.NotesFilePath, validatedConfig.CustomAddNoteToolDesc)
+		if err != nil {
+			return nil, err
+		}
+		readNotesTool, err = newReadNotesTool(validatedConfig.Backend, validatedConfig.NotesFilePath, validatedConfig.CustomReadNotesToolDesc)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, addNoteTool, readNotesTool)
+	}
+
 	return tools, nil
 }
 
@@ -189,7 +328,7 @@ type lsArgs struct {
 	Path string `json:"path"`
 }
 
-func newLsTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
+func newLsTool(fs filesystem.Backend, desc *string, maxResults int) (tool.BaseTool, error) {
 	d := ListFilesToolDesc
 	if desc != nil {
 		d = *desc
@@ -199,14 +338,93 @@ func newLsTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
 		if err != nil {
 			return "", err
 		}
-		paths := make([]string, 0, len(infos))
+		infos, omitted := limitResults(infos, maxResults)
+		paths := make([]string, 0, len(infos)+1)
 		for _, fi := range infos {
-			paths = append(paths, fi.Path)
+			if fi.IsDir {
+				paths = append(paths, fi.Path+"/")
+			} else {
+				paths = append(paths, fi.Path)
+			}
+		}
+		if omitted > 0 {
+			paths = append(paths, omittedNote(omitted))
 		}
 		return strings.Join(paths, "\n"), nil
 	})
 }
 
+type treeArgs struct {
+	Path  string `json:"path"`
+	Depth int    `json:"depth,omitempty"`
+}
+
+const defaultTreeDepth = 3
+
+func newTreeTool(fs filesystem.Backend, desc *string, maxResults int) (tool.BaseTool, error) {
+	d := TreeToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	return utils.InferTool("tree", d, func(ctx context.Context, input treeArgs) (string, error) {
+		depth := input.Depth
+		if depth <= 0 {
+			depth = defaultTreeDepth
+		}
+		root := input.Path
+		if root == "" {
+			root = "/"
+		}
+
+		var lines []string
+		if err := renderTree(ctx, fs, root, 1, depth, &lines); err != nil {
+			return "", err
+		}
+		lines, omitted := limitResults(lines, maxResults)
+		if omitted > 0 {
+			lines = append(lines, omittedNote(omitted))
+		}
+		return strings.TrimSuffix(root, "/") + "/\n" + strings.Join(lines, "\n"), nil
+	})
+}
+
+// renderTree appends the entries of dir to lines, indented by their level below the root of the tree,
+// expanding the subdirectories up to depth levels. The deeper subdirectories only show their number of entries.
+func renderTree(ctx context.Context, fs filesystem.Backend, dir string, level, depth int, lines *[]string) error {
+	infos, err := fs.LsInfo(ctx, &filesystem.LsInfoRequest{Path: dir})
+	if err != nil {
+		return err
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+
+	indent := strings.Repeat("  ", level)
+	for _, fi := range infos {
+		name := path.Base(fi.Path)
+		switch {
+		case !fi.IsDir:
+			*lines = append(*lines, indent+name)
+		case level < depth:
+			*lines = append(*lines, indent+name+"/")
+			if err = renderTree(ctx, fs, fi.Path, level+1, depth, lines); err != nil {
+				return err
+			}
+		default:
+			children, err := fs.LsInfo(ctx, &filesystem.LsInfoRequest{Path: fi.Path})
+			if err != nil {
+				return err
+			}
+			entries := "entries"
+			if len(children) == 1 {
+				entries = "entry"
+			}
+			*lines = append(*lines, fmt.Sprintf("%s%s/ (%d %s)", indent, name, len(children), entries))
+		}
+	}
+	return nil
+}
+
 type readFileArgs struct {
 	FilePath string `json:"file_path"`
 	Offset   int    `json:"offset"`
@@ -233,6 +451,32 @@ func newReadFileTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error)
 	})
 }
 
+const defaultMaxFileBytes = 64 * 1024
+
+type readWholeFileArgs struct {
+	FilePath string `json:"file_path"`
+}
+
+func newReadWholeFileTool(fs filesystem.ReadAllBackend, desc *string, maxBytes int) (tool.BaseTool, error) {
+	d := ReadWholeFileToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFileBytes
+	}
+	return utils.InferTool("read_whole_file", d, func(ctx context.Context, input readWholeFileArgs) (string, error) {
+		content, err := fs.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: input.FilePath})
+		if err != nil {
+			return "", err
+		}
+		if len(content) > maxBytes {
+			return fmt.Sprintf(fileTooLargeMessage, input.FilePath, len(content), maxBytes), nil
+		}
+		return content, nil
+	})
+}
+
 type writeFileArgs struct {
 	FilePath string `json:"file_path"`
 	Content  string `json:"content"`
@@ -244,9 +488,15 @@ func newWriteFileTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error
 		d = *desc
 	}
 	return utils.InferTool("write_file", d, func(ctx context.Context, input writeFileArgs) (string, error) {
-		err := fs.Write(ctx, &filesystem.WriteRequest{
-			FilePath: input.FilePath,
-			Content:  input.Content,
+		unlock, err := lockFiles(ctx, fs, input.FilePath)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+		err = fs.Write(ctx, &filesystem.WriteRequest{
+			FilePath:       input.FilePath,
+			Content:        input.Content,
+			IdempotencyKey: compose.GetToolCallID(ctx),
 		})
 		if err != nil {
 			return "", err
@@ -268,7 +518,12 @@ func newEditFileTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error)
 		d = *desc
 	}
 	return utils.InferTool("edit_file", d, func(ctx context.Context, input editFileArgs) (string, error) {
-		err := fs.Edit(ctx, &filesystem.EditRequest{
+		unlock, err := lockFiles(ctx, fs, input.FilePath)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+		err = fs.Edit(ctx, &filesystem.EditRequest{
 			FilePath:   input.FilePath,
 			OldString:  input.OldString,
 			NewString:  input.NewString,
@@ -281,12 +536,126 @@ func newEditFileTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error)
 	})
 }
 
+type copyFileArgs struct {
+	SrcPath   string `json:"src_path"`
+	DstPath   string `json:"dst_path"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+func newCopyFileTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
+	d := CopyFileToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	return utils.InferTool("copy_file", d, func(ctx context.Context, input copyFileArgs) (string, error) {
+		err := copyFile(ctx, fs, &filesystem.CopyRequest{
+			SrcPath:   input.SrcPath,
+			DstPath:   input.DstPath,
+			Overwrite: input.Overwrite,
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Copied file %s to %s", input.SrcPath, input.DstPath), nil
+	})
+}
+
+// copyFile copies with CopyableBackend.Copy if supported, otherwise by reading the whole source and writing it.
+// Without native copy, overwriting requires a RemovableBackend to remove the destination first, which must be a file.
+func copyFile(ctx context.Context, fs filesystem.Backend, req *filesystem.CopyRequest) error {
+	if cb, ok := fs.(filesystem.CopyableBackend); ok {
+		return cb.Copy(ctx, req)
+	}
+	rb, ok := fs.(filesystem.ReadAllBackend)
+	if !ok {
+		return fmt.Errorf("backend supports neither copy nor reading whole files")
+	}
+
+	content, err := rb.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: req.SrcPath})
+	if err != nil {
+		return err
+	}
+	if req.Overwrite {
+		if rmb, ok := fs.(filesystem.RemovableBackend); ok {
+			// Remove is recursive, so only a regular file at DstPath is removed, never a directory
+			info, err := lookupFileInfo(ctx, fs, req.DstPath)
+			if err != nil {
+				return err
+			}
+			if info != nil && info.IsDir {
+				return fmt.Errorf("cannot copy to %s: it is a directory", req.DstPath)
+			}
+			if info != nil {
+				if err = rmb.Remove(ctx, &filesystem.RemoveRequest{Path: req.DstPath}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Write fails if the destination exists
+	return fs.Write(ctx, &filesystem.WriteRequest{
+		FilePath:       req.DstPath,
+		Content:        content,
+		IdempotencyKey: compose.GetToolCallID(ctx),
+	})
+}
+
+// lookupFileInfo returns the FileInfo of filePath from the listing of its parent directory, or nil if it doesn't exist.
+func lookupFileInfo(ctx context.Context, fs filesystem.Backend, filePath string) (*filesystem.FileInfo, error) {
+	filePath = path.Clean("/" + filePath)
+	infos, err := fs.LsInfo(ctx, &filesystem.LsInfoRequest{Path: path.Dir(filePath)})
+	if err != nil {
+		return nil, err
+	}
+	for i := range infos {
+		if path.Clean("/"+infos[i].Path) == filePath {
+			return &infos[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// lockFiles acquires the locks of the given files if fs is a LockableBackend, returning the func releasing them.
+// The locks are acquired in the order of the paths, so that concurrent callers locking the same files can't deadlock.
+// The paths are cleaned first, so that the spellings of a file, e.g. "/x/y.txt" and "/x//y.txt", lock it once.
+func lockFiles(ctx context.Context, fs filesystem.Backend, paths ...string) (func(), error) {
+	lb, ok := fs.(filesystem.LockableBackend)
+	if !ok {
+		return func() {}, nil
+	}
+
+	sorted := make([]string, len(paths))
+	for i, p := range paths {
+		sorted[i] = path.Clean("/" + p)
+	}
+	sort.Strings(sorted)
+
+	unlocks := make([]func(), 0, len(sorted))
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for i, p := range sorted {
+		if i > 0 && p == sorted[i-1] {
+			continue
+		}
+		unlock, err := lb.Lock(ctx, p)
+		if err != nil {
+			unlockAll()
+			return nil, fmt.Errorf("failed to lock %s: %w", p, err)
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
+
 type globArgs struct {
 	Pattern string `json:"pattern"`
 	Path    string `json:"path"`
 }
 
-func newGlobTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
+func newGlobTool(fs filesystem.Backend, desc *string, maxResults int) (tool.BaseTool, error) {
 	d := GlobToolDesc
 	if desc != nil {
 		d = *desc
@@ -299,22 +668,31 @@ func newGlobTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
 		if err != nil {
 			return "", err
 		}
-		paths := make([]string, 0, len(infos))
+		infos, omitted := limitResults(infos, maxResults)
+		paths := make([]string, 0, len(infos)+1)
 		for _, fi := range infos {
-			paths = append(paths, fi.Path)
+			if fi.IsDir {
+				paths = append(paths, fi.Path+"/")
+			} else {
+				paths = append(paths, fi.Path)
+			}
+		}
+		if omitted > 0 {
+			paths = append(paths, omittedNote(omitted))
 		}
 		return strings.Join(paths, "\n"), nil
 	})
 }
 
 type grepArgs struct {
-	Pattern    string  `json:"pattern"`
-	Path       *string `json:"path,omitempty"`
-	Glob       *string `json:"glob,omitempty"`
-	OutputMode string  `json:"output_mode" jsonschema:"enum=files_with_matches,enum=content,enum=count"`
+	Pattern        string  `json:"pattern"`
+	Path           *string `json:"path,omitempty"`
+	Glob           *string `json:"glob,omitempty"`
+	OutputMode     string  `json:"output_mode" jsonschema:"enum=files_with_matches,enum=content,enum=count"`
+	AllOccurrences *bool   `json:"all_occurrences,omitempty"`
 }
 
-func newGrepTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
+func newGrepTool(fs filesystem.Backend, desc *string, maxMatches int) (tool.BaseTool, error) {
 	d := GrepToolDesc
 	if desc != nil {
 		d = *desc
@@ -328,9 +706,10 @@ func newGrepTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
 			glob = *input.Glob
 		}
 		matches, err := fs.GrepRaw(ctx, &filesystem.GrepRequest{
-			Pattern: input.Pattern,
-			Path:    path,
-			Glob:    glob,
+			Pattern:        input.Pattern,
+			Path:           path,
+			Glob:           glob,
+			AllOccurrences: input.AllOccurrences != nil && *input.AllOccurrences,
 		})
 		if err != nil {
 			return "", err
@@ -339,15 +718,24 @@ func newGrepTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
 		case "count":
 			return strconv.Itoa(len(matches)), nil
 		case "content":
+			matches, omitted := limitResults(matches, maxMatches)
 			var b strings.Builder
 			for _, m := range matches {
 				b.WriteString(m.Path)
 				b.WriteString(":")
 				b.WriteString(strconv.Itoa(m.Line))
 				b.WriteString(":")
+				if m.Column > 0 {
+					b.WriteString(strconv.Itoa(m.Column))
+					b.WriteString(":")
+				}
 				b.WriteString(m.Content)
 				b.WriteString("\n")
 			}
+			if omitted > 0 {
+				b.WriteString(omittedNote(omitted))
+				b.WriteString("\n")
+			}
 			return b.String(), nil
 		default:
 			// default by files_with_matches
@@ -359,34 +747,89 @@ func newGrepTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
 					seen[m.Path] = struct{}{}
 				}
 			}
+			files, omitted := limitResults(files, maxMatches)
+			if omitted > 0 {
+				files = append(files, omittedNote(omitted))
+			}
 			return strings.Join(files, "\n"), nil
 		}
 	})
 }
 
+// limitResults keeps the first max items, and reports how many were dropped. A max <= 0 means no limit.
+func limitResults[T any](items []T, max int) ([]T, int) {
+	if max <= 0 || len(items) <= max {
+		return items, 0
+	}
+	return items[:max], len(items) - max
+}
+
+func omittedNote(omitted int) string {
+	return fmt.Sprintf("(%d more results omitted)", omitted)
+}
+
 type executeArgs struct {
 	Command string `json:"command"`
 }
 
-func newExecuteTool(sb filesystem.ShellBackend, desc *string) (tool.BaseTool, error) {
+func newExecuteTool(sb filesystem.ShellBackend, desc *string, timeout time.Duration) (tool.BaseTool, error) {
 	d := ExecuteToolDesc
 	if desc != nil {
 		d = *desc
 	}
 
 	return utils.InferTool("execute", d, func(ctx context.Context, input executeArgs) (string, error) {
-		result, err := sb.Execute(ctx, &filesystem.ExecuteRequest{
+		req := &filesystem.ExecuteRequest{
 			Command: input.Command,
-		})
-		if err != nil {
-			return "", err
 		}
+		if timeout <= 0 {
+			result, err := sb.Execute(ctx, req)
+			if err != nil {
+				return "", err
+			}
+			return convExecuteResponse(result), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
 
-		return convExecuteResponse(result), nil
+		type executeResult struct {
+			resp *filesystem.ExecuteResponse
+			err  error
+		}
+		// the backend may not honor ctx cancellation, so it runs in its own goroutine to avoid blocking the agent
+		done := make(chan executeResult, 1)
+		go func() {
+			defer func() {
+				if e := recover(); e != nil {
+					done <- executeResult{err: fmt.Errorf("panic: %v,\n stack: %s", e, string(debug.Stack()))}
+				}
+			}()
+			resp, err := sb.Execute(ctx, req)
+			done <- executeResult{resp: resp, err: err}
+		}()
+
+		select {
+		case r := <-done:
+			if r.err != nil {
+				if errors.Is(r.err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Sprintf(executeTimeoutMessage, timeout), nil
+				}
+				return "", r.err
+			}
+			return convExecuteResponse(r.resp), nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Sprintf(executeTimeoutMessage, timeout), nil
+			}
+			return "", ctx.Err()
+		}
 	})
 }
 
-func newStreamingExecuteTool(sb filesystem.StreamingShellBackend, desc *string) (tool.BaseTool, error) {
+func newStreamingExecuteTool(sb filesystem.StreamingShellBackend, desc *string,
+	coalesceInterval time.Duration, coalesceSize int) (tool.BaseTool, error) {
+
 	d := ExecuteToolDesc
 	if desc != nil {
 		d = *desc
@@ -413,6 +856,7 @@ func newStreamingExecuteTool(sb filesystem.StreamingShellBackend, desc *string)
 					break
 				}
 				if recvErr != nil {
+					// the output already sent is kept, followed by the error
 					sw.Send("", recvErr)
 					break
 				}
@@ -423,6 +867,9 @@ func newStreamingExecuteTool(sb filesystem.StreamingShellBackend, desc *string)
 			}
 		}()
 
+		if coalesceInterval > 0 || coalesceSize > 0 {
+			return coalesceStream(sr, coalesceInterval, coalesceSize), nil
+		}
 		return sr, nil
 	})
 }
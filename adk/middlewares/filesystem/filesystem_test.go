@@ -18,13 +18,23 @@ package filesystem
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 
+	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/adk/middlewares/toolerror"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
 )
 
 // setupTestBackend creates a test backend with some initial files
@@ -69,7 +79,7 @@ func invokeTool(_ *testing.T, bt tool.BaseTool, input string) (string, error) {
 
 func TestLsTool(t *testing.T) {
 	backend := setupTestBackend()
-	lsTool, err := newLsTool(backend, nil)
+	lsTool, err := newLsTool(backend, nil, 0)
 	if err != nil {
 		t.Fatalf("Failed to create ls tool: %v", err)
 	}
@@ -82,12 +92,12 @@ func TestLsTool(t *testing.T) {
 		{
 			name:     "list root",
 			input:    `{"path": "/"}`,
-			expected: []string{"/file1.txt", "/file2.go", "/dir1", "/dir2"},
+			expected: []string{"/file1.txt", "/file2.go", "/dir1/", "/dir2/"},
 		},
 		{
 			name:     "list empty path (defaults to root)",
 			input:    `{"path": ""}`,
-			expected: []string{"/file1.txt", "/file2.go", "/dir1", "/dir2"},
+			expected: []string{"/file1.txt", "/file2.go", "/dir1/", "/dir2/"},
 		},
 		{
 			name:     "list dir1",
@@ -312,7 +322,7 @@ func TestEditFileTool(t *testing.T) {
 
 func TestGlobTool(t *testing.T) {
 	backend := setupTestBackend()
-	globTool, err := newGlobTool(backend, nil)
+	globTool, err := newGlobTool(backend, nil, 0)
 	if err != nil {
 		t.Fatalf("Failed to create glob tool: %v", err)
 	}
@@ -365,9 +375,126 @@ func TestGlobTool(t *testing.T) {
 	}
 }
 
+func TestToolInfos(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewMiddleware(ctx, &Config{Backend: setupTestBackend()})
+	assert.NoError(t, err)
+
+	infos, err := adk.ToolInfos(ctx, m.AdditionalTools)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+		assert.NotEmpty(t, info.Desc)
+		assert.NotNil(t, info.ParamsOneOf)
+	}
+	assert.Equal(t, []string{"ls", "tree", "read_file", "read_whole_file", "write_file", "edit_file", "copy_file", "apply_patch", "glob", "grep"}, names)
+}
+
+func TestTreeTool(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+	for _, p := range []string{
+		"/repo/go.mod",
+		"/repo/README.md",
+		"/repo/cmd/main.go",
+		"/repo/internal/util/strings.go",
+		"/repo/internal/util/strings_test.go",
+		"/repo/internal/util/deep/a.go",
+		"/repo/internal/util/deep/b.go",
+		"/repo/internal/doc.go",
+	} {
+		assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: p, Content: "x"}))
+	}
+
+	treeTool, err := newTreeTool(backend, nil, 0)
+	assert.NoError(t, err)
+	result, err := invokeTool(t, treeTool, `{"path": "/repo"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `/repo/
+  README.md
+  cmd/
+    main.go
+  go.mod
+  internal/
+    doc.go
+    util/
+      deep/ (2 entries)
+      strings.go
+      strings_test.go`, result)
+
+	result, err = invokeTool(t, treeTool, `{"path": "/repo", "depth": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `/repo/
+  README.md
+  cmd/ (1 entry)
+  go.mod
+  internal/ (2 entries)`, result)
+
+	limited, err := newTreeTool(backend, nil, 3)
+	assert.NoError(t, err)
+	result, err = invokeTool(t, limited, `{"path": "/repo"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `/repo/
+  README.md
+  cmd/
+    main.go
+(7 more results omitted)`, result)
+}
+
+func TestMaxResults(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+	for i := 0; i < 1000; i++ {
+		err := backend.Write(ctx, &filesystem.WriteRequest{
+			FilePath: fmt.Sprintf("/big/file%04d.txt", i),
+			Content:  "needle",
+		})
+		assert.NoError(t, err)
+	}
+
+	lsTool, err := newLsTool(backend, nil, 100)
+	assert.NoError(t, err)
+	result, err := invokeTool(t, lsTool, `{"path": "/big"}`)
+	assert.NoError(t, err)
+	lines := strings.Split(result, "\n")
+	assert.Len(t, lines, 101)
+	assert.Equal(t, "(900 more results omitted)", lines[100])
+
+	globTool, err := newGlobTool(backend, nil, 100)
+	assert.NoError(t, err)
+	result, err = invokeTool(t, globTool, `{"pattern": "*.txt", "path": "/big"}`)
+	assert.NoError(t, err)
+	lines = strings.Split(result, "\n")
+	assert.Len(t, lines, 101)
+	assert.Equal(t, "(900 more results omitted)", lines[100])
+
+	grepTool, err := newGrepTool(backend, nil, 10)
+	assert.NoError(t, err)
+	result, err = invokeTool(t, grepTool, `{"pattern": "needle", "output_mode": "content"}`)
+	assert.NoError(t, err)
+	lines = strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	assert.Len(t, lines, 11)
+	assert.Equal(t, "(990 more results omitted)", lines[10])
+	result, err = invokeTool(t, grepTool, `{"pattern": "needle"}`)
+	assert.NoError(t, err)
+	lines = strings.Split(result, "\n")
+	assert.Len(t, lines, 11)
+	assert.Equal(t, "(990 more results omitted)", lines[10])
+	result, err = invokeTool(t, grepTool, `{"pattern": "needle", "output_mode": "count"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", result)
+
+	unlimited, err := newLsTool(backend, nil, 0)
+	assert.NoError(t, err)
+	result, err = invokeTool(t, unlimited, `{"path": "/big"}`)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(result, "\n"), 1000)
+}
+
 func TestGrepTool(t *testing.T) {
 	backend := setupTestBackend()
-	grepTool, err := newGrepTool(backend, nil)
+	grepTool, err := newGrepTool(backend, nil, 0)
 	if err != nil {
 		t.Fatalf("Failed to create grep tool: %v", err)
 	}
@@ -386,7 +513,7 @@ func TestGrepTool(t *testing.T) {
 		{
 			name:     "grep with content mode",
 			input:    `{"pattern": "hello", "output_mode": "content"}`,
-			contains: []string{"/dir1/file3.txt:1:hello world", "/dir1/file3.txt:3:hello again", "/dir1/file4.py:1:print('hello')"},
+			contains: []string{"/dir1/file3.txt:1:1:hello world", "/dir1/file3.txt:3:1:hello again", "/dir1/file4.py:1:8:print('hello')"},
 		},
 		{
 			name:     "grep with files_with_matches mode (default)",
@@ -403,6 +530,21 @@ func TestGrepTool(t *testing.T) {
 			input:    `{"pattern": "package", "path": "/dir2", "output_mode": "count"}`,
 			expected: "1", // only in dir2/file5.go
 		},
+		{
+			name:     "grep counting matching lines",
+			input:    `{"pattern": "o", "glob": "*.txt", "output_mode": "count"}`,
+			expected: "3",
+		},
+		{
+			name:     "grep counting all occurrences",
+			input:    `{"pattern": "o", "glob": "*.txt", "output_mode": "count", "all_occurrences": true}`,
+			expected: "5",
+		},
+		{
+			name:     "grep with content mode of all occurrences",
+			input:    `{"pattern": "o", "glob": "*.txt", "output_mode": "content", "all_occurrences": true}`,
+			contains: []string{"/dir1/file3.txt:1:5:hello world", "/dir1/file3.txt:1:8:hello world", "/dir1/file3.txt:2:2:foo bar", "/dir1/file3.txt:2:3:foo bar"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -482,7 +624,7 @@ func TestExecuteTool(t *testing.T) {
 			executeTool, err := newExecuteTool(&mockShellBackend{
 				Backend: backend,
 				resp:    tt.resp,
-			}, nil)
+			}, nil, 0)
 			assert.NoError(t, err)
 
 			result, err := invokeTool(t, executeTool, tt.input)
@@ -496,6 +638,77 @@ func TestExecuteTool(t *testing.T) {
 	}
 }
 
+func TestExecuteToolTimeout(t *testing.T) {
+	backend := setupTestBackend()
+
+	t.Run("backend honoring ctx cancellation", func(t *testing.T) {
+		canceled := make(chan error, 1)
+		executeTool, err := newExecuteTool(&sleepShellBackend{
+			Backend:  backend,
+			sleep:    time.Second,
+			canceled: canceled,
+		}, nil, 50*time.Millisecond)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		result, err := invokeTool(t, executeTool, `{"command": "sleep 1"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "[Command timed out after 50ms and was canceled]", result)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.ErrorIs(t, <-canceled, context.DeadlineExceeded)
+	})
+
+	t.Run("backend ignoring ctx cancellation", func(t *testing.T) {
+		executeTool, err := newExecuteTool(&sleepShellBackend{
+			Backend:   backend,
+			sleep:     time.Second,
+			ignoreCtx: true,
+		}, nil, 50*time.Millisecond)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		result, err := invokeTool(t, executeTool, `{"command": "sleep 1"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "[Command timed out after 50ms and was canceled]", result)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("command finishing in time", func(t *testing.T) {
+		executeTool, err := newExecuteTool(&sleepShellBackend{
+			Backend: backend,
+			sleep:   time.Millisecond,
+		}, nil, time.Second)
+		assert.NoError(t, err)
+
+		result, err := invokeTool(t, executeTool, `{"command": "echo done"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", result)
+	})
+}
+
+type sleepShellBackend struct {
+	filesystem.Backend
+	sleep     time.Duration
+	ignoreCtx bool
+	canceled  chan error
+}
+
+func (s *sleepShellBackend) Execute(ctx context.Context, req *filesystem.ExecuteRequest) (*filesystem.ExecuteResponse, error) {
+	if s.ignoreCtx {
+		time.Sleep(s.sleep)
+		return &filesystem.ExecuteResponse{Output: "done"}, nil
+	}
+	select {
+	case <-time.After(s.sleep):
+		return &filesystem.ExecuteResponse{Output: "done"}, nil
+	case <-ctx.Done():
+		if s.canceled != nil {
+			s.canceled <- ctx.Err()
+		}
+		return nil, ctx.Err()
+	}
+}
+
 func ptrOf[T any](t T) *T {
 	return &t
 }
@@ -509,6 +722,299 @@ func (m *mockShellBackend) Execute(ctx context.Context, req *filesystem.ExecuteR
 	return m.resp, nil
 }
 
+func TestStreamingExecuteToolPartialOutput(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, withToolError bool) (toolResult string, runErr error) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mw, err := NewMiddleware(ctx, &Config{Backend: &mockStreamingShellBackend{
+			Backend: setupTestBackend(),
+			resps:   []*filesystem.ExecuteResponse{{Output: "line1\n"}, {Output: "line2"}},
+			err:     errors.New("connection reset"),
+		}})
+		assert.NoError(t, err)
+		middlewares := []adk.AgentMiddleware{mw}
+		if withToolError {
+			tem, err := toolerror.NewMiddleware(ctx, nil)
+			assert.NoError(t, err)
+			middlewares = append(middlewares, tem)
+		}
+
+		step := 0
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				step++
+				if step > 1 {
+					toolResult = input[len(input)-1].Content
+					return schema.AssistantMessage("done", nil), nil
+				}
+				return schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       "call-1",
+					Function: schema.FunctionCall{Name: "execute", Arguments: `{"command": "tail -f log"}`},
+				}}), nil
+			}).AnyTimes()
+
+		agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+			Name:        "TestAgent",
+			Description: "Test agent with streaming execute tool",
+			Instruction: "You are a helpful assistant.",
+			Model:       cm,
+			Middlewares: middlewares,
+		})
+		assert.NoError(t, err)
+
+		iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "tail the log")
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if event.Err != nil {
+				runErr = event.Err
+			}
+		}
+		return toolResult, runErr
+	}
+
+	t.Run("the error is forwarded", func(t *testing.T) {
+		toolResult, err := run(t, false)
+		assert.ErrorContains(t, err, "connection reset")
+		assert.Empty(t, toolResult)
+	})
+
+	t.Run("the output is kept before the error guidance", func(t *testing.T) {
+		toolResult, err := run(t, true)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(toolResult, "line1\nline2\n[Tool call failed]"), toolResult)
+		assert.Contains(t, toolResult, "Error: connection reset")
+	})
+}
+
+type writeOnlyBackend struct {
+	filesystem.Backend
+	rb filesystem.ReadAllBackend
+}
+
+func (w *writeOnlyBackend) ReadAll(ctx context.Context, req *filesystem.ReadAllRequest) (string, error) {
+	return w.rb.ReadAll(ctx, req)
+}
+
+// removableWriteOnlyBackend is a writeOnlyBackend which can also remove files, to overwrite by the copy fallback.
+type removableWriteOnlyBackend struct {
+	writeOnlyBackend
+	rmb filesystem.RemovableBackend
+}
+
+func (w *removableWriteOnlyBackend) Remove(ctx context.Context, req *filesystem.RemoveRequest) error {
+	return w.rmb.Remove(ctx, req)
+}
+
+func TestCopyFileTool(t *testing.T) {
+	ctx := context.Background()
+
+	for name, backend := range map[string]func() filesystem.Backend{
+		"native copy": func() filesystem.Backend { return setupTestBackend() },
+		"read and write fallback": func() filesystem.Backend {
+			b := setupTestBackend()
+			return &writeOnlyBackend{Backend: b, rb: b}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := backend()
+			copyTool, err := newCopyFileTool(b, nil)
+			assert.NoError(t, err)
+			invokable := copyTool.(tool.InvokableTool)
+
+			result, err := invokable.InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/backup/file1.txt"}`)
+			assert.NoError(t, err)
+			assert.Equal(t, "Copied file /file1.txt to /backup/file1.txt", result)
+			src, _ := b.(filesystem.ReadAllBackend).ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file1.txt"})
+			dst, _ := b.(filesystem.ReadAllBackend).ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/backup/file1.txt"})
+			assert.Equal(t, src, dst)
+
+			// the existing destination is kept without overwrite
+			_, err = invokable.InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/file2.go"}`)
+			assert.ErrorContains(t, err, "already exists")
+			content, _ := b.(filesystem.ReadAllBackend).ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file2.go"})
+			assert.NotEqual(t, src, content)
+		})
+	}
+
+	t.Run("overwrite", func(t *testing.T) {
+		b := setupTestBackend()
+		copyTool, err := newCopyFileTool(b, nil)
+		assert.NoError(t, err)
+		_, err = copyTool.(tool.InvokableTool).InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/file2.go", "overwrite": true}`)
+		assert.NoError(t, err)
+		src, _ := b.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file1.txt"})
+		dst, _ := b.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file2.go"})
+		assert.Equal(t, src, dst)
+	})
+
+	t.Run("overwrite by the read and write fallback", func(t *testing.T) {
+		b := setupTestBackend()
+		assert.NoError(t, b.Write(ctx, &filesystem.WriteRequest{FilePath: "/dir/nested/keep.txt", Content: "keep"}))
+		copyTool, err := newCopyFileTool(&removableWriteOnlyBackend{writeOnlyBackend: writeOnlyBackend{Backend: b, rb: b}, rmb: b}, nil)
+		assert.NoError(t, err)
+		invokable := copyTool.(tool.InvokableTool)
+
+		_, err = invokable.InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/file2.go", "overwrite": true}`)
+		assert.NoError(t, err)
+		src, _ := b.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file1.txt"})
+		dst, _ := b.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/file2.go"})
+		assert.Equal(t, src, dst)
+
+		_, err = invokable.InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/new.txt", "overwrite": true}`)
+		assert.NoError(t, err)
+
+		// a directory isn't removed to be replaced by the copy
+		_, err = invokable.InvokableRun(ctx, `{"src_path": "/file1.txt", "dst_path": "/dir", "overwrite": true}`)
+		assert.ErrorContains(t, err, "is a directory")
+		content, err := b.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: "/dir/nested/keep.txt"})
+		assert.NoError(t, err)
+		assert.Equal(t, "keep", content)
+	})
+}
+
+func TestConditionalSystemPrompt(t *testing.T) {
+	ctx := context.Background()
+
+	getInstruction := func(t *testing.T, backend filesystem.Backend) string {
+		ctrl := gomock.NewController(t)
+		mw, err := NewMiddleware(ctx, &Config{Backend: backend})
+		assert.NoError(t, err)
+
+		var instruction string
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				instruction = input[0].Content
+				return schema.AssistantMessage("done", nil), nil
+			})
+
+		agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+			Name:        "TestAgent",
+			Description: "Test agent with filesystem tools",
+			Instruction: "You are a helpful assistant.",
+			Model:       cm,
+			Middlewares: []adk.AgentMiddleware{mw},
+		})
+		assert.NoError(t, err)
+
+		iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "hello")
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+		}
+		return instruction
+	}
+
+	t.Run("non-shell backend", func(t *testing.T) {
+		instruction := getInstruction(t, setupTestBackend())
+		assert.Contains(t, instruction, ToolsSystemPrompt)
+		assert.Contains(t, instruction, ReadWholeFileToolsSystemPrompt)
+		assert.NotContains(t, instruction, ExecuteToolsSystemPrompt)
+	})
+
+	t.Run("shell backend", func(t *testing.T) {
+		instruction := getInstruction(t, &mockShellBackend{Backend: setupTestBackend()})
+		assert.Contains(t, instruction, ToolsSystemPrompt+ExecuteToolsSystemPrompt)
+		assert.NotContains(t, instruction, ReadWholeFileToolsSystemPrompt)
+	})
+}
+
+func TestStreamingExecuteToolCoalesce(t *testing.T) {
+	ctx := context.Background()
+	resps := make([]*filesystem.ExecuteResponse, 0, 100)
+	for i := 0; i < 100; i++ {
+		resps = append(resps, &filesystem.ExecuteResponse{Output: "x"})
+	}
+	backend := &mockStreamingShellBackend{Backend: setupTestBackend(), resps: resps}
+
+	collect := func(interval time.Duration, size int) []string {
+		et, err := newStreamingExecuteTool(backend, nil, interval, size)
+		assert.NoError(t, err)
+		sr, err := et.(tool.StreamableTool).StreamableRun(ctx, `{"command": "yes x"}`)
+		assert.NoError(t, err)
+		var chunks []string
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				return chunks
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	assert.Len(t, collect(0, 0), 100)
+	assert.Equal(t, []string{strings.Repeat("x", 30), strings.Repeat("x", 30), strings.Repeat("x", 30), strings.Repeat("x", 10)},
+		collect(time.Hour, 30))
+	assert.Equal(t, []string{strings.Repeat("x", 100)}, collect(time.Hour, 0))
+
+	t.Run("interval flushes buffered output", func(t *testing.T) {
+		sr, sw := schema.Pipe[string](0)
+		go func() {
+			sw.Send("a", nil)
+			sw.Send("b", nil)
+			time.Sleep(100 * time.Millisecond)
+			sw.Send("c", nil)
+			sw.Close()
+		}()
+		out := coalesceStream(sr, 20*time.Millisecond, 0)
+		var chunks []string
+		for {
+			chunk, err := out.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		assert.Equal(t, []string{"ab", "c"}, chunks)
+	})
+
+	t.Run("stream error is kept after the buffered output", func(t *testing.T) {
+		et, err := newStreamingExecuteTool(&mockStreamingShellBackend{
+			Backend: setupTestBackend(),
+			resps:   resps[:3],
+			err:     errors.New("connection reset"),
+		}, nil, time.Hour, 0)
+		assert.NoError(t, err)
+		sr, err := et.(tool.StreamableTool).StreamableRun(ctx, `{"command": "yes x"}`)
+		assert.NoError(t, err)
+		chunk, err := sr.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "xxx", chunk)
+		_, err = sr.Recv()
+		assert.EqualError(t, err, "connection reset")
+	})
+}
+
+type mockStreamingShellBackend struct {
+	filesystem.Backend
+	resps []*filesystem.ExecuteResponse
+	err   error
+}
+
+func (m *mockStreamingShellBackend) ExecuteStreaming(ctx context.Context, req *filesystem.ExecuteRequest) (*schema.StreamReader[*filesystem.ExecuteResponse], error) {
+	sr, sw := schema.Pipe[*filesystem.ExecuteResponse](len(m.resps) + 1)
+	for _, resp := range m.resps {
+		sw.Send(resp, nil)
+	}
+	sw.Send(nil, m.err)
+	sw.Close()
+	return sr, nil
+}
+
 func TestNewMiddleware(t *testing.T) {
 	ctx := context.Background()
 	backend := setupTestBackend()
@@ -532,8 +1038,12 @@ func TestNewMiddleware(t *testing.T) {
 		// Check default system prompt
 		assert.Contains(t, m.AdditionalInstruction, ToolsSystemPrompt)
 
-		// Check tools are registered (6 tools for regular Backend)
-		assert.Len(t, m.AdditionalTools, 6)
+		// Check tools are registered (10 tools for InMemoryBackend, which implements ReadAllBackend and CopyableBackend)
+		assert.Len(t, m.AdditionalTools, 10)
+		assert.Contains(t, m.AdditionalInstruction, ReadWholeFileToolsSystemPrompt)
+		assert.Contains(t, m.AdditionalInstruction, CopyFileToolsSystemPrompt)
+		assert.NotContains(t, m.AdditionalInstruction, ExecuteToolsSystemPrompt)
+		assert.Empty(t, m.ConditionalInstructions)
 
 		// Check WrapToolCall is set (offloading enabled by default)
 		assert.NotNil(t, m.WrapToolCall)
@@ -547,6 +1057,7 @@ func TestNewMiddleware(t *testing.T) {
 		})
 		assert.NoError(t, err)
 		assert.Equal(t, customPrompt, m.AdditionalInstruction)
+		assert.Empty(t, m.ConditionalInstructions)
 	})
 
 	t.Run("disable large tool result offloading", func(t *testing.T) {
@@ -567,19 +1078,42 @@ func TestNewMiddleware(t *testing.T) {
 		m, err := NewMiddleware(ctx, &Config{Backend: shellBackend})
 		assert.NoError(t, err)
 
-		// ShellBackend should have 7 tools (6 + execute)
-		assert.Len(t, m.AdditionalTools, 7)
+		// ShellBackend should have 9 tools (8 + execute)
+		assert.Len(t, m.AdditionalTools, 9)
 	})
+
+	t.Run("backend failing ping returns error", func(t *testing.T) {
+		pingErr := errors.New("bucket not found")
+		_, err := NewMiddleware(ctx, &Config{Backend: &pingBackend{Backend: backend, err: pingErr}})
+		assert.ErrorIs(t, err, pingErr)
+		assert.Contains(t, err.Error(), "failed to ping backend")
+
+		pb := &pingBackend{Backend: backend}
+		_, err = NewMiddleware(ctx, &Config{Backend: pb})
+		assert.NoError(t, err)
+		assert.True(t, pb.pinged)
+	})
+}
+
+type pingBackend struct {
+	filesystem.Backend
+	err    error
+	pinged bool
+}
+
+func (p *pingBackend) Ping(context.Context) error {
+	p.pinged = true
+	return p.err
 }
 
 func TestGetFilesystemTools(t *testing.T) {
 	ctx := context.Background()
 	backend := setupTestBackend()
 
-	t.Run("returns 6 tools for regular Backend", func(t *testing.T) {
+	t.Run("returns 10 tools for ReadAllBackend", func(t *testing.T) {
 		tools, err := getFilesystemTools(ctx, &Config{Backend: backend})
 		assert.NoError(t, err)
-		assert.Len(t, tools, 6)
+		assert.Len(t, tools, 10)
 
 		// Verify tool names
 		toolNames := make([]string, 0, len(tools))
@@ -588,21 +1122,25 @@ func TestGetFilesystemTools(t *testing.T) {
 			toolNames = append(toolNames, info.Name)
 		}
 		assert.Contains(t, toolNames, "ls")
+		assert.Contains(t, toolNames, "tree")
 		assert.Contains(t, toolNames, "read_file")
+		assert.Contains(t, toolNames, "read_whole_file")
 		assert.Contains(t, toolNames, "write_file")
 		assert.Contains(t, toolNames, "edit_file")
+		assert.Contains(t, toolNames, "copy_file")
+		assert.Contains(t, toolNames, "apply_patch")
 		assert.Contains(t, toolNames, "glob")
 		assert.Contains(t, toolNames, "grep")
 	})
 
-	t.Run("returns 7 tools for ShellBackend", func(t *testing.T) {
+	t.Run("returns 9 tools for ShellBackend", func(t *testing.T) {
 		shellBackend := &mockShellBackend{
 			Backend: backend,
 			resp:    &filesystem.ExecuteResponse{Output: "ok"},
 		}
 		tools, err := getFilesystemTools(ctx, &Config{Backend: shellBackend})
 		assert.NoError(t, err)
-		assert.Len(t, tools, 7)
+		assert.Len(t, tools, 9)
 
 		// Verify execute tool is included
 		toolNames := make([]string, 0, len(tools))
@@ -623,7 +1161,7 @@ func TestGetFilesystemTools(t *testing.T) {
 			CustomReadFileToolDesc: &customReadDesc,
 		})
 		assert.NoError(t, err)
-		assert.Len(t, tools, 6)
+		assert.Len(t, tools, 10)
 
 		// Verify custom descriptions are applied
 		for _, tool := range tools {
@@ -637,3 +1175,30 @@ func TestGetFilesystemTools(t *testing.T) {
 		}
 	})
 }
+
+func TestReadWholeFileTool(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+	lines := make([]string, 500)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	content := strings.Join(lines, "\n")
+	err := backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/file.txt", Content: content})
+	assert.NoError(t, err)
+
+	readWholeTool, err := newReadWholeFileTool(backend, nil, 0)
+	assert.NoError(t, err)
+	result, err := invokeTool(t, readWholeTool, `{"file_path": "/file.txt"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, content, result)
+
+	readWholeTool, err = newReadWholeFileTool(backend, nil, 1000)
+	assert.NoError(t, err)
+	result, err = invokeTool(t, readWholeTool, `{"file_path": "/file.txt"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(fileTooLargeMessage, "/file.txt", len(content), 1000), result)
+
+	_, err = invokeTool(t, readWholeTool, `{"file_path": "/missing.txt"}`)
+	assert.Error(t, err)
+}
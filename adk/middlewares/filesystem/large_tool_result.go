@@ -17,7 +17,6 @@
 package filesystem
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -116,8 +115,9 @@ func (t *toolResultOffloading) handleResult(ctx context.Context, result string,
 		}
 
 		err = t.backend.Write(ctx, &WriteRequest{
-			FilePath: path,
-			Content:  result,
+			FilePath:       path,
+			Content:        result,
+			IdempotencyKey: input.CallID,
 		})
 		if err != nil {
 			return "", err
@@ -147,15 +147,18 @@ func concatString(sr *schema.StreamReader[string]) (string, error) {
 }
 
 func formatToolMessage(s string) string {
-	reader := bufio.NewScanner(strings.NewReader(s))
+	// strings.Split rather than bufio.Scanner, which gives up on lines longer than its buffer
+	s = strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return ""
+	}
 	var b strings.Builder
 
 	lineNum := 1
-	for reader.Scan() {
+	for _, line := range strings.Split(s, "\n") {
 		if lineNum > 10 {
 			break
 		}
-		line := reader.Text()
 
 		if utf8.RuneCountInString(line) > 1000 {
 			runes := []rune(line)
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+)
+
+// notesPageLines is the number of lines read_notes reads at a time from a Backend without ReadAll.
+const notesPageLines = 200
+
+type addNoteArgs struct {
+	Note string `json:"note"`
+}
+
+func newAddNoteTool(fs filesystem.AppendableBackend, notesPath string, desc *string) (tool.BaseTool, error) {
+	d := AddNoteToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	return utils.InferTool("add_note", d, func(ctx context.Context, input addNoteArgs) (string, error) {
+		note := strings.TrimSpace(input.Note)
+		if note == "" {
+			return "", fmt.Errorf("note should not be empty")
+		}
+		err := fs.Append(ctx, &filesystem.AppendRequest{
+			FilePath:       notesPath,
+			Content:        fmt.Sprintf("[%s] %s\n", time.Now().Format(time.RFC3339), note),
+			IdempotencyKey: compose.GetToolCallID(ctx),
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Added note to %s", notesPath), nil
+	})
+}
+
+type readNotesArgs struct{}
+
+func newReadNotesTool(fs filesystem.Backend, notesPath string, desc *string) (tool.BaseTool, error) {
+	d := ReadNotesToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	return utils.InferTool("read_notes", d, func(ctx context.Context, _ readNotesArgs) (string, error) {
+		if rb, ok := fs.(filesystem.ReadAllBackend); ok {
+			notes, err := rb.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: notesPath})
+			if errors.Is(err, filesystem.ErrFileNotFound) {
+				return noNotesMessage, nil
+			}
+			return notes, err
+		}
+		// without ReadAll, the notes are read a page of numbered lines at a time
+		var pages []string
+		for offset := 0; ; offset += notesPageLines {
+			page, err := fs.Read(ctx, &filesystem.ReadRequest{FilePath: notesPath, Offset: offset, Limit: notesPageLines})
+			if errors.Is(err, filesystem.ErrFileNotFound) && offset == 0 {
+				return noNotesMessage, nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if page == "" {
+				break
+			}
+			pages = append(pages, page)
+			if strings.Count(page, "\n")+1 < notesPageLines {
+				break
+			}
+		}
+		return strings.Join(pages, "\n"), nil
+	})
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// appendOnlyBackend hides the optional interfaces of the InMemoryBackend but AppendableBackend.
+type appendOnlyBackend struct {
+	filesystem.AppendableBackend
+}
+
+func TestNotesTools(t *testing.T) {
+	ctx := context.Background()
+
+	for name, backend := range map[string]filesystem.AppendableBackend{
+		"read all":    setupTestBackend(),
+		"paged reads": &appendOnlyBackend{AppendableBackend: setupTestBackend()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			addNote, err := newAddNoteTool(backend, "/notes.md", nil)
+			assert.NoError(t, err)
+			readNotes, err := newReadNotesTool(backend, "/notes.md", nil)
+			assert.NoError(t, err)
+
+			notes, err := readNotes.(tool.InvokableTool).InvokableRun(ctx, `{}`)
+			assert.NoError(t, err)
+			assert.Equal(t, noNotesMessage, notes)
+
+			result, err := addNote.(tool.InvokableTool).InvokableRun(ctx, `{"note": "the config lives in /etc/app.yaml"}`)
+			assert.NoError(t, err)
+			assert.Equal(t, "Added note to /notes.md", result)
+			_, err = addNote.(tool.InvokableTool).InvokableRun(ctx, `{"note": "tests fail on a cold cache"}`)
+			assert.NoError(t, err)
+			_, err = addNote.(tool.InvokableTool).InvokableRun(ctx, `{"note": "  "}`)
+			assert.ErrorContains(t, err, "note should not be empty")
+
+			notes, err = readNotes.(tool.InvokableTool).InvokableRun(ctx, `{}`)
+			assert.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(`^(\s+1\t)?\[[^]]+\] the config lives in /etc/app\.yaml\n(\s+2\t)?\[[^]]+\] tests fail on a cold cache\n?`), notes)
+		})
+	}
+
+	t.Run("config", func(t *testing.T) {
+		tools, err := getFilesystemTools(ctx, &Config{Backend: setupTestBackend(), NotesFilePath: "/notes.md"})
+		assert.NoError(t, err)
+		var names []string
+		for _, bt := range tools {
+			info, _ := bt.Info(ctx)
+			names = append(names, info.Name)
+		}
+		assert.Subset(t, names, []string{"add_note", "read_notes"})
+
+		tools, err = getFilesystemTools(ctx, &Config{Backend: setupTestBackend()})
+		assert.NoError(t, err)
+		for _, bt := range tools {
+			info, _ := bt.Info(ctx)
+			assert.NotEqual(t, "add_note", info.Name)
+		}
+
+		assert.ErrorContains(t, (&Config{Backend: setupTestBackend(), NotesFilePath: "notes.md"}).Validate(), "should be absolute")
+		assert.ErrorContains(t, (&Config{Backend: &writeOnlyBackend{Backend: setupTestBackend()}, NotesFilePath: "/notes.md"}).Validate(),
+			"AppendableBackend")
+
+		config := &Config{Backend: setupTestBackend(), NotesFilePath: "/work//notes/../notes.md"}
+		assert.NoError(t, config.Validate())
+		assert.Equal(t, "/work/notes.md", config.NotesFilePath)
+	})
+}
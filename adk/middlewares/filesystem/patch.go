@@ -0,0 +1,363 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	devNull = "/dev/null"
+
+	// patchReadPageSize is the number of lines fetched per Backend.Read call when loading a file to patch.
+	patchReadPageSize = 1000
+)
+
+type applyPatchArgs struct {
+	Patch string `json:"patch"`
+}
+
+func newApplyPatchTool(fs filesystem.Backend, desc *string) (tool.BaseTool, error) {
+	d := ApplyPatchToolDesc
+	if desc != nil {
+		d = *desc
+	}
+	return utils.InferTool("apply_patch", d, func(ctx context.Context, input applyPatchArgs) (string, error) {
+		filePatches, err := parseUnifiedDiff(input.Patch)
+		if err != nil {
+			return "", err
+		}
+
+		var locked []string
+		for _, fp := range filePatches {
+			for _, p := range []string{fp.oldPath, fp.newPath} {
+				if p != devNull {
+					locked = append(locked, p)
+				}
+			}
+		}
+		// the files are locked from their reading to their writing, so that concurrent patches don't interleave
+		unlock, err := lockFiles(ctx, fs, locked...)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+
+		// all hunks are verified before anything is written, so a stale patch leaves every file untouched
+		results := make([]*patchedFile, 0, len(filePatches))
+		for _, fp := range filePatches {
+			pf, err := patchFile(ctx, fs, fp)
+			if err != nil {
+				return "", err
+			}
+			results = append(results, pf)
+		}
+
+		paths := make([]string, 0, len(results))
+		for _, pf := range results {
+			if err = pf.write(ctx, fs); err != nil {
+				return "", err
+			}
+			paths = append(paths, pf.path)
+		}
+		return fmt.Sprintf("Successfully applied patch to %s", strings.Join(paths, ", ")), nil
+	})
+}
+
+// filePatch is the set of hunks targeting a single file in a unified diff.
+type filePatch struct {
+	oldPath string
+	newPath string
+	hunks   []*patchHunk
+}
+
+func (fp *filePatch) path() string {
+	if fp.newPath != devNull {
+		return fp.newPath
+	}
+	return fp.oldPath
+}
+
+// patchHunk is a single "@@ -l,s +l,s @@" section of a unified diff.
+type patchHunk struct {
+	// oldStart is the 1-based start line in the original file, 0 for an empty original.
+	oldStart int
+	// oldLines are the context and removed lines expected in the original file.
+	oldLines []string
+	// newLines are the context and added lines that replace oldLines.
+	newLines []string
+}
+
+// parseUnifiedDiff parses a unified diff that may touch several files.
+func parseUnifiedDiff(patch string) ([]*filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+
+	var (
+		result []*filePatch
+		cur    *filePatch
+		hunk   *patchHunk
+	)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			cur = &filePatch{
+				oldPath: parsePatchPath(line[len("--- "):]),
+				newPath: parsePatchPath(lines[i+1][len("+++ "):]),
+			}
+			result = append(result, cur)
+			hunk = nil
+			i++
+		case strings.HasPrefix(line, "@@"):
+			if cur == nil {
+				return nil, fmt.Errorf("invalid patch: hunk header at line %d appears before file header ('--- ' and '+++ ' lines)", i+1)
+			}
+			oldStart, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid patch: line %d: %w", i+1, err)
+			}
+			hunk = &patchHunk{oldStart: oldStart}
+			cur.hunks = append(cur.hunks, hunk)
+		case hunk == nil:
+			// Lines outside hunks, such as "diff --git" or "index" headers, are ignored.
+		case strings.HasPrefix(line, " "):
+			hunk.oldLines = append(hunk.oldLines, line[1:])
+			hunk.newLines = append(hunk.newLines, line[1:])
+		case strings.HasPrefix(line, "-"):
+			hunk.oldLines = append(hunk.oldLines, line[1:])
+		case strings.HasPrefix(line, "+"):
+			hunk.newLines = append(hunk.newLines, line[1:])
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case line == "":
+			// Blank context lines are frequently emitted without the leading space.
+			if i == len(lines)-1 {
+				continue
+			}
+			hunk.oldLines = append(hunk.oldLines, "")
+			hunk.newLines = append(hunk.newLines, "")
+		default:
+			return nil, fmt.Errorf("invalid patch: unexpected line %d in hunk: %q", i+1, line)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("invalid patch: no file headers ('--- ' and '+++ ' lines) found")
+	}
+	for _, fp := range result {
+		if fp.newPath == devNull {
+			return nil, fmt.Errorf("invalid patch: deleting files is not supported: %s", fp.oldPath)
+		}
+		if len(fp.hunks) == 0 {
+			return nil, fmt.Errorf("invalid patch: no hunks found for %s", fp.path())
+		}
+	}
+	return result, nil
+}
+
+func parsePatchPath(s string) string {
+	// strip optional timestamp
+	if idx := strings.Index(s, "\t"); idx >= 0 {
+		s = s[:idx]
+	}
+	s = strings.TrimSpace(s)
+	if s == devNull {
+		return s
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	if !strings.HasPrefix(s, "/") {
+		s = "/" + s
+	}
+	return s
+}
+
+// parseHunkHeader parses "@@ -l,s +l,s @@" and returns the original start line.
+func parseHunkHeader(line string) (int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[0] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, fmt.Errorf("malformed hunk header: %q", line)
+	}
+	oldRange := strings.SplitN(fields[1][1:], ",", 2)
+	start, err := strconv.Atoi(oldRange[0])
+	if err != nil || start < 0 {
+		return 0, fmt.Errorf("malformed hunk header: %q", line)
+	}
+	return start, nil
+}
+
+// patchedFile is the result of applying a filePatch in memory, ready to be written back to the Backend.
+type patchedFile struct {
+	path     string
+	isNew    bool
+	original string
+	content  string
+}
+
+func (p *patchedFile) write(ctx context.Context, fs filesystem.Backend) error {
+	if p.isNew {
+		return fs.Write(ctx, &filesystem.WriteRequest{
+			FilePath: p.path,
+			Content:  p.content,
+		})
+	}
+	if p.content == p.original {
+		return nil
+	}
+	return fs.Edit(ctx, &filesystem.EditRequest{
+		FilePath:  p.path,
+		OldString: p.original,
+		NewString: p.content,
+	})
+}
+
+func patchFile(ctx context.Context, fs filesystem.Backend, fp *filePatch) (*patchedFile, error) {
+	if fp.oldPath == devNull {
+		var added []string
+		for _, h := range fp.hunks {
+			if len(h.oldLines) > 0 {
+				return nil, fmt.Errorf("invalid patch: hunk for new file %s contains context or removed lines", fp.newPath)
+			}
+			added = append(added, h.newLines...)
+		}
+		return &patchedFile{path: fp.newPath, isNew: true, content: strings.Join(added, "\n")}, nil
+	}
+
+	original, err := readWholeFile(ctx, fs, fp.oldPath)
+	if err != nil {
+		return nil, err
+	}
+	// hunks are matched against LF lines, and the line endings of a CRLF file are restored afterwards
+	crlf := strings.Contains(original, "\r\n")
+	text := original
+	if crlf {
+		text = strings.ReplaceAll(original, "\r\n", "\n")
+	}
+	patched, err := applyHunks(strings.Split(text, "\n"), fp)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.Join(patched, "\n")
+	if crlf {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	if original == "" && content != "" {
+		return nil, fmt.Errorf("failed to apply patch to %s: patching an empty file is not supported", fp.oldPath)
+	}
+	return &patchedFile{path: fp.oldPath, original: original, content: content}, nil
+}
+
+// applyHunks applies hunks in order. Each hunk is first tried at the line its header declares (shifted by the
+// drift observed for previous hunks), then at the nearest position where its context matches.
+func applyHunks(lines []string, fp *filePatch) ([]string, error) {
+	result := make([]string, 0, len(lines))
+	cursor, delta := 0, 0
+	for i, h := range fp.hunks {
+		expected := h.oldStart - 1 + delta
+		if len(h.oldLines) == 0 {
+			// pure insertion: "-l,0" means insert after line l
+			expected = h.oldStart + delta
+		}
+		pos := locateHunk(lines, h.oldLines, cursor, expected)
+		if pos < 0 {
+			return nil, fmt.Errorf("failed to apply hunk %d to %s: context lines do not match the file content near line %d, "+
+				"please read the file again and regenerate the patch", i+1, fp.oldPath, h.oldStart)
+		}
+		result = append(result, lines[cursor:pos]...)
+		result = append(result, h.newLines...)
+		cursor = pos + len(h.oldLines)
+		// hunks are located in original line numbers, so only the drift from the declared position carries over
+		delta = pos - expected + delta
+	}
+	return append(result, lines[cursor:]...), nil
+}
+
+// locateHunk returns the position nearest to expected, not before minPos, where target matches lines, or -1.
+func locateHunk(lines, target []string, minPos, expected int) int {
+	maxPos := len(lines) - len(target)
+	if expected < minPos {
+		expected = minPos
+	}
+	if expected > maxPos {
+		expected = maxPos
+	}
+	for dist := 0; ; dist++ {
+		before, after := expected-dist, expected+dist
+		if before < minPos && after > maxPos {
+			return -1
+		}
+		if before >= minPos && matchLinesAt(lines, target, before) {
+			return before
+		}
+		if after <= maxPos && matchLinesAt(lines, target, after) {
+			return after
+		}
+	}
+}
+
+func matchLinesAt(lines, target []string, pos int) bool {
+	for i, t := range target {
+		if lines[pos+i] != t {
+			return false
+		}
+	}
+	return true
+}
+
+// readWholeFile reads the entire file as is through ReadAllBackend.ReadAll if fs supports it, so that the content
+// matches the OldString of the Edit writing the patched file back.
+// Otherwise it's read through Backend.Read, stripping the "cat -n" style line number prefixes.
+func readWholeFile(ctx context.Context, fs filesystem.Backend, path string) (string, error) {
+	if rb, ok := fs.(filesystem.ReadAllBackend); ok {
+		return rb.ReadAll(ctx, &filesystem.ReadAllRequest{FilePath: path})
+	}
+
+	var lines []string
+	for offset := 0; ; offset += patchReadPageSize {
+		out, err := fs.Read(ctx, &filesystem.ReadRequest{
+			FilePath: path,
+			Offset:   offset,
+			Limit:    patchReadPageSize,
+		})
+		if err != nil {
+			return "", err
+		}
+		if out == "" {
+			break
+		}
+		page := strings.Split(out, "\n")
+		for _, l := range page {
+			if idx := strings.Index(l, "\t"); idx >= 0 {
+				l = l[idx+1:]
+			}
+			lines = append(lines, l)
+		}
+		if len(page) < patchReadPageSize {
+			break
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+)
+
+func patchInput(t *testing.T, patch string) string {
+	b, err := json.Marshal(applyPatchArgs{Patch: patch})
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestApplyPatchTool(t *testing.T) {
+	ctx := context.Background()
+	original := "package main\n\nimport \"fmt\"\n\nfunc a() {\n\tfmt.Println(\"a\")\n}\n\nfunc b() {\n\tfmt.Println(\"b\")\n}\n"
+
+	t.Run("two hunks", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/main.go", Content: original}))
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		patch := `--- a/main.go
++++ b/main.go
+@@ -5,3 +5,4 @@
+ func a() {
+-	fmt.Println("a")
++	fmt.Println("A")
++	fmt.Println("aa")
+ }
+@@ -9,3 +10,3 @@
+ func b() {
+-	fmt.Println("b")
++	fmt.Println("B")
+ }
+`
+		result, err := invokeTool(t, patchTool, patchInput(t, patch))
+		assert.NoError(t, err)
+		assert.Equal(t, "Successfully applied patch to /main.go", result)
+
+		content, err := readWholeFile(ctx, backend, "/main.go")
+		assert.NoError(t, err)
+		assert.Equal(t, "package main\n\nimport \"fmt\"\n\nfunc a() {\n\tfmt.Println(\"A\")\n\tfmt.Println(\"aa\")\n}\n\nfunc b() {\n\tfmt.Println(\"B\")\n}\n", content)
+	})
+
+	t.Run("hunk with wrong line numbers is located by context", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/main.go", Content: original}))
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		patch := `--- /main.go
++++ /main.go
+@@ -1,3 +1,3 @@
+ func b() {
+-	fmt.Println("b")
++	fmt.Println("B")
+ }
+`
+		_, err = invokeTool(t, patchTool, patchInput(t, patch))
+		assert.NoError(t, err)
+
+		content, err := readWholeFile(ctx, backend, "/main.go")
+		assert.NoError(t, err)
+		assert.Contains(t, content, "fmt.Println(\"B\")")
+	})
+
+	t.Run("stale context is rejected", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/main.go", Content: original}))
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		patch := `--- /main.go
++++ /main.go
+@@ -5,3 +5,3 @@
+ func a() {
+-	fmt.Println("a")
++	fmt.Println("A")
+ }
+@@ -9,3 +9,3 @@
+ func b() {
+-	fmt.Println("stale")
++	fmt.Println("B")
+ }
+`
+		_, err = invokeTool(t, patchTool, patchInput(t, patch))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to apply hunk 2 to /main.go: context lines do not match")
+
+		// nothing is written when any hunk fails
+		content, err := readWholeFile(ctx, backend, "/main.go")
+		assert.NoError(t, err)
+		assert.Equal(t, original, content)
+	})
+
+	t.Run("crlf file keeps its line endings", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/main.go", Content: strings.ReplaceAll(original, "\n", "\r\n")}))
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		patch := `--- a/main.go
++++ b/main.go
+@@ -9,3 +9,3 @@
+ func b() {
+-	fmt.Println("b")
++	fmt.Println("B")
+ }
+`
+		_, err = invokeTool(t, patchTool, patchInput(t, patch))
+		assert.NoError(t, err)
+
+		content, err := readWholeFile(ctx, backend, "/main.go")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.ReplaceAll(strings.Replace(original, `"b"`, `"B"`, 1), "\n", "\r\n"), content)
+	})
+
+	t.Run("create new file", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		patch := `--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++hello
++world
+`
+		_, err = invokeTool(t, patchTool, patchInput(t, patch))
+		assert.NoError(t, err)
+
+		content, err := readWholeFile(ctx, backend, "/new.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello\nworld", content)
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		backend := filesystem.NewInMemoryBackend()
+		patchTool, err := newApplyPatchTool(backend, nil)
+		assert.NoError(t, err)
+
+		_, err = invokeTool(t, patchTool, patchInput(t, "not a patch"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no file headers")
+
+		_, err = invokeTool(t, patchTool, patchInput(t, "--- /a.txt\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-a\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "deleting files is not supported")
+	})
+}
+
+// slowReadBackend delays reads, widening the window between the read and the write of a patch.
+type slowReadBackend struct {
+	*filesystem.InMemoryBackend
+}
+
+func (b *slowReadBackend) Read(ctx context.Context, req *filesystem.ReadRequest) (string, error) {
+	content, err := b.InMemoryBackend.Read(ctx, req)
+	time.Sleep(time.Millisecond)
+	return content, err
+}
+
+func (b *slowReadBackend) ReadAll(ctx context.Context, req *filesystem.ReadAllRequest) (string, error) {
+	content, err := b.InMemoryBackend.ReadAll(ctx, req)
+	time.Sleep(time.Millisecond)
+	return content, err
+}
+
+func TestApplyPatchToolConcurrent(t *testing.T) {
+	ctx := context.Background()
+	backend := &slowReadBackend{InMemoryBackend: filesystem.NewInMemoryBackend()}
+	const n = 20
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/shared.txt", Content: strings.Join(lines, "\n")}))
+	patchTool, err := newApplyPatchTool(backend, nil)
+	assert.NoError(t, err)
+
+	// each patch reads the whole file and writes it back, so they'd fail on a stale read if they interleaved
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			patch := fmt.Sprintf("--- a/shared.txt\n+++ b/shared.txt\n@@ -%d,1 +%d,1 @@\n-line %d\n+patched %d\n", i+1, i+1, i, i)
+			_, errs[i] = invokeTool(t, patchTool, patchInput(t, patch))
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	content, err := readWholeFile(ctx, backend, "/shared.txt")
+	assert.NoError(t, err)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("patched %d", i)
+	}
+	assert.Equal(t, strings.Join(lines, "\n"), content)
+}
+
+func TestApplyPatchToolLocksFileOnce(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+	assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/x/y.txt", Content: "a\n"}))
+	patchTool, err := newApplyPatchTool(backend, nil)
+	assert.NoError(t, err)
+
+	// both spellings of the path lock the same file, which must be locked once
+	done := make(chan error, 1)
+	go func() {
+		_, err := invokeTool(t, patchTool, patchInput(t, "--- /x/y.txt\n+++ /x//y.txt\n@@ -1,1 +1,1 @@\n-a\n+b\n"))
+		done <- err
+	}()
+	select {
+	case err = <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply_patch deadlocked locking the same file twice")
+	}
+
+	content, err := readWholeFile(ctx, backend, "/x/y.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "b\n", content)
+}
+
+func TestReadWholeFile(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+
+	lines := make([]string, 0, patchReadPageSize*2+5)
+	for i := 0; i < cap(lines); i++ {
+		lines = append(lines, "line\twith tab")
+	}
+	content := ""
+	for i, l := range lines {
+		if i > 0 {
+			content += "\n"
+		}
+		content += l
+	}
+	assert.NoError(t, backend.Write(ctx, &filesystem.WriteRequest{FilePath: "/big.txt", Content: content}))
+
+	got, err := readWholeFile(ctx, backend, "/big.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, content, got)
+}
@@ -38,9 +38,18 @@ Here are the first 10 lines of the result:
 Usage:
 - The path parameter must be an absolute path, not a relative path
 - The list_files tool will return a list of all files in the specified directory.
+- Directories are marked with a trailing slash (e.g. /dir/). Do not read a directory as a file.
 - This is very useful for exploring the file system and finding the right file to read or edit.
 - You should almost ALWAYS use this tool before using the Read or Edit tools.`
 
+	TreeToolDesc = `Shows the directory tree under a directory of the filesystem, as an indented list of its files and subdirectories.
+
+Usage:
+- The path parameter must be an absolute path, not a relative path
+- The depth parameter is the number of levels of subdirectories shown, 3 by default
+- Directories are marked with a trailing slash (e.g. dir/). Those deeper than depth aren't expanded, but show their number of entries
+- Use it first to get an overview of the layout of an unknown directory, e.g. a repository, instead of many ls calls`
+
 	ReadFileToolDesc = `Reads a file from the filesystem. You can access any file directly by using this tool.
 Assume this tool is able to read all files on the machine. If the User provides a path to a file assume that path is valid. It is okay to read a file that does not exist; an error will be returned.
 
@@ -57,6 +66,14 @@ Usage:
 - If you read a file that exists but has empty contents you will receive a system reminder warning in place of file contents.
 - You should ALWAYS make sure a file has been read before editing it.`
 
+	ReadWholeFileToolDesc = `Reads the whole content of a file from the filesystem in a single call.
+
+Usage:
+- The file_path parameter must be an absolute path, not a relative path
+- Use it instead of paging with read_file when you need a complete small or moderate file
+- The content is returned as is, without line numbers
+- Files above the size limit are refused, use read_file with offset and limit to read them`
+
 	EditFileToolDesc = `Performs exact string replacements in files.
 
 Usage:
@@ -67,6 +84,34 @@ Usage:
 - The edit will FAIL if 'old_string' is not unique in the file. Either provide a larger string with more surrounding context to make it unique or use 'replace_all' to change every instance of 'old_string'.
 - Use 'replace_all' for replacing and renaming strings across the file. This parameter is useful if you want to rename a variable for instance.`
 
+	CopyFileToolDesc = `Copies a file of the filesystem to another path.
+
+Usage:
+- The src_path and dst_path parameters must be absolute paths, not relative paths
+- Use it to duplicate a file, e.g. to back it up before a risky edit
+- The copy fails if dst_path already exists, unless overwrite is true`
+
+	ApplyPatchToolDesc = `Applies a patch in unified diff format to one or more files in the filesystem.
+
+Usage:
+- The patch parameter must be a unified diff, as produced by 'diff -u' or 'git diff'
+- Each file section starts with '--- <old path>' and '+++ <new path>' lines, file paths must be absolute
+- Each hunk starts with a '@@ -<old start>,<old count> +<new start>,<new count> @@' header
+- Hunk lines start with ' ' (context), '-' (removed) or '+' (added)
+- Use '--- /dev/null' as the old path to create a new file
+- Include a few unchanged context lines around each change so the hunk can be located reliably
+- The patch is rejected as a whole if any hunk's context or removed lines do not match the current file content. In that case, read the file again and regenerate the patch.
+- Prefer this tool over repeated edit_file calls when making several changes to the same file.
+
+Example:
+--- /src/main.py
++++ /src/main.py
+@@ -1,3 +1,3 @@
+ import os
+-print("hello")
++print("hello world")
+ exit(0)`
+
 	WriteFileToolDesc = `Writes to a new file in the filesystem.
 
 Usage:
@@ -97,8 +142,9 @@ Usage:
 - The glob parameter accepts a glob pattern to filter which files to search (e.g., '*.py')
 - The output_mode parameter controls the output format:
 - 'files_with_matches': List only file paths containing matches (default)
-- 'content': Show matching lines with file path and line numbers
+- 'content': Show matching lines as 'path:line:column:content', where column is the 1-based byte offset of the first match in the line
 - 'count': Show count of matches per file
+- The all_occurrences parameter makes 'content' and 'count' report every match rather than every matching line, so a line with several matches is shown, and counted, once per match (default is false)
 
 Examples:
 - Search all files: 'grep(pattern="TODO")'
@@ -149,19 +195,44 @@ Bad examples (avoid these):
 `
 
 	ToolsSystemPrompt = `
-# Filesystem Tools 'ls', 'read_file', 'write_file', 'edit_file', 'glob', 'grep'
+# Filesystem Tools 'ls', 'tree', 'read_file', 'write_file', 'edit_file', 'apply_patch', 'glob', 'grep'
 
 You have access to a filesystem which you can interact with using these tools.
 All file paths must start with a '/'.
 
 - ls: list files in a directory (requires absolute path)
+- tree: show the directory tree under a directory, to get an overview of its layout
 - read_file: read a file from the filesystem
 - write_file: write to a file in the filesystem
 - edit_file: edit a file in the filesystem
+- apply_patch: apply a unified diff to one or more files in the filesystem
 - glob: find files matching a pattern (e.g., "**/*.py")
 - grep: search for text within files
 `
 
+	ReadWholeFileToolsSystemPrompt = `- read_whole_file: read a complete small file in one call, without line numbers
+`
+
+	CopyFileToolsSystemPrompt = `- copy_file: copy a file to another path, e.g. to back it up before editing it
+`
+
+	NotesToolsSystemPrompt = `- add_note: append a note to your running notes file, e.g. a finding, a decision or an open question to remember
+- read_notes: read all your notes, oldest first
+Notes are your own scratchpad, distinct from todos: record what you learn as you work, and read your notes back before picking up a task.
+`
+
+	AddNoteToolDesc = `Appends a timestamped note to your running notes file.
+Use it to remember observations, findings, decisions and open questions across turns, e.g. the layout of a codebase or the cause of a bug.
+Keep each note short and self-contained. Notes can't be edited or removed.`
+
+	ReadNotesToolDesc = `Reads all the notes added with add_note, oldest first, each prefixed by the time it was added.`
+
+	noNotesMessage = "No notes yet."
+
+	fileTooLargeMessage = "File %s is %d bytes, above the %d bytes limit of read_whole_file. Use read_file with offset and limit to read it in parts."
+
+	executeTimeoutMessage = "[Command timed out after %s and was canceled]"
+
 	ExecuteToolsSystemPrompt = `
 # Execute Tool 'execute'
 
@@ -169,5 +240,15 @@ You have access to an 'execute' tool for running shell commands in a sandboxed e
 Use this tool to run commands, scripts, tests, builds, and other shell operations.
 
 - execute: run a shell command in the sandbox (returns output and exit code)
+`
+
+	// ScratchDirInstruction tells the model about the scratch directory of the run provisioned by NewScratchDirAgent.
+	// It can be appended to the instruction of a ChatModelAgent using the default GenModelInput,
+	// which replaces {scratch_dir} with the directory.
+	ScratchDirInstruction = `
+# Scratch Directory
+
+Your working directory for this run is {scratch_dir}. Write all scratch and intermediate files under it.
+It is private to this run, and is removed when the run ends.
 `
 )
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/internal/safe"
+)
+
+const (
+	// ScratchDirSessionKey is the session key under which NewScratchDirAgent stores the scratch directory of the run.
+	ScratchDirSessionKey = "scratch_dir"
+
+	defaultScratchDirRoot = "/scratch"
+)
+
+// ScratchDirConfig is the configuration for NewScratchDirAgent.
+type ScratchDirConfig struct {
+	// Backend is the backend the scratch files are written to, which removes the scratch directory at run end.
+	// required
+	Backend filesystem.RemovableBackend
+	// Root is the directory under which the scratch directories of the runs are created.
+	// optional, "/scratch" by default
+	Root string
+}
+
+// NewScratchDirAgent wraps agent so that each run gets its own scratch directory on the backend,
+// isolating the files of concurrent runs sharing the backend.
+//
+// The directory is a unique path under Root, stored in the session values under ScratchDirSessionKey,
+// so tools can get it with GetScratchDir, and instructions formatted with the session values can reference it
+// as {scratch_dir}, e.g. by appending ScratchDirInstruction to the instruction of a ChatModelAgent.
+// When the run ends, the directory is removed from the backend, unless the run is interrupted,
+// in which case it's kept for the resumed run, which reuses it.
+// The agent must be run through adk.Runner.
+func NewScratchDirAgent(_ context.Context, agent adk.Agent, config *ScratchDirConfig) (adk.ResumableAgent, error) {
+	if agent == nil {
+		return nil, errors.New("agent is required")
+	}
+	if config == nil || config.Backend == nil {
+		return nil, errors.New("backend is required")
+	}
+
+	root := config.Root
+	if root == "" {
+		root = defaultScratchDirRoot
+	}
+	return &scratchDirAgent{agent: agent, backend: config.Backend, root: root}, nil
+}
+
+// GetScratchDir returns the scratch directory of the current run provisioned by NewScratchDirAgent.
+func GetScratchDir(ctx context.Context) (string, bool) {
+	v, ok := adk.GetSessionValue(ctx, ScratchDirSessionKey)
+	if !ok {
+		return "", false
+	}
+	dir, ok := v.(string)
+	return dir, ok
+}
+
+type scratchDirAgent struct {
+	agent   adk.Agent
+	backend filesystem.RemovableBackend
+	root    string
+}
+
+func (a *scratchDirAgent) Name(ctx context.Context) string {
+	return a.agent.Name(ctx)
+}
+
+func (a *scratchDirAgent) Description(ctx context.Context) string {
+	return a.agent.Description(ctx)
+}
+
+func (a *scratchDirAgent) Run(ctx context.Context, input *adk.AgentInput, opts ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	dir := path.Join(a.root, uuid.NewString())
+	adk.AddSessionValue(ctx, ScratchDirSessionKey, dir)
+
+	return a.forwardAndCleanUp(ctx, dir, a.agent.Run(ctx, input, opts...))
+}
+
+func (a *scratchDirAgent) Resume(ctx context.Context, info *adk.ResumeInfo, opts ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	ra, ok := a.agent.(adk.ResumableAgent)
+	if !ok {
+		iter, generator := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+		generator.Send(&adk.AgentEvent{Err: fmt.Errorf("agent '%s' is not resumable", a.agent.Name(ctx))})
+		generator.Close()
+		return iter
+	}
+
+	// the session values, including the scratch directory, are restored from the checkpoint
+	dir, _ := GetScratchDir(ctx)
+	return a.forwardAndCleanUp(ctx, dir, ra.Resume(ctx, info, opts...))
+}
+
+func (a *scratchDirAgent) forwardAndCleanUp(ctx context.Context, dir string,
+	iter *adk.AsyncIterator[*adk.AgentEvent]) *adk.AsyncIterator[*adk.AgentEvent] {
+
+	ret, generator := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				generator.Send(&adk.AgentEvent{Err: safe.NewPanicErr(panicErr, debug.Stack())})
+			}
+			generator.Close()
+		}()
+
+		var lastEvent *adk.AgentEvent
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			generator.Send(event)
+			lastEvent = event
+		}
+
+		if dir == "" || (lastEvent != nil && lastEvent.Action != nil && lastEvent.Action.Interrupted != nil) {
+			return
+		}
+		if err := a.backend.Remove(ctx, &filesystem.RemoveRequest{Path: dir}); err != nil {
+			generator.Send(&adk.AgentEvent{Err: fmt.Errorf("failed to remove scratch dir %s: %w", dir, err)})
+		}
+	}()
+	return ret
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// scratchWriterAgent writes a file to the scratch dir of its run, and waits for the other runs to write theirs.
+type scratchWriterAgent struct {
+	backend filesystem.Backend
+	written *sync.WaitGroup
+}
+
+func (s *scratchWriterAgent) Name(_ context.Context) string {
+	return "ScratchWriter"
+}
+
+func (s *scratchWriterAgent) Description(_ context.Context) string {
+	return "writes scratch files"
+}
+
+func (s *scratchWriterAgent) Run(ctx context.Context, _ *adk.AgentInput, _ ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iter, generator := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	go func() {
+		defer generator.Close()
+
+		dir, ok := GetScratchDir(ctx)
+		if !ok {
+			generator.Send(&adk.AgentEvent{Err: assert.AnError})
+			return
+		}
+		err := s.backend.Write(ctx, &filesystem.WriteRequest{FilePath: dir + "/notes.txt", Content: dir})
+		s.written.Done()
+		if err != nil {
+			generator.Send(&adk.AgentEvent{Err: err})
+			return
+		}
+		s.written.Wait()
+		generator.Send(adk.EventFromMessage(schema.AssistantMessage(dir, nil), nil, schema.Assistant, ""))
+	}()
+	return iter
+}
+
+func TestScratchDirAgent_ConcurrentRuns(t *testing.T) {
+	ctx := context.Background()
+	backend := filesystem.NewInMemoryBackend()
+	written := &sync.WaitGroup{}
+	written.Add(2)
+
+	agent, err := NewScratchDirAgent(ctx, &scratchWriterAgent{backend: backend, written: written},
+		&ScratchDirConfig{Backend: backend})
+	assert.NoError(t, err)
+	runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent})
+
+	dirs := make([]string, 2)
+	var wg sync.WaitGroup
+	for i := range dirs {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iter := runner.Query(ctx, "take notes")
+			for {
+				event, ok := iter.Next()
+				if !ok {
+					break
+				}
+				if !assert.NoError(t, event.Err) {
+					continue
+				}
+				dirs[i] = event.Output.MessageOutput.Message.Content
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, strings.HasPrefix(dirs[0], "/scratch/"))
+	assert.True(t, strings.HasPrefix(dirs[1], "/scratch/"))
+	assert.NotEqual(t, dirs[0], dirs[1])
+
+	infos, err := backend.GlobInfo(ctx, &filesystem.GlobInfoRequest{Pattern: "**", Path: "/"})
+	assert.NoError(t, err)
+	assert.Empty(t, infos)
+}
+
+func TestScratchDirAgent_Instruction(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var systemPrompt string
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			systemPrompt = input[0].Content
+			return schema.AssistantMessage("done", nil), nil
+		}).Times(1)
+
+	chatAgent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with a scratch dir",
+		Instruction: "You are a helpful assistant." + ScratchDirInstruction,
+		Model:       cm,
+	})
+	assert.NoError(t, err)
+	agent, err := NewScratchDirAgent(ctx, chatAgent, &ScratchDirConfig{Backend: filesystem.NewInMemoryBackend(), Root: "/tmp"})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "hello")
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+	assert.Regexp(t, `Your working directory for this run is /tmp/[0-9a-f-]{36}\.`, systemPrompt)
+}
+
+func TestNewScratchDirAgent_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewScratchDirAgent(ctx, nil, &ScratchDirConfig{Backend: filesystem.NewInMemoryBackend()})
+	assert.Error(t, err)
+	_, err = NewScratchDirAgent(ctx, &scratchWriterAgent{}, &ScratchDirConfig{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// coalesceStream merges the chunks of sr, emitting the buffered content once interval elapsed since its first chunk,
+// or once it reaches maxSize bytes, whichever comes first. A non-positive interval or maxSize disables that trigger.
+func coalesceStream(sr *schema.StreamReader[string], interval time.Duration, maxSize int) *schema.StreamReader[string] {
+	type recvResult struct {
+		chunk string
+		err   error
+	}
+	results := make(chan recvResult)
+	done := make(chan struct{})
+	go func() {
+		defer sr.Close()
+		for {
+			chunk, err := sr.Recv()
+			select {
+			case results <- recvResult{chunk: chunk, err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	out, sw := schema.Pipe[string](10)
+	go func() {
+		defer func() {
+			e := recover()
+			if e != nil {
+				sw.Send("", fmt.Errorf("panic: %v,\n stack: %s", e, string(debug.Stack())))
+			}
+			close(done)
+			sw.Close()
+		}()
+
+		var (
+			buf   strings.Builder
+			timer *time.Timer
+			tick  <-chan time.Time
+		)
+		// flush reports whether the consumer closed the stream
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, tick = nil, nil
+			}
+			if buf.Len() == 0 {
+				return false
+			}
+			closed := sw.Send(buf.String(), nil)
+			buf.Reset()
+			return closed
+		}
+
+		for {
+			select {
+			case r := <-results:
+				if r.err == io.EOF {
+					flush()
+					return
+				}
+				if r.err != nil {
+					if !flush() {
+						sw.Send("", r.err)
+					}
+					return
+				}
+				buf.WriteString(r.chunk)
+				if maxSize > 0 && buf.Len() >= maxSize {
+					if flush() {
+						return
+					}
+				} else if interval > 0 && timer == nil {
+					timer = time.NewTimer(interval)
+					tick = timer.C
+				}
+			case <-tick:
+				timer, tick = nil, nil
+				if flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package finish provides a middleware that gives the agent an explicit tool to finish the task.
+package finish
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const defaultToolName = "finish"
+
+// Config is the configuration for the finish middleware.
+type Config struct {
+	// ToolName is the custom name for the finish tool. If nil, the default name "finish" is used.
+	ToolName *string
+	// CustomToolDesc overrides the default description of the finish tool.
+	CustomToolDesc *string
+	// CustomSystemPrompt overrides the default instruction appended to the agent's instruction.
+	// Set it to an empty string to add no instruction at all.
+	CustomSystemPrompt *string
+}
+
+type finishArgs struct {
+	FinalAnswer string `json:"final_answer" jsonschema:"description=the final answer to return to the user"`
+}
+
+// NewMiddleware creates a middleware that adds a finish tool to the agent.
+// When the model calls the tool, the agent emits an Exit action and stops,
+// and the tool's final answer becomes the last message of the run.
+func NewMiddleware(ctx context.Context, config *Config) (adk.AgentMiddleware, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	name := defaultToolName
+	if config.ToolName != nil {
+		name = *config.ToolName
+	}
+	desc := toolDesc
+	if config.CustomToolDesc != nil {
+		desc = *config.CustomToolDesc
+	}
+	instruction := strings.ReplaceAll(systemPrompt, "{tool_name}", name)
+	if config.CustomSystemPrompt != nil {
+		instruction = *config.CustomSystemPrompt
+	}
+
+	t, err := utils.InferTool(name, desc, func(ctx context.Context, input finishArgs) (string, error) {
+		if err := adk.SendToolGenAction(ctx, name, adk.NewExitAction()); err != nil {
+			return "", err
+		}
+		return input.FinalAnswer, nil
+	})
+	if err != nil {
+		return adk.AgentMiddleware{}, err
+	}
+
+	return adk.AgentMiddleware{
+		AdditionalInstruction:    instruction,
+		AdditionalTools:          []tool.BaseTool{t},
+		AdditionalReturnDirectly: map[string]bool{name: true},
+	}, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package finish
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestFinishMiddleware(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).DoAndReturn(func(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+		assert.Len(t, tools, 1)
+		assert.Equal(t, "done", tools[0].Name)
+		return cm, nil
+	}).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Contains(t, input[0].Content, "'done' tool")
+			return schema.AssistantMessage("", []schema.ToolCall{
+				{
+					ID: "tool-call-1",
+					Function: schema.FunctionCall{
+						Name:      "done",
+						Arguments: `{"final_answer": "the answer is 42"}`,
+					},
+				},
+			}), nil
+		}).Times(1)
+
+	name := "done"
+	mw, err := NewMiddleware(ctx, &Config{ToolName: &name})
+	assert.NoError(t, err)
+
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with finish tool",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		Middlewares: []adk.AgentMiddleware{mw},
+	})
+	assert.NoError(t, err)
+
+	iterator := agent.Run(ctx, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("what is the answer?")}})
+
+	event, ok := iterator.Next()
+	assert.True(t, ok)
+	assert.Nil(t, event.Err)
+	assert.Equal(t, schema.Assistant, event.Output.MessageOutput.Role)
+
+	event, ok = iterator.Next()
+	assert.True(t, ok)
+	assert.Nil(t, event.Err)
+	assert.Equal(t, schema.Tool, event.Output.MessageOutput.Role)
+	assert.NotNil(t, event.Action)
+	assert.True(t, event.Action.Exit)
+	assert.Equal(t, "the answer is 42", event.Output.MessageOutput.Message.Content)
+
+	_, ok = iterator.Next()
+	assert.False(t, ok)
+}
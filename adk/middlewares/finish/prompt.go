@@ -0,0 +1,30 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package finish
+
+const (
+	toolDesc = `Finish the task and return the final answer to the user.
+Call this tool only once, when the task is complete. The agent stops right after this tool is called.`
+
+	systemPrompt = `
+# Finishing the Task
+
+When you have completed the task, call the '{tool_name}' tool with your complete final answer.
+- The final answer is returned to the user as is, so make it self-contained
+- Do not call any other tool in the same response as '{tool_name}'
+`
+)
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pagination provides a middleware that lets tools return large results page by page.
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/slongfield/pyfmt"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultNextPageToolName = "next_page"
+
+	// sessionKeyPrefix prefixes the session keys under which page tokens are stored.
+	sessionKeyPrefix = "_eino_pagination_"
+)
+
+// Page is a single chunk of a paginated tool result.
+type Page struct {
+	// Content is the content of this page.
+	Content string
+	// NextPageToken identifies the next page. Empty if this is the last page.
+	NextPageToken string
+}
+
+// PaginatedTool is a tool whose result is returned page by page.
+type PaginatedTool interface {
+	tool.BaseTool
+	// FetchPage returns the page identified by pageToken for the given arguments.
+	// pageToken is empty for the first page, and otherwise is the NextPageToken of a previously returned Page.
+	FetchPage(ctx context.Context, argumentsInJSON string, pageToken string, opts ...tool.Option) (*Page, error)
+}
+
+// Config is the configuration for the pagination middleware.
+type Config struct {
+	// Tools are the paginated tools to add to the agent.
+	// required
+	Tools []PaginatedTool
+	// NextPageToolName is the custom name for the next page tool.
+	// optional, "next_page" by default
+	NextPageToolName *string
+	// CustomNextPageToolDesc overrides the default description of the next page tool.
+	// optional
+	CustomNextPageToolDesc *string
+}
+
+// NewMiddleware creates a middleware that adds the given paginated tools and a generic next page tool to the agent.
+//
+// A paginated tool returns its first page when called by the model. If more pages are available,
+// the result ends with a page token, and the model calls the next page tool with that token to
+// fetch the following page. Page tokens are stored in the run's session values, so they are
+// only valid within the run that produced them, and the agent must be run through adk.Runner.
+func NewMiddleware(ctx context.Context, config *Config) (adk.AgentMiddleware, error) {
+	if config == nil {
+		return adk.AgentMiddleware{}, errors.New("config is required")
+	}
+	if len(config.Tools) == 0 {
+		return adk.AgentMiddleware{}, errors.New("at least one paginated tool is required")
+	}
+
+	nextPageToolName := defaultNextPageToolName
+	if config.NextPageToolName != nil {
+		nextPageToolName = *config.NextPageToolName
+	}
+	nextPageToolDesc := NextPageToolDesc
+	if config.CustomNextPageToolDesc != nil {
+		nextPageToolDesc = *config.CustomNextPageToolDesc
+	}
+
+	p := &pager{
+		nextPageToolName: nextPageToolName,
+		tools:            make(map[string]PaginatedTool, len(config.Tools)),
+	}
+	tools := make([]tool.BaseTool, 0, len(config.Tools)+1)
+	for _, t := range config.Tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return adk.AgentMiddleware{}, fmt.Errorf("failed to get info of paginated tool: %w", err)
+		}
+		if _, ok := p.tools[info.Name]; ok {
+			return adk.AgentMiddleware{}, fmt.Errorf("duplicate paginated tool: %s", info.Name)
+		}
+		p.tools[info.Name] = t
+		tools = append(tools, &firstPageTool{p: p, t: t, name: info.Name})
+	}
+
+	nextPageTool, err := utils.InferTool(nextPageToolName, nextPageToolDesc, p.nextPage)
+	if err != nil {
+		return adk.AgentMiddleware{}, err
+	}
+	tools = append(tools, nextPageTool)
+
+	return adk.AgentMiddleware{
+		AdditionalInstruction: pyfmt.Must(systemPrompt, map[string]any{"next_page_tool_name": nextPageToolName}),
+		AdditionalTools:       tools,
+	}, nil
+}
+
+// pageState is what a page token refers to. It is stored in the session as a JSON string, so that
+// session values stay serializable for checkpoints.
+type pageState struct {
+	ToolName  string `json:"tool_name"`
+	Arguments string `json:"arguments"`
+	PageToken string `json:"page_token"`
+}
+
+type pager struct {
+	nextPageToolName string
+	tools            map[string]PaginatedTool
+}
+
+type nextPageArgs struct {
+	PageToken string `json:"page_token" jsonschema:"description=the page token returned at the end of the previous page"`
+}
+
+func (p *pager) nextPage(ctx context.Context, input nextPageArgs) (string, error) {
+	v, ok := adk.GetSessionValue(ctx, sessionKeyPrefix+input.PageToken)
+	if !ok {
+		return "", fmt.Errorf("page token %q is unknown or has expired", input.PageToken)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid page state for page token %q: %T", input.PageToken, v)
+	}
+	state := &pageState{}
+	if err := sonic.UnmarshalString(s, state); err != nil {
+		return "", fmt.Errorf("invalid page state for page token %q: %w", input.PageToken, err)
+	}
+	t, ok := p.tools[state.ToolName]
+	if !ok {
+		return "", fmt.Errorf("paginated tool %s not found", state.ToolName)
+	}
+
+	page, err := t.FetchPage(ctx, state.Arguments, state.PageToken)
+	if err != nil {
+		return "", err
+	}
+	return p.render(ctx, state.ToolName, state.Arguments, page)
+}
+
+// render stores the page token of the next page, if any, and formats the page for the model.
+func (p *pager) render(ctx context.Context, toolName, arguments string, page *Page) (string, error) {
+	if page == nil {
+		return "", fmt.Errorf("paginated tool %s returned a nil page", toolName)
+	}
+	if page.NextPageToken == "" {
+		return page.Content, nil
+	}
+
+	s, err := sonic.MarshalString(&pageState{
+		ToolName:  toolName,
+		Arguments: arguments,
+		PageToken: page.NextPageToken,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal page state: %w", err)
+	}
+	token := uuid.NewString()
+	adk.AddSessionValue(ctx, sessionKeyPrefix+token, s)
+
+	return page.Content + pyfmt.Must(nextPageHint, map[string]any{
+		"next_page_tool_name": p.nextPageToolName,
+		"page_token":          token,
+	}), nil
+}
+
+// firstPageTool exposes a PaginatedTool to the model, returning its first page.
+type firstPageTool struct {
+	p    *pager
+	t    PaginatedTool
+	name string
+}
+
+func (f *firstPageTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return f.t.Info(ctx)
+}
+
+func (f *firstPageTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	page, err := f.t.FetchPage(ctx, argumentsInJSON, "", opts...)
+	if err != nil {
+		return "", err
+	}
+	return f.p.render(ctx, f.name, argumentsInJSON, page)
+}
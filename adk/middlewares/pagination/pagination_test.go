@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type listTool struct {
+	pages []string
+	calls []string
+}
+
+func (l *listTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "list_items", Desc: "list items"}, nil
+}
+
+func (l *listTool) FetchPage(_ context.Context, argumentsInJSON string, pageToken string, _ ...tool.Option) (*Page, error) {
+	l.calls = append(l.calls, argumentsInJSON+"|"+pageToken)
+	idx := 0
+	if pageToken != "" {
+		var err error
+		idx, err = strconv.Atoi(pageToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+	page := &Page{Content: l.pages[idx]}
+	if idx+1 < len(l.pages) {
+		page.NextPageToken = strconv.Itoa(idx + 1)
+	}
+	return page, nil
+}
+
+var pageTokenRegexp = regexp.MustCompile(`page_token="([^"]+)"`)
+
+func TestPagination(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lt := &listTool{pages: []string{"a, b", "c, d", "e"}}
+	mw, err := NewMiddleware(ctx, &Config{Tools: []PaginatedTool{lt}})
+	assert.NoError(t, err)
+
+	var toolResults []string
+	step := 0
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			step++
+			last := input[len(input)-1]
+			if last.Role == schema.Tool {
+				toolResults = append(toolResults, last.Content)
+			}
+			switch step {
+			case 1:
+				return schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       "call-1",
+					Function: schema.FunctionCall{Name: "list_items", Arguments: `{"q":"x"}`},
+				}}), nil
+			case 2, 3:
+				m := pageTokenRegexp.FindStringSubmatch(last.Content)
+				if !assert.Len(t, m, 2) {
+					return nil, fmt.Errorf("no page token in %q", last.Content)
+				}
+				return schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       fmt.Sprintf("call-%d", step),
+					Function: schema.FunctionCall{Name: "next_page", Arguments: fmt.Sprintf(`{"page_token":%q}`, m[1])},
+				}}), nil
+			default:
+				return schema.AssistantMessage("a, b, c, d, e", nil), nil
+			}
+		}).Times(4)
+
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with paginated tool",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		Middlewares: []adk.AgentMiddleware{mw},
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "list all items")
+	var lastEvent *adk.AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		lastEvent = event
+	}
+
+	assert.Equal(t, "a, b, c, d, e", lastEvent.Output.MessageOutput.Message.Content)
+	assert.Len(t, toolResults, 3)
+	assert.Regexp(t, `^a, b\n\n\[More results available\. Call the 'next_page' tool with page_token="[^"]+"`, toolResults[0])
+	assert.Regexp(t, `^c, d\n\n\[More results available`, toolResults[1])
+	assert.Equal(t, "e", toolResults[2])
+	assert.Equal(t, []string{`{"q":"x"}|`, `{"q":"x"}|1`, `{"q":"x"}|2`}, lt.calls)
+}
+
+func TestPagination_UnknownToken(t *testing.T) {
+	ctx := context.Background()
+	p := &pager{nextPageToolName: defaultNextPageToolName, tools: map[string]PaginatedTool{}}
+	_, err := p.nextPage(ctx, nextPageArgs{PageToken: "missing"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown or has expired")
+}
+
+func TestNewMiddleware_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewMiddleware(ctx, nil)
+	assert.Error(t, err)
+	_, err = NewMiddleware(ctx, &Config{})
+	assert.Error(t, err)
+	_, err = NewMiddleware(ctx, &Config{Tools: []PaginatedTool{&listTool{}, &listTool{}}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate paginated tool")
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagination
+
+const (
+	NextPageToolDesc = `Fetches the next page of a paginated tool result.
+
+Usage:
+- The page_token parameter must be the page token shown at the end of the previous page
+- Each page token can be used to fetch exactly the page that follows the page it was returned with
+- When a page does not end with a page token, it is the last page`
+
+	systemPrompt = `
+# Paginated Tool Results
+
+Some tools return their results page by page. When a tool result ends with a page token,
+more results are available and you can fetch the next page with the '{next_page_tool_name}' tool.
+Only fetch further pages when the results you already have are not sufficient.
+`
+
+	nextPageHint = `
+
+[More results available. Call the '{next_page_tool_name}' tool with page_token="{page_token}" to fetch the next page.]`
+)
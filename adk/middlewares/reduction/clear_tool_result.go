@@ -19,11 +19,27 @@ package reduction
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
 	"github.com/cloudwego/eino/schema"
 )
 
+const (
+	RetrieveToolResultToolDesc = `Retrieves the original content of an earlier tool result that was cleared from the conversation.
+
+Usage:
+- The tool_call_id parameter must be the tool call id shown in the cleared tool result
+- Only use this tool when the content of the earlier tool result is needed again`
+
+	defaultRetrieveToolName    = "retrieve_tool_result"
+	stashedToolResultKeyPrefix = "_eino_cleared_tool_result_"
+	stashedToolResultHint      = ` (call the '%s' tool with tool_call_id="%s" to retrieve it)`
+)
+
 // ClearToolResultConfig configures the tool result clearing middleware.
 // This middleware clears old tool results when their total token count exceeds a threshold,
 // while protecting recent messages within a token budget.
@@ -46,11 +62,22 @@ type ClearToolResultConfig struct {
 	ClearToolResultPlaceholder string
 
 	// TokenCounter is a custom function to estimate token count for a message.
+	// Its results are cached by the content of the messages, so that it's called once per distinct message.
 	// If nil, uses the default counter (character count / 4).
 	TokenCounter func(msg *schema.Message) int
 
 	// ExcludeTools is a list of tool names whose results should never be cleared.
 	ExcludeTools []string
+
+	// StashClearedResults keeps the original content of cleared tool results in the session values,
+	// keyed by tool call id, and adds a tool for the agent to retrieve it, e.g. to explain an earlier step.
+	// The agent must be run through adk.Runner for the session values to be available.
+	// If false, cleared tool results are discarded.
+	StashClearedResults bool
+
+	// RetrieveToolName is the name of the tool retrieving stashed tool results.
+	// If empty, defaults to "retrieve_tool_result".
+	RetrieveToolName string
 }
 
 // NewClearToolResult creates a new middleware that clears old tool results
@@ -59,12 +86,19 @@ type ClearToolResultConfig struct {
 // Deprecated: Use NewToolResultMiddleware instead, which combines clearing
 // and offloading strategies for better tool result management.
 func NewClearToolResult(ctx context.Context, config *ClearToolResultConfig) (adk.AgentMiddleware, error) {
+	bc, tools, err := newClearToolResult(ctx, config)
+	if err != nil {
+		return adk.AgentMiddleware{}, err
+	}
 	return adk.AgentMiddleware{
-		BeforeChatModel: newClearToolResult(ctx, config),
+		BeforeChatModel: bc,
+		AdditionalTools: tools,
 	}, nil
 }
 
-func newClearToolResult(ctx context.Context, config *ClearToolResultConfig) func(ctx context.Context, state *adk.ChatModelAgentState) error {
+func newClearToolResult(ctx context.Context, config *ClearToolResultConfig) (
+	func(ctx context.Context, state *adk.ChatModelAgentState) error, []tool.BaseTool, error) {
+
 	if config == nil {
 		config = &ClearToolResultConfig{}
 	}
@@ -86,13 +120,46 @@ func newClearToolResult(ctx context.Context, config *ClearToolResultConfig) func
 	}
 
 	// Set token estimator
-	counter := config.TokenCounter
-	if counter == nil {
-		counter = defaultTokenCounter
+	counter := defaultTokenCounter
+	if config.TokenCounter != nil {
+		counter = newCachedTokenCounter(config.TokenCounter).count
 	}
-	return func(ctx context.Context, state *adk.ChatModelAgentState) error {
-		return reduceByTokens(state, toolResultTokenThreshold, keepRecentTokens, placeholder, counter, config.ExcludeTools)
+	if !config.StashClearedResults {
+		return func(ctx context.Context, state *adk.ChatModelAgentState) error {
+			return reduceByTokens(state, toolResultTokenThreshold, keepRecentTokens, placeholder, counter, config.ExcludeTools, nil)
+		}, nil, nil
 	}
+
+	toolName := config.RetrieveToolName
+	if toolName == "" {
+		toolName = defaultRetrieveToolName
+	}
+	retrieveTool, err := newRetrieveToolResultTool(toolName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(ctx context.Context, state *adk.ChatModelAgentState) error {
+		return reduceByTokens(state, toolResultTokenThreshold, keepRecentTokens, placeholder, counter, config.ExcludeTools,
+			func(msg *schema.Message) {
+				adk.AddSessionValue(ctx, stashedToolResultKeyPrefix+msg.ToolCallID, msg.Content)
+				msg.Content = placeholder + fmt.Sprintf(stashedToolResultHint, toolName, msg.ToolCallID)
+			})
+	}, []tool.BaseTool{retrieveTool}, nil
+}
+
+type retrieveToolResultArgs struct {
+	ToolCallID string `json:"tool_call_id"`
+}
+
+func newRetrieveToolResultTool(name string) (tool.BaseTool, error) {
+	return utils.InferTool(name, RetrieveToolResultToolDesc, func(ctx context.Context, input retrieveToolResultArgs) (string, error) {
+		v, ok := adk.GetSessionValue(ctx, stashedToolResultKeyPrefix+input.ToolCallID)
+		if !ok {
+			return "", fmt.Errorf("no cleared tool result found for tool_call_id %q", input.ToolCallID)
+		}
+		content, _ := v.(string)
+		return content, nil
+	})
 }
 
 // defaultTokenCounter estimates token count using character count / 4
@@ -113,7 +180,9 @@ func defaultTokenCounter(msg *schema.Message) int {
 // It clears old tool results when:
 // 1. The total tokens of all tool results exceed toolResultTokenThreshold
 // 2. Only tool results outside the keepRecentTokens range (from the end) are cleared
-func reduceByTokens(state *adk.ChatModelAgentState, toolResultTokenThreshold, keepRecentTokens int, placeholder string, counter func(*schema.Message) int, excludedTools []string) error {
+// A tool result is cleared by clear, which replaces its content by placeholder if nil.
+func reduceByTokens(state *adk.ChatModelAgentState, toolResultTokenThreshold, keepRecentTokens int, placeholder string,
+	counter func(*schema.Message) int, excludedTools []string, clear func(msg *schema.Message)) error {
 	if len(state.Messages) == 0 {
 		return nil
 	}
@@ -121,7 +190,7 @@ func reduceByTokens(state *adk.ChatModelAgentState, toolResultTokenThreshold, ke
 	// Step 1: Calculate total tool result tokens
 	totalToolResultTokens := 0
 	for _, msg := range state.Messages {
-		if msg.Role == schema.Tool && msg.Content != placeholder {
+		if msg.Role == schema.Tool && !strings.HasPrefix(msg.Content, placeholder) {
 			totalToolResultTokens += counter(msg)
 		}
 	}
@@ -150,8 +219,12 @@ func reduceByTokens(state *adk.ChatModelAgentState, toolResultTokenThreshold, ke
 	// Step 3: Clear tool results outside the protected range (before recentStartIdx)
 	for i := 0; i < recentStartIdx; i++ {
 		msg := state.Messages[i]
-		if msg.Role == schema.Tool && msg.Content != placeholder && !excluded(msg.ToolName, excludedTools) {
-			msg.Content = placeholder
+		if msg.Role == schema.Tool && !strings.HasPrefix(msg.Content, placeholder) && !excluded(msg.ToolName, excludedTools) {
+			if clear != nil {
+				clear(msg)
+			} else {
+				msg.Content = placeholder
+			}
 		}
 	}
 
@@ -23,8 +23,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -256,7 +263,7 @@ func Test_reduceByTokens(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := reduceByTokens(tt.args.state, tt.args.toolResultTokenThreshold, tt.args.keepRecentTokens, tt.args.placeholder, tt.args.estimator, []string{})
+			err := reduceByTokens(tt.args.state, tt.args.toolResultTokenThreshold, tt.args.keepRecentTokens, tt.args.placeholder, tt.args.estimator, []string{}, nil)
 			tt.wantErr(t, err, fmt.Sprintf("reduceByTokens(%v, %v, %v, %v)", tt.args.state, tt.args.toolResultTokenThreshold, tt.args.keepRecentTokens, tt.args.placeholder))
 			if tt.validateState != nil {
 				tt.validateState(t, tt.args.state)
@@ -269,7 +276,8 @@ func Test_newClearToolResult(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("nil config uses defaults", func(t *testing.T) {
-		fn := newClearToolResult(ctx, nil)
+		fn, _, err := newClearToolResult(ctx, nil)
+		assert.NoError(t, err)
 		assert.NotNil(t, fn)
 
 		// Test that function works with nil config (uses defaults)
@@ -279,14 +287,15 @@ func Test_newClearToolResult(t *testing.T) {
 				schema.ToolMessage("short result", "call-1", schema.WithToolName("tool1")),
 			},
 		}
-		err := fn(ctx, state)
+		err = fn(ctx, state)
 		assert.NoError(t, err)
 		// Default threshold is 20000, so short result should not be cleared
 		assert.Equal(t, "short result", state.Messages[1].Content)
 	})
 
 	t.Run("empty config uses defaults", func(t *testing.T) {
-		fn := newClearToolResult(ctx, &ClearToolResultConfig{})
+		fn, _, err := newClearToolResult(ctx, &ClearToolResultConfig{})
+		assert.NoError(t, err)
 		assert.NotNil(t, fn)
 
 		state := &adk.ChatModelAgentState{
@@ -295,8 +304,124 @@ func Test_newClearToolResult(t *testing.T) {
 				schema.ToolMessage("short result", "call-1", schema.WithToolName("tool1")),
 			},
 		}
-		err := fn(ctx, state)
+		err = fn(ctx, state)
 		assert.NoError(t, err)
 		assert.Equal(t, "short result", state.Messages[1].Content)
 	})
 }
+
+func TestClearToolResult_StashAndRetrieve(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mw, err := NewToolResultMiddleware(ctx, &ToolResultConfig{
+		ClearingTokenThreshold: 1,
+		KeepRecentTokens:       1,
+		Backend:                filesystem.NewInMemoryBackend(),
+		StashClearedResults:    true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, mw.AdditionalTools, 1)
+
+	lookup, err := utils.InferTool("lookup", "look up a key", func(ctx context.Context, input struct {
+		Key string `json:"key"`
+	}) (string, error) {
+		return "value of " + input.Key, nil
+	})
+	assert.NoError(t, err)
+
+	toolCall := func(id, name, args string) *schema.Message {
+		return schema.AssistantMessage("", []schema.ToolCall{{ID: id, Function: schema.FunctionCall{Name: name, Arguments: args}}})
+	}
+	var clearedContent, retrievedContent string
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	gomock.InOrder(
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(toolCall("call-1", "lookup", `{"key":"a"}`), nil),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(toolCall("call-2", "lookup", `{"key":"b"}`), nil),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				for _, m := range input {
+					if m.Role == schema.Tool && m.ToolCallID == "call-1" {
+						clearedContent = m.Content
+					}
+				}
+				return toolCall("call-3", "retrieve_tool_result", `{"tool_call_id":"call-1"}`), nil
+			}),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				retrievedContent = input[len(input)-1].Content
+				return schema.AssistantMessage("done", nil), nil
+			}),
+	)
+
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with stashed tool results",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{lookup}},
+		},
+		Middlewares: []adk.AgentMiddleware{mw},
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "look up a and b")
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, `[Old tool result content cleared] (call the 'retrieve_tool_result' tool with tool_call_id="call-1" to retrieve it)`, clearedContent)
+	assert.Equal(t, "value of a", retrievedContent)
+}
+
+func TestRetrieveToolResultTool_NotFound(t *testing.T) {
+	ctx := context.Background()
+	rt, err := newRetrieveToolResultTool(defaultRetrieveToolName)
+	assert.NoError(t, err)
+	_, err = rt.(tool.InvokableTool).InvokableRun(ctx, `{"tool_call_id":"missing"}`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no cleared tool result found")
+}
+
+func TestClearToolResult_CachedTokenCounter(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fn, _, err := newClearToolResult(ctx, &ClearToolResultConfig{
+		ToolResultTokenThreshold: 1,
+		KeepRecentTokens:         1000,
+		TokenCounter: func(msg *schema.Message) int {
+			calls++
+			return defaultTokenCounter(msg)
+		},
+	})
+	assert.NoError(t, err)
+
+	var history []func() adk.Message
+	history = append(history, func() adk.Message { return schema.UserMessage("list the files") })
+	for turn := 0; turn < 3; turn++ {
+		callID := fmt.Sprintf("call-%d", turn)
+		history = append(history,
+			func() adk.Message {
+				return schema.AssistantMessage("", []schema.ToolCall{{ID: callID, Function: schema.FunctionCall{Name: "ls"}}})
+			},
+			func() adk.Message { return schema.ToolMessage("file-"+callID, callID, schema.WithToolName("ls")) },
+		)
+
+		// each turn has its own copies of the messages, as when they're rebuilt from the session
+		state := &adk.ChatModelAgentState{}
+		for _, msg := range history {
+			state.Messages = append(state.Messages, msg())
+		}
+		assert.NoError(t, fn(ctx, state))
+		assert.Equal(t, len(history), calls)
+	}
+}
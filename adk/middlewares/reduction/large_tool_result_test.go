@@ -433,6 +433,16 @@ func TestFormatToolMessage(t *testing.T) {
 			input:    strings.Repeat("a", 1500),
 			expected: fmt.Sprintf("1: %s\n", strings.Repeat("a", 1000)),
 		},
+		{
+			name:     "crlf line endings",
+			input:    "line1\r\nline2\r\n",
+			expected: "1: line1\n2: line2\n",
+		},
+		{
+			name:     "trailing newline",
+			input:    "line1\nline2\n",
+			expected: "1: line1\n2: line2\n",
+		},
 		{
 			name:     "unicode characters",
 			input:    "你好世界\n测试",
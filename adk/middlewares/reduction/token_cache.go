@@ -0,0 +1,72 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reduction
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// maxCachedTokenCounts bounds the token counts kept by a cachedTokenCounter, so that a middleware serving
+// many runs doesn't grow memory without limit. The cache is emptied when it's full.
+const maxCachedTokenCounts = 1 << 16
+
+// cachedTokenCounter wraps a custom TokenCounter, e.g. one running a tokenizer, so that each message is only
+// counted once: the history is counted on every turn, mostly with the same messages.
+// Messages are keyed by the hash of their content rather than their pointers, as a message may be changed,
+// e.g. by clearing its tool result, and different runs have different messages with the same content.
+type cachedTokenCounter struct {
+	counter func(msg *schema.Message) int
+
+	mu     sync.Mutex
+	counts map[[sha256.Size]byte]int
+}
+
+func newCachedTokenCounter(counter func(msg *schema.Message) int) *cachedTokenCounter {
+	return &cachedTokenCounter{
+		counter: counter,
+		counts:  make(map[[sha256.Size]byte]int),
+	}
+}
+
+func (c *cachedTokenCounter) count(msg *schema.Message) int {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return c.counter(msg)
+	}
+	key := sha256.Sum256(b)
+
+	c.mu.Lock()
+	n, ok := c.counts[key]
+	c.mu.Unlock()
+	if ok {
+		return n
+	}
+
+	n = c.counter(msg)
+
+	c.mu.Lock()
+	if len(c.counts) >= maxCachedTokenCounts {
+		c.counts = make(map[[sha256.Size]byte]int)
+	}
+	c.counts[key] = n
+	c.mu.Unlock()
+	return n
+}
@@ -51,6 +51,7 @@ type ToolResultConfig struct {
 	ClearToolResultPlaceholder string
 
 	// TokenCounter is a custom function to estimate token count for a message.
+	// Its results are cached by the content of the messages, so that it's called once per distinct message when clearing.
 	// optional, uses the default counter (character count / 4) if nil
 	TokenCounter func(msg *schema.Message) int
 
@@ -58,6 +59,16 @@ type ToolResultConfig struct {
 	// optional
 	ExcludeTools []string
 
+	// StashClearedResults keeps the original content of cleared tool results in the session values,
+	// keyed by tool call id, and adds a tool for the agent to retrieve it, e.g. to explain an earlier step.
+	// Unlike offloading, it applies to every cleared tool result, and the agent must be run through adk.Runner.
+	// optional, false by default
+	StashClearedResults bool
+
+	// RetrieveToolName is the name of the tool retrieving stashed tool results.
+	// optional, "retrieve_tool_result" by default
+	RetrieveToolName string
+
 	// Backend is the storage backend for offloaded tool results.
 	// required
 	Backend Backend
@@ -101,13 +112,18 @@ type ToolResultConfig struct {
 //     which provides the read_file tool automatically, OR
 //   - Implement your own read_file tool that reads from the same Backend
 func NewToolResultMiddleware(ctx context.Context, cfg *ToolResultConfig) (adk.AgentMiddleware, error) {
-	bc := newClearToolResult(ctx, &ClearToolResultConfig{
+	bc, tools, err := newClearToolResult(ctx, &ClearToolResultConfig{
 		ToolResultTokenThreshold:   cfg.ClearingTokenThreshold,
 		KeepRecentTokens:           cfg.KeepRecentTokens,
 		ClearToolResultPlaceholder: cfg.ClearToolResultPlaceholder,
 		TokenCounter:               cfg.TokenCounter,
 		ExcludeTools:               cfg.ExcludeTools,
+		StashClearedResults:        cfg.StashClearedResults,
+		RetrieveToolName:           cfg.RetrieveToolName,
 	})
+	if err != nil {
+		return adk.AgentMiddleware{}, err
+	}
 	tm := newToolResultOffloading(ctx, &toolResultOffloadingConfig{
 		Backend:          cfg.Backend,
 		ReadFileToolName: cfg.ReadFileToolName,
@@ -116,6 +132,7 @@ func NewToolResultMiddleware(ctx context.Context, cfg *ToolResultConfig) (adk.Ag
 	})
 	return adk.AgentMiddleware{
 		BeforeChatModel: bc,
+		AdditionalTools: tools,
 		WrapToolCall:    tm,
 	}, nil
 }
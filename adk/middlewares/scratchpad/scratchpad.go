@@ -0,0 +1,274 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package scratchpad provides a chat model wrapper that gives the model a hidden scratchpad.
+package scratchpad
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/slongfield/pyfmt"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultSessionKey     = "_eino_scratchpad"
+	defaultStartDelimiter = "<scratchpad>"
+	defaultEndDelimiter   = "</scratchpad>"
+)
+
+// Config is the configuration for the scratchpad chat model.
+type Config struct {
+	// Model is the chat model to wrap.
+	// required
+	Model model.ToolCallingChatModel
+
+	// SessionKey is the session value key under which the scratchpad is stored.
+	// optional, "_eino_scratchpad" by default
+	SessionKey string
+
+	// StartDelimiter and EndDelimiter enclose scratchpad content in the model's response content.
+	// optional, "<scratchpad>" and "</scratchpad>" by default
+	StartDelimiter string
+	EndDelimiter   string
+
+	// UseReasoningContent additionally treats the response's ReasoningContent as scratchpad content.
+	// optional, false by default
+	UseReasoningContent bool
+
+	// FormatScratchpad builds the message carrying the current scratchpad, which is appended to the model input.
+	// optional, a system message built from the default template by default
+	FormatScratchpad func(ctx context.Context, scratchpad string) (*schema.Message, error)
+}
+
+// NewChatModel wraps a chat model with a hidden scratchpad that is kept across generations.
+//
+// Before each generation, the current scratchpad is appended to the model input.
+// After each generation, scratchpad content is extracted from the response, appended to the scratchpad,
+// and stripped from the response, so it never shows up in the agent's output or message history.
+// The scratchpad is stored in the run's session values, so the agent must be run through adk.Runner,
+// and adk.WithSessionValues can be used to carry it over from a previous run.
+//
+// NOTE: Stream buffers the whole response before returning it, because scratchpad content can only be
+// stripped once it is complete.
+func NewChatModel(_ context.Context, config *Config) (model.ToolCallingChatModel, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if config.Model == nil {
+		return nil, errors.New("model is required")
+	}
+
+	c := *config
+	if c.SessionKey == "" {
+		c.SessionKey = defaultSessionKey
+	}
+	if c.StartDelimiter == "" {
+		c.StartDelimiter = defaultStartDelimiter
+	}
+	if c.EndDelimiter == "" {
+		c.EndDelimiter = defaultEndDelimiter
+	}
+	if c.FormatScratchpad == nil {
+		c.FormatScratchpad = func(_ context.Context, scratchpad string) (*schema.Message, error) {
+			return schema.SystemMessage(pyfmt.Must(scratchpadTemplate, map[string]any{
+				"start_delimiter": c.StartDelimiter,
+				"end_delimiter":   c.EndDelimiter,
+				"scratchpad":      scratchpad,
+			})), nil
+		}
+	}
+
+	return &chatModel{inner: c.Model, config: &c}, nil
+}
+
+type chatModel struct {
+	inner  model.ToolCallingChatModel
+	config *Config
+}
+
+func (c *chatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := c.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &chatModel{inner: inner, config: c.config}, nil
+}
+
+func (c *chatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	input, err := c.injectScratchpad(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// callbacks are reported here with the stripped response, the inner model must not report the raw one
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	nCtx := callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+
+	out, err := c.inner.Generate(callbacks.InitCallbacks(nCtx, nil), input, opts...)
+	if err != nil {
+		callbacks.OnError(nCtx, err)
+		return nil, err
+	}
+
+	out = c.extractScratchpad(ctx, out)
+	callbacks.OnEnd(nCtx, &model.CallbackOutput{Message: out})
+	return out, nil
+}
+
+func (c *chatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	input, err := c.injectScratchpad(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	nCtx := callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+
+	stream, err := c.inner.Stream(callbacks.InitCallbacks(nCtx, nil), input, opts...)
+	if err != nil {
+		callbacks.OnError(nCtx, err)
+		return nil, err
+	}
+	out, err := schema.ConcatMessageStream(stream)
+	if err != nil {
+		callbacks.OnError(nCtx, err)
+		return nil, err
+	}
+
+	out = c.extractScratchpad(ctx, out)
+	_, cbOut := callbacks.OnEndWithStreamOutput(nCtx, schema.StreamReaderFromArray([]*model.CallbackOutput{{Message: out}}))
+	return schema.StreamReaderWithConvert(cbOut, func(o *model.CallbackOutput) (*schema.Message, error) {
+		return o.Message, nil
+	}), nil
+}
+
+func (c *chatModel) GetType() string {
+	if gt, ok := c.inner.(components.Typer); ok {
+		return gt.GetType()
+	}
+	return generic.ParseTypeName(reflect.ValueOf(c.inner))
+}
+
+func (c *chatModel) IsCallbacksEnabled() bool { return true }
+
+func (c *chatModel) injectScratchpad(ctx context.Context, input []*schema.Message) ([]*schema.Message, error) {
+	scratchpad := getScratchpad(ctx, c.config.SessionKey)
+	if scratchpad == "" {
+		return input, nil
+	}
+	msg, err := c.config.FormatScratchpad(ctx, scratchpad)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*schema.Message, 0, len(input)+1)
+	ret = append(ret, input...)
+	return append(ret, msg), nil
+}
+
+// extractScratchpad stores the scratchpad content of msg in the session, and returns msg without it.
+func (c *chatModel) extractScratchpad(ctx context.Context, msg *schema.Message) *schema.Message {
+	if msg == nil {
+		return nil
+	}
+
+	content, notes := splitScratchpad(msg.Content, c.config.StartDelimiter, c.config.EndDelimiter)
+	useReasoning := c.config.UseReasoningContent && msg.ReasoningContent != ""
+	if content == msg.Content && !useReasoning {
+		return msg
+	}
+	if useReasoning {
+		if note := strings.TrimSpace(msg.ReasoningContent); note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	cp := *msg
+	cp.Content = content
+	if useReasoning {
+		cp.ReasoningContent = ""
+	}
+	if len(notes) == 0 {
+		return &cp
+	}
+
+	adk.UpdateSessionValue(ctx, c.config.SessionKey, func(old any) any {
+		s, _ := old.(string)
+		if s != "" {
+			notes = append([]string{s}, notes...)
+		}
+		return strings.Join(notes, "\n")
+	})
+	return &cp
+}
+
+// splitScratchpad removes every delimited section from content, returning the remaining content
+// and the trimmed sections. An unterminated section extends to the end of content.
+func splitScratchpad(content, start, end string) (string, []string) {
+	var (
+		sb      strings.Builder
+		found   []string
+		matched bool
+	)
+	rest := content
+	for {
+		i := strings.Index(rest, start)
+		if i < 0 {
+			break
+		}
+		matched = true
+		sb.WriteString(rest[:i])
+		rest = rest[i+len(start):]
+
+		j := strings.Index(rest, end)
+		if j < 0 {
+			j = len(rest)
+		}
+		if note := strings.TrimSpace(rest[:j]); note != "" {
+			found = append(found, note)
+		}
+		if j+len(end) > len(rest) {
+			rest = ""
+		} else {
+			rest = rest[j+len(end):]
+		}
+	}
+	if !matched {
+		return content, nil
+	}
+	sb.WriteString(rest)
+	return strings.TrimSpace(sb.String()), found
+}
+
+func getScratchpad(ctx context.Context, key string) string {
+	v, ok := adk.GetSessionValue(ctx, key)
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
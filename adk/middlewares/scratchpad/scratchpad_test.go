@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scratchpad
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type lookupArgs struct {
+	Query string `json:"query"`
+}
+
+func newLookupTool(t *testing.T) tool.BaseTool {
+	lookup, err := utils.InferTool("lookup", "look up a fact", func(ctx context.Context, input lookupArgs) (string, error) {
+		return "42", nil
+	})
+	assert.NoError(t, err)
+	return lookup
+}
+
+func runAgent(t *testing.T, cm model.ToolCallingChatModel, enableStreaming bool) []adk.Message {
+	ctx := context.Background()
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with scratchpad",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{newLookupTool(t)}},
+		},
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, EnableStreaming: enableStreaming}).Query(ctx, "what is the answer?")
+	var outputs []adk.Message
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		msg, err := event.Output.MessageOutput.GetMessage()
+		assert.NoError(t, err)
+		outputs = append(outputs, msg)
+	}
+	return outputs
+}
+
+func TestScratchpad(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mockModel.NewMockToolCallingChatModel(ctrl)
+	inner.EXPECT().WithTools(gomock.Any()).Return(inner, nil).AnyTimes()
+	gomock.InOrder(
+		inner.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				// the scratchpad is empty at first
+				assert.Equal(t, schema.User, input[len(input)-1].Role)
+				return schema.AssistantMessage("<scratchpad>the user wants the answer</scratchpad>Let me look it up.",
+					[]schema.ToolCall{{ID: "call-1", Function: schema.FunctionCall{Name: "lookup", Arguments: `{"query":"answer"}`}}}), nil
+			}),
+		inner.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				// the scratchpad from the first generation is passed in, while the history only has the stripped message
+				last := input[len(input)-1]
+				assert.Equal(t, schema.System, last.Role)
+				assert.Contains(t, last.Content, "the user wants the answer")
+				assert.Equal(t, "Let me look it up.", input[len(input)-3].Content)
+				return schema.AssistantMessage("<scratchpad>lookup returned 42</scratchpad>\nThe answer is 42.", nil), nil
+			}),
+	)
+
+	cm, err := NewChatModel(ctx, &Config{Model: inner})
+	assert.NoError(t, err)
+
+	outputs := runAgent(t, cm, false)
+	assert.Len(t, outputs, 3)
+	assert.Equal(t, "Let me look it up.", outputs[0].Content)
+	assert.Equal(t, "42", outputs[1].Content)
+	assert.Equal(t, "The answer is 42.", outputs[2].Content)
+	for _, o := range outputs {
+		assert.NotContains(t, o.Content, "scratchpad")
+	}
+}
+
+func TestScratchpad_StreamReasoningContent(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inner := mockModel.NewMockToolCallingChatModel(ctrl)
+	inner.EXPECT().WithTools(gomock.Any()).Return(inner, nil).AnyTimes()
+	gomock.InOrder(
+		inner.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				first := schema.AssistantMessage("", []schema.ToolCall{{Index: ptrOf(0), ID: "call-1", Function: schema.FunctionCall{Name: "lookup", Arguments: `{"query":"answer"}`}}})
+				first.ReasoningContent = "I should look it up."
+				return schema.StreamReaderFromArray([]*schema.Message{first}), nil
+			}),
+		inner.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+				assert.Contains(t, input[len(input)-1].Content, "I should look it up.")
+				return schema.StreamReaderFromArray([]*schema.Message{
+					{Role: schema.Assistant, ReasoningContent: "Got it."},
+					schema.AssistantMessage("The answer ", nil),
+					schema.AssistantMessage("is 42.", nil),
+				}), nil
+			}),
+	)
+
+	cm, err := NewChatModel(ctx, &Config{Model: inner, UseReasoningContent: true})
+	assert.NoError(t, err)
+
+	outputs := runAgent(t, cm, true)
+	assert.Len(t, outputs, 3)
+	assert.Empty(t, outputs[0].ReasoningContent)
+	assert.Equal(t, "The answer is 42.", outputs[2].Content)
+	assert.Empty(t, outputs[2].ReasoningContent)
+}
+
+func TestSplitScratchpad(t *testing.T) {
+	content, notes := splitScratchpad("no notes", "<s>", "</s>")
+	assert.Equal(t, "no notes", content)
+	assert.Empty(t, notes)
+
+	content, notes = splitScratchpad("<s> a </s>hello <s>b</s>world", "<s>", "</s>")
+	assert.Equal(t, "hello world", content)
+	assert.Equal(t, []string{"a", "b"}, notes)
+
+	content, notes = splitScratchpad("hello<s></s>", "<s>", "</s>")
+	assert.Equal(t, "hello", content)
+	assert.Empty(t, notes)
+
+	content, notes = splitScratchpad("hello <s>unterminated", "<s>", "</s>")
+	assert.Equal(t, "hello", content)
+	assert.Equal(t, []string{"unterminated"}, notes)
+}
+
+func TestNewChatModel_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewChatModel(ctx, nil)
+	assert.Error(t, err)
+	_, err = NewChatModel(ctx, &Config{})
+	assert.Error(t, err)
+}
+
+func ptrOf[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ArchiveFormat is the format of a skill archive.
+type ArchiveFormat string
+
+const (
+	// ArchiveFormatZip is a zip archive.
+	ArchiveFormatZip ArchiveFormat = "zip"
+	// ArchiveFormatTar is an uncompressed tar archive.
+	ArchiveFormatTar ArchiveFormat = "tar"
+	// ArchiveFormatTarGz is a gzip compressed tar archive.
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+)
+
+// ArchiveBackend is a Backend implementation that reads skills from a zip or tar archive, e.g. a shipped skill bundle.
+// Each directory of the archive containing a SKILL.md file is a skill, and the other files under it are its supporting files.
+// The archive isn't extracted: the BaseDirectory of a skill is a virtual path, and ArchiveBackend implements
+// BundlingBackend, so that the supporting files are written to the filesystem of the agent when the skill is invoked,
+// by setting the BundleBackend of the skill middleware Config.
+type ArchiveBackend struct {
+	// skills are sorted by their directories in the archive.
+	skills []archiveSkill
+}
+
+type archiveSkill struct {
+	skill Skill
+	// files are the supporting files, keyed by their paths relative to the directory of the skill.
+	files map[string]string
+}
+
+// ArchiveBackendConfig is the configuration for creating an ArchiveBackend.
+type ArchiveBackendConfig struct {
+	// Archive is the content of the archive. It's read entirely by NewArchiveBackend.
+	Archive io.Reader
+	// Format is the format of the archive.
+	// optional, detected from the content of the archive by default
+	Format ArchiveFormat
+	// BaseDirectory is the virtual directory the archive is located at:
+	// the BaseDirectory of a skill is the path of its directory in the archive joined to it.
+	// optional, "/skills" by default
+	BaseDirectory string
+	// FrontmatterFormat is the format of the frontmatter.
+	// optional, FrontmatterFormatYAML by default
+	FrontmatterFormat FrontmatterFormat
+	// Delimiter is the line that opens and closes the frontmatter.
+	// optional, "+++" for FrontmatterFormatTOML and "---" otherwise by default
+	Delimiter string
+}
+
+// NewArchiveBackend creates a new ArchiveBackend with the given configuration.
+func NewArchiveBackend(config *ArchiveBackendConfig) (*ArchiveBackend, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if config.Archive == nil {
+		return nil, fmt.Errorf("archive is required")
+	}
+
+	parser, err := newSkillParser(config.FrontmatterFormat, config.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(config.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	format := config.Format
+	if format == "" {
+		format = detectArchiveFormat(data)
+	}
+
+	var files map[string]string
+	switch format {
+	case ArchiveFormatZip:
+		files, err = readZipArchive(data)
+	case ArchiveFormatTar:
+		files, err = readTarArchive(bytes.NewReader(data))
+	case ArchiveFormatTarGz:
+		var gr *gzip.Reader
+		gr, err = gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			files, err = readTarArchive(gr)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported archive format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	baseDir := config.BaseDirectory
+	if baseDir == "" {
+		baseDir = "/skills"
+	}
+
+	return loadArchiveSkills(files, parser, baseDir)
+}
+
+// List returns all skills from the archive.
+func (b *ArchiveBackend) List(_ context.Context) ([]FrontMatter, error) {
+	matters := make([]FrontMatter, 0, len(b.skills))
+	for _, s := range b.skills {
+		matters = append(matters, s.skill.FrontMatter)
+	}
+	return matters, nil
+}
+
+// Get returns a skill by name from the archive.
+func (b *ArchiveBackend) Get(_ context.Context, name string) (Skill, error) {
+	for _, s := range b.skills {
+		if s.skill.Name == name {
+			return s.skill, nil
+		}
+	}
+	return Skill{}, fmt.Errorf("skill not found: %s", name)
+}
+
+// Bundle returns the supporting files of a skill by name from the archive,
+// i.e. all the files under the directory of its SKILL.md except SKILL.md itself.
+func (b *ArchiveBackend) Bundle(_ context.Context, name string) (map[string]string, error) {
+	for _, s := range b.skills {
+		if s.skill.Name == name {
+			files := make(map[string]string, len(s.files))
+			for k, v := range s.files {
+				files[k] = v
+			}
+			return files, nil
+		}
+	}
+	return nil, fmt.Errorf("skill not found: %s", name)
+}
+
+func detectArchiveFormat(data []byte) ArchiveFormat {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return ArchiveFormatZip
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		return ArchiveFormatTarGz
+	default:
+		return ArchiveFormatTar
+	}
+}
+
+func readZipArchive(data []byte) (map[string]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name, err := cleanArchivePath(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		files[name] = string(content)
+	}
+	return files, nil
+}
+
+func readTarArchive(r io.Reader) (map[string]string, error) {
+	tr := tar.NewReader(r)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := cleanArchivePath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		files[name] = string(content)
+	}
+}
+
+// cleanArchivePath returns the slash-separated path of an archive entry relative to the archive root,
+// rejecting paths escaping it.
+func cleanArchivePath(name string) (string, error) {
+	p := strings.TrimPrefix(path.Clean(strings.ReplaceAll(name, "\\", "/")), "/")
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+	return p, nil
+}
+
+func loadArchiveSkills(files map[string]string, parser skillParser, baseDir string) (*ArchiveBackend, error) {
+	var skillDirs []string
+	for name := range files {
+		if path.Base(name) == skillFileName {
+			skillDirs = append(skillDirs, path.Dir(name))
+		}
+	}
+	sort.Strings(skillDirs)
+
+	b := &ArchiveBackend{}
+	names := make(map[string]string)
+	for _, dir := range skillDirs {
+		skillPath := path.Join(dir, skillFileName)
+		skill, err := parser.parse(files[skillPath], path.Join(baseDir, dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load skill from %s: %w", skillPath, err)
+		}
+		if other, ok := names[skill.Name]; ok {
+			return nil, fmt.Errorf("duplicate skill name %s in %s and %s", skill.Name, other, dir)
+		}
+		names[skill.Name] = dir
+
+		prefix := dir + "/"
+		if dir == "." {
+			prefix = ""
+		}
+		supporting := make(map[string]string)
+		for name, content := range files {
+			if name == skillPath || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			supporting[strings.TrimPrefix(name, prefix)] = content
+		}
+
+		b.skills = append(b.skills, archiveSkill{skill: skill, files: supporting})
+	}
+
+	return b, nil
+}
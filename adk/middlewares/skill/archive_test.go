@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/components/tool"
+)
+
+var archiveFiles = []struct{ name, content string }{
+	{"bundle/pdf/SKILL.md", "---\nname: pdf\ndescription: pdf tools\n---\nRun scripts/extract.py"},
+	{"bundle/pdf/scripts/extract.py", "print('extract')"},
+	{"bundle/xlsx/SKILL.md", "---\nname: xlsx\ndescription: xlsx tools\n---\nRead reference.md"},
+	{"bundle/xlsx/reference.md", "reference"},
+	{"README.md", "skill bundle"},
+}
+
+func zipArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range archiveFiles {
+		w, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestArchiveBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("zip archive", func(t *testing.T) {
+		backend, err := NewArchiveBackend(&ArchiveBackendConfig{Archive: bytes.NewReader(zipArchive(t))})
+		require.NoError(t, err)
+
+		matters, err := backend.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []FrontMatter{
+			{Name: "pdf", Description: "pdf tools"},
+			{Name: "xlsx", Description: "xlsx tools"},
+		}, matters)
+
+		skill, err := backend.Get(ctx, "xlsx")
+		assert.NoError(t, err)
+		assert.Equal(t, "Read reference.md", skill.Content)
+		assert.Equal(t, "/skills/bundle/xlsx", skill.BaseDirectory)
+
+		files, err := backend.Bundle(ctx, "pdf")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"scripts/extract.py": "print('extract')"}, files)
+
+		_, err = backend.Get(ctx, "docx")
+		assert.ErrorContains(t, err, "skill not found")
+	})
+
+	t.Run("tar archive", func(t *testing.T) {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, f := range archiveFiles {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+			_, err := tw.Write([]byte(f.content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+
+		backend, err := NewArchiveBackend(&ArchiveBackendConfig{Archive: &buf, BaseDirectory: "/opt"})
+		require.NoError(t, err)
+
+		skill, err := backend.Get(ctx, "pdf")
+		assert.NoError(t, err)
+		assert.Equal(t, "/opt/bundle/pdf", skill.BaseDirectory)
+	})
+
+	t.Run("supporting files are bundled into the agent filesystem", func(t *testing.T) {
+		backend, err := NewArchiveBackend(&ArchiveBackendConfig{Archive: bytes.NewReader(zipArchive(t))})
+		require.NoError(t, err)
+		fsBackend := filesystem.NewInMemoryBackend()
+
+		m, err := New(ctx, &Config{Backend: backend, BundleBackend: fsBackend})
+		require.NoError(t, err)
+		result, err := m.AdditionalTools[0].(tool.InvokableTool).InvokableRun(ctx, `{"skill": "pdf"}`)
+		assert.NoError(t, err)
+		dir := "/skills/pdf/" + contentHash("scripts/extract.py", "print('extract')")
+		assert.Contains(t, result, "Base directory for this skill: "+dir+"\n")
+
+		content, err := fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: dir + "/scripts/extract.py", Limit: 10})
+		assert.NoError(t, err)
+		assert.Contains(t, content, "print('extract')")
+	})
+
+	t.Run("invalid archives", func(t *testing.T) {
+		_, err := NewArchiveBackend(&ArchiveBackendConfig{})
+		assert.ErrorContains(t, err, "archive is required")
+
+		_, err = NewArchiveBackend(&ArchiveBackendConfig{Archive: bytes.NewReader(zipArchive(t)), Format: "rar"})
+		assert.ErrorContains(t, err, "unsupported archive format")
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, name := range []string{"a/SKILL.md", "b/SKILL.md"} {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte("---\nname: same\n---\ncontent"))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+		_, err = NewArchiveBackend(&ArchiveBackendConfig{Archive: &buf})
+		assert.ErrorContains(t, err, "duplicate skill name same")
+	})
+}
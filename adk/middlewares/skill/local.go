@@ -18,28 +18,58 @@ package skill
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/pelletier/go-toml/v2"
 	"gopkg.in/yaml.v3"
 )
 
 const skillFileName = "SKILL.md"
 
+// FrontmatterFormat is the format of the frontmatter in SKILL.md files.
+type FrontmatterFormat string
+
+const (
+	// FrontmatterFormatYAML is YAML frontmatter, delimited by "---" by default.
+	FrontmatterFormatYAML FrontmatterFormat = "yaml"
+	// FrontmatterFormatTOML is TOML frontmatter, delimited by "+++" by default.
+	FrontmatterFormatTOML FrontmatterFormat = "toml"
+	// FrontmatterFormatJSON is JSON frontmatter, delimited by "---" by default.
+	FrontmatterFormatJSON FrontmatterFormat = "json"
+)
+
 // LocalBackend is a Backend implementation that reads skills from the local filesystem.
 // Skills are stored in subdirectories of baseDir, each containing a SKILL.md file.
 type LocalBackend struct {
+	skillParser
 	// baseDir is the root directory containing skill subdirectories.
 	baseDir string
 }
 
+// skillParser parses SKILL.md files into skills.
+type skillParser struct {
+	// delimiter encloses the frontmatter. Empty means the default delimiter of format.
+	delimiter string
+	// format is the frontmatter format. Empty means FrontmatterFormatYAML.
+	format FrontmatterFormat
+}
+
 // LocalBackendConfig is the configuration for creating a LocalBackend.
 type LocalBackendConfig struct {
 	// BaseDir is the root directory containing skill subdirectories.
 	// Each subdirectory should contain a SKILL.md file with frontmatter and content.
 	BaseDir string
+	// FrontmatterFormat is the format of the frontmatter.
+	// optional, FrontmatterFormatYAML by default
+	FrontmatterFormat FrontmatterFormat
+	// Delimiter is the line that opens and closes the frontmatter.
+	// optional, "+++" for FrontmatterFormatTOML and "---" otherwise by default
+	Delimiter string
 }
 
 // NewLocalBackend creates a new LocalBackend with the given configuration.
@@ -60,11 +90,26 @@ func NewLocalBackend(config *LocalBackendConfig) (*LocalBackend, error) {
 		return nil, fmt.Errorf("baseDir is not a directory: %s", config.BaseDir)
 	}
 
+	parser, err := newSkillParser(config.FrontmatterFormat, config.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LocalBackend{
-		baseDir: config.BaseDir,
+		skillParser: parser,
+		baseDir:     config.BaseDir,
 	}, nil
 }
 
+func newSkillParser(format FrontmatterFormat, delimiter string) (skillParser, error) {
+	switch format {
+	case "", FrontmatterFormatYAML, FrontmatterFormatTOML, FrontmatterFormatJSON:
+	default:
+		return skillParser{}, fmt.Errorf("unsupported frontmatter format: %s", format)
+	}
+	return skillParser{delimiter: delimiter, format: format}, nil
+}
+
 // List returns all skills from the local filesystem.
 // It scans subdirectories of baseDir for SKILL.md files and parses them as skills.
 func (b *LocalBackend) List(ctx context.Context) ([]FrontMatter, error) {
@@ -98,6 +143,45 @@ func (b *LocalBackend) Get(ctx context.Context, name string) (Skill, error) {
 	return Skill{}, fmt.Errorf("skill not found: %s", name)
 }
 
+// Bundle returns the supporting files of a skill by name from the local filesystem,
+// i.e. all the files under the directory of its SKILL.md except SKILL.md itself.
+func (b *LocalBackend) Bundle(ctx context.Context, name string) (map[string]string, error) {
+	skill, err := b.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	err = filepath.WalkDir(skill.BaseDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(skill.BaseDirectory, path)
+		if err != nil {
+			return err
+		}
+		if rel == skillFileName {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle skill %s: %w", name, err)
+	}
+
+	return files, nil
+}
+
 func (b *LocalBackend) list(ctx context.Context) ([]Skill, error) {
 	var skills []Skill
 
@@ -144,20 +228,25 @@ func (b *LocalBackend) loadSkillFromFile(path string) (Skill, error) {
 		return Skill{}, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	frontmatter, content, err := parseFrontmatter(string(data))
+	// Get the absolute path of the directory containing SKILL.md
+	absDir, err := filepath.Abs(filepath.Dir(path))
 	if err != nil {
-		return Skill{}, fmt.Errorf("failed to parse frontmatter: %w", err)
+		return Skill{}, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	var fm FrontMatter
-	if err = yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
-		return Skill{}, fmt.Errorf("failed to unmarshal frontmatter: %w", err)
+	return b.parse(string(data), absDir)
+}
+
+// parse parses the data of a SKILL.md file into a skill located at baseDirectory.
+func (p skillParser) parse(data string, baseDirectory string) (Skill, error) {
+	frontmatter, content, err := parseFrontmatter(data, p.frontmatterDelimiter())
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
-	// Get the absolute path of the directory containing SKILL.md
-	absDir, err := filepath.Abs(filepath.Dir(path))
+	fm, err := p.unmarshalFrontmatter(frontmatter)
 	if err != nil {
-		return Skill{}, fmt.Errorf("failed to get absolute path: %w", err)
+		return Skill{}, fmt.Errorf("failed to unmarshal frontmatter: %w", err)
 	}
 
 	return Skill{
@@ -166,23 +255,46 @@ func (b *LocalBackend) loadSkillFromFile(path string) (Skill, error) {
 			Description: fm.Description,
 		},
 		Content:       strings.TrimSpace(content),
-		BaseDirectory: absDir,
+		BaseDirectory: baseDirectory,
 	}, nil
 }
 
-// parseFrontmatter parses a markdown file with YAML frontmatter.
-// Returns the frontmatter content (without ---), the remaining content, and any error.
-func parseFrontmatter(data string) (frontmatter string, content string, err error) {
-	const delimiter = "---"
+func (p skillParser) frontmatterDelimiter() string {
+	if p.delimiter != "" {
+		return p.delimiter
+	}
+	if p.format == FrontmatterFormatTOML {
+		return "+++"
+	}
+	return "---"
+}
+
+func (p skillParser) unmarshalFrontmatter(frontmatter string) (FrontMatter, error) {
+	var fm FrontMatter
+	switch p.format {
+	case FrontmatterFormatTOML:
+		return fm, toml.Unmarshal([]byte(frontmatter), &fm)
+	case FrontmatterFormatJSON:
+		if frontmatter == "" {
+			return fm, nil
+		}
+		return fm, json.Unmarshal([]byte(frontmatter), &fm)
+	default:
+		return fm, yaml.Unmarshal([]byte(frontmatter), &fm)
+	}
+}
 
+// parseFrontmatter parses a markdown file with frontmatter enclosed by delimiter lines.
+// Returns the frontmatter content (without the delimiters), the remaining content, and any error.
+func parseFrontmatter(data string, delimiter string) (frontmatter string, content string, err error) {
 	data = strings.TrimSpace(data)
 
-	// Must start with ---
+	// Must start with the delimiter
 	if !strings.HasPrefix(data, delimiter) {
 		return "", "", fmt.Errorf("file does not start with frontmatter delimiter")
 	}
 
-	// Find the closing ---
+	// Find the closing delimiter
 	rest := data[len(delimiter):]
 	endIdx := strings.Index(rest, "\n"+delimiter)
 	if endIdx == -1 {
@@ -192,7 +304,7 @@ func parseFrontmatter(data string) (frontmatter string, content string, err erro
 	frontmatter = strings.TrimSpace(rest[:endIdx])
 	content = rest[endIdx+len("\n"+delimiter):]
 
-	// Remove the newline after the closing ---
+	// Remove the newline after the closing delimiter
 	if strings.HasPrefix(content, "\n") {
 		content = content[1:]
 	}
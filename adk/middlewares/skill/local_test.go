@@ -297,7 +297,7 @@ description: test description
 ---
 This is the content.`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Equal(t, "name: test\ndescription: test description", fm)
 		assert.Equal(t, "This is the content.", content)
@@ -311,7 +311,7 @@ Line 1
 Line 2
 Line 3`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Equal(t, "name: test", fm)
 		assert.Equal(t, "Line 1\nLine 2\nLine 3", content)
@@ -324,7 +324,7 @@ name: test
 ---
 Content  `
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Equal(t, "name: test", fm)
 		// Note: parseFrontmatter trims trailing whitespace from input data
@@ -336,7 +336,7 @@ Content  `
 ---
 Content`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.Error(t, err)
 		assert.Empty(t, fm)
 		assert.Empty(t, content)
@@ -348,7 +348,7 @@ Content`
 name: test
 Content without closing`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.Error(t, err)
 		assert.Empty(t, fm)
 		assert.Empty(t, content)
@@ -360,7 +360,7 @@ Content without closing`
 ---
 Content only`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Empty(t, fm)
 		assert.Equal(t, "Content only", content)
@@ -371,7 +371,7 @@ Content only`
 name: test
 ---`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Equal(t, "name: test", fm)
 		assert.Empty(t, content)
@@ -383,7 +383,7 @@ name: test
 ---
 Content with --- in the middle`
 
-		fm, content, err := parseFrontmatter(data)
+		fm, content, err := parseFrontmatter(data, "---")
 		assert.NoError(t, err)
 		assert.Equal(t, "name: test", fm)
 		assert.Equal(t, "Content with --- in the middle", content)
@@ -462,3 +462,75 @@ description: desc
 		assert.Equal(t, "Content with whitespace", skill.Content)
 	})
 }
+
+func TestLocalBackend_FrontmatterFormat(t *testing.T) {
+	ctx := context.Background()
+
+	writeSkill := func(t *testing.T, data string) string {
+		tmpDir, err := os.MkdirTemp("", "skill-test-*")
+		require.NoError(t, err)
+		skillDir := filepath.Join(tmpDir, "my-skill")
+		require.NoError(t, os.Mkdir(skillDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(data), 0644))
+		return tmpDir
+	}
+
+	t.Run("toml frontmatter", func(t *testing.T) {
+		tmpDir := writeSkill(t, `+++
+name = "my-skill"
+description = "TOML skill"
++++
+TOML content.`)
+		defer os.RemoveAll(tmpDir)
+
+		backend, err := NewLocalBackend(&LocalBackendConfig{BaseDir: tmpDir, FrontmatterFormat: FrontmatterFormatTOML})
+		require.NoError(t, err)
+
+		skill, err := backend.Get(ctx, "my-skill")
+		assert.NoError(t, err)
+		assert.Equal(t, "my-skill", skill.Name)
+		assert.Equal(t, "TOML skill", skill.Description)
+		assert.Equal(t, "TOML content.", skill.Content)
+	})
+
+	t.Run("json frontmatter with custom delimiter", func(t *testing.T) {
+		tmpDir := writeSkill(t, `;;;
+{"name": "my-skill", "description": "JSON skill"}
+;;;
+JSON content.`)
+		defer os.RemoveAll(tmpDir)
+
+		backend, err := NewLocalBackend(&LocalBackendConfig{BaseDir: tmpDir, FrontmatterFormat: FrontmatterFormatJSON, Delimiter: ";;;"})
+		require.NoError(t, err)
+
+		skill, err := backend.Get(ctx, "my-skill")
+		assert.NoError(t, err)
+		assert.Equal(t, "my-skill", skill.Name)
+		assert.Equal(t, "JSON skill", skill.Description)
+		assert.Equal(t, "JSON content.", skill.Content)
+	})
+
+	t.Run("format mismatch returns error", func(t *testing.T) {
+		tmpDir := writeSkill(t, `---
+name: my-skill
+---
+content`)
+		defer os.RemoveAll(tmpDir)
+
+		backend, err := NewLocalBackend(&LocalBackendConfig{BaseDir: tmpDir, FrontmatterFormat: FrontmatterFormatJSON})
+		require.NoError(t, err)
+
+		_, err = backend.Get(ctx, "my-skill")
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported format returns error", func(t *testing.T) {
+		tmpDir, err := os.MkdirTemp("", "skill-test-*")
+		require.NoError(t, err)
+		defer os.RemoveAll(tmpDir)
+
+		_, err = NewLocalBackend(&LocalBackendConfig{BaseDir: tmpDir, FrontmatterFormat: "xml"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported frontmatter format")
+	})
+}
@@ -147,5 +147,17 @@ Important:
 	userContentChinese = `此 Skill 的目录：%s
 
 %s`
+	tooLargeSkillContent = `The skill content is too large, it was saved in the filesystem at this path: {file_path}
+Read the full instructions by using the '{read_file_tool_name}' tool before following the skill, a part at a time by specifying an offset and limit.
+For example, to read the first 100 lines, use the '{read_file_tool_name}' tool with offset=0 and limit=100.
+
+Here are the first 10 lines of the skill:
+{content_sample}`
+	tooLargeSkillContentChinese = `Skill 内容过大，已保存到文件系统中的路径：{file_path}
+在遵循该 Skill 之前，请使用 '{read_file_tool_name}' 工具阅读完整说明，每次通过指定 offset 和 limit 读取一部分。
+例如，要读取前 100 行，可以使用 '{read_file_tool_name}' 工具并设置 offset=0、limit=100。
+
+以下是该 Skill 的前 10 行：
+{content_sample}`
 	toolName = "skill"
 )
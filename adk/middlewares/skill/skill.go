@@ -14,26 +14,34 @@
  * limitations under the License.
  */
 
-// Package skill provides the skill middleware, types, and a local filesystem backend.
+// Package skill provides the skill middleware, types, and backends reading skills from the local filesystem or an archive.
 package skill
 
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/slongfield/pyfmt"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/adk/middlewares/reduction"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 )
 
 type FrontMatter struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Name        string `yaml:"name" json:"name" toml:"name"`
+	Description string `yaml:"description" json:"description" toml:"description"`
 }
 
 type Skill struct {
@@ -47,6 +55,15 @@ type Backend interface {
 	Get(ctx context.Context, name string) (Skill, error)
 }
 
+// BundlingBackend is an optional interface for a Backend to provide the supporting files of skills,
+// e.g. the scripts and references next to SKILL.md, so that they can be pushed to the filesystem of the agent.
+type BundlingBackend interface {
+	Backend
+	// Bundle returns the supporting files of the skill, excluding SKILL.md,
+	// keyed by their slash-separated paths relative to the BaseDirectory of the skill.
+	Bundle(ctx context.Context, name string) (map[string]string, error)
+}
+
 // Config is the configuration for the skill middleware.
 type Config struct {
 	// Backend is the backend for retrieving skills.
@@ -56,6 +73,31 @@ type Config struct {
 	// UseChinese controls whether to use Chinese prompts. When set to true, Chinese prompts are used;
 	// when set to false (default), English prompts are used.
 	UseChinese bool
+
+	// OffloadingBackend is the storage for the content of large skills, usually the Backend of the filesystem middleware.
+	// When set, the content of a skill exceeding OffloadingTokenLimit is written to it,
+	// and the tool result only contains the first lines of the content and the path to read the rest from,
+	// consistent with the tool result offloading of the reduction middleware.
+	// optional, large skills are returned as a whole by default
+	OffloadingBackend reduction.Backend
+	// OffloadingTokenLimit is the token threshold of the skill content to trigger offloading.
+	// Token estimation uses a simple heuristic: character count / 4.
+	// optional, 20000 by default
+	OffloadingTokenLimit int
+	// ReadFileToolName is the name of the tool the model should use to read offloaded skill content.
+	// optional, "read_file" by default
+	ReadFileToolName string
+
+	// BundleBackend is the filesystem of the agent, usually the Backend of the filesystem middleware.
+	// When set and Backend implements BundlingBackend, the supporting files of a skill are written to it
+	// under BundleDirectory when the skill is invoked, and the BaseDirectory of the skill is rewritten to that directory,
+	// so that skills also work for remote or sandboxed agents which don't share the filesystem of Backend.
+	// optional, the BaseDirectory of Backend is used as is by default
+	BundleBackend reduction.Backend
+	// BundleDirectory is the directory of BundleBackend under which the supporting files of each skill are written,
+	// to the subdirectory named after the skill and keyed by the hash of the files, e.g. "/skills/pdf/3f1a2b4c5d6e7f80".
+	// optional, "/skills" by default
+	BundleDirectory string
 }
 
 // New creates a new skill middleware.
@@ -75,7 +117,7 @@ func New(ctx context.Context, config *Config) (adk.AgentMiddleware, error) {
 
 	return adk.AgentMiddleware{
 		AdditionalInstruction: buildSystemPrompt(name, config.UseChinese),
-		AdditionalTools:       []tool.BaseTool{&skillTool{b: config.Backend, toolName: name, useChinese: config.UseChinese}},
+		AdditionalTools:       []tool.BaseTool{newSkillTool(config, name)},
 	}, nil
 }
 
@@ -93,6 +135,36 @@ type skillTool struct {
 	b          Backend
 	toolName   string
 	useChinese bool
+
+	offloadingBackend    reduction.Backend
+	offloadingTokenLimit int
+	readFileToolName     string
+
+	bundleBackend   reduction.Backend
+	bundleDirectory string
+}
+
+func newSkillTool(config *Config, name string) *skillTool {
+	t := &skillTool{
+		b:                    config.Backend,
+		toolName:             name,
+		useChinese:           config.UseChinese,
+		offloadingBackend:    config.OffloadingBackend,
+		offloadingTokenLimit: config.OffloadingTokenLimit,
+		readFileToolName:     config.ReadFileToolName,
+		bundleBackend:        config.BundleBackend,
+		bundleDirectory:      config.BundleDirectory,
+	}
+	if t.offloadingTokenLimit <= 0 {
+		t.offloadingTokenLimit = 20000
+	}
+	if len(t.readFileToolName) == 0 {
+		t.readFileToolName = "read_file"
+	}
+	if len(t.bundleDirectory) == 0 {
+		t.bundleDirectory = "/skills"
+	}
+	return t
 }
 
 type descriptionTemplateHelper struct {
@@ -152,7 +224,139 @@ func (s *skillTool) InvokableRun(ctx context.Context, argumentsInJSON string, op
 		contentFmt = userContentChinese
 	}
 
-	return fmt.Sprintf(resultFmt, skill.Name) + fmt.Sprintf(contentFmt, skill.BaseDirectory, skill.Content), nil
+	skill, err = s.bundle(ctx, skill)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := s.offload(ctx, skill)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(resultFmt, skill.Name) + fmt.Sprintf(contentFmt, skill.BaseDirectory, content), nil
+}
+
+// bundle writes the supporting files of a skill to the bundle backend,
+// and returns the skill with its BaseDirectory rewritten to the directory they are written to.
+func (s *skillTool) bundle(ctx context.Context, skill Skill) (Skill, error) {
+	bb, ok := s.b.(BundlingBackend)
+	if s.bundleBackend == nil || !ok {
+		return skill, nil
+	}
+
+	files, err := bb.Bundle(ctx, skill.Name)
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to bundle skill: %w", err)
+	}
+
+	if err = checkSkillName(skill.Name); err != nil {
+		return Skill{}, fmt.Errorf("failed to bundle skill: %w", err)
+	}
+	rels := make([]string, 0, len(files))
+	hashed := make([]string, 0, 2*len(files))
+	for rel := range files {
+		if rel == "" || path.IsAbs(rel) || path.Clean(rel) != rel || rel == ".." || strings.HasPrefix(rel, "../") {
+			return Skill{}, fmt.Errorf("failed to bundle skill: invalid file path %q", rel)
+		}
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		hashed = append(hashed, rel, files[rel])
+	}
+
+	// the directory is keyed by the hash of the files, so that invoking the skill again reuses them,
+	// while updated files are written to a new directory
+	dir := path.Join(s.bundleDirectory, skill.Name, contentHash(hashed...))
+	for _, rel := range rels {
+		filePath := path.Join(dir, rel)
+		if err = writeOnce(ctx, s.bundleBackend, filePath, files[rel]); err != nil {
+			return Skill{}, fmt.Errorf("failed to write skill file %s: %w", filePath, err)
+		}
+	}
+
+	skill.BaseDirectory = dir
+	return skill, nil
+}
+
+// offload writes the content of a skill over the token limit to the offloading backend,
+// and returns a sample of it along with the instruction to read the rest.
+func (s *skillTool) offload(ctx context.Context, skill Skill) (string, error) {
+	if s.offloadingBackend == nil || utf8.RuneCountInString(skill.Content) <= s.offloadingTokenLimit*4 {
+		return skill.Content, nil
+	}
+
+	if err := checkSkillName(skill.Name); err != nil {
+		return "", fmt.Errorf("failed to offload skill content: %w", err)
+	}
+	// the path is keyed by the hash of the content, so that invoking the skill again reuses the file
+	path := fmt.Sprintf("/large_skill_content/%s/%s", skill.Name, contentHash(skill.Content))
+	err := writeOnce(ctx, s.offloadingBackend, path, skill.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to offload skill content: %w", err)
+	}
+
+	msg := tooLargeSkillContent
+	if s.useChinese {
+		msg = tooLargeSkillContentChinese
+	}
+	return pyfmt.Fmt(msg, map[string]any{
+		"file_path":           path,
+		"read_file_tool_name": s.readFileToolName,
+		"content_sample":      contentSample(skill.Content, 10),
+	})
+}
+
+// writeOnce writes content to filePath, a path keyed by the hash of content, or of the bundle it belongs to.
+// A file already at filePath was written by a former invocation with the same content, so the write is skipped:
+// either by the IdempotencyKey, or after checking the file exists if backend can also read files.
+func writeOnce(ctx context.Context, backend reduction.Backend, filePath, content string) error {
+	err := backend.Write(ctx, &filesystem.WriteRequest{
+		FilePath:       filePath,
+		Content:        content,
+		IdempotencyKey: "skill:" + filePath,
+	})
+	if err == nil {
+		return nil
+	}
+	if r, ok := backend.(interface {
+		Read(ctx context.Context, req *filesystem.ReadRequest) (string, error)
+	}); ok {
+		if _, rErr := r.Read(ctx, &filesystem.ReadRequest{FilePath: filePath, Limit: 1}); rErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// contentHash returns a short hex digest of contents, used to key the paths of the files written for a skill.
+func contentHash(contents ...string) string {
+	h := sha256.New()
+	for _, c := range contents {
+		h.Write([]byte(c))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// checkSkillName checks the name of a skill can be used as a single element of the paths the skill is written to.
+func checkSkillName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("invalid skill name %q for a path element", name)
+	}
+	return nil
+}
+
+func contentSample(content string, lines int) string {
+	var b strings.Builder
+	for i, line := range strings.SplitN(content, "\n", lines+1) {
+		if i == lines {
+			break
+		}
+		b.WriteString(fmt.Sprintf("%d: %s\n", i+1, line))
+	}
+	return b.String()
 }
 
 func renderToolDescription(matters []FrontMatter) (string, error) {
@@ -19,11 +19,15 @@ package skill
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/adk/filesystem"
 	"github.com/cloudwego/eino/components/tool"
 )
 
@@ -177,3 +181,160 @@ func TestSkillToolName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "load_skill", info.Name)
 }
+
+func TestToolOffloadsLargeSkill(t *testing.T) {
+	ctx := context.Background()
+	lines := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("step %d", i))
+	}
+	backend := &inMemoryBackend{m: []Skill{
+		{FrontMatter: FrontMatter{Name: "large"}, Content: strings.Join(lines, "\n"), BaseDirectory: "/skills/large"},
+		{FrontMatter: FrontMatter{Name: "small"}, Content: "short", BaseDirectory: "/skills/small"},
+	}}
+	fsBackend := filesystem.NewInMemoryBackend()
+
+	m, err := New(ctx, &Config{
+		Backend:              backend,
+		OffloadingBackend:    fsBackend,
+		OffloadingTokenLimit: 100,
+	})
+	assert.NoError(t, err)
+	to := m.AdditionalTools[0].(tool.InvokableTool)
+
+	result, err := to.InvokableRun(ctx, `{"skill": "large"}`)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result, "Launching skill: large\nBase directory for this skill: /skills/large\n"))
+	offloadedPath := "/large_skill_content/large/" + contentHash(backend.m[0].Content)
+	assert.Contains(t, result, "saved in the filesystem at this path: "+offloadedPath)
+	assert.Contains(t, result, "'read_file' tool")
+	assert.Contains(t, result, "10: step 9\n")
+	assert.NotContains(t, result, "step 10")
+
+	content, err := fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: offloadedPath, Limit: 200})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "step 99")
+
+	// invoking the large skill again reuses the offloaded file
+	again, err := to.InvokableRun(ctx, `{"skill": "large"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, result, again)
+
+	result, err = to.InvokableRun(ctx, `{"skill": "small"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "Launching skill: small\nBase directory for this skill: /skills/small\n\nshort", result)
+}
+
+// writeOnlyBackend is a Backend ignoring idempotency keys, whose Write fails on existing files.
+type writeOnlyBackend struct {
+	*filesystem.InMemoryBackend
+}
+
+func (b *writeOnlyBackend) Write(ctx context.Context, req *filesystem.WriteRequest) error {
+	return b.InMemoryBackend.Write(ctx, &filesystem.WriteRequest{FilePath: req.FilePath, Content: req.Content})
+}
+
+func TestToolOffloadsLargeSkillWithoutIdempotency(t *testing.T) {
+	ctx := context.Background()
+	backend := &inMemoryBackend{m: []Skill{
+		{FrontMatter: FrontMatter{Name: "large"}, Content: strings.Repeat("step\n", 200)},
+		{FrontMatter: FrontMatter{Name: "../large"}, Content: strings.Repeat("step\n", 200)},
+	}}
+	m, err := New(ctx, &Config{
+		Backend:              backend,
+		OffloadingBackend:    &writeOnlyBackend{filesystem.NewInMemoryBackend()},
+		OffloadingTokenLimit: 100,
+	})
+	assert.NoError(t, err)
+	to := m.AdditionalTools[0].(tool.InvokableTool)
+
+	for i := 0; i < 2; i++ {
+		_, err = to.InvokableRun(ctx, `{"skill": "large"}`)
+		assert.NoError(t, err)
+	}
+
+	_, err = to.InvokableRun(ctx, `{"skill": "../large"}`)
+	assert.ErrorContains(t, err, "invalid skill name")
+}
+
+func TestToolBundlesSupportingFiles(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	skillDir := filepath.Join(tmpDir, "pdf")
+	assert.NoError(t, os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(`---
+name: pdf
+description: pdf tools
+---
+Run scripts/extract.py`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(skillDir, "scripts", "extract.py"), []byte("print('extract')"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(skillDir, "reference.md"), []byte("reference"), 0644))
+
+	backend, err := NewLocalBackend(&LocalBackendConfig{BaseDir: tmpDir})
+	assert.NoError(t, err)
+	fsBackend := filesystem.NewInMemoryBackend()
+
+	m, err := New(ctx, &Config{Backend: backend, BundleBackend: fsBackend})
+	assert.NoError(t, err)
+	to := m.AdditionalTools[0].(tool.InvokableTool)
+
+	result, err := to.InvokableRun(ctx, `{"skill": "pdf"}`)
+	assert.NoError(t, err)
+	dir := "/skills/pdf/" + contentHash("reference.md", "reference", "scripts/extract.py", "print('extract')")
+	assert.Equal(t, "Launching skill: pdf\nBase directory for this skill: "+dir+"\n\nRun scripts/extract.py", result)
+
+	content, err := fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: dir + "/scripts/extract.py", Limit: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "print('extract')")
+	content, err = fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: dir + "/reference.md", Limit: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "reference")
+	_, err = fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: dir + "/SKILL.md", Limit: 10})
+	assert.Error(t, err)
+
+	// invoking the skill again doesn't fail on the files already bundled
+	again, err := to.InvokableRun(ctx, `{"skill": "pdf"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, result, again)
+
+	// updated files are bundled again
+	assert.NoError(t, os.WriteFile(filepath.Join(skillDir, "reference.md"), []byte("updated reference"), 0644))
+	result, err = to.InvokableRun(ctx, `{"skill": "pdf"}`)
+	assert.NoError(t, err)
+	dir = "/skills/pdf/" + contentHash("reference.md", "updated reference", "scripts/extract.py", "print('extract')")
+	assert.Contains(t, result, "Base directory for this skill: "+dir+"\n")
+	content, err = fsBackend.Read(ctx, &filesystem.ReadRequest{FilePath: dir + "/reference.md", Limit: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "updated reference")
+
+	// a backend without idempotency reuses the files already bundled too
+	m, err = New(ctx, &Config{Backend: backend, BundleBackend: &writeOnlyBackend{filesystem.NewInMemoryBackend()}})
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = m.AdditionalTools[0].(tool.InvokableTool).InvokableRun(ctx, `{"skill": "pdf"}`)
+		assert.NoError(t, err)
+	}
+}
+
+type escapingBundleBackend struct {
+	inMemoryBackend
+	files map[string]string
+}
+
+func (b *escapingBundleBackend) Bundle(ctx context.Context, name string) (map[string]string, error) {
+	return b.files, nil
+}
+
+func TestToolRejectsEscapingBundlePaths(t *testing.T) {
+	ctx := context.Background()
+	for _, rel := range []string{"../outside.py", "/etc/passwd", "scripts/../../outside.py", "scripts//run.py"} {
+		backend := &escapingBundleBackend{
+			inMemoryBackend: inMemoryBackend{m: []Skill{{FrontMatter: FrontMatter{Name: "pdf"}, Content: "content"}}},
+			files:           map[string]string{rel: "content"},
+		}
+		m, err := New(ctx, &Config{Backend: backend, BundleBackend: filesystem.NewInMemoryBackend()})
+		assert.NoError(t, err)
+		_, err = m.AdditionalTools[0].(tool.InvokableTool).InvokableRun(ctx, `{"skill": "pdf"}`)
+		assert.ErrorContains(t, err, "invalid file path", rel)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// sqlTableNamePattern matches the table names NewSQLBackend accepts, optionally qualified by a schema,
+// as the name is interpolated into the queries.
+var sqlTableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLBackend is a Backend implementation that reads skills from a database table, e.g. a SQLite table,
+// for deployments managing skills in a database.
+// Each row of the table is a skill, whose name, description, content and base_directory columns are read,
+// the description and base_directory columns being nullable.
+// e.g.
+//
+//	CREATE TABLE skills (
+//		name TEXT PRIMARY KEY,
+//		description TEXT,
+//		content TEXT NOT NULL,
+//		base_directory TEXT
+//	);
+type SQLBackend struct {
+	db *sql.DB
+
+	listQuery string
+	getQuery  string
+}
+
+// NewSQLBackend creates a new SQLBackend reading skills from the table tableName of db.
+// The queries use "?" placeholders, as supported by the drivers of SQLite and MySQL.
+func NewSQLBackend(db *sql.DB, tableName string) (*SQLBackend, error) {
+	if db == nil {
+		return nil, errors.New("db is required")
+	}
+	if !sqlTableNamePattern.MatchString(tableName) {
+		return nil, fmt.Errorf("invalid table name: %q", tableName)
+	}
+
+	return &SQLBackend{
+		db:        db,
+		listQuery: fmt.Sprintf("SELECT name, description FROM %s ORDER BY name", tableName),
+		getQuery:  fmt.Sprintf("SELECT name, description, content, base_directory FROM %s WHERE name = ?", tableName),
+	}, nil
+}
+
+// List returns all skills from the table, sorted by name.
+func (b *SQLBackend) List(ctx context.Context) ([]FrontMatter, error) {
+	rows, err := b.db.QueryContext(ctx, b.listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+	defer rows.Close()
+
+	var matters []FrontMatter
+	for rows.Next() {
+		var (
+			name        string
+			description sql.NullString
+		)
+		if err = rows.Scan(&name, &description); err != nil {
+			return nil, fmt.Errorf("failed to list skills: %w", err)
+		}
+		matters = append(matters, FrontMatter{Name: name, Description: description.String})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+	return matters, nil
+}
+
+// Get returns a skill by name from the table.
+func (b *SQLBackend) Get(ctx context.Context, name string) (Skill, error) {
+	var (
+		skill                      Skill
+		description, baseDirectory sql.NullString
+	)
+	err := b.db.QueryRowContext(ctx, b.getQuery, name).Scan(&skill.Name, &description, &skill.Content, &baseDirectory)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Skill{}, fmt.Errorf("skill not found: %s", name)
+	}
+	if err != nil {
+		return Skill{}, fmt.Errorf("failed to get skill %s: %w", name, err)
+	}
+	skill.Description = description.String
+	skill.BaseDirectory = baseDirectory.String
+	return skill, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSkillTable is a database/sql connector serving the queries of SQLBackend from rows of
+// name, description, content and base_directory, sorted by name.
+type fakeSkillTable struct {
+	rows    [][]driver.Value
+	queries []string
+}
+
+func (f *fakeSkillTable) Connect(context.Context) (driver.Conn, error) {
+	return &fakeSkillConn{table: f}, nil
+}
+func (f *fakeSkillTable) Driver() driver.Driver { return nil }
+
+type fakeSkillConn struct {
+	table *fakeSkillTable
+}
+
+func (c *fakeSkillConn) Prepare(query string) (driver.Stmt, error) {
+	c.table.queries = append(c.table.queries, query)
+	return &fakeSkillStmt{table: c.table, query: query}, nil
+}
+func (c *fakeSkillConn) Close() error              { return nil }
+func (c *fakeSkillConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeSkillStmt struct {
+	table *fakeSkillTable
+	query string
+}
+
+func (s *fakeSkillStmt) Close() error  { return nil }
+func (s *fakeSkillStmt) NumInput() int { return -1 }
+func (s *fakeSkillStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeSkillStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.Contains(s.query, "WHERE name = ?") {
+		rows := &fakeSkillRows{columns: []string{"name", "description"}}
+		for _, row := range s.table.rows {
+			rows.values = append(rows.values, row[:2])
+		}
+		return rows, nil
+	}
+	rows := &fakeSkillRows{columns: []string{"name", "description", "content", "base_directory"}}
+	for _, row := range s.table.rows {
+		if row[0] == args[0] {
+			rows.values = append(rows.values, row)
+		}
+	}
+	return rows, nil
+}
+
+type fakeSkillRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeSkillRows) Columns() []string { return r.columns }
+func (r *fakeSkillRows) Close() error      { return nil }
+func (r *fakeSkillRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestSQLBackend(t *testing.T) {
+	ctx := context.Background()
+	table := &fakeSkillTable{rows: [][]driver.Value{
+		{"pdf", "pdf tools", "Run scripts/extract.py", "/skills/pdf"},
+		{"xlsx", nil, "Read reference.md", nil},
+	}}
+	db := sql.OpenDB(table)
+	defer db.Close()
+
+	backend, err := NewSQLBackend(db, "skills")
+	require.NoError(t, err)
+
+	matters, err := backend.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []FrontMatter{
+		{Name: "pdf", Description: "pdf tools"},
+		{Name: "xlsx"},
+	}, matters)
+
+	skill, err := backend.Get(ctx, "pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, Skill{
+		FrontMatter:   FrontMatter{Name: "pdf", Description: "pdf tools"},
+		Content:       "Run scripts/extract.py",
+		BaseDirectory: "/skills/pdf",
+	}, skill)
+
+	skill, err = backend.Get(ctx, "xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, "Read reference.md", skill.Content)
+	assert.Empty(t, skill.BaseDirectory)
+
+	_, err = backend.Get(ctx, "docx")
+	assert.EqualError(t, err, "skill not found: docx")
+
+	assert.Contains(t, table.queries, "SELECT name, description FROM skills ORDER BY name")
+
+	_, err = NewSQLBackend(db, "skills; DROP TABLE skills")
+	assert.ErrorContains(t, err, "invalid table name")
+	_, err = NewSQLBackend(nil, "skills")
+	assert.ErrorContains(t, err, "db is required")
+}
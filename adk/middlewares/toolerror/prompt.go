@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package toolerror
+
+const (
+	defaultHint = `Check the arguments against the parameters schema and fix them before calling the tool again. Do not repeat a call that failed with the same arguments.`
+
+	guidancePrompt = `[Tool call failed]
+Tool: {tool_name}
+Error: {error}
+
+Arguments of the failed call:
+{arguments}
+{schema_section}
+Hint: {hint}`
+
+	schemaSection = `
+Parameters schema of the tool:
+{schema}
+`
+
+	exitPrompt = `[Tool call failed]
+Tool: {tool_name}
+Error: {error}
+
+The tool failed {failures} times in a row, so the agent stops here.`
+)
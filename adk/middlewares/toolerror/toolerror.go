@@ -0,0 +1,236 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package toolerror provides a middleware that turns tool errors into guidance for the model's next attempt.
+package toolerror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+
+	"github.com/slongfield/pyfmt"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// sessionKeyPrefix prefixes the session keys under which the consecutive failures of a tool are counted.
+const sessionKeyPrefix = "_eino_tool_error_failures_"
+
+// Config is the configuration for the tool error middleware.
+type Config struct {
+	// Tools are the tools whose parameters schema is included in the guidance, usually the tools of the agent.
+	// Errors of other tools get guidance as well, without the schema.
+	// optional
+	Tools []tool.BaseTool
+	// Hint overrides the default hint ending the guidance.
+	// optional
+	Hint *string
+	// MaxConsecutiveFailures caps the consecutive failures of the same tool. When a tool fails that many times
+	// in a row, the agent emits an Exit action and stops, with the last error as its final output.
+	// The failures are counted in the run's session values, so the agent must be run through adk.Runner.
+	// optional, 0 by default, which means no cap
+	MaxConsecutiveFailures int
+}
+
+// NewMiddleware creates a middleware that returns tool errors to the model as the tool result instead of failing the run,
+// together with guidance for the next attempt: the failing arguments, the parameters schema of the tool and a hint.
+// Interrupts raised by tools are passed through. For streaming tools, an error the stream fails with is replaced by
+// the guidance after the output already streamed, and isn't counted toward MaxConsecutiveFailures,
+// as the tool call has returned by then.
+func NewMiddleware(ctx context.Context, config *Config) (adk.AgentMiddleware, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if config.MaxConsecutiveFailures < 0 {
+		return adk.AgentMiddleware{}, fmt.Errorf("MaxConsecutiveFailures must not be negative, got %d", config.MaxConsecutiveFailures)
+	}
+
+	g := &guide{
+		hint:        defaultHint,
+		maxFailures: config.MaxConsecutiveFailures,
+		schemas:     make(map[string]string, len(config.Tools)),
+	}
+	if config.Hint != nil {
+		g.hint = *config.Hint
+	}
+	for _, t := range config.Tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return adk.AgentMiddleware{}, fmt.Errorf("failed to get tool info: %w", err)
+		}
+		js, err := info.ToJSONSchema()
+		if err != nil {
+			return adk.AgentMiddleware{}, fmt.Errorf("failed to get parameters schema of tool %s: %w", info.Name, err)
+		}
+		if js == nil {
+			continue
+		}
+		b, err := json.MarshalIndent(js, "", "  ")
+		if err != nil {
+			return adk.AgentMiddleware{}, fmt.Errorf("failed to marshal parameters schema of tool %s: %w", info.Name, err)
+		}
+		g.schemas[info.Name] = string(b)
+	}
+
+	return adk.AgentMiddleware{
+		WrapToolCall: compose.ToolMiddleware{
+			Invokable:  g.invokable,
+			Streamable: g.streamable,
+		},
+		// the run ends with the last error when the failures are capped
+		SetsReturnDirectly: g.maxFailures > 0,
+	}, nil
+}
+
+type guide struct {
+	hint        string
+	maxFailures int
+	schemas     map[string]string
+}
+
+func (g *guide) invokable(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+	return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+		output, err := next(ctx, input)
+		if err == nil {
+			g.resetFailures(ctx, input.Name)
+			return output, nil
+		}
+		result, err := g.handleError(ctx, input, err)
+		if err != nil {
+			return nil, err
+		}
+		return &compose.ToolOutput{Result: result}, nil
+	}
+}
+
+func (g *guide) streamable(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+	return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+		output, err := next(ctx, input)
+		if err == nil {
+			g.resetFailures(ctx, input.Name)
+			return &compose.StreamToolOutput{Result: g.guideStream(input, output.Result)}, nil
+		}
+		result, err := g.handleError(ctx, input, err)
+		if err != nil {
+			return nil, err
+		}
+		return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{result})}, nil
+	}
+}
+
+// handleError returns the tool result replacing toolErr, or toolErr itself if it must be passed through.
+func (g *guide) handleError(ctx context.Context, input *compose.ToolInput, toolErr error) (string, error) {
+	if _, ok := compose.IsInterruptRerunError(toolErr); ok {
+		return "", toolErr
+	}
+
+	failures := g.countFailure(ctx, input.Name)
+	if g.maxFailures > 0 && failures >= g.maxFailures {
+		if err := adk.SendToolGenAction(ctx, input.Name, adk.NewExitAction()); err != nil {
+			return "", err
+		}
+		if err := adk.SetReturnDirectly(ctx); err != nil {
+			return "", err
+		}
+		return pyfmt.Must(exitPrompt, map[string]any{
+			"tool_name": input.Name,
+			"error":     toolErr.Error(),
+			"failures":  failures,
+		}), nil
+	}
+
+	return g.guidance(input, toolErr), nil
+}
+
+// guideStream returns a stream forwarding sr, which ends with the guidance in place of an error sr fails with,
+// so that the output streamed before the error reaches the model as well.
+func (g *guide) guideStream(input *compose.ToolInput, sr *schema.StreamReader[string]) *schema.StreamReader[string] {
+	out, sw := schema.Pipe[string](0)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				sw.Send("", safe.NewPanicErr(e, debug.Stack()))
+			}
+			sr.Close()
+			sw.Close()
+		}()
+		sent := false
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				if _, ok := compose.IsInterruptRerunError(err); ok {
+					sw.Send("", err)
+					return
+				}
+				result := g.guidance(input, err)
+				if sent {
+					result = "\n" + result
+				}
+				sw.Send(result, nil)
+				return
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+			sent = sent || chunk != ""
+		}
+	}()
+	return out
+}
+
+func (g *guide) guidance(input *compose.ToolInput, toolErr error) string {
+	schemaSec := ""
+	if s, ok := g.schemas[input.Name]; ok {
+		schemaSec = pyfmt.Must(schemaSection, map[string]any{"schema": s})
+	}
+	return pyfmt.Must(guidancePrompt, map[string]any{
+		"tool_name":      input.Name,
+		"error":          toolErr.Error(),
+		"arguments":      input.Arguments,
+		"schema_section": schemaSec,
+		"hint":           g.hint,
+	})
+}
+
+// countFailure increments and returns the consecutive failures of the tool, or 0 if there is no session.
+func (g *guide) countFailure(ctx context.Context, toolName string) int {
+	if g.maxFailures == 0 {
+		return 0
+	}
+	v := adk.UpdateSessionValue(ctx, sessionKeyPrefix+toolName, func(old any) any {
+		n, _ := old.(int)
+		return n + 1
+	})
+	n, _ := v.(int)
+	return n
+}
+
+func (g *guide) resetFailures(ctx context.Context, toolName string) {
+	if g.maxFailures == 0 {
+		return
+	}
+	adk.UpdateSessionValue(ctx, sessionKeyPrefix+toolName, func(any) any { return 0 })
+}
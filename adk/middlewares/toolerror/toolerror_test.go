@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package toolerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type weatherTool struct {
+	calls int
+}
+
+func (w *weatherTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_weather",
+		Desc: "get the weather of a city",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"city": {Type: schema.String, Desc: "the city name", Required: true},
+		}),
+	}, nil
+}
+
+func (w *weatherTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	w.calls++
+	var args struct {
+		City string `json:"city"`
+	}
+	if err := sonic.UnmarshalString(argumentsInJSON, &args); err != nil {
+		return "", err
+	}
+	if args.City == "" {
+		return "", errors.New("city is required")
+	}
+	return "sunny in " + args.City, nil
+}
+
+// runAgent runs an agent calling get_weather with the given arguments in turn, and returns the tool results
+// seen by the model and the last event.
+func runAgent(t *testing.T, mw adk.AgentMiddleware, wt *weatherTool, arguments []string) ([]string, *adk.AgentEvent) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	var toolResults []string
+	step := 0
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			if last := input[len(input)-1]; last.Role == schema.Tool {
+				toolResults = append(toolResults, last.Content)
+			}
+			step++
+			if step > len(arguments) {
+				return schema.AssistantMessage("done", nil), nil
+			}
+			return schema.AssistantMessage("", []schema.ToolCall{{
+				ID:       fmt.Sprintf("call-%d", step),
+				Function: schema.FunctionCall{Name: "get_weather", Arguments: arguments[step-1]},
+			}}), nil
+		}).AnyTimes()
+
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "TestAgent",
+		Description: "Test agent with a failing tool",
+		Instruction: "You are a helpful assistant.",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{wt}}},
+		Middlewares: []adk.AgentMiddleware{mw},
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "what's the weather")
+	var lastEvent *adk.AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		lastEvent = event
+	}
+	return toolResults, lastEvent
+}
+
+func TestToolErrorGuidance(t *testing.T) {
+	ctx := context.Background()
+	wt := &weatherTool{}
+	mw, err := NewMiddleware(ctx, &Config{Tools: []tool.BaseTool{wt}, MaxConsecutiveFailures: 3})
+	assert.NoError(t, err)
+
+	toolResults, lastEvent := runAgent(t, mw, wt, []string{`{"town":"Paris"}`, `{"town":"Paris"}`, `{"town":"Paris"}`})
+
+	assert.Equal(t, 3, wt.calls)
+	assert.Len(t, toolResults, 2)
+	for _, result := range toolResults {
+		assert.Contains(t, result, "Tool: get_weather")
+		assert.Contains(t, result, "Error: city is required")
+		assert.Contains(t, result, `{"town":"Paris"}`)
+		assert.Contains(t, result, `"city"`)
+		assert.Contains(t, result, "Hint: "+defaultHint)
+	}
+
+	assert.NotNil(t, lastEvent.Action)
+	assert.True(t, lastEvent.Action.Exit)
+	assert.Equal(t, schema.Tool, lastEvent.Output.MessageOutput.Role)
+	assert.Contains(t, lastEvent.Output.MessageOutput.Message.Content, "failed 3 times in a row")
+}
+
+func TestToolErrorGuidance_SuccessResetsFailures(t *testing.T) {
+	ctx := context.Background()
+	wt := &weatherTool{}
+	mw, err := NewMiddleware(ctx, &Config{MaxConsecutiveFailures: 2})
+	assert.NoError(t, err)
+
+	toolResults, lastEvent := runAgent(t, mw, wt, []string{`{}`, `{"city":"Paris"}`, `{}`, `{"city":"Rome"}`})
+
+	assert.Equal(t, 4, wt.calls)
+	assert.Len(t, toolResults, 4)
+	assert.Contains(t, toolResults[0], "Error: city is required")
+	assert.NotContains(t, toolResults[0], "Parameters schema")
+	assert.Equal(t, "sunny in Paris", toolResults[1])
+	assert.Contains(t, toolResults[2], "Error: city is required")
+	assert.Equal(t, "sunny in Rome", toolResults[3])
+	assert.Nil(t, lastEvent.Action)
+	assert.Equal(t, "done", lastEvent.Output.MessageOutput.Message.Content)
+}
+
+func TestToolErrorGuidance_StreamError(t *testing.T) {
+	ctx := context.Background()
+	mw, err := NewMiddleware(ctx, nil)
+	assert.NoError(t, err)
+
+	sr, sw := schema.Pipe[string](3)
+	sw.Send("line1\n", nil)
+	sw.Send("line2", nil)
+	sw.Send("", errors.New("connection reset"))
+	sw.Close()
+	output, err := mw.WrapToolCall.Streamable(func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+		return &compose.StreamToolOutput{Result: sr}, nil
+	})(ctx, &compose.ToolInput{Name: "tail", Arguments: `{}`})
+	assert.NoError(t, err)
+
+	var result string
+	for {
+		chunk, err := output.Result.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		result += chunk
+	}
+	assert.True(t, strings.HasPrefix(result, "line1\nline2\n[Tool call failed]"), result)
+	assert.Contains(t, result, "Error: connection reset")
+}
+
+func TestNewMiddleware_InvalidConfig(t *testing.T) {
+	_, err := NewMiddleware(context.Background(), &Config{MaxConsecutiveFailures: -1})
+	assert.Error(t, err)
+}
@@ -50,7 +50,13 @@ type Config struct {
 	Instruction string
 	// SubAgents are specialized agents that can be invoked by the agent.
 	SubAgents []adk.Agent
+	// SubAgentConfigs describe specialized agents built by the Deep agent to be invoked like SubAgents,
+	// each with its own tools rather than the ToolsConfig of the Deep agent, e.g. a research agent with search tools only.
+	SubAgentConfigs []*SubAgentConfig
 	// ToolsConfig provides the tools and tool-calling configurations available for the agent to invoke.
+	// With EmitInternalEvents, the events of the sub-agents dispatched by the task tool are forwarded to the event
+	// stream of the Deep agent as they're produced, e.g. the chunks of their streamed messages when running in
+	// streaming mode, rather than only their final result being returned to the Deep agent by the task tool.
 	ToolsConfig adk.ToolsConfig
 	// MaxIteration limits the maximum number of reasoning iterations the agent can perform.
 	MaxIteration int
@@ -72,6 +78,25 @@ type Config struct {
 	OutputKey string
 }
 
+// SubAgentConfig defines a sub-agent built by the Deep agent.
+type SubAgentConfig struct {
+	// Name is the identifier of the sub-agent, used as subagent_type by the task tool.
+	Name string
+	// Description tells the Deep agent what the sub-agent is for.
+	Description string
+	// Instruction is the system prompt of the sub-agent.
+	// Optional. Defaults to the instruction of the Deep agent.
+	Instruction string
+	// ChatModel is the model used by the sub-agent.
+	// Optional. Defaults to the ChatModel of the Deep agent.
+	ChatModel model.ToolCallingChatModel
+	// ToolsConfig provides the only tools available to the sub-agent.
+	ToolsConfig adk.ToolsConfig
+	// Middlewares of the sub-agent. The Middlewares of the Deep agent aren't applied to it,
+	// so that they don't add tools beyond ToolsConfig.
+	Middlewares []adk.AgentMiddleware
+}
+
 // New creates a new Deep agent instance with the provided configuration.
 // This function initializes built-in tools, creates a task tool for subagent orchestration,
 // and returns a fully configured ChatModelAgent ready for execution.
@@ -86,11 +111,12 @@ func New(ctx context.Context, cfg *Config) (adk.ResumableAgent, error) {
 		instruction = baseAgentInstruction
 	}
 
-	if !cfg.WithoutGeneralSubAgent || len(cfg.SubAgents) > 0 {
+	if !cfg.WithoutGeneralSubAgent || len(cfg.SubAgents) > 0 || len(cfg.SubAgentConfigs) > 0 {
 		tt, err := newTaskToolMiddleware(
 			ctx,
 			cfg.TaskToolDescriptionGenerator,
 			cfg.SubAgents,
+			cfg.SubAgentConfigs,
 
 			cfg.WithoutGeneralSubAgent,
 			cfg.ChatModel,
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -28,6 +29,7 @@ import (
 	"github.com/cloudwego/eino/adk/prebuilt/planexecute"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
 	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
 	"github.com/cloudwego/eino/schema"
 )
@@ -212,6 +214,99 @@ func (s *spyStreamingSubAgent) Run(ctx context.Context, input *adk.AgentInput, _
 	return it
 }
 
+// streamingSubAgent streams a message whose second chunk is only sent once release is closed.
+type streamingSubAgent struct {
+	release chan struct{}
+}
+
+func (s *streamingSubAgent) Name(context.Context) string        { return "streaming-subagent" }
+func (s *streamingSubAgent) Description(context.Context) string { return "streaming" }
+func (s *streamingSubAgent) Run(_ context.Context, _ *adk.AgentInput, _ ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	it, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	sr, sw := schema.Pipe[*schema.Message](2)
+	gen.Send(adk.EventFromMessage(nil, sr, schema.Assistant, ""))
+	go func() {
+		defer gen.Close()
+		defer sw.Close()
+		sw.Send(schema.AssistantMessage("first ", nil), nil)
+		<-s.release
+		sw.Send(schema.AssistantMessage("second", nil), nil)
+	}()
+	return it
+}
+
+func TestDeepSubAgentStreamsEvents(t *testing.T) {
+	ctx := context.Background()
+	sub := &streamingSubAgent{release: make(chan struct{})}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	gomock.InOrder(
+		cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.StreamReaderFromArray([]*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       "id-1",
+					Type:     "function",
+					Function: schema.FunctionCall{Name: taskToolName, Arguments: fmt.Sprintf(`{"subagent_type":"%s","description":"stream it"}`, sub.Name(ctx))},
+				}}),
+			}), nil),
+		cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("done", nil)}), nil),
+	)
+
+	agent, err := New(ctx, &Config{
+		Name:                   "deep",
+		Description:            "deep agent",
+		ChatModel:              cm,
+		SubAgents:              []adk.Agent{sub},
+		ToolsConfig:            adk.ToolsConfig{EmitInternalEvents: true},
+		MaxIteration:           4,
+		WithoutWriteTodos:      true,
+		WithoutGeneralSubAgent: true,
+	})
+	assert.NoError(t, err)
+
+	it := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, EnableStreaming: true}).Query(ctx, "hi")
+	done := make(chan []string)
+	go func() {
+		var chunks []string
+		for {
+			event, ok := it.Next()
+			if !ok {
+				done <- chunks
+				return
+			}
+			assert.NoError(t, event.Err)
+			if event.AgentName != sub.Name(ctx) {
+				continue
+			}
+			stream := event.Output.MessageOutput.MessageStream
+			chunk, err := stream.Recv()
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk.Content)
+			// the first chunk surfaces while the sub-agent is still running
+			close(sub.release)
+			for {
+				chunk, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				chunks = append(chunks, chunk.Content)
+			}
+		}
+	}()
+
+	select {
+	case chunks := <-done:
+		assert.Equal(t, []string{"first ", "second"}, chunks)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the chunks of the sub-agent didn't surface before it completed")
+	}
+}
+
 func TestDeepAgentWithPlanExecuteSubAgent_InternalEventsEmitted(t *testing.T) {
 	ctx := context.Background()
 
@@ -549,3 +644,195 @@ func (s *sessionCaptureAgent) Run(ctx context.Context, input *adk.AgentInput, op
 	}()
 	return it
 }
+
+type namedTool struct {
+	name string
+}
+
+func (n *namedTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: n.name, Desc: n.name}, nil
+}
+
+func (n *namedTool) InvokableRun(_ context.Context, _ string, _ ...tool.Option) (string, error) {
+	return n.name, nil
+}
+
+func TestDeepSubAgentConfigTools(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	calls := 0
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, msgs []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			calls++
+			if calls == 1 {
+				c := schema.ToolCall{ID: "id-1", Type: "function"}
+				c.Function.Name = taskToolName
+				c.Function.Arguments = `{"subagent_type":"research","description":"search for it"}`
+				return schema.AssistantMessage("", []schema.ToolCall{c}), nil
+			}
+			return schema.AssistantMessage("done", nil), nil
+		}).Times(2)
+
+	var subAgentTools []string
+	subCM := mockModel.NewMockToolCallingChatModel(ctrl)
+	subCM.EXPECT().WithTools(gomock.Any()).
+		DoAndReturn(func(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+			for _, ti := range tools {
+				subAgentTools = append(subAgentTools, ti.Name)
+			}
+			return subCM, nil
+		}).AnyTimes()
+	subCM.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("found", nil), nil).Times(1)
+
+	agent, err := New(ctx, &Config{
+		Name:        "deep",
+		Description: "deep agent",
+		ChatModel:   cm,
+		Instruction: "you are deep agent",
+		ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{
+			Tools: []tool.BaseTool{&namedTool{name: "write"}},
+		}},
+		SubAgentConfigs: []*SubAgentConfig{{
+			Name:        "research",
+			Description: "researches things",
+			ChatModel:   subCM,
+			ToolsConfig: adk.ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{
+				Tools: []tool.BaseTool{&namedTool{name: "search"}},
+			}},
+		}},
+		MaxIteration:           4,
+		WithoutWriteTodos:      true,
+		WithoutGeneralSubAgent: true,
+	})
+	assert.NoError(t, err)
+
+	it := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "hi")
+	for {
+		event, ok := it.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	assert.Equal(t, []string{"search"}, subAgentTools)
+}
+
+func TestDeepHiddenWriteTodosEvents(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, hidden bool) (events []*adk.AgentEvent, history []*schema.Message) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		gomock.InOrder(
+			cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(schema.AssistantMessage("", []schema.ToolCall{{
+					ID:       "id-1",
+					Type:     "function",
+					Function: schema.FunctionCall{Name: "write_todos", Arguments: `{"todos":[{"content":"c","activeForm":"","status":"pending"}]}`},
+				}}), nil),
+			cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, msgs []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+					history = msgs
+					return schema.AssistantMessage("done", nil), nil
+				}),
+		)
+
+		agent, err := New(ctx, &Config{
+			Name:                   "deep",
+			Description:            "deep agent",
+			ChatModel:              cm,
+			ToolsConfig:            adk.ToolsConfig{HiddenTools: map[string]bool{"write_todos": hidden}},
+			MaxIteration:           4,
+			WithoutGeneralSubAgent: true,
+		})
+		assert.NoError(t, err)
+
+		it := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "hi")
+		for {
+			event, ok := it.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			events = append(events, event)
+		}
+		return events, history
+	}
+
+	t.Run("visible", func(t *testing.T) {
+		events, history := run(t, false)
+		assert.Len(t, events, 3)
+		assert.Equal(t, "write_todos", events[0].Output.MessageOutput.Message.ToolCalls[0].Function.Name)
+		assert.Equal(t, "write_todos", events[1].Output.MessageOutput.ToolName)
+		assert.Equal(t, "done", events[2].Output.MessageOutput.Message.Content)
+		assert.Equal(t, schema.Tool, history[len(history)-1].Role)
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		events, history := run(t, true)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "done", events[0].Output.MessageOutput.Message.Content)
+		// still recorded in the history of the agent
+		assert.Equal(t, schema.Tool, history[len(history)-1].Role)
+		assert.Equal(t, "write_todos", history[len(history)-1].ToolName)
+	})
+}
+
+func TestDeepTaskDecisionEvent(t *testing.T) {
+	ctx := context.Background()
+	spy := &spySubAgent{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	gomock.InOrder(
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("the spy knows the answer", []schema.ToolCall{{
+				ID:       "id-1",
+				Type:     "function",
+				Function: schema.FunctionCall{Name: taskToolName, Arguments: fmt.Sprintf(`{"subagent_type":"%s","description":"find it"}`, spy.Name(ctx))},
+			}}), nil),
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(schema.AssistantMessage("done", nil), nil),
+	)
+
+	agent, err := New(ctx, &Config{
+		Name:                   "deep",
+		Description:            "deep agent",
+		ChatModel:              cm,
+		SubAgents:              []adk.Agent{spy},
+		MaxIteration:           4,
+		WithoutWriteTodos:      true,
+		WithoutGeneralSubAgent: true,
+	})
+	assert.NoError(t, err)
+
+	var decision *TaskDecision
+	it := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "hi")
+	for {
+		event, ok := it.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Output != nil && event.Output.MessageOutput.ToolName == taskToolName {
+			assert.NotNil(t, event.Action)
+			decision, _ = event.Action.CustomizedAction.(*TaskDecision)
+		}
+	}
+
+	assert.Equal(t, &TaskDecision{
+		SubAgent:    spy.Name(ctx),
+		Description: "find it",
+		Rationale:   "the spy knows the answer",
+	}, decision)
+}
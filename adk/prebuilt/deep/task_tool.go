@@ -28,6 +28,7 @@ import (
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -35,6 +36,7 @@ func newTaskToolMiddleware(
 	ctx context.Context,
 	taskToolDescriptionGenerator func(ctx context.Context, subAgents []adk.Agent) (string, error),
 	subAgents []adk.Agent,
+	subAgentConfigs []*SubAgentConfig,
 
 	withoutGeneralSubAgent bool,
 	cm model.ToolCallingChatModel,
@@ -43,7 +45,7 @@ func newTaskToolMiddleware(
 	maxIteration int,
 	middlewares []adk.AgentMiddleware,
 ) (adk.AgentMiddleware, error) {
-	t, err := newTaskTool(ctx, taskToolDescriptionGenerator, subAgents, withoutGeneralSubAgent, cm, instruction, toolsConfig, maxIteration, middlewares)
+	t, err := newTaskTool(ctx, taskToolDescriptionGenerator, subAgents, subAgentConfigs, withoutGeneralSubAgent, cm, instruction, toolsConfig, maxIteration, middlewares)
 	if err != nil {
 		return adk.AgentMiddleware{}, err
 	}
@@ -57,6 +59,7 @@ func newTaskTool(
 	ctx context.Context,
 	taskToolDescriptionGenerator func(ctx context.Context, subAgents []adk.Agent) (string, error),
 	subAgents []adk.Agent,
+	subAgentConfigs []*SubAgentConfig,
 
 	withoutGeneralSubAgent bool,
 	Model model.ToolCallingChatModel,
@@ -98,6 +101,36 @@ func newTaskTool(
 		t.subAgentSlice = append(t.subAgentSlice, generalAgent)
 	}
 
+	for _, sc := range subAgentConfigs {
+		instruction, cm := sc.Instruction, sc.ChatModel
+		if len(instruction) == 0 {
+			instruction = Instruction
+		}
+		if cm == nil {
+			cm = Model
+		}
+		a, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+			Name:          sc.Name,
+			Description:   sc.Description,
+			Instruction:   instruction,
+			Model:         cm,
+			ToolsConfig:   sc.ToolsConfig,
+			MaxIterations: MaxIteration,
+			Middlewares:   sc.Middlewares,
+			GenModelInput: genModelInput,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to new sub-agent %s: %w", sc.Name, err)
+		}
+
+		it, err := assertAgentTool(adk.NewAgentTool(ctx, a))
+		if err != nil {
+			return nil, err
+		}
+		t.subAgents[sc.Name] = it
+		t.subAgentSlice = append(t.subAgentSlice, a)
+	}
+
 	for _, a := range subAgents {
 		name := a.Name(ctx)
 		it, err := assertAgentTool(adk.NewAgentTool(ctx, a))
@@ -151,6 +184,8 @@ func (t *taskTool) InvokableRun(ctx context.Context, argumentsInJSON string, opt
 		return "", fmt.Errorf("subagent type %s not found", input.SubagentType)
 	}
 
+	sendTaskDecision(ctx, &TaskDecision{SubAgent: input.SubagentType, Description: input.Description})
+
 	params, err := sonic.MarshalString(map[string]string{
 		"request": input.Description,
 	})
@@ -161,6 +196,21 @@ func (t *taskTool) InvokableRun(ctx context.Context, argumentsInJSON string, opt
 	return a.InvokableRun(ctx, params, opts...)
 }
 
+// sendTaskDecision attaches decision to the event of the task tool result, completed with the rationale
+// found in the state of the ChatModelAgent. Outside a ChatModelAgent, there's no event to attach it to.
+func sendTaskDecision(ctx context.Context, decision *TaskDecision) {
+	_ = compose.ProcessState(ctx, func(_ context.Context, st *adk.State) error {
+		if n := len(st.Messages); n > 0 && st.Messages[n-1].Role == schema.Assistant {
+			decision.Rationale = st.Messages[n-1].Content
+			if decision.Rationale == "" {
+				decision.Rationale = st.Messages[n-1].ReasoningContent
+			}
+		}
+		return nil
+	})
+	_ = adk.SendToolGenAction(ctx, taskToolName, &adk.AgentAction{CustomizedAction: decision})
+}
+
 func defaultTaskToolDescription(ctx context.Context, subAgents []adk.Agent) (string, error) {
 	subAgentsDescBuilder := strings.Builder{}
 	for _, a := range subAgents {
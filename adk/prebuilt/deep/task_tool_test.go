@@ -34,6 +34,7 @@ func TestTaskTool(t *testing.T) {
 		ctx,
 		nil,
 		[]adk.Agent{a1, a2},
+		nil,
 		true,
 		nil,
 		"",
@@ -31,6 +31,18 @@ const (
 	SessionKeyTodos = "deep_agent_session_key_todos"
 )
 
+// TaskDecision records the dispatch of a task to a sub-agent by the Deep agent, for observers to audit its plan execution.
+// It is the AgentAction.CustomizedAction of the event of the task tool result.
+type TaskDecision struct {
+	// SubAgent is the name of the sub-agent the task is dispatched to.
+	SubAgent string
+	// Description is the task given to the sub-agent.
+	Description string
+	// Rationale is the text of the assistant message calling the task tool, if any,
+	// i.e. what the model said, or else reasoned, when deciding to dispatch the task.
+	Rationale string
+}
+
 func assertAgentTool(t tool.BaseTool) (tool.InvokableTool, error) {
 	it, ok := t.(tool.InvokableTool)
 	if !ok {
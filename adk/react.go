@@ -20,8 +20,10 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sort"
 
 	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 )
@@ -106,6 +108,23 @@ func SendToolGenAction(ctx context.Context, toolName string, action *AgentAction
 	})
 }
 
+// SetReturnDirectly makes the ChatModelAgent return the result of the current tool call as its final output,
+// as if the tool were in ToolsConfig.ReturnDirectly, e.g. for a tool middleware to end the run.
+// It must be called before the tool call returns, and is intended for use within ChatModelAgent runs only,
+// by the WrapToolCall of an AgentMiddleware with SetsReturnDirectly or by a tool of an agent with ReturnDirectly tools.
+func SetReturnDirectly(ctx context.Context) error {
+	toolCallID := compose.GetToolCallID(ctx)
+	if len(toolCallID) == 0 {
+		return errors.New("SetReturnDirectly must be called within a tool call")
+	}
+
+	return compose.ProcessState(ctx, func(ctx context.Context, st *State) error {
+		st.ReturnDirectlyToolCallID = toolCallID
+		st.HasReturnDirectly = true
+		return nil
+	})
+}
+
 func popToolGenAction(ctx context.Context, toolName string) *AgentAction {
 	toolCallID := compose.GetToolCallID(ctx)
 
@@ -184,6 +203,11 @@ type reactConfig struct {
 
 	toolsReturnDirectly map[string]bool
 
+	// setsReturnDirectly is set if the tool calls may call SetReturnDirectly.
+	setsReturnDirectly bool
+
+	toolPriority map[string]int
+
 	agentName string
 
 	maxIterations int
@@ -193,9 +217,30 @@ type reactConfig struct {
 	modelRetryConfig *ModelRetryConfig
 }
 
-func genToolInfos(ctx context.Context, config *compose.ToolsNodeConfig) ([]*schema.ToolInfo, error) {
-	toolInfos := make([]*schema.ToolInfo, 0, len(config.Tools))
-	for _, t := range config.Tools {
+func genToolInfos(ctx context.Context, config *compose.ToolsNodeConfig, priority map[string]int) ([]*schema.ToolInfo, error) {
+	infos, err := ToolInfos(ctx, config.Tools)
+	if err != nil {
+		return nil, err
+	}
+	sortToolInfos(infos, priority)
+	return infos, nil
+}
+
+// sortToolInfos sorts infos by descending priority, see ToolsConfig.ToolPriority, keeping the order of those with equal ones.
+func sortToolInfos(infos []*schema.ToolInfo, priority map[string]int) {
+	if len(priority) == 0 {
+		return
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return priority[infos[i].Name] > priority[infos[j].Name]
+	})
+}
+
+// ToolInfos returns the infos of tools, in order, to bind them to a ChatModel with WithTools,
+// e.g. in a custom agent loop using the tools of middlewares.
+func ToolInfos(ctx context.Context, tools []tool.BaseTool) ([]*schema.ToolInfo, error) {
+	toolInfos := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
 		tl, err := t.Info(ctx)
 		if err != nil {
 			return nil, err
@@ -246,7 +291,7 @@ func newReact(ctx context.Context, config *reactConfig) (reactGraph, error) {
 
 	g := compose.NewGraph[[]Message, Message](compose.WithGenLocalState(genState))
 
-	toolsInfo, err := genToolInfos(ctx, config.toolsConfig)
+	toolsInfo, err := genToolInfos(ctx, config.toolsConfig, config.toolPriority)
 	if err != nil {
 		return nil, err
 	}
@@ -342,7 +387,7 @@ func newReact(ctx context.Context, config *reactConfig) (reactGraph, error) {
 	branch := compose.NewStreamGraphBranch(toolCallCheck, map[string]bool{compose.END: true, toolNode_: true})
 	_ = g.AddBranch(chatModel_, branch)
 
-	if len(config.toolsReturnDirectly) == 0 {
+	if len(config.toolsReturnDirectly) == 0 && !config.setsReturnDirectly {
 		_ = g.AddEdge(toolNode_, chatModel_)
 	} else {
 		const (
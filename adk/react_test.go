@@ -498,6 +498,40 @@ func TestReact(t *testing.T) {
 
 // Helper types and functions for testing
 
+type graphNodesCallback struct {
+	nodes map[string]bool
+}
+
+func (c *graphNodesCallback) OnFinish(_ context.Context, info *compose.GraphInfo) {
+	c.nodes = make(map[string]bool, len(info.Nodes))
+	for key := range info.Nodes {
+		c.nodes[key] = true
+	}
+}
+
+func TestReactReturnDirectlyTopology(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	nodes := func(config *reactConfig) map[string]bool {
+		config.model = cm
+		config.toolsConfig = &compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}}
+		graph, err := newReact(ctx, config)
+		assert.NoError(t, err)
+		cb := &graphNodesCallback{}
+		_, err = graph.Compile(ctx, compose.WithGraphCompileCallbacks(cb))
+		assert.NoError(t, err)
+		return cb.nodes
+	}
+
+	// the converter ending the run with a tool result is only added when some tool calls may return directly
+	assert.False(t, nodes(&reactConfig{})["ToolNodeToEndConverter"])
+	assert.True(t, nodes(&reactConfig{toolsReturnDirectly: map[string]bool{"test_tool": true}})["ToolNodeToEndConverter"])
+	assert.True(t, nodes(&reactConfig{setsReturnDirectly: true})["ToolNodeToEndConverter"])
+}
+
 type fakeStreamToolForTest struct {
 	tarCount int
 	curCount int
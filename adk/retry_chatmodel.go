@@ -117,8 +117,19 @@ type ModelRetryConfig struct {
 	// base delay 100ms, exponentially increasing up to 10s max,
 	// with random jitter (0-50% of delay) to prevent thundering herd.
 	BackoffFunc func(ctx context.Context, attempt int) time.Duration
+
+	// DisableRetryAfter makes retries always wait by BackoffFunc. By default, when the error implements
+	// model.RetryAfterError, e.g. for a rate-limited response with a Retry-After header,
+	// the retry waits the duration asked by the provider instead.
+	DisableRetryAfter bool
+
+	// MaxRetryAfter caps the duration waited for the retry-after of an error.
+	// optional, 10s by default, the max delay of the default backoff
+	MaxRetryAfter time.Duration
 }
 
+const defaultMaxRetryAfter = 10 * time.Second
+
 func defaultIsRetryAble(_ context.Context, err error) bool {
 	return err != nil
 }
@@ -144,6 +155,37 @@ func defaultBackoff(_ context.Context, attempt int) time.Duration {
 	return delay + jitter
 }
 
+// retryDelay returns the delay before the retry attempt after err, honoring the retry-after of err if any.
+func retryDelay(ctx context.Context, config *ModelRetryConfig, backoffFunc func(context.Context, int) time.Duration,
+	attempt int, err error) time.Duration {
+
+	if !config.DisableRetryAfter {
+		if d, ok := model.GetRetryAfter(err); ok {
+			maxRetryAfter := config.MaxRetryAfter
+			if maxRetryAfter <= 0 {
+				maxRetryAfter = defaultMaxRetryAfter
+			}
+			if d > maxRetryAfter {
+				d = maxRetryAfter
+			}
+			return d
+		}
+	}
+	return backoffFunc(ctx, attempt)
+}
+
+// waitRetry waits d before a retry attempt, returning the error of ctx if it is done meanwhile.
+func waitRetry(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func genErrWrapper(ctx context.Context, config ModelRetryConfig, info streamRetryInfo) func(error) error {
 	return func(err error) error {
 		isRetryAble := config.IsRetryAble == nil || config.IsRetryAble(ctx, err)
@@ -214,7 +256,9 @@ func (r *retryChatModel) Generate(ctx context.Context, input []*schema.Message,
 		lastErr = err
 		if attempt < r.config.MaxRetries {
 			log.Printf("retrying ChatModel.Generate (attempt %d/%d): %v", attempt+1, r.config.MaxRetries, err)
-			time.Sleep(backoffFunc(ctx, attempt+1))
+			if waitErr := waitRetry(ctx, retryDelay(ctx, r.config, backoffFunc, attempt+1, err)); waitErr != nil {
+				return nil, waitErr
+			}
 		}
 	}
 
@@ -282,7 +326,9 @@ func (r *retryChatModel) Stream(ctx context.Context, input []*schema.Message, op
 			lastErr = err
 			if attempt < r.config.MaxRetries {
 				log.Printf("retrying ChatModel.Stream (attempt %d/%d): %v", attempt+1, r.config.MaxRetries, err)
-				time.Sleep(backoffFunc(ctx, attempt+1))
+				if waitErr := waitRetry(ctx, retryDelay(ctx, r.config, backoffFunc, attempt+1, err)); waitErr != nil {
+					return nil, waitErr
+				}
 			}
 			continue
 		}
@@ -304,7 +350,9 @@ func (r *retryChatModel) Stream(ctx context.Context, input []*schema.Message, op
 		lastErr = streamErr
 		if attempt < r.config.MaxRetries {
 			log.Printf("retrying ChatModel.Stream (attempt %d/%d): %v", attempt+1, r.config.MaxRetries, streamErr)
-			time.Sleep(backoffFunc(ctx, attempt+1))
+			if waitErr := waitRetry(ctx, retryDelay(ctx, r.config, backoffFunc, attempt+1, streamErr)); waitErr != nil {
+				return nil, waitErr
+			}
 		}
 	}
 
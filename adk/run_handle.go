@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunStatus is the status of a run started by a Runner.
+type RunStatus string
+
+const (
+	// RunStatusRunning means the run is still producing events.
+	RunStatusRunning RunStatus = "running"
+	// RunStatusCompleted means the run ended normally.
+	RunStatusCompleted RunStatus = "completed"
+	// RunStatusInterrupted means the run ended with an interrupt, and may be resumed from its checkpoint.
+	RunStatusInterrupted RunStatus = "interrupted"
+	// RunStatusFailed means the last event of the run carried an error.
+	RunStatusFailed RunStatus = "failed"
+	// RunStatusCanceled means the run ended after RunHandle.Cancel was called.
+	RunStatusCanceled RunStatus = "canceled"
+)
+
+// RunHandle identifies a run started by Runner.Run, Runner.Query, Runner.Resume or Runner.ResumeWithParams,
+// to inspect or cancel it, e.g. from an admin endpoint or on graceful shutdown.
+type RunHandle struct {
+	// ID is a unique id generated for the run.
+	ID string
+	// CheckPointID is the checkpoint id of the run, empty if it has none.
+	CheckPointID string
+	// StartTime is when the run started.
+	StartTime time.Time
+
+	cancel     context.CancelFunc
+	trajectory *trajectoryRecorder
+
+	mu       sync.Mutex
+	status   RunStatus
+	canceled bool
+}
+
+// Cancel cancels the context of the run. The run ends once the agent returns on the canceled context,
+// and ends with RunStatusCanceled. Canceling a run that already ended has no effect.
+func (h *RunHandle) Cancel() {
+	h.mu.Lock()
+	if h.status == RunStatusRunning {
+		h.canceled = true
+	}
+	h.mu.Unlock()
+
+	h.cancel()
+}
+
+// Status returns the current status of the run.
+func (h *RunHandle) Status() RunStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// ActiveRuns returns the runs of the Runner which are still running, in the order they started.
+// A run is removed once its event iterator is closed.
+// Runs are only tracked if RunnerConfig.EnableRunTracking is set, otherwise it returns none.
+func (r *Runner) ActiveRuns() []*RunHandle {
+	r.runsMu.Lock()
+	defer r.runsMu.Unlock()
+
+	runs := make([]*RunHandle, len(r.runs))
+	copy(runs, r.runs)
+	return runs
+}
+
+// startRun starts a new run, registered if runs are tracked, returning its handle and the context to run the agent with.
+func (r *Runner) startRun(ctx context.Context, checkPointID *string) (context.Context, *RunHandle) {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &RunHandle{
+		ID:        uuid.NewString(),
+		StartTime: time.Now(),
+		cancel:    cancel,
+		status:    RunStatusRunning,
+	}
+	if checkPointID != nil {
+		h.CheckPointID = *checkPointID
+	}
+
+	if r.trackRuns {
+		r.runsMu.Lock()
+		r.runs = append(r.runs, h)
+		r.runsMu.Unlock()
+	}
+
+	return ctx, h
+}
+
+// finishRun unregisters the run and sets its final status from its last event.
+func (r *Runner) finishRun(h *RunHandle, lastEvent *AgentEvent) {
+	r.runsMu.Lock()
+	for i, run := range r.runs {
+		if run == h {
+			r.runs = append(r.runs[:i], r.runs[i+1:]...)
+			break
+		}
+	}
+	r.runsMu.Unlock()
+
+	h.mu.Lock()
+	switch {
+	case h.canceled:
+		h.status = RunStatusCanceled
+	case lastEvent != nil && lastEvent.Err != nil:
+		h.status = RunStatusFailed
+	case lastEvent != nil && lastEvent.Action != nil && lastEvent.Action.Interrupted != nil:
+		h.status = RunStatusInterrupted
+	default:
+		h.status = RunStatusCompleted
+	}
+	h.mu.Unlock()
+
+	h.cancel()
+}
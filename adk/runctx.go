@@ -36,6 +36,40 @@ type runSession struct {
 	Events     []*agentEventWrapper
 	LaneEvents *laneEvents
 	mtx        sync.Mutex
+
+	// State is the internal state of the run, shared by the sessions sharing Values.
+	State *runState
+
+	// maxEvents bounds the events retained in Events, see RunnerConfig.MaxSessionEvents.
+	maxEvents int
+}
+
+// runState CheckpointSchema: persisted via serialization.RunCtx (gob).
+// runState is the state the framework keeps for a run, apart from the session values visible to users.
+type runState struct {
+	mu sync.Mutex
+
+	// InactiveMiddlewares maps the addresses of ChatModelAgents to the indexes of their middlewares
+	// not applying to their runs, see ChatModelAgent.inactiveMiddlewares.
+	InactiveMiddlewares map[string][]int
+	// TransferChain is the names of the agents traversed by transfers, see GetTransferChain.
+	TransferChain []string
+}
+
+func (s *runState) getInactiveMiddlewares(addr string) ([]int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	indexes, ok := s.InactiveMiddlewares[addr]
+	return indexes, ok
+}
+
+func (s *runState) setInactiveMiddlewares(addr string, indexes []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.InactiveMiddlewares == nil {
+		s.InactiveMiddlewares = make(map[string][]int)
+	}
+	s.InactiveMiddlewares[addr] = indexes
 }
 
 // laneEvents CheckpointSchema: persisted via serialization.RunCtx (gob).
@@ -85,6 +119,7 @@ func newRunSession() *runSession {
 	return &runSession{
 		Values:    make(map[string]any),
 		valuesMtx: &sync.Mutex{},
+		State:     &runState{},
 	}
 }
 
@@ -128,6 +163,52 @@ func GetSessionValue(ctx context.Context, key string) (any, bool) {
 	return session.getValue(key)
 }
 
+// UpdateSessionValue atomically replaces the session value of key with the result of update,
+// and returns the new value. update receives the current value, or nil if the key is not set.
+// The update is performed under the session lock, so concurrent sub-agents can safely perform
+// read-modify-write operations such as incrementing a shared counter.
+// update must not call other session value functions, otherwise it deadlocks.
+// It does nothing and returns nil if ctx carries no run session.
+func UpdateSessionValue(ctx context.Context, key string, update func(old any) any) any {
+	session := getSession(ctx)
+	if session == nil {
+		return nil
+	}
+
+	return session.updateValue(key, update)
+}
+
+// GetTransferChain returns the names of the agents the current run went through by transferring, in order,
+// starting with the agent making the first transfer, e.g. [router, planner, executor].
+// It returns nil if no transfer has happened or ctx carries no run session.
+func GetTransferChain(ctx context.Context) []string {
+	session := getSession(ctx)
+	if session == nil || session.State == nil {
+		return nil
+	}
+
+	state := session.State
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return append([]string(nil), state.TransferChain...)
+}
+
+// appendTransferChain records the transfer from agent fromName to agent toName in the transfer chain of the run.
+func appendTransferChain(ctx context.Context, fromName, toName string) {
+	session := getSession(ctx)
+	if session == nil || session.State == nil {
+		return
+	}
+
+	state := session.State
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if len(state.TransferChain) == 0 {
+		state.TransferChain = []string{fromName}
+	}
+	state.TransferChain = append(state.TransferChain, toName)
+}
+
 func (rs *runSession) addEvent(event *AgentEvent) {
 	wrapper := &agentEventWrapper{AgentEvent: event, TS: time.Now().UnixNano()}
 	// If LaneEvents is not nil, we are in a parallel lane.
@@ -140,9 +221,33 @@ func (rs *runSession) addEvent(event *AgentEvent) {
 	// Otherwise, we are on the main path. Append to the shared Events slice (with lock).
 	rs.mtx.Lock()
 	rs.Events = append(rs.Events, wrapper)
+	rs.trimEventsLocked()
 	rs.mtx.Unlock()
 }
 
+// trimEventsLocked drops the oldest events beyond maxEvents. The dropped slots are cleared,
+// and are released along with the backing array once appending reallocates it.
+// Events are dropped at turn boundaries: the tool results following a dropped tool call message are dropped along
+// with it, since models reject tool messages without the assistant message calling them.
+func (rs *runSession) trimEventsLocked() {
+	if rs.maxEvents <= 0 || len(rs.Events) <= rs.maxEvents {
+		return
+	}
+	dropped := len(rs.Events) - rs.maxEvents
+	for dropped < len(rs.Events) && isToolResultEvent(rs.Events[dropped].AgentEvent) {
+		dropped++
+	}
+	for i := 0; i < dropped; i++ {
+		rs.Events[i] = nil
+	}
+	rs.Events = rs.Events[dropped:]
+}
+
+func isToolResultEvent(event *AgentEvent) bool {
+	return event != nil && event.Output != nil && event.Output.MessageOutput != nil &&
+		event.Output.MessageOutput.Role == schema.Tool
+}
+
 func (rs *runSession) getEvents() []*agentEventWrapper {
 	// If there are no in-flight lane events, we can return the main slice directly.
 	if rs.LaneEvents == nil {
@@ -205,6 +310,15 @@ func (rs *runSession) addValues(kvs map[string]any) {
 	rs.valuesMtx.Unlock()
 }
 
+func (rs *runSession) updateValue(key string, update func(old any) any) any {
+	rs.valuesMtx.Lock()
+	defer rs.valuesMtx.Unlock()
+
+	value := update(rs.Values[key])
+	rs.Values[key] = value
+	return value
+}
+
 func (rs *runSession) getValue(key string) (any, bool) {
 	rs.valuesMtx.Lock()
 	value, ok := rs.Values[key]
@@ -218,6 +332,10 @@ type runContext struct {
 	RunPath   []RunStep
 
 	Session *runSession
+
+	// History holds the history entries of the previous runs of the conversation, see WithConversationID.
+	// They precede RootInput and the events of the run in the input of each agent, rewritten the same way.
+	History []*HistoryEntry
 }
 
 func (rc *runContext) isRoot() bool {
@@ -229,6 +347,7 @@ func (rc *runContext) deepCopy() *runContext {
 		RootInput: rc.RootInput,
 		RunPath:   make([]RunStep, len(rc.RunPath)),
 		Session:   rc.Session,
+		History:   rc.History,
 	}
 
 	copy(copied.RunPath, rc.RunPath)
@@ -304,6 +423,7 @@ func commitEvents(ctx context.Context, newEvents []*agentEventWrapper) {
 		// Otherwise, commit to the main, shared Events slice with a lock.
 		runCtx.Session.mtx.Lock()
 		runCtx.Session.Events = append(runCtx.Session.Events, newEvents...)
+		runCtx.Session.trimEventsLocked()
 		runCtx.Session.mtx.Unlock()
 	}
 }
@@ -334,6 +454,7 @@ func forkRunCtx(ctx context.Context) context.Context {
 		Events:    parentRunCtx.Session.Events, // Share the committed history
 		Values:    parentRunCtx.Session.Values, // Share the values map
 		valuesMtx: parentRunCtx.Session.valuesMtx,
+		State:     parentRunCtx.Session.State,
 	}
 
 	// Fork the lane events within the new session struct.
@@ -387,6 +508,7 @@ func ctxWithNewRunCtx(ctx context.Context, input *AgentInput, sharedParentSessio
 			session = &runSession{
 				Values:    parentSession.Values,
 				valuesMtx: parentSession.valuesMtx,
+				State:     parentSession.State,
 			}
 		}
 	}
@@ -18,6 +18,7 @@ package adk
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -334,6 +335,78 @@ func TestSessionValues(t *testing.T) {
 	})
 }
 
+func TestUpdateSessionValue(t *testing.T) {
+	t.Run("EmptyContext", func(t *testing.T) {
+		ctx := context.Background()
+		v := UpdateSessionValue(ctx, "key", func(old any) any { return 1 })
+		assert.Nil(t, v)
+		assert.Empty(t, GetSessionValues(ctx))
+	})
+
+	t.Run("ConcurrentSubAgents", func(t *testing.T) {
+		ctx := context.Background()
+		const agents, increments = 8, 100
+
+		subAgents := make([]Agent, 0, agents)
+		for i := 0; i < agents; i++ {
+			subAgents = append(subAgents, &dtTestAgent{
+				name: fmt.Sprintf("counter_%d", i),
+				runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+					iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+					go func() {
+						defer gen.Close()
+						for j := 0; j < increments; j++ {
+							UpdateSessionValue(ctx, "counter", func(old any) any {
+								n, _ := old.(int)
+								return n + 1
+							})
+						}
+					}()
+					return iter
+				},
+			})
+		}
+
+		parallel, err := NewParallelAgent(ctx, &ParallelAgentConfig{
+			Name:        "parallel",
+			Description: "parallel counters",
+			SubAgents:   subAgents,
+		})
+		assert.NoError(t, err)
+
+		var counter any
+		root := &dtTestAgent{
+			name: "root",
+			runFn: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+				iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+				go func() {
+					defer gen.Close()
+					subIter := parallel.Run(ctx, input, options...)
+					for {
+						event, ok := subIter.Next()
+						if !ok {
+							break
+						}
+						gen.Send(event)
+					}
+					counter, _ = GetSessionValue(ctx, "counter")
+				}()
+				return iter
+			},
+		}
+
+		iter := NewRunner(ctx, RunnerConfig{Agent: root}).Query(ctx, "count")
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+		}
+		assert.Equal(t, agents*increments, counter)
+	})
+}
+
 func TestForkJoinRunCtx(t *testing.T) {
 	// Helper to create a named event
 	newEvent := func(name string) *AgentEvent {
@@ -423,3 +496,38 @@ func TestForkJoinRunCtx(t *testing.T) {
 	mainRunCtx.Session.addEvent(eventF)
 	assert.Equal(t, []string{"A", "B", "C1", "D", "E", "F"}, getEventNames(mainRunCtx.Session.getEvents()), "After F")
 }
+
+func TestSessionMaxEvents(t *testing.T) {
+	session := newRunSession()
+	session.maxEvents = 5
+	for i := 0; i < 1000; i++ {
+		session.addEvent(EventFromMessage(schema.AssistantMessage(fmt.Sprintf("event %d", i), nil), nil, schema.Assistant, ""))
+	}
+
+	events := session.getEvents()
+	assert.Len(t, events, 5)
+	assert.LessOrEqual(t, cap(session.Events), 4*session.maxEvents)
+	for i, e := range events {
+		assert.Equal(t, fmt.Sprintf("event %d", 995+i), e.Output.MessageOutput.Message.Content)
+	}
+}
+
+func TestSessionMaxEventsKeepsToolCallGroups(t *testing.T) {
+	session := newRunSession()
+	session.maxEvents = 3
+	toolCall := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "search"}},
+		{ID: "2", Function: schema.FunctionCall{Name: "search"}},
+	})
+	session.addEvent(EventFromMessage(schema.AssistantMessage("hi", nil), nil, schema.Assistant, ""))
+	session.addEvent(EventFromMessage(toolCall, nil, schema.Assistant, ""))
+	session.addEvent(EventFromMessage(schema.ToolMessage("r1", "1"), nil, schema.Tool, "search"))
+	session.addEvent(EventFromMessage(schema.ToolMessage("r2", "2"), nil, schema.Tool, "search"))
+	assert.Len(t, session.getEvents(), 3)
+
+	// the limit falls inside the tool call group, which is dropped whole
+	session.addEvent(EventFromMessage(schema.AssistantMessage("done", nil), nil, schema.Assistant, ""))
+	events := session.getEvents()
+	assert.Len(t, events, 1)
+	assert.Equal(t, "done", events[0].Output.MessageOutput.Message.Content)
+}
@@ -18,6 +18,7 @@ package adk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"sync"
@@ -37,6 +38,14 @@ type Runner struct {
 	// store is the checkpoint store used to persist agent state upon interruption.
 	// If nil, checkpointing is disabled.
 	store CheckPointStore
+	// maxSessionEvents bounds the events retained in the session, see RunnerConfig.MaxSessionEvents.
+	maxSessionEvents int
+
+	// trackRuns registers the runs for ActiveRuns, see RunnerConfig.EnableRunTracking.
+	trackRuns bool
+	// runs holds the handles of the active runs, in the order they started.
+	runsMu sync.Mutex
+	runs   []*RunHandle
 }
 
 type CheckPointStore = core.CheckPointStore
@@ -45,7 +54,31 @@ type RunnerConfig struct {
 	Agent           Agent
 	EnableStreaming bool
 
+	// CheckPointStore persists the state of a run started with WithCheckPointID when it's interrupted:
+	// the session events the history of the agents is built from, the session values and the state of the pending
+	// interrupts, keyed by the checkpoint ID.
+	// As the checkpoint holds the whole state of the run, a Runner created afresh with the same store, e.g. after the
+	// service restarted, resumes the run exactly with Resume or ResumeWithParams.
+	// It also persists the conversations of the runs started with WithConversationID, across runs.
+	// The types of the session values and interrupt states must be registered by schema.RegisterName to be persisted.
+	// Optional. If nil, runs can't be resumed.
 	CheckPointStore CheckPointStore
+
+	// MaxSessionEvents bounds the number of events retained in the session of a run, so that a long run
+	// doesn't grow memory, nor its checkpoints, without limit. When exceeded, the oldest events are dropped:
+	// the history given to agents, built from the session events, and the events saved in checkpoints
+	// only cover the latest MaxSessionEvents events, from which runs are resumed as usual.
+	// A tool call message is dropped along with all of its tool results, so that the history never holds
+	// tool results without their call, and fewer than MaxSessionEvents events may be retained.
+	// Optional. If not positive, all events are retained.
+	MaxSessionEvents int
+
+	// EnableRunTracking registers the runs of the Runner while they are running, so that they can be listed
+	// by ActiveRuns and canceled. The events of each run are then forwarded through a goroutine,
+	// to find when the run ends.
+	// Optional, false by default, in which case a run without CheckPointStore nor WithTrajectoryWriter
+	// returns the event iterator of the agent as is.
+	EnableRunTracking bool
 }
 
 // ResumeParams contains all parameters needed to resume an execution.
@@ -65,10 +98,14 @@ func NewRunner(_ context.Context, conf RunnerConfig) *Runner {
 		enableStreaming: conf.EnableStreaming,
 		a:               conf.Agent,
 		store:           conf.CheckPointStore,
+
+		maxSessionEvents: conf.MaxSessionEvents,
+		trackRuns:        conf.EnableRunTracking,
 	}
 }
 
 // Run starts a new execution of the agent with a given set of messages.
+// The messages can be replaced or extended by WithInitialMessages and WithAppendedMessages.
 // It returns an iterator that yields agent events as they occur.
 // If the Runner was configured with a CheckPointStore, it will automatically save the agent's state
 // upon interruption.
@@ -78,23 +115,56 @@ func (r *Runner) Run(ctx context.Context, messages []Message,
 
 	fa := toFlowAgent(ctx, r.a)
 
+	messages = getCommonOptions(nil, filterOptions(r.a.Name(ctx), opts)...).inputMessages(messages)
+	var conversation *runContext
+	if o.conversationID != nil {
+		var err error
+		conversation, err = r.loadConversation(ctx, *o.conversationID)
+		if err != nil {
+			return genErrorIter(err)
+		}
+		o.checkPointID = o.conversationID
+	}
+
 	input := &AgentInput{
 		Messages:        messages,
 		EnableStreaming: r.enableStreaming,
 	}
 
 	ctx = ctxWithNewRunCtx(ctx, input, o.sharedParentSession)
+	getSession(ctx).maxEvents = r.maxSessionEvents
 
+	if conversation != nil {
+		getRunCtx(ctx).History = conversation.History
+		AddSessionValues(ctx, conversation.Session.Values)
+	}
 	AddSessionValues(ctx, o.sessionValues)
 
-	iter := fa.Run(ctx, input, opts...)
 	if r.store == nil {
-		return iter
+		trajectory := newTrajectoryRecorder(o.trajectoryWriter, input.Messages)
+		if trajectory == nil && !r.trackRuns {
+			// nothing follows the events of the run
+			return fa.Run(ctx, input, opts...)
+		}
+		ctx, h := r.startRun(ctx, nil)
+		h.trajectory = trajectory
+		iter := fa.Run(ctx, input, opts...)
+		niter, gen := NewAsyncIteratorPair[*AgentEvent]()
+		go r.forwardIter(iter, gen, h)
+		return niter
 	}
 
+	ctx, h := r.startRun(ctx, o.checkPointID)
+	h.trajectory = newTrajectoryRecorder(o.trajectoryWriter, input.Messages)
+	iter := fa.Run(ctx, input, opts...)
+
 	niter, gen := NewAsyncIteratorPair[*AgentEvent]()
 
-	go r.handleIter(ctx, iter, gen, o.checkPointID)
+	var conversationID string
+	if o.conversationID != nil {
+		conversationID = *o.conversationID
+	}
+	go r.handleIter(ctx, iter, gen, o.checkPointID, conversationID, h)
 	return niter
 }
 
@@ -105,6 +175,77 @@ func (r *Runner) Query(ctx context.Context,
 	return r.Run(ctx, []Message{schema.UserMessage(query)}, opts...)
 }
 
+// ErrRunInterrupted is returned by RunToCompletion when the run is interrupted instead of completing.
+// If the Runner has a CheckPointStore, the run can be resumed from the checkpoint as usual.
+var ErrRunInterrupted = errors.New("agent run interrupted")
+
+// RunToCompletion runs the agent like Run, drains all the events, and returns the final output of the run:
+// the output of the last event that isn't a transfer to another agent, with a streaming message concatenated into
+// a non-streaming one. It returns the error of the first event carrying one, or ErrRunInterrupted if the run
+// is interrupted, and a nil output if no event has any.
+func (r *Runner) RunToCompletion(ctx context.Context, messages []Message, opts ...AgentRunOption) (*AgentOutput, error) {
+	return drainToCompletion(r.Run(ctx, messages, opts...))
+}
+
+// RunToCompletion runs agent with input by a Runner without CheckPointStore, see Runner.RunToCompletion.
+// e.g.
+//
+//	output, err := adk.RunToCompletion(ctx, agent, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("hi")}})
+func RunToCompletion(ctx context.Context, agent Agent, input *AgentInput, opts ...AgentRunOption) (*AgentOutput, error) {
+	r := NewRunner(ctx, RunnerConfig{Agent: agent, EnableStreaming: input.EnableStreaming})
+	return r.RunToCompletion(ctx, input.Messages, opts...)
+}
+
+func drainToCompletion(iter *AsyncIterator[*AgentEvent]) (*AgentOutput, error) {
+	var (
+		output      *AgentOutput
+		interrupted bool
+		err         error
+	)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		var msg Message
+		if event.Output != nil && event.Output.MessageOutput != nil {
+			// streams are always consumed, so that the agent isn't blocked writing them
+			var err_ error
+			msg, err_ = event.Output.MessageOutput.GetMessage()
+			if err_ != nil && err == nil {
+				err = err_
+			}
+		}
+		if err != nil {
+			continue
+		}
+		if event.Err != nil {
+			err = event.Err
+			continue
+		}
+
+		interrupted = event.Action != nil && event.Action.Interrupted != nil
+		if event.Output == nil || event.Action != nil && event.Action.TransferToAgent != nil {
+			continue
+		}
+
+		o := *event.Output
+		if mo := o.MessageOutput; mo != nil {
+			o.MessageOutput = &MessageVariant{Message: msg, Role: mo.Role, ToolName: mo.ToolName}
+		}
+		output = &o
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if interrupted {
+		return nil, ErrRunInterrupted
+	}
+	return output, nil
+}
+
 // Resume continues an interrupted execution from a checkpoint, using an "Implicit Resume All" strategy.
 // This method is best for simpler use cases where the act of resuming implies that all previously
 // interrupted points should proceed without specific data.
@@ -146,10 +287,11 @@ func (r *Runner) resume(ctx context.Context, checkPointID string, resumeData map
 		return nil, fmt.Errorf("failed to resume: store is nil")
 	}
 
-	ctx, runCtx, resumeInfo, err := r.loadCheckPoint(ctx, checkPointID)
+	ctx, s, resumeInfo, err := r.loadCheckPoint(ctx, checkPointID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load from checkpoint: %w", err)
 	}
+	runCtx := s.RunCtx
 
 	o := getCommonOptions(nil, opts...)
 	if o.sharedParentSession {
@@ -157,14 +299,22 @@ func (r *Runner) resume(ctx context.Context, checkPointID string, resumeData map
 		if parentSession != nil {
 			runCtx.Session.Values = parentSession.Values
 			runCtx.Session.valuesMtx = parentSession.valuesMtx
+			runCtx.Session.State = parentSession.State
 		}
 	}
+	runCtx.Session.mtx.Lock()
+	runCtx.Session.maxEvents = r.maxSessionEvents
+	runCtx.Session.trimEventsLocked()
+	runCtx.Session.mtx.Unlock()
 	if runCtx.Session.valuesMtx == nil {
 		runCtx.Session.valuesMtx = &sync.Mutex{}
 	}
 	if runCtx.Session.Values == nil {
 		runCtx.Session.Values = make(map[string]any)
 	}
+	if runCtx.Session.State == nil {
+		runCtx.Session.State = &runState{}
+	}
 
 	ctx = setRunCtx(ctx, runCtx)
 
@@ -174,27 +324,59 @@ func (r *Runner) resume(ctx context.Context, checkPointID string, resumeData map
 		ctx = core.BatchResumeWithData(ctx, resumeData)
 	}
 
+	ctx, h := r.startRun(ctx, &checkPointID)
+	h.trajectory = newTrajectoryRecorder(o.trajectoryWriter, nil)
 	fa := toFlowAgent(ctx, r.a)
 	aIter := fa.Resume(ctx, resumeInfo, opts...)
-	if r.store == nil {
-		return aIter, nil
-	}
 
 	niter, gen := NewAsyncIteratorPair[*AgentEvent]()
 
-	go r.handleIter(ctx, aIter, gen, &checkPointID)
+	go r.handleIter(ctx, aIter, gen, &checkPointID, s.ConversationID, h)
 	return niter, nil
 }
 
+// forwardIter forwards the events of a run without checkpointing, and finishes the run once they end.
+func (r *Runner) forwardIter(aIter *AsyncIterator[*AgentEvent], gen *AsyncGenerator[*AgentEvent], h *RunHandle) {
+	var lastEvent *AgentEvent
+	defer func() {
+		if errEvent := r.exportTrajectory(h, lastEvent); errEvent != nil {
+			lastEvent = errEvent
+			gen.Send(errEvent)
+		}
+		r.finishRun(h, lastEvent)
+		gen.Close()
+	}()
+
+	for {
+		event, ok := aIter.Next()
+		if !ok {
+			return
+		}
+		h.trajectory.record(event)
+		lastEvent = event
+		gen.Send(event)
+	}
+}
+
 func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEvent],
-	gen *AsyncGenerator[*AgentEvent], checkPointID *string) {
+	gen *AsyncGenerator[*AgentEvent], checkPointID *string, conversationID string, h *RunHandle) {
+	var (
+		lastEvent *AgentEvent
+		failed    bool
+	)
 	defer func() {
 		panicErr := recover()
 		if panicErr != nil {
 			e := safe.NewPanicErr(panicErr, debug.Stack())
-			gen.Send(&AgentEvent{Err: e})
+			lastEvent = &AgentEvent{Err: e}
+			gen.Send(lastEvent)
 		}
 
+		if errEvent := r.exportTrajectory(h, lastEvent); errEvent != nil {
+			lastEvent = errEvent
+			gen.Send(errEvent)
+		}
+		r.finishRun(h, lastEvent)
 		gen.Close()
 	}()
 	var (
@@ -235,13 +417,23 @@ func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEven
 				// so when end-user receives interrupt event, they can resume from this checkpoint
 				err := r.saveCheckPoint(ctx, *checkPointID, &InterruptInfo{
 					Data: legacyData,
-				}, interruptSignal)
+				}, interruptSignal, conversationID)
 				if err != nil {
 					gen.Send(&AgentEvent{Err: fmt.Errorf("failed to save checkpoint: %w", err)})
 				}
 			}
 		}
 
+		h.trajectory.record(event)
+		lastEvent = event
+		failed = failed || event.Err != nil
 		gen.Send(event)
 	}
+
+	if conversationID != "" && interruptSignal == nil && !failed && ctx.Err() == nil {
+		if err := r.saveConversation(ctx, conversationID); err != nil {
+			lastEvent = &AgentEvent{Err: fmt.Errorf("failed to save conversation: %w", err)}
+			gen.Send(lastEvent)
+		}
+	}
 }
@@ -18,6 +18,7 @@ package adk
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -261,3 +262,225 @@ func TestRunner_Query_WithStreaming(t *testing.T) {
 	_, ok = iterator.Next()
 	assert.False(t, ok)
 }
+
+func TestRunner_Run_InputMessageOptions(t *testing.T) {
+	ctx := context.Background()
+	history := []Message{schema.UserMessage("hi"), schema.AssistantMessage("hello", nil)}
+
+	contents := func(msgs []Message) []string {
+		var ret []string
+		for _, m := range msgs {
+			ret = append(ret, m.Content)
+		}
+		return ret
+	}
+
+	t.Run("initial messages replace run messages", func(t *testing.T) {
+		a := newMockRunnerAgent("TestAgent", "", nil)
+		NewRunner(ctx, RunnerConfig{Agent: a}).Run(ctx, []Message{schema.UserMessage("query")},
+			WithInitialMessages(history...))
+		assert.Equal(t, []string{"hi", "hello"}, contents(a.lastInput.Messages))
+	})
+
+	t.Run("appended messages follow run messages", func(t *testing.T) {
+		a := newMockRunnerAgent("TestAgent", "", nil)
+		NewRunner(ctx, RunnerConfig{Agent: a}).Run(ctx, history,
+			WithAppendedMessages(schema.UserMessage("query")), WithAppendedMessages(schema.UserMessage("more")))
+		assert.Equal(t, []string{"hi", "hello", "query", "more"}, contents(a.lastInput.Messages))
+		assert.Len(t, history, 2)
+	})
+
+	t.Run("appended messages follow initial messages", func(t *testing.T) {
+		a := newMockRunnerAgent("TestAgent", "", nil)
+		NewRunner(ctx, RunnerConfig{Agent: a}).Query(ctx, "ignored",
+			WithAppendedMessages(schema.UserMessage("query")), WithInitialMessages(history...))
+		assert.Equal(t, []string{"hi", "hello", "query"}, contents(a.lastInput.Messages))
+	})
+
+	t.Run("options designated to other agents are ignored", func(t *testing.T) {
+		a := newMockRunnerAgent("TestAgent", "", nil)
+		NewRunner(ctx, RunnerConfig{Agent: a}).Query(ctx, "query",
+			WithInitialMessages(history...).DesignateAgent("OtherAgent"))
+		assert.Equal(t, []string{"query"}, contents(a.lastInput.Messages))
+	})
+}
+
+func TestRunner_Run_Artifacts(t *testing.T) {
+	ctx := context.Background()
+	artifacts := []Artifact{
+		{Name: "chart.png", MIMEType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}},
+		{Name: "report.csv", MIMEType: "text/csv", Path: "/workspace/report.csv"},
+	}
+	agent := newMockRunnerAgent("ArtifactAgent", "agent producing artifacts", []*AgentEvent{
+		{
+			AgentName: "ArtifactAgent",
+			Output: &AgentOutput{
+				MessageOutput: &MessageVariant{
+					Message: schema.AssistantMessage("here is the chart", nil),
+					Role:    schema.Assistant,
+				},
+				Artifacts: artifacts,
+			},
+		},
+	})
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent})
+	iter := runner.Query(ctx, "draw a chart")
+
+	var received []Artifact
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Output != nil {
+			received = append(received, event.Output.Artifacts...)
+		}
+	}
+	assert.Equal(t, artifacts, received)
+}
+
+// blockingRunnerAgent emits a message once released, or an error once its context is canceled.
+type blockingRunnerAgent struct {
+	release chan struct{}
+}
+
+func (a *blockingRunnerAgent) Name(_ context.Context) string {
+	return "blocking"
+}
+
+func (a *blockingRunnerAgent) Description(_ context.Context) string {
+	return "blocks until released or canceled"
+}
+
+func (a *blockingRunnerAgent) Run(ctx context.Context, _ *AgentInput, _ ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	iterator, generator := NewAsyncIteratorPair[*AgentEvent]()
+	go func() {
+		defer generator.Close()
+		select {
+		case <-a.release:
+			generator.Send(EventFromMessage(schema.AssistantMessage("done", nil), nil, schema.Assistant, ""))
+		case <-ctx.Done():
+			generator.Send(&AgentEvent{Err: ctx.Err()})
+		}
+	}()
+	return iterator
+}
+
+func TestRunner_ActiveRuns(t *testing.T) {
+	ctx := context.Background()
+	agent := &blockingRunnerAgent{release: make(chan struct{})}
+
+	// runs are not tracked by default
+	untracked := NewRunner(ctx, RunnerConfig{Agent: agent})
+	iter := untracked.Query(ctx, "untracked")
+	assert.Empty(t, untracked.ActiveRuns())
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent, EnableRunTracking: true})
+
+	iter1 := runner.Query(ctx, "first")
+	iter2 := runner.Query(ctx, "second")
+
+	runs := runner.ActiveRuns()
+	assert.Len(t, runs, 2)
+	for _, h := range runs {
+		assert.NotEmpty(t, h.ID)
+		assert.Equal(t, RunStatusRunning, h.Status())
+	}
+	assert.NotEqual(t, runs[0].ID, runs[1].ID)
+
+	// cancel the first run, the second one keeps running
+	canceled, other := runs[0], runs[1]
+	canceled.Cancel()
+
+	event, ok := iter1.Next()
+	assert.True(t, ok)
+	assert.ErrorIs(t, event.Err, context.Canceled)
+	_, ok = iter1.Next()
+	assert.False(t, ok)
+	assert.Equal(t, RunStatusCanceled, canceled.Status())
+
+	assert.Equal(t, []*RunHandle{other}, runner.ActiveRuns())
+	assert.Equal(t, RunStatusRunning, other.Status())
+
+	close(agent.release)
+	event, ok = iter2.Next()
+	assert.True(t, ok)
+	assert.NoError(t, event.Err)
+	assert.Equal(t, "done", event.Output.MessageOutput.Message.Content)
+	_, ok = iter2.Next()
+	assert.False(t, ok)
+	assert.Equal(t, RunStatusCompleted, other.Status())
+	assert.Empty(t, runner.ActiveRuns())
+
+	event, ok = iter.Next()
+	assert.True(t, ok)
+	assert.NoError(t, event.Err)
+
+	// canceling a finished run has no effect
+	other.Cancel()
+	assert.Equal(t, RunStatusCompleted, other.Status())
+}
+
+func TestRunToCompletion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("final message", func(t *testing.T) {
+		agent := newMockRunnerAgent("TestAgent", "Test agent", []*AgentEvent{
+			EventFromMessage(schema.AssistantMessage("thinking", nil), nil, schema.Assistant, ""),
+			EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+				schema.AssistantMessage("final ", nil),
+				schema.AssistantMessage("answer", nil),
+			}), schema.Assistant, ""),
+		})
+
+		output, err := RunToCompletion(ctx, agent, &AgentInput{Messages: []Message{schema.UserMessage("hi")}, EnableStreaming: true})
+		assert.NoError(t, err)
+		assert.False(t, output.MessageOutput.IsStreaming)
+		assert.Equal(t, "final answer", output.MessageOutput.Message.Content)
+		assert.Equal(t, schema.Assistant, output.MessageOutput.Role)
+	})
+
+	t.Run("final message of the agent transferred to", func(t *testing.T) {
+		transferMsg, transferToolMsg := GenTransferMessages(ctx, "Other")
+		agent := newMockRunnerAgent("TestAgent", "Test agent", []*AgentEvent{
+			EventFromMessage(transferMsg, nil, schema.Assistant, ""),
+			{
+				Output: &AgentOutput{MessageOutput: &MessageVariant{
+					Message: transferToolMsg, Role: schema.Tool, ToolName: TransferToAgentToolName,
+				}},
+				Action: NewTransferToAgentAction("Other"),
+			},
+		})
+		other := newMockRunnerAgent("Other", "Other agent", []*AgentEvent{
+			EventFromMessage(schema.AssistantMessage("answer from other", nil), nil, schema.Assistant, ""),
+		})
+		root, err := SetSubAgents(ctx, agent, []Agent{other})
+		assert.NoError(t, err)
+
+		output, err := NewRunner(ctx, RunnerConfig{Agent: root}).RunToCompletion(ctx, []Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "answer from other", output.MessageOutput.Message.Content)
+	})
+
+	t.Run("error event", func(t *testing.T) {
+		agent := newMockRunnerAgent("TestAgent", "Test agent", []*AgentEvent{
+			EventFromMessage(schema.AssistantMessage("thinking", nil), nil, schema.Assistant, ""),
+			{Err: errors.New("model unavailable")},
+		})
+
+		output, err := NewRunner(ctx, RunnerConfig{Agent: agent}).RunToCompletion(ctx, []Message{schema.UserMessage("hi")})
+		assert.EqualError(t, err, "model unavailable")
+		assert.Nil(t, output)
+	})
+
+	t.Run("interrupt", func(t *testing.T) {
+		agent := newMockRunnerAgent("TestAgent", "Test agent", []*AgentEvent{
+			{Action: &AgentAction{Interrupted: &InterruptInfo{Data: "confirm"}}},
+		})
+
+		_, err := RunToCompletion(ctx, agent, &AgentInput{Messages: []Message{schema.UserMessage("hi")}})
+		assert.ErrorIs(t, err, ErrRunInterrupted)
+	})
+}
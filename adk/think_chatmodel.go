@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// thinkThenToolChatModel generates in two phases: a free-form generation with tools forbidden, for the model to reason,
+// then a generation forced to call tools, with the reasoning appended to the input as an assistant message.
+// The returned message carries the reasoning in ReasoningContent.
+type thinkThenToolChatModel struct {
+	inner model.ToolCallingChatModel
+}
+
+func newThinkThenToolChatModel(inner model.ToolCallingChatModel) *thinkThenToolChatModel {
+	return &thinkThenToolChatModel{inner: inner}
+}
+
+func (t *thinkThenToolChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := t.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &thinkThenToolChatModel{inner: inner}, nil
+}
+
+func (t *thinkThenToolChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	reasoning, input, err := t.think(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := t.inner.Generate(ctx, input, append(opts, model.WithToolChoice(schema.ToolChoiceForced))...)
+	if err != nil {
+		return nil, err
+	}
+	msg.ReasoningContent = reasoning
+	return msg, nil
+}
+
+func (t *thinkThenToolChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	reasoning, input, err := t.think(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := t.inner.Stream(ctx, input, append(opts, model.WithToolChoice(schema.ToolChoiceForced))...)
+	if err != nil {
+		return nil, err
+	}
+	first := true
+	return schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (*schema.Message, error) {
+		if first {
+			first = false
+			cp := *msg
+			cp.ReasoningContent = reasoning
+			return &cp, nil
+		}
+		return msg, nil
+	}), nil
+}
+
+// think runs the reasoning phase, and returns the reasoning and the input of the tool calling phase.
+func (t *thinkThenToolChatModel) think(ctx context.Context, input []*schema.Message, opts []model.Option) (
+	string, []*schema.Message, error) {
+
+	thought, err := t.inner.Generate(ctx, input, append(opts, model.WithToolChoice(schema.ToolChoiceForbidden))...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	reasoning := thought.Content
+	if thought.ReasoningContent != "" && thought.Content != "" {
+		reasoning = thought.ReasoningContent + "\n" + thought.Content
+	} else if thought.ReasoningContent != "" {
+		reasoning = thought.ReasoningContent
+	}
+
+	next := make([]*schema.Message, 0, len(input)+1)
+	next = append(next, input...)
+	next = append(next, schema.AssistantMessage(reasoning, nil))
+	return reasoning, next, nil
+}
+
+func (t *thinkThenToolChatModel) GetType() string {
+	typ, _ := components.GetType(t.inner)
+	return typ
+}
+
+func (t *thinkThenToolChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(t.inner)
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// TrajectoryTurn is a JSON line written by WithTrajectoryExport, one per turn of a completed run,
+// e.g. to harvest successful agent trajectories as fine-tuning data.
+// A turn starts with an assistant message and holds the results of the tools it called.
+//
+//	{"run_id":"...","turn":0,"agent_name":"assistant","input":[...],"assistant":{"role":"assistant","tool_calls":[...]},"tool_results":[{"role":"tool","content":"...","tool_call_id":"..."}]}
+//	{"run_id":"...","turn":1,"agent_name":"assistant","assistant":{"role":"assistant","content":"..."}}
+type TrajectoryTurn struct {
+	// RunID is the id of the run, see RunHandle.ID.
+	RunID string `json:"run_id"`
+	// Turn is the 0-based index of the turn in the run.
+	Turn int `json:"turn"`
+	// AgentName is the name of the agent which produced the assistant message.
+	AgentName string `json:"agent_name"`
+	// Input is the messages the run started with, only set on the first turn of a run started by Runner.Run or Runner.Query.
+	Input []Message `json:"input,omitempty"`
+	// Assistant is the assistant message, with streamed messages concatenated into one.
+	// It's nil if tool results were emitted before any assistant message.
+	Assistant Message `json:"assistant,omitempty"`
+	// ToolResults are the tool messages following the assistant message.
+	ToolResults []Message `json:"tool_results,omitempty"`
+}
+
+// WithTrajectoryExport writes each run of the Runner which completes without error, interrupt or cancellation
+// to w as JSON lines, one TrajectoryTurn per turn. The lines of a run are written by a single call to w.Write,
+// so w can be shared by concurrent runs if its Write is safe for concurrent use.
+// Writing to w is done before the event iterator of the run is closed, and a failure is reported as a last error event.
+func WithTrajectoryExport(w io.Writer) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.trajectoryWriter = w
+	})
+}
+
+type trajectoryMessage struct {
+	agentName string
+	role      schema.RoleType
+	msg       Message
+	stream    MessageStream
+}
+
+// trajectoryRecorder keeps the messages of a run to export them once it completes.
+type trajectoryRecorder struct {
+	w        io.Writer
+	input    []Message
+	messages []*trajectoryMessage
+}
+
+func newTrajectoryRecorder(w io.Writer, input []Message) *trajectoryRecorder {
+	if w == nil {
+		return nil
+	}
+	return &trajectoryRecorder{w: w, input: input}
+}
+
+// record keeps the message of event, copying its stream so that the event can still be consumed.
+func (t *trajectoryRecorder) record(event *AgentEvent) {
+	if t == nil || event.Output == nil || event.Output.MessageOutput == nil {
+		return
+	}
+	mv := event.Output.MessageOutput
+	if mv.Role != schema.Assistant && mv.Role != schema.Tool {
+		return
+	}
+
+	tm := &trajectoryMessage{agentName: event.AgentName, role: mv.Role}
+	if mv.IsStreaming {
+		if mv.MessageStream == nil {
+			return
+		}
+		ss := mv.MessageStream.Copy(2)
+		mv.MessageStream = ss[0]
+		tm.stream = ss[1]
+	} else {
+		if mv.Message == nil {
+			return
+		}
+		tm.msg = mv.Message
+	}
+	t.messages = append(t.messages, tm)
+}
+
+// discard closes the copied streams of a run which isn't exported.
+func (t *trajectoryRecorder) discard() {
+	if t == nil {
+		return
+	}
+	for _, m := range t.messages {
+		if m.stream != nil {
+			m.stream.Close()
+		}
+	}
+}
+
+func (t *trajectoryRecorder) export(runID string) error {
+	if t == nil {
+		return nil
+	}
+
+	var turns []*TrajectoryTurn
+	for _, m := range t.messages {
+		msg := m.msg
+		if m.stream != nil {
+			var err error
+			msg, err = schema.ConcatMessageStream(m.stream)
+			if err != nil {
+				return fmt.Errorf("failed to concat streamed message of trajectory: %w", err)
+			}
+		}
+
+		if m.role == schema.Assistant || len(turns) == 0 {
+			turn := &TrajectoryTurn{RunID: runID, Turn: len(turns), AgentName: m.agentName}
+			turns = append(turns, turn)
+			if m.role == schema.Assistant {
+				turn.Assistant = msg
+				continue
+			}
+		}
+		last := turns[len(turns)-1]
+		last.ToolResults = append(last.ToolResults, msg)
+	}
+	if len(turns) == 0 {
+		return nil
+	}
+	turns[0].Input = t.input
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, turn := range turns {
+		if err := enc.Encode(turn); err != nil {
+			return fmt.Errorf("failed to encode trajectory turn: %w", err)
+		}
+	}
+	if _, err := t.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write trajectory: %w", err)
+	}
+	return nil
+}
+
+// exportTrajectory exports the trajectory of the run if it completed, returning the error event to end the run with
+// if the export fails.
+func (r *Runner) exportTrajectory(h *RunHandle, lastEvent *AgentEvent) *AgentEvent {
+	if h.trajectory == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	canceled := h.canceled
+	h.mu.Unlock()
+	if canceled || (lastEvent != nil && (lastEvent.Err != nil ||
+		(lastEvent.Action != nil && lastEvent.Action.Interrupted != nil))) {
+		h.trajectory.discard()
+		return nil
+	}
+
+	if err := h.trajectory.export(h.ID); err != nil {
+		return &AgentEvent{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWithTrajectoryExport(t *testing.T) {
+	ctx := context.Background()
+	toolCall := schema.ToolCall{ID: "call_1", Type: "function", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"eino"}`}}
+	stream := schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("let me ", nil),
+		schema.AssistantMessage("search", []schema.ToolCall{toolCall}),
+	})
+	agent := newMockRunnerAgent("assistant", "", []*AgentEvent{
+		EventFromMessage(nil, stream, schema.Assistant, ""),
+		EventFromMessage(schema.ToolMessage("eino is a framework", "call_1", schema.WithToolName("search")), nil, schema.Tool, "search"),
+		EventFromMessage(schema.AssistantMessage("eino is a framework", nil), nil, schema.Assistant, ""),
+	})
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent, EnableStreaming: true})
+
+	var buf bytes.Buffer
+	iter := runner.Query(ctx, "what is eino?", WithTrajectoryExport(&buf))
+	var streamed []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		// the consumer still receives the whole stream
+		if event.Output.MessageOutput.IsStreaming {
+			msg, err := schema.ConcatMessageStream(event.Output.MessageOutput.MessageStream)
+			assert.NoError(t, err)
+			streamed = append(streamed, msg.Content)
+		}
+	}
+	assert.Equal(t, []string{"let me search"}, streamed)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	var runIDs []string
+	for i, line := range lines {
+		var turn TrajectoryTurn
+		assert.NoError(t, json.Unmarshal([]byte(line), &turn))
+		assert.NotEmpty(t, turn.RunID)
+		runIDs = append(runIDs, turn.RunID)
+		assert.Contains(t, line, fmt.Sprintf(`"turn":%d,"agent_name":"assistant"`, i))
+	}
+	assert.Equal(t, runIDs[0], runIDs[1])
+	assert.Contains(t, lines[0], `"input":[{"role":"user","content":"what is eino?"}]`)
+	assert.Contains(t, lines[0], `"assistant":{"role":"assistant","content":"let me search","tool_calls":[{"id":"call_1","type":"function","function":{"name":"search","arguments":"{\"q\":\"eino\"}"}}]}`)
+	assert.Contains(t, lines[0], `"tool_results":[{"role":"tool","content":"eino is a framework","tool_call_id":"call_1","tool_name":"search"}]`)
+	assert.NotContains(t, lines[1], `"input"`)
+	assert.Contains(t, lines[1], `"assistant":{"role":"assistant","content":"eino is a framework"}`)
+	assert.NotContains(t, lines[1], `"tool_results"`)
+
+	t.Run("failed runs are not exported", func(t *testing.T) {
+		agent := newMockRunnerAgent("assistant", "", []*AgentEvent{
+			EventFromMessage(schema.AssistantMessage("partial", nil), nil, schema.Assistant, ""),
+			{AgentName: "assistant", Err: errors.New("model unavailable")},
+		})
+		runner := NewRunner(ctx, RunnerConfig{Agent: agent})
+
+		var buf bytes.Buffer
+		iter := runner.Query(ctx, "hi", WithTrajectoryExport(&buf))
+		for {
+			if _, ok := iter.Next(); !ok {
+				break
+			}
+		}
+		assert.Empty(t, buf.String())
+	})
+}
@@ -50,11 +50,25 @@ func (ag *AsyncGenerator[T]) Close() {
 
 // NewAsyncIteratorPair returns a paired async iterator and generator
 // that share the same underlying channel.
+// The channel is unbounded, so AsyncGenerator.Send never blocks no matter how slowly the iterator is consumed.
 func NewAsyncIteratorPair[T any]() (*AsyncIterator[T], *AsyncGenerator[T]) {
 	ch := internal.NewUnboundedChan[T]()
 	return &AsyncIterator[T]{ch}, &AsyncGenerator[T]{ch}
 }
 
+// NewAsyncIteratorPairWithBuffer returns a paired async iterator and generator
+// that share the same underlying channel holding at most n pending items.
+//
+// Backpressure semantics: AsyncGenerator.Send returns immediately while fewer than n items are pending,
+// and blocks once n items have been sent but not yet received by AsyncIterator.Next, until the consumer
+// catches up or the generator is closed. The producer therefore never gets more than n items ahead,
+// which bounds memory usage, at the cost of the producer stalling whenever the consumer stops reading.
+// A non-positive n is equivalent to NewAsyncIteratorPair.
+func NewAsyncIteratorPairWithBuffer[T any](n int) (*AsyncIterator[T], *AsyncGenerator[T]) {
+	ch := internal.NewBoundedChan[T](n)
+	return &AsyncIterator[T]{ch}, &AsyncGenerator[T]{ch}
+}
+
 func copyMap[K comparable, V any](m map[K]V) map[K]V {
 	res := make(map[K]V, len(m))
 	for k, v := range m {
@@ -84,6 +98,17 @@ func GenTransferMessages(_ context.Context, destAgentName string) (Message, Mess
 	return assistantMessage, toolMessage
 }
 
+// GenTransferMessagesBatch generates the transfer messages for each of the destination agents in order,
+// i.e. the assistant and tool messages of GenTransferMessages for the first agent, then for the second, and so on.
+func GenTransferMessagesBatch(ctx context.Context, destAgentNames []string) []Message {
+	msgs := make([]Message, 0, 2*len(destAgentNames))
+	for _, destAgentName := range destAgentNames {
+		aMsg, tMsg := GenTransferMessages(ctx, destAgentName)
+		msgs = append(msgs, aMsg, tMsg)
+	}
+	return msgs
+}
+
 // set automatic close for event's message stream
 func setAutomaticClose(e *AgentEvent) {
 	if e.Output == nil || e.Output.MessageOutput == nil || !e.Output.MessageOutput.IsStreaming {
@@ -191,6 +216,10 @@ func copyAgentEvent(ae *AgentEvent) *AgentEvent {
 	copied.Output = &AgentOutput{
 		CustomizedOutput: ae.Output.CustomizedOutput,
 	}
+	if ae.Output.Artifacts != nil {
+		copied.Output.Artifacts = make([]Artifact, len(ae.Output.Artifacts))
+		copy(copied.Output.Artifacts, ae.Output.Artifacts)
+	}
 
 	mv := ae.Output.MessageOutput
 	if mv == nil {
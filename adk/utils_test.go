@@ -18,6 +18,7 @@ package adk
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"errors"
 	"fmt"
@@ -162,6 +163,94 @@ func TestAsyncIteratorPair_Concurrency(t *testing.T) {
 	}
 }
 
+func TestAsyncIteratorPairWithBuffer(t *testing.T) {
+	iterator, generator := NewAsyncIteratorPairWithBuffer[int](3)
+
+	// Sends within the buffer size don't block even though nothing is consumed yet
+	for i := 0; i < 3; i++ {
+		generator.Send(i)
+	}
+
+	// The next send blocks until the consumer catches up
+	sent := make(chan struct{})
+	go func() {
+		generator.Send(3)
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("send should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	val, ok := iterator.Next()
+	assert.True(t, ok)
+	assert.Equal(t, 0, val)
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send should be unblocked after the consumer receives")
+	}
+
+	generator.Close()
+	for i := 1; i <= 3; i++ {
+		val, ok = iterator.Next()
+		assert.True(t, ok)
+		assert.Equal(t, i, val)
+	}
+	_, ok = iterator.Next()
+	assert.False(t, ok)
+}
+
+func TestAsyncIteratorPairWithBuffer_NonPositiveIsUnbounded(t *testing.T) {
+	iterator, generator := NewAsyncIteratorPairWithBuffer[int](0)
+	for i := 0; i < 100; i++ {
+		generator.Send(i)
+	}
+	generator.Close()
+
+	count := 0
+	for {
+		_, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		count++
+	}
+	assert.Equal(t, 100, count)
+}
+
+// BenchmarkAsyncIteratorPairWithBuffer measures how long a bursty producer is held up by a slow consumer:
+// with a larger buffer the producer gets further ahead before it has to wait.
+func BenchmarkAsyncIteratorPairWithBuffer(b *testing.B) {
+	const burst = 64
+	for _, size := range []int{1, 8, burst} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				iterator, generator := NewAsyncIteratorPairWithBuffer[int](size)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					for {
+						if _, ok := iterator.Next(); !ok {
+							return
+						}
+						time.Sleep(time.Microsecond)
+					}
+				}()
+
+				for j := 0; j < burst; j++ {
+					generator.Send(j)
+				}
+				generator.Close()
+				<-done
+			}
+		})
+	}
+}
+
 func TestGenErrorIter(t *testing.T) {
 	iter := genErrorIter(fmt.Errorf("test"))
 	e, ok := iter.Next()
@@ -403,3 +492,58 @@ func TestAgentEventWrapper_GobEncoding_WithStreamSuccess(t *testing.T) {
 	assert.Equal(t, int64(67890), decoded.TS)
 	assert.Empty(t, decoded.StreamErr)
 }
+
+func TestAgentEventWrapper_GobEncoding_WithArtifacts(t *testing.T) {
+	event := &AgentEvent{
+		AgentName: "TestAgent",
+		Output: &AgentOutput{
+			Artifacts: []Artifact{
+				{Name: "chart.png", MIMEType: "image/png", Data: []byte("png")},
+				{Name: "report.csv", MIMEType: "text/csv", Path: "/workspace/report.csv"},
+			},
+		},
+	}
+	copied := copyAgentEvent(event)
+	assert.Equal(t, event.Output.Artifacts, copied.Output.Artifacts)
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&agentEventWrapper{AgentEvent: copied})
+	assert.NoError(t, err)
+
+	var decoded agentEventWrapper
+	err = gob.NewDecoder(&buf).Decode(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, event.Output.Artifacts, decoded.Output.Artifacts)
+}
+
+func TestGenTransferMessagesBatch(t *testing.T) {
+	ctx := context.Background()
+	names := []string{"agent_a", "agent_b", "agent_c"}
+
+	var expected []Message
+	for _, name := range names {
+		aMsg, tMsg := GenTransferMessages(ctx, name)
+		expected = append(expected, aMsg, tMsg)
+	}
+	batch := GenTransferMessagesBatch(ctx, names)
+
+	// tool call ids are random, so they are compared by pairing instead of by value
+	clearIDs := func(msgs []Message) []Message {
+		cleared := make([]Message, 0, len(msgs))
+		for i := 0; i < len(msgs); i += 2 {
+			aMsg, tMsg := *msgs[i], *msgs[i+1]
+			assert.Len(t, aMsg.ToolCalls, 1)
+			assert.Equal(t, aMsg.ToolCalls[0].ID, tMsg.ToolCallID)
+			aMsg.ToolCalls = []schema.ToolCall{aMsg.ToolCalls[0]}
+			aMsg.ToolCalls[0].ID = ""
+			tMsg.ToolCallID = ""
+			cleared = append(cleared, &aMsg, &tMsg)
+		}
+		return cleared
+	}
+	assert.Len(t, batch, 2*len(names))
+	assert.Equal(t, clearIDs(expected), clearIDs(batch))
+	for i, name := range names {
+		assert.Equal(t, name, batch[2*i].ToolCalls[0].Function.Arguments)
+	}
+}
@@ -85,3 +85,24 @@ const (
 // Eino's callback mechanism will try to use this interface to determine whether any handlers are needed for the given timing.
 // Also, the callback handler that is not needed for that timing will be skipped.
 type TimingChecker = callbacks.TimingChecker
+
+// HeartbeatHandler receives periodic heartbeats while a graph node is executing, enabled by compose.WithNodeHeartbeat,
+// e.g. to tell a long running node from a stuck one.
+// It's an optional interface for callback handlers: handlers implementing it receive the heartbeats of the nodes
+// they are called for, with the RunInfo of the node and the time elapsed since the node started.
+// OnHeartbeat is called from another goroutine than the node's, and should return quickly.
+type HeartbeatHandler = callbacks.HeartbeatHandler
+
+// StateSnapshot is the graph state captured before and after a node ran, enabled by compose.WithStateSnapshots.
+// Before and After are the JSON representations of the state, and Changes lists the fields that differ,
+// e.g. a Path of "A" or "Inner.B" for a state struct.
+type StateSnapshot = callbacks.StateSnapshot
+
+// StateChange is a field of the graph state changed by a node.
+type StateChange = callbacks.StateChange
+
+// StateSnapshotHandler receives the state snapshots of graph nodes, enabled by compose.WithStateSnapshots,
+// e.g. to find which node changed what in the shared state.
+// It's an optional interface for callback handlers: handlers implementing it receive the snapshots of the nodes
+// they are called for, with the RunInfo of the node.
+type StateSnapshotHandler = callbacks.StateSnapshotHandler
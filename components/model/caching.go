@@ -0,0 +1,233 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ResponseCache stores the responses of a caching chat model, keyed by a hash of the prompt.
+// Implementations must be safe for concurrent use.
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (*schema.Message, bool)
+	Set(ctx context.Context, key string, msg *schema.Message)
+}
+
+// NewInMemoryResponseCache returns a ResponseCache backed by an in-process map.
+// Entries expire ttl after they are set, a ttl <= 0 means entries never expire.
+func NewInMemoryResponseCache(ttl time.Duration) ResponseCache {
+	return &inMemoryResponseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]responseCacheEntry),
+	}
+}
+
+type responseCacheEntry struct {
+	msg       *schema.Message
+	expiresAt time.Time
+}
+
+type inMemoryResponseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]responseCacheEntry
+}
+
+func (c *inMemoryResponseCache) Get(_ context.Context, key string) (*schema.Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.msg, true
+}
+
+func (c *inMemoryResponseCache) Set(_ context.Context, key string, msg *schema.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := responseCacheEntry{msg: msg}
+	if c.ttl > 0 {
+		e.expiresAt = c.now().Add(c.ttl)
+	}
+	c.entries[key] = e
+}
+
+// NewCachingChatModel wraps inner so that identical prompts are answered from cache instead of calling the model.
+// The cache key is a hash of the input messages, the common options and the tools bound by WithTools.
+// If cache is nil, an in-memory cache without expiration is used.
+//
+// Stream materializes the whole response of inner before returning, caches it,
+// and replays it as a stream with a single chunk, on misses as well as on hits.
+// Calls with implementation specific options, or whose input can't be JSON encoded, bypass the cache,
+// and errors are never cached.
+// The wrapper reports the type and callback status of inner, so callbacks of a model handling them itself
+// only fire on cache misses.
+// e.g.
+//
+//	cm := model.NewCachingChatModel(openaiModel, model.NewInMemoryResponseCache(time.Hour))
+func NewCachingChatModel(inner ToolCallingChatModel, cache ResponseCache) ToolCallingChatModel {
+	if cache == nil {
+		cache = NewInMemoryResponseCache(0)
+	}
+	return &cachingChatModel{inner: inner, cache: cache}
+}
+
+type cachingChatModel struct {
+	inner ToolCallingChatModel
+	cache ResponseCache
+	tools []*schema.ToolInfo
+}
+
+func (c *cachingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	key, ok := c.cacheKey(input, opts)
+	if !ok {
+		return c.inner.Generate(ctx, input, opts...)
+	}
+	if msg, ok := c.cache.Get(ctx, key); ok {
+		return copyMessage(msg), nil
+	}
+
+	msg, err := c.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(ctx, key, copyMessage(msg))
+	return msg, nil
+}
+
+func (c *cachingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	key, ok := c.cacheKey(input, opts)
+	if !ok {
+		return c.inner.Stream(ctx, input, opts...)
+	}
+	if msg, ok := c.cache.Get(ctx, key); ok {
+		return schema.StreamReaderFromArray([]*schema.Message{copyMessage(msg)}), nil
+	}
+
+	sr, err := c.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := schema.ConcatMessageStream(sr)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(ctx, key, copyMessage(msg))
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (c *cachingChatModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	inner, err := c.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingChatModel{inner: inner, cache: c.cache, tools: tools}, nil
+}
+
+func (c *cachingChatModel) GetType() string {
+	typ, _ := components.GetType(c.inner)
+	return typ
+}
+
+func (c *cachingChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.inner)
+}
+
+func (c *cachingChatModel) cacheKey(input []*schema.Message, opts []Option) (string, bool) {
+	for _, opt := range opts {
+		if opt.implSpecificOptFn != nil {
+			return "", false
+		}
+	}
+
+	options := GetCommonOptions(nil, opts...)
+	// the parameters of ToolInfo aren't exported to JSON, so they are encoded separately
+	optionToolParams, err := toolParams(options.Tools)
+	if err != nil {
+		return "", false
+	}
+	boundToolParams, err := toolParams(c.tools)
+	if err != nil {
+		return "", false
+	}
+
+	b, err := json.Marshal(struct {
+		Messages         []*schema.Message
+		Options          *Options
+		OptionToolParams []*jsonschema.Schema
+		BoundTools       []*schema.ToolInfo
+		BoundToolParams  []*jsonschema.Schema
+	}{
+		Messages:         input,
+		Options:          options,
+		OptionToolParams: optionToolParams,
+		BoundTools:       c.tools,
+		BoundToolParams:  boundToolParams,
+	})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), true
+}
+
+func toolParams(tools []*schema.ToolInfo) ([]*jsonschema.Schema, error) {
+	params := make([]*jsonschema.Schema, 0, len(tools))
+	for _, t := range tools {
+		if t == nil {
+			params = append(params, nil)
+			continue
+		}
+		js, err := t.ToJSONSchema()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, js)
+	}
+	return params, nil
+}
+
+// copyMessage returns a copy of msg along with its tool calls and multi content,
+// so that callers modifying a returned message don't alter the cache.
+func copyMessage(msg *schema.Message) *schema.Message {
+	if msg == nil {
+		return nil
+	}
+	return msg.Copy()
+}
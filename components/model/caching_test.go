@@ -0,0 +1,201 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type countingChatModel struct {
+	generateCalls int
+	streamCalls   int
+	err           error
+	toolCalls     []schema.ToolCall
+}
+
+func (m *countingChatModel) Generate(_ context.Context, input []*schema.Message, _ ...Option) (*schema.Message, error) {
+	m.generateCalls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage("echo: "+input[len(input)-1].Content, m.toolCalls), nil
+}
+
+func (m *countingChatModel) Stream(_ context.Context, input []*schema.Message, _ ...Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	m.streamCalls++
+	return schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("echo: ", nil),
+		schema.AssistantMessage(input[len(input)-1].Content, nil),
+	}), nil
+}
+
+func (m *countingChatModel) WithTools(_ []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	return m, nil
+}
+
+func TestCachingChatModel(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("identical generate calls hit the model once", func(t *testing.T) {
+		inner := &countingChatModel{}
+		cm := NewCachingChatModel(inner, nil)
+
+		for i := 0; i < 2; i++ {
+			msg, err := cm.Generate(ctx, input, WithTemperature(0.5))
+			assert.NoError(t, err)
+			assert.Equal(t, "echo: hi", msg.Content)
+		}
+		assert.Equal(t, 1, inner.generateCalls)
+
+		_, err := cm.Generate(ctx, input, WithTemperature(0.7))
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("bye")}, WithTemperature(0.5))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, inner.generateCalls)
+	})
+
+	t.Run("tools are part of the key", func(t *testing.T) {
+		inner := &countingChatModel{}
+		cm := NewCachingChatModel(inner, nil)
+		toolWithParams := func(param string) *schema.ToolInfo {
+			return &schema.ToolInfo{
+				Name: "search",
+				ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+					param: {Type: schema.String},
+				}),
+			}
+		}
+
+		_, err := cm.Generate(ctx, input, WithTools([]*schema.ToolInfo{toolWithParams("query")}))
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input, WithTools([]*schema.ToolInfo{toolWithParams("keyword")}))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.generateCalls)
+
+		bound, err := cm.WithTools([]*schema.ToolInfo{toolWithParams("query")})
+		assert.NoError(t, err)
+		_, err = bound.Generate(ctx, input)
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, inner.generateCalls)
+	})
+
+	t.Run("impl specific options bypass the cache", func(t *testing.T) {
+		type implOptions struct{ seed int }
+		inner := &countingChatModel{}
+		cm := NewCachingChatModel(inner, nil)
+
+		for i := 0; i < 2; i++ {
+			_, err := cm.Generate(ctx, input, WrapImplSpecificOptFn(func(o *implOptions) { o.seed = 1 }))
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, inner.generateCalls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		inner := &countingChatModel{err: errors.New("unavailable")}
+		cm := NewCachingChatModel(inner, nil)
+
+		_, err := cm.Generate(ctx, input)
+		assert.Error(t, err)
+		inner.err = nil
+		msg, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+		assert.Equal(t, 2, inner.generateCalls)
+	})
+
+	t.Run("stream replays a single chunk", func(t *testing.T) {
+		inner := &countingChatModel{}
+		cm := NewCachingChatModel(inner, nil)
+
+		for i := 0; i < 2; i++ {
+			sr, err := cm.Stream(ctx, input)
+			assert.NoError(t, err)
+			msg, err := sr.Recv()
+			assert.NoError(t, err)
+			assert.Equal(t, "echo: hi", msg.Content)
+			_, err = sr.Recv()
+			assert.ErrorIs(t, err, io.EOF)
+		}
+		assert.Equal(t, 1, inner.streamCalls)
+
+		msg, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+		assert.Equal(t, 0, inner.generateCalls)
+	})
+
+	t.Run("entries expire after ttl", func(t *testing.T) {
+		now := time.Now()
+		cache := NewInMemoryResponseCache(time.Minute).(*inMemoryResponseCache)
+		cache.now = func() time.Time { return now }
+		inner := &countingChatModel{}
+		cm := NewCachingChatModel(inner, cache)
+
+		_, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		now = now.Add(30 * time.Second)
+		_, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, inner.generateCalls)
+
+		now = now.Add(time.Minute)
+		_, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.generateCalls)
+	})
+
+	t.Run("returned messages don't alter the cache", func(t *testing.T) {
+		cm := NewCachingChatModel(&countingChatModel{}, nil)
+
+		msg, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		msg.Content = "changed"
+		msg, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+	})
+
+	t.Run("returned tool calls don't alter the cache", func(t *testing.T) {
+		cm := NewCachingChatModel(&countingChatModel{toolCalls: []schema.ToolCall{
+			{ID: "call-1", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"hi"}`}},
+		}}, nil)
+
+		// on a miss, then on a hit
+		for i := 0; i < 2; i++ {
+			msg, err := cm.Generate(ctx, input)
+			assert.NoError(t, err)
+			msg.ToolCalls[0].Function.Arguments = "changed"
+		}
+		msg, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"q":"hi"}`, msg.ToolCalls[0].Function.Arguments)
+	})
+}
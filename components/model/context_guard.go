@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrContextWindowExceeded is returned by a context guarded chat model when the prompt doesn't fit its context window.
+var ErrContextWindowExceeded = errors.New("context window exceeded")
+
+// ContextOverflowHandler is called by a context guarded chat model when the estimated tokens of the prompt exceed the limit.
+// It either returns a reduced prompt, e.g. with old messages trimmed, or an error to reject the call.
+type ContextOverflowHandler func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error)
+
+// NewContextGuard wraps inner so that prompts exceeding maxTokens are handled before reaching the model,
+// rather than being rejected by the provider.
+// Tokens are estimated with a simple heuristic: the character count of the contents and tool call arguments / 4.
+// When the prompt is over the limit, onOverflow is called, and the prompt it returns is checked again.
+// If onOverflow is nil, or the prompt is still over the limit, the call fails with ErrContextWindowExceeded.
+// It's meant as a hard backstop at the model boundary, complementing the reduction middlewares.
+// e.g.
+//
+//	cm := model.NewContextGuard(openaiModel, 128000, func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error) {
+//		// keep the system prompt and the latest messages
+//		return append(input[:1:1], input[len(input)/2:]...), nil
+//	})
+func NewContextGuard(inner ToolCallingChatModel, maxTokens int, onOverflow ContextOverflowHandler) ToolCallingChatModel {
+	return &contextGuardChatModel{inner: inner, maxTokens: maxTokens, onOverflow: onOverflow}
+}
+
+type contextGuardChatModel struct {
+	inner      ToolCallingChatModel
+	maxTokens  int
+	onOverflow ContextOverflowHandler
+}
+
+func (c *contextGuardChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	input, err := c.guard(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return c.inner.Generate(ctx, input, opts...)
+}
+
+func (c *contextGuardChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (
+	*schema.StreamReader[*schema.Message], error) {
+
+	input, err := c.guard(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return c.inner.Stream(ctx, input, opts...)
+}
+
+func (c *contextGuardChatModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	inner, err := c.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &contextGuardChatModel{inner: inner, maxTokens: c.maxTokens, onOverflow: c.onOverflow}, nil
+}
+
+func (c *contextGuardChatModel) GetType() string {
+	typ, _ := components.GetType(c.inner)
+	return typ
+}
+
+func (c *contextGuardChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.inner)
+}
+
+func (c *contextGuardChatModel) guard(ctx context.Context, input []*schema.Message) ([]*schema.Message, error) {
+	tokens := estimateTokens(input)
+	if tokens <= c.maxTokens {
+		return input, nil
+	}
+	if c.onOverflow == nil {
+		return nil, fmt.Errorf("%w: estimated %d tokens, max %d", ErrContextWindowExceeded, tokens, c.maxTokens)
+	}
+
+	input, err := c.onOverflow(ctx, input, tokens, c.maxTokens)
+	if err != nil {
+		return nil, err
+	}
+	if tokens = estimateTokens(input); tokens > c.maxTokens {
+		return nil, fmt.Errorf("%w: estimated %d tokens after overflow handling, max %d",
+			ErrContextWindowExceeded, tokens, c.maxTokens)
+	}
+	return input, nil
+}
+
+// estimateTokens estimates the token count of messages using character count / 4.
+func estimateTokens(input []*schema.Message) int {
+	count := 0
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		count += len(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			count += len(tc.Function.Arguments)
+		}
+	}
+	return (count + 3) / 4
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestContextGuard(t *testing.T) {
+	ctx := context.Background()
+	long := []*schema.Message{
+		schema.UserMessage(strings.Repeat("a", 400)),
+		schema.UserMessage("hi"),
+	}
+
+	t.Run("prompt within the window is passed as is", func(t *testing.T) {
+		inner := &countingChatModel{}
+		called := false
+		cm := NewContextGuard(inner, 200, func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error) {
+			called = true
+			return input, nil
+		})
+
+		msg, err := cm.Generate(ctx, long)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+		assert.False(t, called)
+	})
+
+	t.Run("over-window prompt is trimmed by the overflow handler", func(t *testing.T) {
+		inner := &countingChatModel{}
+		var gotTokens, gotMax int
+		cm := NewContextGuard(inner, 50, func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error) {
+			gotTokens, gotMax = tokens, maxTokens
+			return input[len(input)-1:], nil
+		})
+
+		msg, err := cm.Generate(ctx, long)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+		assert.Equal(t, 101, gotTokens)
+		assert.Equal(t, 50, gotMax)
+		assert.Equal(t, 1, inner.generateCalls)
+
+		sr, err := cm.Stream(ctx, long)
+		assert.NoError(t, err)
+		msg, err = schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hi", msg.Content)
+	})
+
+	t.Run("over-window prompt is rejected", func(t *testing.T) {
+		inner := &countingChatModel{}
+		rejectErr := errors.New("rejected")
+		cm := NewContextGuard(inner, 50, func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error) {
+			return nil, rejectErr
+		})
+		_, err := cm.Generate(ctx, long)
+		assert.ErrorIs(t, err, rejectErr)
+
+		// no handler
+		cm = NewContextGuard(inner, 50, nil)
+		_, err = cm.Stream(ctx, long)
+		assert.ErrorIs(t, err, ErrContextWindowExceeded)
+
+		// still over the window after handling
+		cm = NewContextGuard(inner, 50, func(ctx context.Context, input []*schema.Message, tokens, maxTokens int) ([]*schema.Message, error) {
+			return input, nil
+		})
+		_, err = cm.Generate(ctx, long)
+		assert.ErrorIs(t, err, ErrContextWindowExceeded)
+
+		assert.Equal(t, 0, inner.generateCalls)
+		assert.Equal(t, 0, inner.streamCalls)
+	})
+}
@@ -51,6 +51,10 @@ type Options struct {
 	//   - "5m"   = 5-minute TTL
 	//   - "1h"   = 1-hour TTL
 	MessageCacheTTL string
+
+	// PromptCaching asks the model to cache the prompt up to the messages marked by schema.SetCacheControl.
+	// Model implementations not supporting prompt caching ignore it.
+	PromptCaching bool
 }
 
 // Option is the call option for ChatModel component.
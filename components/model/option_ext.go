@@ -42,6 +42,15 @@ func WithMessageCacheTTL(ttl string) Option {
 	}}
 }
 
+// WithPromptCaching returns an Option that enables prompt caching.
+// The prefix of the input up to the messages marked by schema.SetCacheControl may then be cached by the provider,
+// typically used for long system prompts that are identical across requests.
+func WithPromptCaching() Option {
+	return Option{apply: func(opts *Options) {
+		opts.PromptCaching = true
+	}}
+}
+
 func (options *Options) ToOptionList() []Option {
 	if options == nil {
 		return nil
@@ -75,5 +84,8 @@ func (options *Options) ToOptionList() []Option {
 	if len(options.Extra) > 0 {
 		result = append(result, WithExtra(options.Extra))
 	}
+	if options.PromptCaching {
+		result = append(result, WithPromptCaching())
+	}
 	return result
 }
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfterError is an optional interface for the errors returned by a ChatModel implementation,
+// telling how long the provider asked to wait before retrying, e.g. by the Retry-After header of a 429 response.
+// Retrying wrappers honor it instead of their own backoff, so as not to hammer a rate-limited endpoint.
+type RetryAfterError interface {
+	error
+	// RetryAfter returns the duration to wait before retrying.
+	RetryAfter() time.Duration
+}
+
+// GetRetryAfter returns the retry-after duration of the first error in the chain of err implementing RetryAfterError.
+// It reports false if there is none, or its duration isn't positive.
+func GetRetryAfter(err error) (time.Duration, bool) {
+	var rae RetryAfterError
+	if !errors.As(err, &rae) {
+		return 0, false
+	}
+	d := rae.RetryAfter()
+	return d, d > 0
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, either a number of seconds or an HTTP date,
+// into the duration to wait from now. It's meant for ChatModel implementations to implement RetryAfterError.
+// It reports false if value is malformed, and returns 0 for a date in the past.
+// e.g.
+//
+//	d, ok := model.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return "429 too many requests"
+}
+
+func (e *rateLimitedError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := GetRetryAfter(fmt.Errorf("generate failed: %w", &rateLimitedError{retryAfter: 2 * time.Second}))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+
+	_, ok = GetRetryAfter(&rateLimitedError{})
+	assert.False(t, ok)
+	_, ok = GetRetryAfter(errors.New("500 internal error"))
+	assert.False(t, ok)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok = ParseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+	d, ok = ParseRetryAfter("Wed, 01 Jan 2025 00:00:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+	d, ok = ParseRetryAfter("Tue, 31 Dec 2024 23:59:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+	_, ok = ParseRetryAfter("soon", now)
+	assert.False(t, ok)
+	_, ok = ParseRetryAfter("-1", now)
+	assert.False(t, ok)
+}
@@ -21,6 +21,8 @@ import (
 	"reflect"
 
 	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/schema"
 )
 
 // UnmarshalArguments is the function type for unmarshalling the arguments.
@@ -33,6 +35,7 @@ type toolOptions struct {
 	um         UnmarshalArguments
 	m          MarshalOutput
 	scModifier SchemaModifierFn
+	outSchema  *jsonschema.Schema
 }
 
 // Option is the option func for the tool.
@@ -70,6 +73,24 @@ func WithSchemaModifier(modifier SchemaModifierFn) Option {
 	}
 }
 
+// WithOutputSchema sets the JSON schema of the output of the tool, as the OutputSchema of its ToolInfo,
+// so that the structured output the tool promises can be validated, e.g. by compose.NewToolOutputValidationMiddleware.
+func WithOutputSchema(outputSchema *jsonschema.Schema) Option {
+	return func(o *toolOptions) {
+		o.outSchema = outputSchema
+	}
+}
+
+// toolInfo returns desc with the output schema of the options set, leaving desc itself unchanged.
+func (o *toolOptions) toolInfo(desc *schema.ToolInfo) *schema.ToolInfo {
+	if o.outSchema == nil || desc == nil {
+		return desc
+	}
+	info := *desc
+	info.OutputSchema = o.outSchema
+	return &info
+}
+
 func getToolOptions(opt ...Option) *toolOptions {
 	opts := &toolOptions{
 		um: nil,
@@ -108,7 +108,7 @@ func newOptionableTool[T, D any](desc *schema.ToolInfo, i OptionableInvokeFunc[T
 	to := getToolOptions(opts...)
 
 	return &invokableTool[T, D]{
-		info: desc,
+		info: to.toolInfo(desc),
 		um:   to.um,
 		m:    to.m,
 		Fn:   i,
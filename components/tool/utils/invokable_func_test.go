@@ -246,6 +246,16 @@ func TestInferTool(t *testing.T) {
 		assert.NoError(t, err)
 		assert.JSONEq(t, `{"code":200,"msg":"update bruce lee success"}`, content)
 	})
+
+	t.Run("infer_tool_with_output_schema", func(t *testing.T) {
+		outputSchema := &jsonschema.Schema{Type: string(schema.Object)}
+		tl, err := InferTool("update_user_info", "full update user info", updateUserInfo, WithOutputSchema(outputSchema))
+		assert.NoError(t, err)
+
+		info, err := tl.Info(context.Background())
+		assert.NoError(t, err)
+		assert.Same(t, outputSchema, info.OutputSchema)
+	})
 }
 
 func TestInferOptionableTool(t *testing.T) {
@@ -69,7 +69,7 @@ func newOptionableStreamTool[T, D any](desc *schema.ToolInfo, s OptionableStream
 	to := getToolOptions(opts...)
 
 	return &streamableTool[T, D]{
-		info: desc,
+		info: to.toolInfo(desc),
 
 		um: to.um,
 		m:  to.m,
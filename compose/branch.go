@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/cloudwego/eino/internal/generic"
@@ -37,6 +38,11 @@ type GraphMultiBranchCondition[T any] func(ctx context.Context, in T) (endNode m
 // StreamGraphMultiBranchCondition is the condition type for the stream multi choice branch.
 type StreamGraphMultiBranchCondition[T any] func(ctx context.Context, in *schema.StreamReader[T]) (endNodes map[string]bool, err error)
 
+// GraphResumeBranchCondition is the condition type for the resume branch.
+// resumeData is the data provided through ResumeWithData (or BatchResumeWithData) for the interrupt of the graph
+// that the branch belongs to, hasResumeData is false if the graph is not being resumed or no data of type D is provided.
+type GraphResumeBranchCondition[T, D any] func(ctx context.Context, in T, resumeData D, hasResumeData bool) (endNode string, err error)
+
 // GraphBranch is the branch type for the graph.
 // It is used to determine the next node based on the condition.
 type GraphBranch struct {
@@ -174,3 +180,52 @@ func NewStreamGraphBranch[T any](condition StreamGraphBranchCondition[T], endNod
 		return map[string]bool{ret: true}, nil
 	}, endNodes)
 }
+
+// BranchOnToolCalls creates a branch for the *schema.Message output of a chat model node, as in ReAct graphs,
+// going to toolNode if the message has tool calls, or to endNode, e.g. END, otherwise.
+// When streaming, the message chunks are read until one with tool calls is found,
+// so that tool calls following some text content are detected.
+// e.g.
+//
+//	graph.AddBranch("chat_model", compose.BranchOnToolCalls("tools", compose.END))
+func BranchOnToolCalls(toolNode, endNode string) *GraphBranch {
+	return NewStreamGraphBranch(func(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (string, error) {
+		defer sr.Close()
+		for {
+			msg, err := sr.Recv()
+			if err == io.EOF {
+				return endNode, nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if msg != nil && len(msg.ToolCalls) > 0 {
+				return toolNode, nil
+			}
+		}
+	}, map[string]bool{toolNode: true, endNode: true})
+}
+
+// NewGraphResumeBranch creates a branch whose condition can read the resume data of the graph, so that a human decision
+// made during an interrupt (e.g. approve or reject) can steer the routing after the graph is resumed.
+// Interrupts triggered by WithInterruptBeforeNodes / WithInterruptAfterNodes are addressed to the graph itself,
+// so the data passed to ResumeWithData with the corresponding interrupt ID is delivered to this branch.
+// Note that the resume data stays visible for the whole resumed run, and that for graphs with local state,
+// data targeted at the graph also replaces the state, so D should be the state type in that case.
+// e.g.
+//
+//	condition := func(ctx context.Context, in string, decision string, hasDecision bool) (string, error) {
+//		if hasDecision && decision == "reject" {
+//			return compose.END, nil
+//		}
+//		return "publish", nil
+//	}
+//	branch := compose.NewGraphResumeBranch(condition, map[string]bool{"publish": true, compose.END: true})
+//
+//	graph.AddBranch("review", branch)
+func NewGraphResumeBranch[T, D any](condition GraphResumeBranchCondition[T, D], endNodes map[string]bool) *GraphBranch {
+	return NewGraphBranch(func(ctx context.Context, in T) (endNode string, err error) {
+		isResumeTarget, hasData, data := GetResumeContext[D](ctx)
+		return condition(ctx, in, data, isResumeTarget && hasData)
+	}, endNodes)
+}
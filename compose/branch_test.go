@@ -130,3 +130,105 @@ func TestStreamMultiBranch(t *testing.T) {
 		"2": "start",
 	}, result)
 }
+
+func TestGraphResumeBranch(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[string, string]()
+	var published bool
+	err := g.AddLambdaNode("review", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + " reviewed", nil
+	}))
+	assert.NoError(t, err)
+	err = g.AddLambdaNode("publish", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		published = true
+		return input + " published", nil
+	}))
+	assert.NoError(t, err)
+	err = g.AddEdge(START, "review")
+	assert.NoError(t, err)
+	err = g.AddBranch("review", NewGraphResumeBranch(func(ctx context.Context, in string, decision string, hasDecision bool) (string, error) {
+		if hasDecision && decision == "reject" {
+			return END, nil
+		}
+		return "publish", nil
+	}, map[string]bool{"publish": true, END: true}))
+	assert.NoError(t, err)
+	err = g.AddEdge("publish", END)
+	assert.NoError(t, err)
+
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithInterruptBeforeNodes([]string{"review"}))
+	assert.NoError(t, err)
+
+	t.Run("reject", func(t *testing.T) {
+		published = false
+		_, err = r.Invoke(ctx, "draft", WithCheckPointID("reject"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"review"}, info.BeforeNodes)
+
+		result, err := r.Invoke(ResumeWithData(ctx, info.InterruptContexts[0].ID, "reject"), "", WithCheckPointID("reject"))
+		assert.NoError(t, err)
+		assert.Equal(t, "draft reviewed", result)
+		assert.False(t, published)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		published = false
+		_, err = r.Invoke(ctx, "draft", WithCheckPointID("approve"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+
+		result, err := r.Invoke(ResumeWithData(ctx, info.InterruptContexts[0].ID, "approve"), "", WithCheckPointID("approve"))
+		assert.NoError(t, err)
+		assert.Equal(t, "draft reviewed published", result)
+		assert.True(t, published)
+	})
+
+	t.Run("not resumed", func(t *testing.T) {
+		published = false
+		r2, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		result, err := r2.Invoke(ctx, "draft")
+		assert.NoError(t, err)
+		assert.Equal(t, "draft reviewed published", result)
+		assert.True(t, published)
+	})
+}
+
+func TestBranchOnToolCalls(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[*schema.Message, *schema.Message]()
+	// emits the content and the tool calls of the input message as separate chunks
+	assert.NoError(t, g.AddLambdaNode("model", StreamableLambda(func(ctx context.Context, in *schema.Message) (*schema.StreamReader[*schema.Message], error) {
+		return schema.StreamReaderFromArray([]*schema.Message{
+			schema.AssistantMessage(in.Content, nil),
+			schema.AssistantMessage("", in.ToolCalls),
+		}), nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("tools", InvokableLambda(func(ctx context.Context, in *schema.Message) (*schema.Message, error) {
+		return schema.ToolMessage("tool result", in.ToolCalls[0].ID), nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "model"))
+	assert.NoError(t, g.AddBranch("model", BranchOnToolCalls("tools", END)))
+	assert.NoError(t, g.AddEdge("tools", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	withToolCalls := schema.AssistantMessage("let me check", []schema.ToolCall{{ID: "call-1", Function: schema.FunctionCall{Name: "search"}}})
+	out, err := r.Invoke(ctx, withToolCalls)
+	assert.NoError(t, err)
+	assert.Equal(t, "tool result", out.Content)
+
+	out, err = r.Invoke(ctx, schema.AssistantMessage("final answer", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "final answer", out.Content)
+	assert.Empty(t, out.ToolCalls)
+
+	sr, err := r.Stream(ctx, withToolCalls)
+	assert.NoError(t, err)
+	out, err = schema.ConcatMessageStream(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "tool result", out.Content)
+}
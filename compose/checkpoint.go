@@ -17,7 +17,9 @@
 package compose
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/cloudwego/eino/internal/core"
@@ -50,6 +52,33 @@ func RegisterSerializableType[T any](name string) (err error) {
 
 type CheckPointStore = core.CheckPointStore
 
+// CheckPointLister is an optional interface for a CheckPointStore to enumerate the checkpoints it holds,
+// e.g. to show the runs waiting to be resumed, or to garbage-collect stale ones.
+type CheckPointLister interface {
+	// List returns the IDs of all the checkpoints in the store.
+	List(ctx context.Context) ([]string, error)
+}
+
+// ErrCheckPointListNotSupported is returned by ListCheckPoints if the store doesn't implement CheckPointLister.
+var ErrCheckPointListNotSupported = errors.New("checkpoint store doesn't support listing checkpoints")
+
+// ListCheckPoints returns the IDs of all the checkpoints in store,
+// or ErrCheckPointListNotSupported if it doesn't implement CheckPointLister.
+func ListCheckPoints(ctx context.Context, store CheckPointStore) ([]string, error) {
+	lister, ok := store.(CheckPointLister)
+	if !ok {
+		return nil, ErrCheckPointListNotSupported
+	}
+	return lister.List(ctx)
+}
+
+// CheckPointDeleter is an optional interface for a CheckPointStore to delete the checkpoints it holds,
+// required by WithDeleteCheckPointOnSuccess.
+type CheckPointDeleter interface {
+	// Delete deletes the checkpoint of the given ID. Deleting a checkpoint that doesn't exist is not an error.
+	Delete(ctx context.Context, checkPointID string) error
+}
+
 type Serializer interface {
 	Marshal(v any) ([]byte, error)
 	Unmarshal(data []byte, v any) error
@@ -62,7 +91,21 @@ func WithCheckPointStore(store CheckPointStore) GraphCompileOption {
 	}
 }
 
-// WithSerializer sets the serializer used to persist checkpoint state.
+// WithDeleteCheckPointOnSuccess makes the graph delete its checkpoint from the store once a run completes without
+// interrupting, so that the checkpoints of finished runs don't pile up in the store.
+// The checkpoint deleted is the one written to, i.e. the one of WithWriteToCheckPointID if set, else of WithCheckPointID.
+// The store must implement CheckPointDeleter.
+// e.g.
+//
+//	runnable, err := graph.Compile(ctx, compose.WithCheckPointStore(store), compose.WithDeleteCheckPointOnSuccess(true))
+func WithDeleteCheckPointOnSuccess(deleteOnSuccess bool) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.deleteCheckPointOnSuccess = deleteOnSuccess
+	}
+}
+
+// WithSerializer sets the serializer used to persist checkpoint state, e.g. NewGzipSerializer to compress it.
+// optional, a JSON based serializer of the types registered by schema.RegisterName by default
 func WithSerializer(serializer Serializer) GraphCompileOption {
 	return func(o *graphCompileOptions) {
 		o.serializer = serializer
@@ -114,6 +157,9 @@ type checkpoint struct {
 
 	InterruptID2Addr  map[string]Address
 	InterruptID2State map[string]core.InterruptState
+
+	// Version is the version of the graph structure the checkpoint was written by.
+	Version string
 }
 
 type stateModifierKey struct{}
@@ -197,7 +243,11 @@ func (c *checkPointer) get(ctx context.Context, id string) (*checkpoint, bool, e
 	cp := &checkpoint{}
 	err = c.serializer.Unmarshal(data, cp)
 	if err != nil {
-		return nil, false, err
+		if _, isGzip := c.serializer.(*gzipSerializer); !isGzip && bytes.HasPrefix(data, gzipMagic) {
+			return nil, false, fmt.Errorf("failed to unmarshal checkpoint[%s], which is gzip compressed, "+
+				"it may have been written by the serializer of NewGzipSerializer: %w", id, err)
+		}
+		return nil, false, fmt.Errorf("failed to unmarshal checkpoint[%s]: %w", id, err)
 	}
 
 	return cp, true, nil
@@ -212,6 +262,14 @@ func (c *checkPointer) set(ctx context.Context, id string, cp *checkpoint) error
 	return c.store.Set(ctx, id, data)
 }
 
+func (c *checkPointer) delete(ctx context.Context, id string) error {
+	deleter, ok := c.store.(CheckPointDeleter)
+	if !ok {
+		return fmt.Errorf("checkpoint store doesn't support deleting checkpoints")
+	}
+	return deleter.Delete(ctx, id)
+}
+
 // convertCheckPoint if value in checkpoint is streamReader, convert it to non-stream
 func (c *checkPointer) convertCheckPoint(cp *checkpoint, isStream bool) (err error) {
 	for _, ch := range cp.Channels {
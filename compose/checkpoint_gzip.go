@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/internal/serialization"
+)
+
+// gzipMagic is the header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ErrCheckPointNotCompressed is returned by the Serializer of NewGzipSerializer when unmarshaling data that isn't
+// gzip compressed, e.g. a checkpoint written by another serializer.
+var ErrCheckPointNotCompressed = errors.New("checkpoint isn't gzip compressed, it may have been written by another serializer")
+
+// NewGzipSerializer returns a Serializer gzip compressing the data of inner, e.g. to keep the checkpoints of graphs
+// with long message histories within the value size limit of a store.
+// inner is optional, the default serializer of checkpoints by default.
+// The checkpoints written by it can only be read by a gzip serializer over the same inner serializer.
+// e.g.
+//
+//	runnable, err := graph.Compile(ctx, compose.WithCheckPointStore(store), compose.WithSerializer(compose.NewGzipSerializer(nil)))
+func NewGzipSerializer(inner Serializer) Serializer {
+	if inner == nil {
+		inner = &serialization.InternalSerializer{}
+	}
+	return &gzipSerializer{inner: inner}
+}
+
+type gzipSerializer struct {
+	inner Serializer
+}
+
+func (g *gzipSerializer) Marshal(v any) ([]byte, error) {
+	data, err := g.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress checkpoint: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress checkpoint: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *gzipSerializer) Unmarshal(data []byte, v any) error {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return ErrCheckPointNotCompressed
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decompress checkpoint: %w", err)
+	}
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to decompress checkpoint: %w", err)
+	}
+	return g.inner.Unmarshal(data, v)
+}
@@ -17,9 +17,12 @@
 package compose
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -48,6 +51,20 @@ func (i *inMemoryStore) Set(_ context.Context, checkPointID string, checkPoint [
 	return nil
 }
 
+func (i *inMemoryStore) List(_ context.Context) ([]string, error) {
+	ids := make([]string, 0, len(i.m))
+	for id := range i.m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (i *inMemoryStore) Delete(_ context.Context, checkPointID string) error {
+	delete(i.m, checkPointID)
+	return nil
+}
+
 func newInMemoryStore() *inMemoryStore {
 	return &inMemoryStore{
 		m: make(map[string][]byte),
@@ -116,42 +133,116 @@ func TestSimpleCheckPoint(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "start1state2", result)
 
-	/*	_, err = r.Stream(ctx, "start", WithCheckPointID("2"))
-		assert.NotNil(t, err)
-		info, ok = ExtractInterruptInfo(err)
-		assert.True(t, ok)
-		assert.Equal(t, &testStruct{A: ""}, info.State)
-		assert.Equal(t, []string{"2"}, info.BeforeNodes)
-		assert.Equal(t, []string{"1"}, info.AfterNodes)
-		assert.Empty(t, info.RerunNodesExtra)
-		assert.Empty(t, info.SubGraphs)
-		assert.True(t, info.InterruptContexts[0].EqualsWithoutID(&InterruptCtx{
-			Address: Address{
-				{
-					Type: AddressSegmentRunnable,
-					ID:   "root",
-				},
-			},
-			Info: &testStruct{
-				A: "",
+	_, err = r.Stream(ctx, "start", WithCheckPointID("2"))
+	assert.NotNil(t, err)
+	info, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, &testStruct{A: ""}, info.State)
+	assert.Equal(t, []string{"2"}, info.BeforeNodes)
+	assert.Equal(t, []string{"1"}, info.AfterNodes)
+	assert.Empty(t, info.RerunNodesExtra)
+	assert.Empty(t, info.SubGraphs)
+	assert.True(t, info.InterruptContexts[0].EqualsWithoutID(&InterruptCtx{
+		Address: Address{
+			{
+				Type: AddressSegmentRunnable,
+				ID:   "root",
 			},
-			IsRootCause: true,
-		}))
+		},
+		Info: &testStruct{
+			A: "",
+		},
+		IsRootCause: true,
+	}))
 
-		rCtx = ResumeWithData(ctx, info.InterruptContexts[0].ID, &testStruct{A: "state"})
-		streamResult, err := r.Stream(rCtx, "start", WithCheckPointID("2"))
+	rCtx = ResumeWithData(ctx, info.InterruptContexts[0].ID, &testStruct{A: "state"})
+	streamResult, err := r.Stream(rCtx, "start", WithCheckPointID("2"))
+	assert.NoError(t, err)
+	result = ""
+	for {
+		chunk, err := streamResult.Recv()
+		if err == io.EOF {
+			break
+		}
 		assert.NoError(t, err)
-		result = ""
-		for {
-			chunk, err := streamResult.Recv()
-			if err == io.EOF {
-				break
-			}
-			assert.NoError(t, err)
-			result += chunk
+		result += chunk
+	}
+
+	assert.Equal(t, "start1state2", result)
+}
+
+func TestListCheckPoints(t *testing.T) {
+	store := newInMemoryStore()
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	ctx := context.Background()
+	r, err := g.Compile(ctx, WithCheckPointStore(store), WithInterruptBeforeNodes([]string{"1"}))
+	assert.NoError(t, err)
+
+	ids, err := ListCheckPoints(ctx, store)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	for _, id := range []string{"b", "a"} {
+		_, err = r.Invoke(ctx, "start", WithCheckPointID(id))
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+	}
+	ids, err = ListCheckPoints(ctx, store)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	_, err = ListCheckPoints(ctx, struct{ CheckPointStore }{store})
+	assert.ErrorIs(t, err, ErrCheckPointListNotSupported)
+}
+
+func TestDeleteCheckPointOnSuccess(t *testing.T) {
+	store := newInMemoryStore()
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		if input == "interrupt" {
+			return "", Interrupt(ctx, "confirm")
+		}
+		if wasInterrupted, _, _ := GetInterruptState[any](ctx); wasInterrupted {
+			return "resumed", nil
 		}
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	ctx := context.Background()
+
+	_, err := g.Compile(ctx, WithCheckPointStore(struct{ CheckPointStore }{store}), WithDeleteCheckPointOnSuccess(true))
+	assert.ErrorContains(t, err, "CheckPointDeleter")
+
+	r, err := g.Compile(ctx, WithCheckPointStore(store), WithDeleteCheckPointOnSuccess(true))
+	assert.NoError(t, err)
+
+	result, err := r.Invoke(ctx, "start", WithCheckPointID("clean"))
+	assert.NoError(t, err)
+	assert.Equal(t, "start1", result)
+	_, ok := store.m["clean"]
+	assert.False(t, ok)
 
-		assert.Equal(t, "start1state2", result)*/
+	_, err = r.Invoke(ctx, "interrupt", WithCheckPointID("interrupted"))
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	_, ok = store.m["interrupted"]
+	assert.True(t, ok)
+
+	sr, err := r.Stream(ResumeWithData(ctx, info.InterruptContexts[0].ID, nil), "interrupt", WithCheckPointID("interrupted"))
+	assert.NoError(t, err)
+	result, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "resumed", result)
+	_, ok = store.m["interrupted"]
+	assert.False(t, ok)
 }
 
 func TestCustomStructInAn2y(t *testing.T) {
@@ -570,6 +661,26 @@ func TestNestedSubGraph(t *testing.T) {
 			},
 		},
 	}, info.SubGraphs)
+	assert.Equal(t, []PendingNode{
+		{
+			Address:         Address{{Type: AddressSegmentRunnable, ID: "root"}, {Type: AddressSegmentNode, ID: "2"}},
+			NodePath:        []string{"2"},
+			AfterNodes:      []string{"3"},
+			RerunNodesExtra: make(map[string]interface{}),
+			State:           &testStruct{A: "state"},
+		},
+		{
+			Address: Address{
+				{Type: AddressSegmentRunnable, ID: "root"},
+				{Type: AddressSegmentNode, ID: "2"},
+				{Type: AddressSegmentNode, ID: "2"},
+			},
+			NodePath:        []string{"2", "2"},
+			AfterNodes:      []string{"1"},
+			RerunNodesExtra: make(map[string]interface{}),
+			State:           &testStruct{A: ""},
+		},
+	}, info.PendingNodes())
 	assert.True(t, info.InterruptContexts[0].EqualsWithoutID(&InterruptCtx{
 		Address: Address{
 			{
@@ -1205,6 +1316,13 @@ func TestRerunNodeInterrupt(t *testing.T) {
 	assert.True(t, existed)
 	assert.Equal(t, []string{"1"}, info.RerunNodes)
 	assert.Equal(t, "test extra", info.RerunNodesExtra["1"].(string))
+	extra, ok := GetRerunExtra[string](info, "1")
+	assert.True(t, ok)
+	assert.Equal(t, "test extra", extra)
+	_, ok = GetRerunExtra[int](info, "1")
+	assert.False(t, ok)
+	_, ok = GetRerunExtra[string](info, "2")
+	assert.False(t, ok)
 
 	streamResult, err := r.Stream(ctx, "", WithCheckPointID("2"), WithStateModifier(func(ctx context.Context, path NodePath, state any) error {
 		state.(*testStruct).A = "state"
@@ -1218,6 +1336,43 @@ func TestRerunNodeInterrupt(t *testing.T) {
 	assert.Equal(t, io.EOF, err)
 }
 
+type rerunExtra struct {
+	Reason string
+}
+
+func TestGetRerunExtraAfterSerialization(t *testing.T) {
+	schema.RegisterName[*rerunExtra]("_test_compose_rerun_extra")
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return "", Interrupt(ctx, &rerunExtra{Reason: "needs approval"})
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", END))
+	ctx := context.Background()
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("1"))
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+
+	// the interrupt info is persisted with the checkpoint, e.g. by the adk runner
+	serializer := &serialization.InternalSerializer{}
+	data, err := serializer.Marshal(info)
+	assert.NoError(t, err)
+	restored := &InterruptInfo{}
+	assert.NoError(t, serializer.Unmarshal(data, restored))
+
+	extra, ok := GetRerunExtra[*rerunExtra](restored, "1")
+	assert.True(t, ok)
+	assert.Equal(t, &rerunExtra{Reason: "needs approval"}, extra)
+	_, ok = GetRerunExtra[rerunExtra](restored, "1")
+	assert.False(t, ok)
+	_, ok = GetRerunExtra[*rerunExtra](nil, "1")
+	assert.False(t, ok)
+}
+
 type myInterface interface {
 	A()
 }
@@ -1296,6 +1451,29 @@ func TestGraphStartInterrupt(t *testing.T) {
 	assert.Equal(t, "input1sub1", result)
 }
 
+func TestInterruptNodesNotFound(t *testing.T) {
+	ctx := context.Background()
+	newSubGraph := func() *Graph[string, string] {
+		subG := NewGraph[string, string]()
+		_ = subG.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+			return input, nil
+		}))
+		_ = subG.AddEdge(START, "1")
+		_ = subG.AddEdge("1", END)
+		return subG
+	}
+
+	g := NewGraph[string, string]()
+	_ = g.AddGraphNode("2", newSubGraph(), WithGraphCompileOptions(WithInterruptAfterNodes([]string{"3"})))
+	_ = g.AddEdge(START, "2")
+	_ = g.AddEdge("2", END)
+	_, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.ErrorContains(t, err, "interrupt after node '3' not found in graph")
+
+	_, err = newSubGraph().Compile(ctx, WithGraphName("sub"), WithInterruptBeforeNodes([]string{"1", "missing"}))
+	assert.ErrorContains(t, err, "interrupt before node 'missing' not found in graph 'sub'")
+}
+
 func TestWithForceNewRun(t *testing.T) {
 	g := NewGraph[string, string]()
 	_ = g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
@@ -2002,3 +2180,101 @@ func (f *checkpointTestTool[I, O]) InvokableRun(ctx context.Context, argumentsIn
 	}
 	return sonic.MarshalString(o)
 }
+
+func TestCheckpointVersionCheck(t *testing.T) {
+	ctx := context.Background()
+	store := newInMemoryStore()
+
+	compile := func(extraNode bool) Runnable[string, string] {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+			return input + "1", nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+			return input + "2", nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", "2"))
+		if extraNode {
+			assert.NoError(t, g.AddLambdaNode("3", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+				return input + "3", nil
+			})))
+			assert.NoError(t, g.AddEdge("2", "3"))
+			assert.NoError(t, g.AddEdge("3", END))
+		} else {
+			assert.NoError(t, g.AddEdge("2", END))
+		}
+		r, err := g.Compile(ctx, WithCheckPointStore(store), WithInterruptBeforeNodes([]string{"2"}))
+		assert.NoError(t, err)
+		return r
+	}
+
+	r := compile(false)
+	for _, id := range []string{"1", "2"} {
+		_, err := r.Invoke(ctx, "start", WithCheckPointID(id))
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+	}
+
+	// the same graph structure resumes normally
+	result, err := compile(false).Invoke(ctx, "", WithCheckPointID("1"), WithCheckpointVersionCheck())
+	assert.NoError(t, err)
+	assert.Equal(t, "start12", result)
+
+	// a modified graph is rejected
+	modified := compile(true)
+	_, err = modified.Invoke(ctx, "", WithCheckPointID("2"), WithCheckpointVersionCheck())
+	assert.ErrorContains(t, err, "graph structure has changed since the checkpoint was written")
+
+	// without the check, the checkpoint is resumed regardless
+	result, err = modified.Invoke(ctx, "", WithCheckPointID("2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "start123", result)
+}
+
+func TestGzipSerializer(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + strings.Repeat(" history", 100), nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + " done", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+
+	store := newInMemoryStore()
+	compile := func(opts ...GraphCompileOption) Runnable[string, string] {
+		r, err := g.Compile(ctx, append([]GraphCompileOption{WithCheckPointStore(store), WithInterruptBeforeNodes([]string{"2"})}, opts...)...)
+		assert.NoError(t, err)
+		return r
+	}
+	plain, gzipped := compile(), compile(WithSerializer(NewGzipSerializer(nil)))
+
+	_, err := plain.Invoke(ctx, "input", WithCheckPointID("plain"))
+	_, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	_, err = gzipped.Invoke(ctx, "input", WithCheckPointID("gzip"))
+	_, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+
+	plainData, _, _ := store.Get(ctx, "plain")
+	gzipData, _, _ := store.Get(ctx, "gzip")
+	assert.True(t, bytes.HasPrefix(gzipData, []byte{0x1f, 0x8b}))
+	assert.Less(t, len(gzipData), len(plainData))
+
+	// a mismatched serializer fails clearly
+	_, err = plain.Invoke(ctx, "input", WithCheckPointID("gzip"))
+	assert.ErrorContains(t, err, "failed to unmarshal checkpoint[gzip], which is gzip compressed")
+	_, err = gzipped.Invoke(ctx, "input", WithCheckPointID("plain"))
+	assert.ErrorIs(t, err, ErrCheckPointNotCompressed)
+
+	result, err := gzipped.Invoke(ctx, "input", WithCheckPointID("gzip"))
+	assert.NoError(t, err)
+	assert.Equal(t, "input"+strings.Repeat(" history", 100)+" done", result)
+	result, err = plain.Invoke(ctx, "input", WithCheckPointID("plain"))
+	assert.NoError(t, err)
+	assert.Equal(t, "input"+strings.Repeat(" history", 100)+" done", result)
+}
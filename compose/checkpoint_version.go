@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithCheckpointVersionCheck makes resuming from a checkpoint fail if the structure of the graph,
+// i.e. its nodes and edges, including those of its subgraphs, has changed since the checkpoint was written,
+// e.g. by a deploy in between, instead of resuming a graph that may not match the checkpoint.
+// Checkpoints written before the graph version was recorded aren't checked.
+func WithCheckpointVersionCheck() Option {
+	return Option{
+		checkpointVersionCheck: true,
+	}
+}
+
+func isCheckpointVersionCheck(opts ...Option) bool {
+	for _, opt := range opts {
+		if opt.checkpointVersionCheck {
+			return true
+		}
+	}
+	return false
+}
+
+type checkpointVersionCheckKey struct{}
+
+func setCheckpointVersionCheck(ctx context.Context) context.Context {
+	return context.WithValue(ctx, checkpointVersionCheckKey{}, true)
+}
+
+func getCheckpointVersionCheck(ctx context.Context) bool {
+	check, _ := ctx.Value(checkpointVersionCheckKey{}).(bool)
+	return check
+}
+
+func (r *runner) checkCheckpointVersion(cp *checkpoint) error {
+	if cp.Version == "" || cp.Version == r.version {
+		return nil
+	}
+	return fmt.Errorf("graph structure has changed since the checkpoint was written, "+
+		"checkpoint graph version: %s, current graph version: %s", cp.Version, r.version)
+}
+
+// graphVersion hashes the nodes and edges of the graph.
+func graphVersion(r *runner) string {
+	keys := make([]string, 0, len(r.chanSubscribeTo))
+	for key := range r.chanSubscribeTo {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sortedJoin := func(s []string) string {
+		c := make([]string, len(s))
+		copy(c, s)
+		sort.Strings(c)
+		return strings.Join(c, ",")
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s>%s\n", START, sortedJoin(getSuccessors(r.inputChannels)))
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(h, "%s>%s|data:%s|control:%s\n", key, sortedJoin(r.successors[key]),
+			sortedJoin(r.dataPredecessors[key]), sortedJoin(r.controlPredecessors[key]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -125,7 +126,7 @@ func (ch *dagChannel) reportSkip(keys []string) bool {
 	return allSkipped
 }
 
-func (ch *dagChannel) get(isStream bool, name string, edgeHandler *edgeHandlerManager) (
+func (ch *dagChannel) get(ctx context.Context, isStream bool, name string, edgeHandler *edgeHandlerManager) (
 	any, bool, error) {
 	if ch.Skipped {
 		return nil, false, nil
@@ -160,7 +161,7 @@ func (ch *dagChannel) get(isStream bool, name string, edgeHandler *edgeHandlerMa
 	names := make([]string, len(ch.Values))
 	i := 0
 	for k, value := range ch.Values {
-		resolvedV, err := edgeHandler.handle(k, name, value, isStream)
+		resolvedV, err := edgeHandler.handle(ctx, k, name, value, isStream)
 		if err != nil {
 			return nil, false, err
 		}
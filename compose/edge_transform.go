@@ -0,0 +1,154 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+// EdgeTransform converts the value flowing on an edge, see Graph.AddEdgeWithTransform.
+type EdgeTransform struct {
+	inputType, outputType reflect.Type
+	genericHelper         *genericHelper
+
+	invoke    func(ctx context.Context, value any) (any, error)
+	transform func(ctx context.Context, sr streamReader) streamReader
+}
+
+// NewEdgeTransform creates an EdgeTransform converting the output of the start node of an edge, of type I,
+// to the input of its end node, of type O.
+// When the graph is streaming, fn is applied to each chunk of the stream.
+// e.g.
+//
+//	transform := compose.NewEdgeTransform(func(ctx context.Context, doc *schema.Document) (string, error) {
+//		return doc.Content, nil
+//	})
+func NewEdgeTransform[I, O any](fn func(ctx context.Context, in I) (O, error)) *EdgeTransform {
+	convert := func(ctx context.Context, v any) (O, error) {
+		in, ok := v.(I)
+		if !ok {
+			var (
+				i I
+				o O
+			)
+			return o, fmt.Errorf("edge transform: runtime type check fail, expected type: %T, actual type: %T", i, v)
+		}
+		return fn(ctx, in)
+	}
+
+	return &EdgeTransform{
+		inputType:     generic.TypeOf[I](),
+		outputType:    generic.TypeOf[O](),
+		genericHelper: newGenericHelper[I, O](),
+		invoke: func(ctx context.Context, value any) (any, error) {
+			return convert(ctx, value)
+		},
+		transform: func(ctx context.Context, sr streamReader) streamReader {
+			return packStreamReader(schema.StreamReaderWithConvert(sr.toAnyStreamReader(), func(v any) (O, error) {
+				return convert(ctx, v)
+			}))
+		},
+	}
+}
+
+func (g *graph) addEdgeWithTransform(startNode, endNode string, transform *EdgeTransform) (err error) {
+	if g.buildError != nil {
+		return g.buildError
+	}
+	if g.compiled {
+		return ErrGraphCompiled
+	}
+	if transform == nil {
+		return fmt.Errorf("edge[%s]-[%s]: transform is nil", startNode, endNode)
+	}
+
+	// check the edge itself first, so that the node types are only touched for a valid edge
+	if startNode == END {
+		return errors.New("END cannot be a start node")
+	}
+	if endNode == START {
+		return errors.New("START cannot be an end node")
+	}
+	if _, ok := g.nodes[startNode]; !ok && startNode != START {
+		return fmt.Errorf("edge start node '%s' needs to be added to graph first", startNode)
+	}
+	if _, ok := g.nodes[endNode]; !ok && endNode != END {
+		return fmt.Errorf("edge end node '%s' needs to be added to graph first", endNode)
+	}
+	for _, n := range g.dataEdges[startNode] {
+		if n == endNode {
+			return fmt.Errorf("data edge[%s]-[%s] have been added yet", startNode, endNode)
+		}
+	}
+
+	defer func() {
+		if err != nil {
+			g.buildError = err
+		}
+	}()
+
+	// the transform decouples the types of both ends, which are checked against the transform separately
+	if startOutputType := g.getNodeOutputType(startNode); startOutputType == nil {
+		g.nodes[startNode].cr.inputType = transform.inputType
+		g.nodes[startNode].cr.outputType = transform.inputType
+		g.nodes[startNode].cr.genericHelper = transform.genericHelper.forPredecessorPassthrough()
+	} else if checkAssignable(startOutputType, transform.inputType) == assignableTypeMustNot {
+		// assignableTypeMay is checked at runtime by the transform
+		return fmt.Errorf("graph edge[%s]-[%s]: start node's output type[%s] and transform's input type[%s] mismatch",
+			startNode, endNode, startOutputType.String(), transform.inputType.String())
+	}
+
+	if endInputType := g.getNodeInputType(endNode); endInputType == nil {
+		g.nodes[endNode].cr.inputType = transform.outputType
+		g.nodes[endNode].cr.outputType = transform.outputType
+		g.nodes[endNode].cr.genericHelper = transform.genericHelper.forSuccessorPassthrough()
+	} else {
+		switch checkAssignable(transform.outputType, endInputType) {
+		case assignableTypeMustNot:
+			return fmt.Errorf("graph edge[%s]-[%s]: transform's output type[%s] and end node's input type[%s] mismatch",
+				startNode, endNode, transform.outputType.String(), endInputType.String())
+		case assignableTypeMay:
+			if _, ok := g.handlerOnEdges[startNode]; !ok {
+				g.handlerOnEdges[startNode] = make(map[string][]handlerPair)
+			}
+			g.handlerOnEdges[startNode][endNode] = append(g.handlerOnEdges[startNode][endNode], g.getNodeGenericHelper(endNode).inputConverter)
+		}
+	}
+
+	// the types set to passthrough nodes may let pending edges be validated
+	if err = g.updateToValidateMap(); err != nil {
+		return err
+	}
+
+	if err = g.addEdgeWithMappings(startNode, endNode, false, true); err != nil {
+		return err
+	}
+	g.dataEdges[startNode] = append(g.dataEdges[startNode], endNode)
+
+	if _, ok := g.edgeTransforms[startNode]; !ok {
+		g.edgeTransforms[startNode] = make(map[string]*EdgeTransform)
+	}
+	g.edgeTransforms[startNode][endNode] = transform
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type weatherReport struct {
+	City        string
+	Temperature int
+}
+
+func TestEdgeTransform(t *testing.T) {
+	ctx := context.Background()
+	reportToString := NewEdgeTransform(func(ctx context.Context, r *weatherReport) (string, error) {
+		return fmt.Sprintf("%s: %d°C", r.City, r.Temperature), nil
+	})
+
+	t.Run("struct output converted to the string the successor expects", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("report", InvokableLambda(func(ctx context.Context, city string) (*weatherReport, error) {
+			return &weatherReport{City: city, Temperature: 21}, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("format", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return "weather of " + in, nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "report"))
+		assert.NoError(t, g.AddEdgeWithTransform("report", "format", reportToString))
+		assert.NoError(t, g.AddEdge("format", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "Paris")
+		assert.NoError(t, err)
+		assert.Equal(t, "weather of Paris: 21°C", out)
+
+		sr, err := r.Stream(ctx, "Paris")
+		assert.NoError(t, err)
+		out, err = concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "weather of Paris: 21°C", out)
+	})
+
+	t.Run("passthrough nodes get the types of the transform", func(t *testing.T) {
+		g := NewGraph[*weatherReport, string]()
+		assert.NoError(t, g.AddPassthroughNode("p1"))
+		assert.NoError(t, g.AddPassthroughNode("p2"))
+		assert.NoError(t, g.AddEdge(START, "p1"))
+		assert.NoError(t, g.AddEdgeWithTransform("p1", "p2", reportToString))
+		assert.NoError(t, g.AddEdge("p2", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, &weatherReport{City: "Oslo", Temperature: -3})
+		assert.NoError(t, err)
+		assert.Equal(t, "Oslo: -3°C", out)
+	})
+
+	t.Run("transforms apply per edge before the output keys are merged", func(t *testing.T) {
+		g := NewGraph[string, map[string]any]()
+		assert.NoError(t, g.AddLambdaNode("report", InvokableLambda(func(ctx context.Context, city string) (*weatherReport, error) {
+			return &weatherReport{City: city, Temperature: 21}, nil
+		}), WithOutputKey("report")))
+		assert.NoError(t, g.AddLambdaNode("count", InvokableLambda(func(ctx context.Context, city string) (int, error) {
+			return len(city), nil
+		}), WithOutputKey("count")))
+		assert.NoError(t, g.AddEdge(START, "report"))
+		assert.NoError(t, g.AddEdge(START, "count"))
+		assert.NoError(t, g.AddEdgeWithTransform("report", END, NewEdgeTransform(func(ctx context.Context, m map[string]any) (map[string]any, error) {
+			r := m["report"].(*weatherReport)
+			return map[string]any{"report": fmt.Sprintf("%s: %d°C", r.City, r.Temperature)}, nil
+		})))
+		assert.NoError(t, g.AddEdge("count", END))
+		r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "Paris")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"report": "Paris: 21°C", "count": 5}, out)
+	})
+
+	t.Run("mismatched types", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("echo", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "echo"))
+		err := g.AddEdgeWithTransform("echo", END, reportToString)
+		assert.ErrorContains(t, err, "start node's output type[string] and transform's input type[*compose.weatherReport] mismatch")
+
+		g = NewGraph[string, string]()
+		err = g.AddEdgeWithTransform(START, END, NewEdgeTransform(func(ctx context.Context, in string) (*schema.Message, error) {
+			return schema.UserMessage(in), nil
+		}))
+		assert.ErrorContains(t, err, "transform's output type[*schema.Message] and end node's input type[string] mismatch")
+	})
+}
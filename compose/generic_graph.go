@@ -26,6 +26,9 @@ import (
 type newGraphOptions struct {
 	withState func(ctx context.Context) any
 	stateType reflect.Type
+
+	stateInitHook     func(ctx context.Context, state any) error
+	stateInitHookType reflect.Type
 }
 
 // NewGraphOption configures behavior when creating a new graph, such as
@@ -43,6 +46,27 @@ func WithGenLocalState[S any](gls GenLocalState[S]) NewGraphOption {
 	}
 }
 
+// WithStateInitHook registers a hook called with the local state right after it's generated by the function of
+// WithGenLocalState, e.g. to load the state persisted by a former run into the fresh one.
+// The hook isn't called when the state is restored from a checkpoint, and an error of the hook fails the run.
+// S must be the state type of WithGenLocalState.
+// e.g.
+//
+//	graph := compose.NewGraph[string, string](
+//		compose.WithGenLocalState(genStateFunc),
+//		compose.WithStateInitHook(func(ctx context.Context, state *testState) error {
+//			return store.Load(ctx, state)
+//		}))
+func WithStateInitHook[S any](hook func(ctx context.Context, state S) error) NewGraphOption {
+	return func(ngo *newGraphOptions) {
+		ngo.stateInitHook = func(ctx context.Context, state any) error {
+			s, _ := state.(S)
+			return hook(ctx, s)
+		}
+		ngo.stateInitHookType = generic.TypeOf[S]()
+	}
+}
+
 // NewGraph create a directed graph that can compose components, lambda, chain, parallel etc.
 // simultaneously provide flexible and multi-granular aspect governance capabilities.
 // I: the input type of graph compiled product
@@ -78,8 +102,7 @@ func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O] {
 	g := &Graph[I, O]{
 		newGraphFromGeneric[I, O](
 			ComponentOfGraph,
-			options.withState,
-			options.stateType,
+			options,
 			opts,
 		),
 	}
@@ -97,6 +120,8 @@ type Graph[I, O any] struct {
 // AddEdge adds an edge to the graph, edge means a data flow from startNode to endNode.
 // the previous node's output type must be set to the next node's input type.
 // NOTE: startNode and endNode must have been added to the graph before adding edge.
+// Adding edges from START to several nodes delivers the graph input to each of them,
+// in both trigger modes and when resuming from an interrupt.
 // e.g.
 //
 //	graph.AddNode("start_node_key", compose.NewPassthroughNode())
@@ -107,6 +132,22 @@ func (g *Graph[I, O]) AddEdge(startNode, endNode string) (err error) {
 	return g.graph.addEdgeWithMappings(startNode, endNode, false, false)
 }
 
+// AddEdgeWithTransform adds an edge to the graph like AddEdge, converting the output of startNode by transform
+// before it's passed to endNode, so that nodes of incompatible types can be connected without an adapter lambda node.
+// The input type of transform must match the output of startNode, which is a map[string]any if startNode
+// has WithOutputKey, and the output type of transform must match the input of endNode.
+// When endNode has several predecessors, the transform only converts the value of this edge,
+// before the values of all the predecessors are merged, e.g. by the keys set with WithOutputKey.
+// e.g.
+//
+//	err := graph.AddEdgeWithTransform("retriever_node_key", "model_node_key",
+//		compose.NewEdgeTransform(func(ctx context.Context, docs []*schema.Document) ([]*schema.Message, error) {
+//			return []*schema.Message{schema.UserMessage(docs[0].Content)}, nil
+//		}))
+func (g *Graph[I, O]) AddEdgeWithTransform(startNode, endNode string, transform *EdgeTransform) (err error) {
+	return g.graph.addEdgeWithTransform(startNode, endNode, transform)
+}
+
 // Compile take the raw graph and compile it into a form ready to be run.
 // e.g.
 //
@@ -146,6 +187,7 @@ func compileAnyGraph[I, O any](ctx context.Context, g AnyGraph, opts ...GraphCom
 	}
 
 	ctxWrapper := func(ctx context.Context, opts ...Option) context.Context {
+		ctx = ctxWithTraceID(ctx, opts...)
 		return initGraphCallbacks(AppendAddressSegment(ctx, AddressSegmentRunnable, option.graphName), cr.nodeInfo, cr.meta, opts...)
 	}
 
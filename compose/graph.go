@@ -34,6 +34,8 @@ import (
 )
 
 // START is the start node of the graph. You can add your first edge with START.
+// START may have several successors, each of which receives the graph input,
+// e.g. to seed independent pipelines with the same input. For stream input, each successor reads its own copy.
 const START = "start"
 
 // END is the end node of the graph. You can add your last edge with END.
@@ -69,6 +71,7 @@ type graph struct {
 
 	stateType      reflect.Type
 	stateGenerator func(ctx context.Context) any
+	stateInitHook  func(ctx context.Context, state any) error
 	newOpts        []NewGraphOption
 
 	expectedInputType, expectedOutputType reflect.Type
@@ -86,6 +89,8 @@ type graph struct {
 	handlerOnEdges   map[string]map[string][]handlerPair
 	handlerPreNode   map[string][]handlerPair
 	handlerPreBranch map[string][][]handlerPair
+
+	edgeTransforms map[string]map[string]*EdgeTransform
 }
 
 type newGraphConfig struct {
@@ -94,27 +99,39 @@ type newGraphConfig struct {
 	cmp                   component
 	stateType             reflect.Type
 	stateGenerator        func(ctx context.Context) any
+	stateInitHook         func(ctx context.Context, state any) error
+	stateInitHookType     reflect.Type
 	newOpts               []NewGraphOption
 }
 
 func newGraphFromGeneric[I, O any](
 	cmp component,
-	stateGenerator func(ctx context.Context) any,
-	stateType reflect.Type,
+	options *newGraphOptions,
 	opts []NewGraphOption,
 ) *graph {
 	return newGraph(&newGraphConfig{
-		inputType:      generic.TypeOf[I](),
-		outputType:     generic.TypeOf[O](),
-		gh:             newGenericHelper[I, O](),
-		cmp:            cmp,
-		stateType:      stateType,
-		stateGenerator: stateGenerator,
-		newOpts:        opts,
+		inputType:         generic.TypeOf[I](),
+		outputType:        generic.TypeOf[O](),
+		gh:                newGenericHelper[I, O](),
+		cmp:               cmp,
+		stateType:         options.stateType,
+		stateGenerator:    options.withState,
+		stateInitHook:     options.stateInitHook,
+		stateInitHookType: options.stateInitHookType,
+		newOpts:           opts,
 	})
 }
 
 func newGraph(cfg *newGraphConfig) *graph {
+	var buildError error
+	if cfg.stateInitHook != nil {
+		if cfg.stateGenerator == nil {
+			buildError = errors.New("state init hook requires graph state, which is not enabled")
+		} else if cfg.stateInitHookType != cfg.stateType {
+			buildError = fmt.Errorf("state init hook's state type[%v] is different from graph[%v]", cfg.stateInitHookType, cfg.stateType)
+		}
+	}
+
 	return &graph{
 		nodes:        make(map[string]*graphNode),
 		dataEdges:    make(map[string][]string),
@@ -136,11 +153,16 @@ func newGraph(cfg *newGraphConfig) *graph {
 
 		stateType:      cfg.stateType,
 		stateGenerator: cfg.stateGenerator,
+		stateInitHook:  cfg.stateInitHook,
 		newOpts:        cfg.newOpts,
 
+		buildError: buildError,
+
 		handlerOnEdges:   make(map[string]map[string][]handlerPair),
 		handlerPreNode:   make(map[string][]handlerPair),
 		handlerPreBranch: make(map[string][][]handlerPair),
+
+		edgeTransforms: make(map[string]map[string]*EdgeTransform),
 	}
 }
 
@@ -793,7 +815,7 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 
 		preBranchHandlerManager: &preBranchHandlerManager{h: g.handlerPreBranch},
 		preNodeHandlerManager:   &preNodeHandlerManager{h: g.handlerPreNode},
-		edgeHandlerManager:      &edgeHandlerManager{h: g.handlerOnEdges},
+		edgeHandlerManager:      &edgeHandlerManager{h: g.handlerOnEdges, transforms: g.edgeTransforms},
 
 		mergeConfigs: mergeConfigs,
 	}
@@ -803,18 +825,26 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		successors[ch] = getSuccessors(r.chanSubscribeTo[ch])
 	}
 	r.successors = successors
+	r.version = graphVersion(r)
 
 	if g.stateGenerator != nil {
-		r.runCtx = func(ctx context.Context) context.Context {
+		r.runCtx = func(ctx context.Context) (context.Context, error) {
 			var parent *internalState
 			if p, ok := ctx.Value(stateKey{}).(*internalState); ok {
 				parent = p
 			}
 
+			state := g.stateGenerator(ctx)
+			if g.stateInitHook != nil {
+				if err := g.stateInitHook(ctx, state); err != nil {
+					return nil, err
+				}
+			}
+
 			return context.WithValue(ctx, stateKey{}, &internalState{
-				state:  g.stateGenerator(ctx),
+				state:  state,
 				parent: parent,
-			})
+			}), nil
 		}
 	}
 
@@ -827,6 +857,10 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 	}
 
 	if opt != nil {
+		if err := g.validateInterruptNodes(opt); err != nil {
+			return nil, err
+		}
+
 		inputPairs := make(map[string]streamConvertPair)
 		outputPairs := make(map[string]streamConvertPair)
 		for key, c := range r.chanSubscribeTo {
@@ -836,6 +870,11 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		inputPairs[END] = r.outputConvertStreamPair
 		outputPairs[START] = r.inputConvertStreamPair
 		r.checkPointer = newCheckPointer(inputPairs, outputPairs, opt.checkPointStore, opt.serializer)
+		if opt.deleteCheckPointOnSuccess {
+			if _, ok := opt.checkPointStore.(CheckPointDeleter); !ok {
+				return nil, fmt.Errorf("delete checkpoint on success requires a checkpoint store implementing CheckPointDeleter")
+			}
+		}
 
 		r.interruptBeforeNodes = opt.interruptBeforeNodes
 		r.interruptAfterNodes = opt.interruptAfterNodes
@@ -856,6 +895,29 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 	return r.toComposableRunnable(), nil
 }
 
+// validateInterruptNodes makes sure the nodes to interrupt before or after exist, as misspelled node keys
+// would otherwise silently never interrupt.
+func (g *graph) validateInterruptNodes(opt *graphCompileOptions) error {
+	for _, key := range opt.interruptBeforeNodes {
+		if _, ok := g.nodes[key]; !ok {
+			return fmt.Errorf("interrupt before node '%s' not found in graph%s", key, graphNameSuffix(opt.graphName))
+		}
+	}
+	for _, key := range opt.interruptAfterNodes {
+		if _, ok := g.nodes[key]; !ok {
+			return fmt.Errorf("interrupt after node '%s' not found in graph%s", key, graphNameSuffix(opt.graphName))
+		}
+	}
+	return nil
+}
+
+func graphNameSuffix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " '" + name + "'"
+}
+
 func getSuccessors(c *chanCall) []string {
 	ret := make([]string, len(c.writeTo))
 	copy(ret, c.writeTo)
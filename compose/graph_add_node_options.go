@@ -18,6 +18,7 @@ package compose
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/cloudwego/eino/internal/generic"
 )
@@ -43,6 +44,11 @@ type nodeOptions struct {
 	inputKey  string
 	outputKey string
 
+	timeout time.Duration
+
+	retryAttempts int
+	retryBackoff  func(attempt int) time.Duration
+
 	graphCompileOption []GraphCompileOption // when this node is itself an AnyGraph, this option will be used to compile the node as a nested graph
 }
 
@@ -79,6 +85,42 @@ func WithOutputKey(k string) GraphAddNodeOpt {
 	}
 }
 
+// WithNodeTimeout sets the max duration of an execution of the node, after which the context of the node is canceled,
+// and the graph run fails with an error wrapping context.DeadlineExceeded, reported to the OnError callbacks of the node.
+// The graph waits for a timed-out node to return on the cancellation of its context for a short grace period only,
+// so that a node not respecting the cancellation doesn't stall the run: it's left running in the background,
+// its result discarded, and its output stream closed if it returns one. A node returning its output despite the
+// cancellation within the grace period completes as usual.
+// As with any failure, a timed-out node isn't checkpointed as completed, so resuming from a checkpoint reruns it.
+// For a streaming node, the timeout only covers the time to return its output stream.
+// e.g.
+//
+//	graph.AddLambdaNode("slow_node", lambda, compose.WithNodeTimeout(30*time.Second))
+func WithNodeTimeout(timeout time.Duration) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.timeout = timeout
+	}
+}
+
+// WithNodeRetry reruns the node when it fails, up to maxAttempts executions in total, the last error being returned
+// when all of them fail. Before the next attempt, the node waits for backoff(attempt), attempt being the number of
+// the failed attempt starting from 1, and gives up on the cancellation of the context of the graph run.
+// backoff is optional, retrying immediately if nil.
+// Each attempt triggers the callbacks of the node, while the state handlers of the node run once,
+// and interrupts aren't retried. With WithNodeTimeout, the timeout applies to each attempt.
+// For a streaming node, its input stream is copied to be replayed to each attempt.
+// e.g.
+//
+//	graph.AddChatModelNode("model", chatModel, compose.WithNodeRetry(3, func(attempt int) time.Duration {
+//		return time.Duration(attempt) * time.Second
+//	}))
+func WithNodeRetry(maxAttempts int, backoff func(attempt int) time.Duration) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.retryAttempts = maxAttempts
+		o.nodeOptions.retryBackoff = backoff
+	}
+}
+
 // WithGraphCompileOptions when the node is an AnyGraph, use this option to set compile option for the node.
 // e.g.
 //
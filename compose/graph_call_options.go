@@ -22,6 +22,8 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
@@ -29,6 +31,7 @@ import (
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/components/retriever"
+	icb "github.com/cloudwego/eino/internal/callbacks"
 )
 
 type graphCancelChanKey struct{}
@@ -99,11 +102,19 @@ type Option struct {
 
 	paths []*NodePath
 
-	maxRunSteps         int
-	checkPointID        *string
-	writeToCheckPointID *string
-	forceNewRun         bool
-	stateModifier       StateModifier
+	maxRunSteps            int
+	checkPointID           *string
+	writeToCheckPointID    *string
+	forceNewRun            bool
+	stateModifier          StateModifier
+	nodeHeartbeat          time.Duration
+	interruptLog           *InterruptLogConfig
+	stateSnapshots         bool
+	failFast               bool
+	resumeOnly             bool
+	checkpointVersionCheck bool
+	nodeOverrides          map[string]NodeOverride
+	traceID                *string
 }
 
 func (o Option) deepCopy() Option {
@@ -227,6 +238,83 @@ func WithCallbacks(cbs ...callbacks.Handler) Option {
 	}
 }
 
+// WithNodeHeartbeat makes each node of the graph emit a heartbeat every interval while it's executing,
+// to the callback handlers implementing callbacks.HeartbeatHandler, so monitors know a long running node is alive.
+// A node is executing until it returns, so for a streaming node the heartbeats stop once it returns its stream.
+// It applies to the nodes of the graph it's passed to, a subgraph node emitting heartbeats as a whole.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithNodeHeartbeat(10*time.Second), compose.WithCallbacks(monitor))
+func WithNodeHeartbeat(interval time.Duration) Option {
+	return Option{
+		nodeHeartbeat: interval,
+	}
+}
+
+// WithStateSnapshots makes each node of the graph capture the graph state before its pre handler runs
+// and after its post handler runs, and pass the snapshot with the changed fields to the callback handlers
+// implementing callbacks.StateSnapshotHandler, e.g. a TraceSink set by WithTraceSink, to find which node changed what.
+// The state is captured as JSON, so unexported fields aren't part of it. For a streaming node, changes made
+// while its output stream is consumed after the post handler returned aren't seen.
+// It applies to the nodes of the graph it's passed to, a subgraph node being snapshotted as a whole.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithStateSnapshots(), compose.WithTraceSink(sink))
+func WithStateSnapshots() Option {
+	return Option{
+		stateSnapshots: true,
+	}
+}
+
+// WithFailFast makes the graph cancel the contexts of the nodes still executing as soon as a node fails,
+// instead of waiting for them to finish on their own before the run fails, e.g. for the parallel branches of a graph
+// in AllPredecessor mode. An interrupt isn't a failure: the other nodes keep executing, so that they can be
+// checkpointed as usual. Only nodes respecting the cancellation of their context stop early.
+// It applies to the nodes of the graph it's passed to, a subgraph node being canceled as a whole.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithFailFast())
+func WithFailFast() Option {
+	return Option{
+		failFast: true,
+	}
+}
+
+// WithTraceID sets the trace ID of the run, shared by all its nodes, including the ones of subgraphs and the tools
+// called by ToolsNodes, e.g. to correlate the spans and logs of a run in distributed tracing.
+// The trace ID is available by GetTraceID in the node bodies, and is set as RunInfo.TraceID for the callbacks.
+// If traceID is empty, a random one is generated.
+// Without this option, the trace ID of the context the graph is run with is kept, e.g. the one of a parent graph.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithTraceID(requestID))
+func WithTraceID(traceID string) Option {
+	return Option{
+		traceID: &traceID,
+	}
+}
+
+// GetTraceID returns the trace ID of the run ctx belongs to, set by WithTraceID, or "" if there is none.
+func GetTraceID(ctx context.Context) string {
+	return icb.GetTraceID(ctx)
+}
+
+func ctxWithTraceID(ctx context.Context, opts ...Option) context.Context {
+	var traceID *string
+	for _, opt := range opts {
+		if opt.traceID != nil {
+			traceID = opt.traceID
+		}
+	}
+	if traceID == nil {
+		return ctx
+	}
+	if *traceID == "" {
+		return icb.WithTraceID(ctx, uuid.NewString())
+	}
+	return icb.WithTraceID(ctx, *traceID)
+}
+
 // WithRuntimeMaxSteps sets the maximum number of steps for the graph runtime.
 // e.g.
 //
@@ -18,7 +18,10 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -494,3 +497,192 @@ func TestCallOptionInSubGraph(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, result, "input grandparent-1 parent-1 child1-1 child2-1")
 }
+
+type heartbeatRecorder struct {
+	callbacks.Handler
+
+	mu    sync.Mutex
+	beats map[string]int
+}
+
+// Needed skips all the other callbacks, as the builder's check is hidden by the embedding.
+func (h *heartbeatRecorder) Needed(context.Context, *callbacks.RunInfo, callbacks.CallbackTiming) bool {
+	return false
+}
+
+func (h *heartbeatRecorder) OnHeartbeat(_ context.Context, info *callbacks.RunInfo, _ time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beats[info.Name]++
+}
+
+func (h *heartbeatRecorder) count(name string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.beats[name]
+}
+
+func TestWithNodeHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	h := &heartbeatRecorder{Handler: callbacks.NewHandlerBuilder().Build(), beats: map[string]int{}}
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("slow", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		time.Sleep(200 * time.Millisecond)
+		return input, nil
+	}), WithNodeName("slow")))
+	assert.NoError(t, g.AddLambdaNode("fast", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input, nil
+	}), WithNodeName("fast")))
+	assert.NoError(t, g.AddEdge(START, "slow"))
+	assert.NoError(t, g.AddEdge("slow", "fast"))
+	assert.NoError(t, g.AddEdge("fast", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "input", WithNodeHeartbeat(20*time.Millisecond), WithCallbacks(h))
+	assert.NoError(t, err)
+	beats := h.count("slow")
+	assert.GreaterOrEqual(t, beats, 2)
+	assert.Zero(t, h.count("fast"))
+
+	// no heartbeat is emitted once the nodes returned
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, beats, h.count("slow"))
+
+	// without the option, there is no heartbeat
+	_, err = r.Invoke(ctx, "input", WithCallbacks(h))
+	assert.NoError(t, err)
+	assert.Equal(t, beats, h.count("slow"))
+}
+
+func TestWithFailFast(t *testing.T) {
+	ctx := context.Background()
+	newGraph := func(failure func(ctx context.Context) error, slowCanceled chan bool) Runnable[string, map[string]any] {
+		g := NewGraph[string, map[string]any]()
+		assert.NoError(t, g.AddLambdaNode("failing", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return "", failure(ctx)
+		}), WithOutputKey("failing")))
+		assert.NoError(t, g.AddLambdaNode("slow", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			select {
+			case <-ctx.Done():
+				slowCanceled <- true
+				return "", ctx.Err()
+			case <-time.After(time.Second):
+				slowCanceled <- false
+				return input, nil
+			}
+		}), WithOutputKey("slow")))
+		assert.NoError(t, g.AddEdge(START, "failing"))
+		assert.NoError(t, g.AddEdge(START, "slow"))
+		assert.NoError(t, g.AddEdge("failing", END))
+		assert.NoError(t, g.AddEdge("slow", END))
+		r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+		assert.NoError(t, err)
+		return r
+	}
+
+	t.Run("a failing branch cancels the slow one", func(t *testing.T) {
+		slowCanceled := make(chan bool, 1)
+		r := newGraph(func(ctx context.Context) error {
+			return errors.New("boom")
+		}, slowCanceled)
+
+		start := time.Now()
+		_, err := r.Invoke(ctx, "input", WithFailFast())
+		assert.ErrorContains(t, err, "boom")
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.True(t, <-slowCanceled)
+	})
+
+	t.Run("an interrupting branch doesn't cancel the slow one", func(t *testing.T) {
+		slowCanceled := make(chan bool, 1)
+		r := newGraph(func(ctx context.Context) error {
+			return Interrupt(ctx, "confirm")
+		}, slowCanceled)
+
+		_, err := r.Invoke(ctx, "input", WithFailFast())
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.False(t, <-slowCanceled)
+	})
+
+	t.Run("the contexts of completed nodes are canceled", func(t *testing.T) {
+		var invokeCtx, streamCtx context.Context
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("invoke", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			invokeCtx = ctx
+			return input, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("stream", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+			streamCtx = ctx
+			return schema.StreamReaderFromArray([]string{input, "!"}), nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "invoke"))
+		assert.NoError(t, g.AddEdge("invoke", "stream"))
+		assert.NoError(t, g.AddEdge("stream", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, "input", WithFailFast())
+		assert.NoError(t, err)
+		assert.Error(t, invokeCtx.Err())
+		assert.NoError(t, streamCtx.Err())
+		out, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "input!", out)
+		assert.Eventually(t, func() bool { return streamCtx.Err() != nil }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestWithTraceID(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var nodeTraceIDs []string
+	cbTraceIDs := map[string]string{}
+	cb := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		mu.Lock()
+		defer mu.Unlock()
+		cbTraceIDs[info.Name] = info.TraceID
+		return ctx
+	}).Build()
+	traceLambda := func() *Lambda {
+		return InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			nodeTraceIDs = append(nodeTraceIDs, GetTraceID(ctx))
+			return input, nil
+		})
+	}
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("inner", traceLambda(), WithNodeName("inner")))
+	assert.NoError(t, sub.AddEdge(START, "inner"))
+	assert.NoError(t, sub.AddEdge("inner", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("outer", traceLambda(), WithNodeName("outer")))
+	assert.NoError(t, g.AddGraphNode("sub", sub, WithNodeName("sub")))
+	assert.NoError(t, g.AddEdge(START, "outer"))
+	assert.NoError(t, g.AddEdge("outer", "sub"))
+	assert.NoError(t, g.AddEdge("sub", END))
+	r, err := g.Compile(ctx, WithGraphName("root"))
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "input", WithTraceID("trace-1"), WithCallbacks(cb))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"trace-1", "trace-1"}, nodeTraceIDs)
+	assert.Equal(t, map[string]string{"root": "trace-1", "outer": "trace-1", "sub": "trace-1", "inner": "trace-1"}, cbTraceIDs)
+
+	nodeTraceIDs = nil
+	_, err = r.Invoke(ctx, "input", WithTraceID(""))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, nodeTraceIDs[0])
+	assert.Equal(t, nodeTraceIDs[0], nodeTraceIDs[1])
+
+	nodeTraceIDs = nil
+	_, err = r.Invoke(ctx, "input")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", ""}, nodeTraceIDs)
+}
@@ -30,6 +30,8 @@ type graphCompileOptions struct {
 	interruptBeforeNodes []string
 	interruptAfterNodes  []string
 
+	deleteCheckPointOnSuccess bool
+
 	eagerDisabled bool
 
 	mergeConfigs map[string]FanInMergeConfig
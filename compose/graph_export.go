@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExportMermaid returns the topology of the graph as a Mermaid flowchart, e.g. to render it for debugging.
+// Each node is labeled with its key and component, START and END included, and a subgraph node is rendered as
+// a Mermaid subgraph containing its own nodes. Edges carrying data are solid, control-only edges are dotted,
+// and the possible end nodes of a branch are linked from its start node by dotted edges labeled "branch".
+// It only reads the graph, and can be called before or after Compile, the edges of a Chain or Workflow subgraph
+// being complete once compiled.
+// e.g.
+//
+//	graph := compose.NewGraph[string, string]()
+//	// add nodes, edges and branches
+//	chart, err := graph.ExportMermaid()
+func (g *graph) ExportMermaid() (string, error) {
+	if g.buildError != nil {
+		return "", g.buildError
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	writeMermaidGraph(&sb, g, "", "  ")
+	return sb.String(), nil
+}
+
+// mermaidGraph returns the graph a subgraph node is built on, to be exported with its parent.
+func (g *graph) mermaidGraph() *graph {
+	return g
+}
+
+func (c *Chain[I, O]) mermaidGraph() *graph {
+	return c.gg.graph
+}
+
+func (wf *Workflow[I, O]) mermaidGraph() *graph {
+	return wf.g
+}
+
+// writeMermaidGraph writes the nodes and edges of g, whose node IDs are prefixed by prefix to be unique in the chart.
+func writeMermaidGraph(sb *strings.Builder, g *graph, prefix, indent string) {
+	keys := make([]string, 0, len(g.nodes))
+	for key := range g.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ids := make(map[string]string, len(keys)+2)
+	ids[START] = prefix + "start"
+	ids[END] = prefix + "end_"
+	for i, key := range keys {
+		ids[key] = fmt.Sprintf("%snode%d", prefix, i)
+	}
+
+	fmt.Fprintf(sb, "%s%s([%s])\n", indent, ids[START], mermaidLabel(START))
+	for _, key := range keys {
+		node := g.nodes[key]
+		label := mermaidLabel(fmt.Sprintf("%s (%s)", key, node.executorMeta.component))
+		if sub, ok := node.g.(interface{ mermaidGraph() *graph }); ok {
+			fmt.Fprintf(sb, "%ssubgraph %s[%s]\n", indent, ids[key], label)
+			writeMermaidGraph(sb, sub.mermaidGraph(), ids[key]+"_", indent+"  ")
+			fmt.Fprintf(sb, "%send\n", indent)
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s[%s]\n", indent, ids[key], label)
+	}
+	fmt.Fprintf(sb, "%s%s([%s])\n", indent, ids[END], mermaidLabel(END))
+
+	for _, from := range append([]string{START}, keys...) {
+		// to -> whether the edge carries data
+		tos := make(map[string]bool)
+		for _, to := range g.controlEdges[from] {
+			tos[to] = false
+		}
+		for _, to := range g.dataEdges[from] {
+			tos[to] = true
+		}
+		for _, to := range sortedMermaidKeys(tos) {
+			arrow := "-->"
+			if !tos[to] {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(sb, "%s%s %s %s\n", indent, ids[from], arrow, ids[to])
+		}
+
+		for _, branch := range g.branches[from] {
+			for _, to := range sortedMermaidKeys(branch.endNodes) {
+				fmt.Fprintf(sb, "%s%s -.->|branch| %s\n", indent, ids[from], ids[to])
+			}
+		}
+	}
+}
+
+// mermaidLabel quotes a label, escaping the characters Mermaid can't take in it.
+func mermaidLabel(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
+
+func sortedMermaidKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
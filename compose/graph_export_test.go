@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportMermaid(t *testing.T) {
+	ctx := context.Background()
+	identity := InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input, nil
+	})
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("inner", identity))
+	assert.NoError(t, sub.AddEdge(START, "inner"))
+	assert.NoError(t, sub.AddEdge("inner", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("classify", identity))
+	assert.NoError(t, g.AddGraphNode("sub", sub))
+	assert.NoError(t, g.AddPassthroughNode(`say "hi"`))
+	assert.NoError(t, g.AddEdge(START, "classify"))
+	assert.NoError(t, g.AddBranch("classify", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		return "sub", nil
+	}, map[string]bool{"sub": true, `say "hi"`: true})))
+	assert.NoError(t, g.AddEdge("sub", END))
+	assert.NoError(t, g.AddEdge(`say "hi"`, END))
+
+	chart, err := g.ExportMermaid()
+	assert.NoError(t, err)
+	assert.Equal(t, `flowchart TD
+  start(["start"])
+  node0["classify (Lambda)"]
+  node1["say #quot;hi#quot; (Passthrough)"]
+  subgraph node2["sub (Graph)"]
+    node2_start(["start"])
+    node2_node0["inner (Lambda)"]
+    node2_end_(["end"])
+    node2_start --> node2_node0
+    node2_node0 --> node2_end_
+  end
+  end_(["end"])
+  start --> node0
+  node0 -.->|branch| node1
+  node0 -.->|branch| node2
+  node1 --> end_
+  node2 --> end_
+`, chart)
+
+	// exporting doesn't affect the execution
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+	out, err := r.Invoke(ctx, "input")
+	assert.NoError(t, err)
+	assert.Equal(t, "input", out)
+	compiledChart, err := g.ExportMermaid()
+	assert.NoError(t, err)
+	assert.Equal(t, chart, compiledChart)
+
+	broken := NewGraph[string, string]()
+	assert.Error(t, broken.AddEdge("missing", END))
+	_, err = broken.ExportMermaid()
+	assert.ErrorContains(t, err, "needs to be added to graph first")
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudwego/eino/internal"
+	icb "github.com/cloudwego/eino/internal/callbacks"
 	"github.com/cloudwego/eino/internal/safe"
 )
 
@@ -30,7 +32,7 @@ type channel interface {
 	reportValues(map[string]any) error
 	reportDependencies([]string)
 	reportSkip([]string) bool
-	get(bool, string, *edgeHandlerManager) (any, bool, error)
+	get(context.Context, bool, string, *edgeHandlerManager) (any, bool, error)
 	convertValues(fn func(map[string]any) error) error
 	load(channel) error
 
@@ -39,9 +41,22 @@ type channel interface {
 
 type edgeHandlerManager struct {
 	h map[string]map[string][]handlerPair
+	// transforms are applied before the handlers of the same edge
+	transforms map[string]map[string]*EdgeTransform
 }
 
-func (e *edgeHandlerManager) handle(from, to string, value any, isStream bool) (any, error) {
+func (e *edgeHandlerManager) handle(ctx context.Context, from, to string, value any, isStream bool) (any, error) {
+	if t, ok := e.transforms[from][to]; ok {
+		if isStream {
+			value = t.transform(ctx, value.(streamReader))
+		} else {
+			var err error
+			value, err = t.invoke(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	if _, ok := e.h[from]; !ok {
 		return value, nil
 	}
@@ -186,10 +201,10 @@ func (c *channelManager) updateDependencies(_ context.Context, dependenciesMap m
 	return nil
 }
 
-func (c *channelManager) getFromReadyChannels(_ context.Context) (map[string]any, error) {
+func (c *channelManager) getFromReadyChannels(ctx context.Context) (map[string]any, error) {
 	result := make(map[string]any)
 	for target, ch := range c.channels {
-		v, ready, err := ch.get(c.isStream, target, c.edgeHandlerManager)
+		v, ready, err := ch.get(ctx, c.isStream, target, c.edgeHandlerManager)
 		if err != nil {
 			return nil, fmt.Errorf("get value from ready channel[%s] fail: %w", target, err)
 		}
@@ -264,6 +279,10 @@ type task struct {
 	option         []any
 	err            error
 	skipPreHandler bool
+	stateBefore    any
+	// cancel cancels ctx, set when the graph fails fast.
+	// It's called by the task manager once the task completes and its output is no longer being produced.
+	cancel context.CancelFunc
 }
 
 type taskManager struct {
@@ -280,6 +299,12 @@ type taskManager struct {
 	deadline *time.Time
 
 	persistRerunInput bool
+
+	heartbeatInterval time.Duration
+	stateSnapshots    bool
+	failFast          bool
+
+	nodeOverrides map[string]NodeOverride
 }
 
 func (t *taskManager) execute(currentTask *task) {
@@ -294,7 +319,222 @@ func (t *taskManager) execute(currentTask *task) {
 	}()
 
 	ctx := initNodeCallbacks(currentTask.ctx, currentTask.nodeKey, currentTask.call.action.nodeInfo, currentTask.call.action.meta, t.opts...)
-	currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+	if t.heartbeatInterval > 0 {
+		stop := startNodeHeartbeat(ctx, t.heartbeatInterval)
+		defer stop()
+	}
+	info := currentTask.call.action.nodeInfo
+	run := func(ctx context.Context, input any) (any, error) {
+		if output, ok := getSubGraphAbort(ctx); ok {
+			// the input isn't needed, and may be missing for the rerun of the interrupted node
+			sr, isStream := input.(streamReader)
+			if isStream {
+				sr.close()
+			}
+			return toNodeOutput(currentTask.nodeKey, currentTask.call.action, output, isStream)
+		}
+		if override, ok := t.nodeOverrides[currentTask.nodeKey]; ok {
+			return runNodeOverride(ctx, currentTask.nodeKey, currentTask.call.action, override, input)
+		}
+		return t.runWrapper(ctx, currentTask.call.action, input, currentTask.option...)
+	}
+	runAttempt := run
+	if info != nil && info.timeout > 0 {
+		runAttempt = func(ctx context.Context, input any) (any, error) {
+			return runWithNodeTimeout(ctx, currentTask.nodeKey, info.timeout, func(ctx context.Context) (any, error) {
+				return run(ctx, input)
+			})
+		}
+	}
+	if info != nil && info.retryAttempts > 1 {
+		currentTask.output, currentTask.err = runWithNodeRetry(ctx, info.retryAttempts, info.retryBackoff, currentTask.input, runAttempt)
+		return
+	}
+	currentTask.output, currentTask.err = runAttempt(ctx, currentTask.input)
+}
+
+// runWithNodeRetry runs the node until it succeeds, is interrupted or has failed maxAttempts times,
+// waiting for backoff between the attempts. A stream input is copied, so that each attempt reads it from the start.
+func runWithNodeRetry(ctx context.Context, maxAttempts int, backoff func(attempt int) time.Duration, input any,
+	run func(ctx context.Context, input any) (any, error)) (any, error) {
+	inputs := make([]any, maxAttempts)
+	if sr, ok := input.(streamReader); ok {
+		copies := sr.copy(maxAttempts)
+		for i := range copies {
+			inputs[i] = copies[i]
+		}
+	} else {
+		for i := range inputs {
+			inputs[i] = input
+		}
+	}
+
+	var (
+		output any
+		err    error
+	)
+	for attempt := 1; ; attempt++ {
+		output, err = run(ctx, inputs[attempt-1])
+		if err == nil || attempt == maxAttempts || isInterruptError(err) || !waitNodeRetry(ctx, backoff, attempt) {
+			// the stream copies of the attempts not made have to be closed
+			for _, in := range inputs[attempt:] {
+				if sr, ok := in.(streamReader); ok {
+					sr.close()
+				}
+			}
+			return output, err
+		}
+	}
+}
+
+// waitNodeRetry waits for the backoff of the failed attempt, returning false if the context is canceled meanwhile.
+func waitNodeRetry(ctx context.Context, backoff func(attempt int) time.Duration, attempt int) bool {
+	var d time.Duration
+	if backoff != nil {
+		d = backoff(attempt)
+	}
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// nodeTimeoutGracePeriod is how long a timed-out node is waited for after the cancellation of its context.
+const nodeTimeoutGracePeriod = 100 * time.Millisecond
+
+// runWithNodeTimeout runs the node in its own goroutine. When the timeout is reached, it waits for the node
+// to return on the cancellation of its context for nodeTimeoutGracePeriod at most, and then returns anyway,
+// so that a node not respecting the cancellation of its context can't stall the graph.
+// The output of a node returning later is discarded, closing it if it's a stream.
+// The context of a node returning a stream in time is canceled once the stream is read to the end or closed.
+func runWithNodeTimeout(ctx context.Context, nodeKey string, timeout time.Duration,
+	run func(ctx context.Context) (any, error)) (any, error) {
+	runCtx, cancel := newNodeTimeoutContext(ctx)
+
+	type result struct {
+		output any
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if panicInfo := recover(); panicInfo != nil {
+				done <- result{err: safe.NewPanicErr(panicInfo, debug.Stack())}
+			}
+		}()
+		output, err := run(runCtx)
+		done <- result{output: output, err: err}
+	}()
+
+	returned := func(r result) (any, error) {
+		if r.err != nil && ctx.Err() == nil && runCtx.timedOut() {
+			cancel()
+			// the node returned on the cancellation of its context, having reported the error to its callbacks itself
+			return nil, fmt.Errorf("node '%s' timed out after %s: %w", nodeKey, timeout, r.err)
+		}
+		if sr, ok := r.output.(streamReader); ok && r.err == nil {
+			// the stream may still be produced under the context of the node
+			return sr.withFinish(cancel), nil
+		}
+		cancel()
+		return r.output, r.err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return returned(r)
+	case <-timer.C:
+		runCtx.timeout()
+	}
+
+	grace := time.NewTimer(nodeTimeoutGracePeriod)
+	defer grace.Stop()
+	select {
+	case r := <-done:
+		return returned(r)
+	case <-grace.C:
+	}
+
+	cancel()
+	go func() {
+		if sr, ok := (<-done).output.(streamReader); ok {
+			sr.close()
+		}
+	}()
+	_, err := onError(ctx, fmt.Errorf("node '%s' timed out after %s: %w", nodeKey, timeout, context.DeadlineExceeded))
+	return nil, err
+}
+
+// nodeTimeoutContext is the context of a node run with a timeout. Unlike a context with a deadline,
+// it's done on the timeout only if the node hasn't returned yet, so that its output stream isn't cut off by it.
+type nodeTimeoutContext struct {
+	context.Context
+	cancel context.CancelFunc
+	// expired is set to 1 on the timeout
+	expired int32
+}
+
+func newNodeTimeoutContext(ctx context.Context) (*nodeTimeoutContext, context.CancelFunc) {
+	c := &nodeTimeoutContext{}
+	c.Context, c.cancel = context.WithCancel(ctx)
+	return c, c.cancel
+}
+
+func (c *nodeTimeoutContext) timeout() {
+	atomic.StoreInt32(&c.expired, 1)
+	c.cancel()
+}
+
+func (c *nodeTimeoutContext) timedOut() bool {
+	return atomic.LoadInt32(&c.expired) == 1
+}
+
+// Err returns context.DeadlineExceeded once the node timed out, as a context with a deadline does.
+func (c *nodeTimeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && c.timedOut() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// startNodeHeartbeat emits a heartbeat every interval until stop is called, after which no more heartbeat is emitted.
+func startNodeHeartbeat(ctx context.Context, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	start := time.Now()
+	go func() {
+		defer func() {
+			// a panicking heartbeat handler must not crash the process
+			_ = recover()
+			close(exited)
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				icb.OnHeartbeat(ctx, time.Since(start))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
 }
 
 func (t *taskManager) submit(tasks []*task) error {
@@ -317,6 +557,14 @@ func (t *taskManager) submit(tasks []*task) error {
 			}
 		}
 
+		if t.stateSnapshots {
+			currentTask.stateBefore = snapshotState(currentTask.ctx)
+		}
+
+		if t.failFast {
+			currentTask.ctx, currentTask.cancel = context.WithCancel(currentTask.ctx)
+		}
+
 		err := runPreHandler(currentTask, t.runWrapper)
 		if err != nil {
 			// pre-handler error, regarded as a failure of the task itself
@@ -335,7 +583,8 @@ func (t *taskManager) submit(tasks []*task) error {
 	}
 
 	var syncTask *task
-	if t.num == 0 && (len(tasks) == 1 || t.needAll) && t.cancelCh == nil /*if graph can be interrupted by user, shouldn't sync run task*/ {
+	// if graph fails fast, a task running synchronously couldn't be canceled by the failure of the other tasks
+	if t.num == 0 && (len(tasks) == 1 || t.needAll && !t.failFast) && t.cancelCh == nil /*if graph can be interrupted by user, shouldn't sync run task*/ {
 		syncTask = tasks[0]
 		tasks = tasks[1:]
 	}
@@ -406,12 +655,43 @@ func (t *taskManager) waitOne() (ta *task, success bool, canceled bool) {
 
 	if ta.err != nil {
 		// biz error, jump post processor
+		t.emitStateSnapshot(ta)
+		if t.failFast && !isInterruptError(ta.err) {
+			t.cancelRunningTasks()
+		}
+		releaseTask(ta)
 		return ta, true, false
 	}
 	runPostHandler(ta, t.runWrapper)
+	t.emitStateSnapshot(ta)
+	releaseTask(ta)
 	return ta, true, false
 }
 
+// releaseTask cancels the context of a completed task, which an output stream may still be produced under,
+// so it's canceled once the stream is read to the end or closed.
+func releaseTask(ta *task) {
+	if ta.cancel == nil {
+		return
+	}
+	if sr, ok := ta.output.(streamReader); ok && ta.err == nil {
+		ta.output = sr.withFinish(ta.cancel)
+	} else {
+		ta.cancel()
+	}
+	ta.cancel = nil
+}
+
+// cancelRunningTasks cancels the contexts of the tasks still executing, so that they return early after a task failed.
+// The completed tasks have been released by releaseTask already.
+func (t *taskManager) cancelRunningTasks() {
+	for _, rt := range t.runningTasks {
+		if rt.cancel != nil {
+			rt.cancel()
+		}
+	}
+}
+
 func (t *taskManager) waitAll() (successTasks []*task, canceledTasks []*task) {
 	result := make([]*task, 0, t.num)
 	for {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/internal/generic"
@@ -51,6 +52,13 @@ type nodeInfo struct {
 	inputKey  string
 	outputKey string
 
+	// timeout is the max duration of an execution of the node, passed from WithNodeTimeout()
+	timeout time.Duration
+
+	// retryAttempts and retryBackoff are the max executions of the node and the wait between them, passed from WithNodeRetry()
+	retryAttempts int
+	retryBackoff  func(attempt int) time.Duration
+
 	preProcessor, postProcessor *composableRunnable
 
 	compileOption *graphCompileOptions // if the node is an AnyGraph, it will need compile options of its own
@@ -170,6 +178,9 @@ func getNodeInfo(opts ...GraphAddNodeOpt) (*nodeInfo, *graphAddNodeOpts) {
 		name:          opt.nodeOptions.nodeName,
 		inputKey:      opt.nodeOptions.inputKey,
 		outputKey:     opt.nodeOptions.outputKey,
+		timeout:       opt.nodeOptions.timeout,
+		retryAttempts: opt.nodeOptions.retryAttempts,
+		retryBackoff:  opt.nodeOptions.retryBackoff,
 		preProcessor:  opt.processor.statePreHandler,
 		postProcessor: opt.processor.statePostHandler,
 		compileOption: newGraphCompileOptions(opt.nodeOptions.graphCompileOption...),
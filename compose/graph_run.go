@@ -53,7 +53,7 @@ type runner struct {
 	eager       bool
 	dag         bool
 
-	runCtx func(ctx context.Context) context.Context
+	runCtx func(ctx context.Context) (context.Context, error)
 
 	options graphCompileOptions
 
@@ -77,6 +77,7 @@ type runner struct {
 	preBranchHandlerManager *preBranchHandlerManager
 
 	checkPointer         *checkPointer
+	version              string // hash of the graph structure, recorded in checkpoints
 	interruptBeforeNodes []string
 	interruptAfterNodes  []string
 
@@ -140,6 +141,11 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 	if extractErr != nil {
 		return nil, newGraphRunError(fmt.Errorf("graph extract option fail: %w", extractErr))
 	}
+	for key := range tm.nodeOverrides {
+		if _, ok := r.chanSubscribeTo[key]; !ok {
+			return nil, newGraphRunError(fmt.Errorf("node override designated an unknown node: %s", key))
+		}
+	}
 
 	// Extract CheckPointID
 	checkPointID, writeToCheckPointID, stateModifier, forceNewRun := getCheckPointInfo(opts...)
@@ -149,6 +155,26 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 	// Extract subgraph
 	path, isSubGraph := getNodePath(ctx)
+	logConfig := getInterruptLogConfig(opts...)
+	if logConfig != nil && !isSubGraph {
+		defer func() {
+			logConfig.logInterrupt(ctx, writeToCheckPointID, err)
+		}()
+	}
+
+	if r.options.deleteCheckPointOnSuccess && writeToCheckPointID != nil && !isSubGraph {
+		defer func() {
+			if err != nil {
+				return
+			}
+			if err = r.checkPointer.delete(ctx, *writeToCheckPointID); err != nil {
+				if sr, ok := result.(streamReader); ok {
+					sr.close()
+				}
+				result, err = nil, newGraphRunError(fmt.Errorf("delete checkpoint fail: %w", err))
+			}
+		}()
+	}
 
 	// load checkpoint from ctx/store or init graph
 	initialized := false
@@ -157,6 +183,12 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		// in subgraph, try to load checkpoint from ctx
 		initialized = true
 
+		if getCheckpointVersionCheck(ctx) {
+			if err = r.checkCheckpointVersion(cp); err != nil {
+				return nil, newGraphRunError(err)
+			}
+		}
+
 		ctx, err = r.restoreCheckPointState(ctx, *path, getStateModifier(ctx), cp, isStream, cm)
 		if err != nil {
 			return nil, err
@@ -178,8 +210,18 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 			// load checkpoint from store
 			initialized = true
 
+			if isCheckpointVersionCheck(opts...) {
+				if err = r.checkCheckpointVersion(cp); err != nil {
+					return nil, newGraphRunError(err)
+				}
+				ctx = setCheckpointVersionCheck(ctx)
+			}
+
 			ctx = setStateModifier(ctx, stateModifier)
 			ctx = setCheckPointToCtx(ctx, cp)
+			if logConfig != nil && !isSubGraph {
+				logConfig.logResume(ctx, *checkPointID, cp)
+			}
 
 			ctx, err = r.restoreCheckPointState(ctx, *NewNodePath(), stateModifier, cp, isStream, cm)
 			if err != nil {
@@ -195,10 +237,17 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 			}
 		}
 	}
+	if !initialized && !isSubGraph && isResumeOnly(opts...) {
+		return nil, newGraphRunError(fmt.Errorf("checkpoint to resume from not found"))
+	}
 	if !initialized {
 		// have not inited from checkpoint
 		if r.runCtx != nil {
-			ctx = r.runCtx(ctx)
+			stateCtx, err := r.runCtx(ctx)
+			if err != nil {
+				return nil, newGraphRunError(fmt.Errorf("init state fail: %w", err))
+			}
+			ctx = stateCtx
 		}
 
 		ctx, input = onGraphStart(ctx, input, isStream)
@@ -511,6 +560,7 @@ func (r *runner) handleInterrupt(
 		Channels:       channels,
 		Inputs:         make(map[string]any),
 		SkipPreHandler: map[string]bool{},
+		Version:        r.version,
 	}
 	if r.runCtx != nil {
 		// current graph has enable state
@@ -641,6 +691,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 		Inputs:         make(map[string]any),
 		SkipPreHandler: skipPreHandler,
 		SubGraphs:      make(map[string]*checkpoint),
+		Version:        r.version,
 	}
 	if r.runCtx != nil {
 		// current graph has enable state
@@ -940,6 +991,23 @@ func (r *runner) initTaskManager(runWrapper runnableCallWrapper, cancelVal *grap
 	if cancelVal != nil {
 		tm.cancelCh = cancelVal.ch
 	}
+	for i := range opts {
+		if opts[i].nodeHeartbeat > 0 {
+			tm.heartbeatInterval = opts[i].nodeHeartbeat
+		}
+		if opts[i].stateSnapshots {
+			tm.stateSnapshots = true
+		}
+		if opts[i].failFast {
+			tm.failFast = true
+		}
+		for key, override := range opts[i].nodeOverrides {
+			if tm.nodeOverrides == nil {
+				tm.nodeOverrides = make(map[string]NodeOverride)
+			}
+			tm.nodeOverrides[key] = override
+		}
+	}
 	return tm
 }
 
@@ -18,13 +18,17 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -2069,3 +2073,319 @@ func TestUniqueSlice(t *testing.T) {
 	assert.Equal(t, []string{"a", "b", "c"}, uniqueSlice([]string{"a", "b", "a", "c", "b"}))
 	assert.Equal(t, []string{}, uniqueSlice([]string{}))
 }
+
+func TestStartFanOut(t *testing.T) {
+	ctx := context.Background()
+	newGraph := func() *Graph[string, map[string]any] {
+		g := NewGraph[string, map[string]any]()
+		for _, key := range []string{"a", "b"} {
+			key := key
+			assert.NoError(t, g.AddLambdaNode(key, InvokableLambda(func(ctx context.Context, input string) (map[string]any, error) {
+				return map[string]any{key: key + ":" + input}, nil
+			})))
+			assert.NoError(t, g.AddEdge(START, key))
+			assert.NoError(t, g.AddEdge(key, END))
+		}
+		return g
+	}
+	expected := map[string]any{"a": "a:input", "b": "b:input"}
+
+	for _, mode := range []NodeTriggerMode{AnyPredecessor, AllPredecessor} {
+		t.Run(string(mode), func(t *testing.T) {
+			r, err := newGraph().Compile(ctx, WithNodeTriggerMode(mode))
+			assert.NoError(t, err)
+
+			out, err := r.Invoke(ctx, "input")
+			assert.NoError(t, err)
+			assert.Equal(t, expected, out)
+
+			// the input stream is copied to each START successor
+			sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]string{"in", "put"}))
+			assert.NoError(t, err)
+			out, err = concatStreamReader(sr)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, out)
+		})
+
+		t.Run(string(mode)+"_interrupt", func(t *testing.T) {
+			r, err := newGraph().Compile(ctx, WithNodeTriggerMode(mode),
+				WithCheckPointStore(newInMemoryStore()), WithInterruptBeforeNodes([]string{"b"}))
+			assert.NoError(t, err)
+
+			_, err = r.Invoke(ctx, "input", WithCheckPointID("fan_out"))
+			info, ok := ExtractInterruptInfo(err)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"b"}, info.BeforeNodes)
+
+			// b is resumed with the START input saved in the checkpoint
+			out, err := r.Invoke(ctx, "ignored", WithCheckPointID("fan_out"))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, out)
+		})
+	}
+}
+
+func TestNodeTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a node ignoring its context doesn't stall the graph", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		g := NewGraph[string, map[string]any]()
+		assert.NoError(t, g.AddLambdaNode("fast", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input, nil
+		}), WithOutputKey("fast")))
+		assert.NoError(t, g.AddLambdaNode("stuck", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			<-release
+			return input, nil
+		}), WithNodeName("stuck node"), WithOutputKey("stuck"), WithNodeTimeout(50*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "fast"))
+		assert.NoError(t, g.AddEdge(START, "stuck"))
+		assert.NoError(t, g.AddEdge("fast", END))
+		assert.NoError(t, g.AddEdge("stuck", END))
+		r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+		assert.NoError(t, err)
+
+		var mu sync.Mutex
+		var errorNodes []string
+		cb := callbacks.NewHandlerBuilder().OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, context.DeadlineExceeded) {
+				errorNodes = append(errorNodes, info.Name)
+			}
+			return ctx
+		}).Build()
+
+		start := time.Now()
+		_, err = r.Invoke(ctx, "input", WithCallbacks(cb))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "node 'stuck' timed out after 50ms")
+		assert.Less(t, time.Since(start), time.Second)
+		// the node, then the graph
+		assert.Equal(t, []string{"stuck node", ""}, errorNodes)
+	})
+
+	t.Run("a node respecting its context", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("slow", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}), WithNodeTimeout(10*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "slow"))
+		assert.NoError(t, g.AddEdge("slow", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "input")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "node 'slow' timed out after 10ms")
+	})
+
+	t.Run("a node returning shortly after the cancellation is waited for", func(t *testing.T) {
+		var returned int32
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("slow", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt32(&returned, 1)
+			return "", ctx.Err()
+		}), WithNodeTimeout(10*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "slow"))
+		assert.NoError(t, g.AddEdge("slow", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "input")
+		assert.ErrorContains(t, err, "node 'slow' timed out after 10ms")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&returned))
+	})
+
+	t.Run("the output stream of a node returning too late is closed", func(t *testing.T) {
+		release := make(chan struct{})
+		closed := make(chan struct{})
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("stuck", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+			<-release
+			sr, sw := schema.Pipe[string](0)
+			go func() {
+				defer sw.Close()
+				for !sw.Send("chunk", nil) {
+				}
+				close(closed)
+			}()
+			return sr, nil
+		}), WithNodeTimeout(10*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "stuck"))
+		assert.NoError(t, g.AddEdge("stuck", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Stream(ctx, "input")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(release)
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("the output stream of the timed-out node isn't closed")
+		}
+	})
+
+	t.Run("the output stream of a node returning in time isn't cut off", func(t *testing.T) {
+		var streamCtx context.Context
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("stream", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+			streamCtx = ctx
+			sr, sw := schema.Pipe[string](0)
+			go func() {
+				defer sw.Close()
+				for _, chunk := range []string{input, "!"} {
+					// the stream outlives the timeout, and is produced as long as the context isn't canceled
+					time.Sleep(20 * time.Millisecond)
+					if ctx.Err() != nil {
+						sw.Send("", ctx.Err())
+						return
+					}
+					sw.Send(chunk, nil)
+				}
+			}()
+			return sr, nil
+		}), WithNodeTimeout(10*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "stream"))
+		assert.NoError(t, g.AddEdge("stream", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, "input")
+		assert.NoError(t, err)
+		out, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "input!", out)
+		assert.Eventually(t, func() bool { return streamCtx.Err() != nil }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("a timed-out node is rerun on resume", func(t *testing.T) {
+		attempts := 0
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("a", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input + "a", nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("b", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			attempts++
+			if attempts == 1 {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return input + "b", nil
+		}), WithNodeTimeout(10*time.Millisecond)))
+		assert.NoError(t, g.AddEdge(START, "a"))
+		assert.NoError(t, g.AddEdge("a", "b"))
+		assert.NoError(t, g.AddEdge("b", END))
+		r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithInterruptBeforeNodes([]string{"b"}))
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "start", WithCheckPointID("1"))
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+
+		_, err = r.Invoke(ctx, "start", WithCheckPointID("1"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		result, err := r.Invoke(ctx, "start", WithCheckPointID("1"))
+		assert.NoError(t, err)
+		assert.Equal(t, "startab", result)
+		assert.Equal(t, 2, attempts)
+	})
+}
+
+func TestNodeRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a node failing twice then succeeding", func(t *testing.T) {
+		attempts := 0
+		var backoffs []int
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("flaky", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			attempts++
+			if attempts < 3 {
+				return "", fmt.Errorf("attempt %d failed", attempts)
+			}
+			return input + " done", nil
+		}), WithNodeName("flaky node"), WithNodeRetry(3, func(attempt int) time.Duration {
+			backoffs = append(backoffs, attempt)
+			return time.Millisecond
+		})))
+		assert.NoError(t, g.AddEdge(START, "flaky"))
+		assert.NoError(t, g.AddEdge("flaky", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		var starts, errs int
+		cb := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Name == "flaky node" {
+				starts++
+			}
+			return ctx
+		}).OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if info.Name == "flaky node" {
+				errs++
+			}
+			return ctx
+		}).Build()
+
+		result, err := r.Invoke(ctx, "input", WithCallbacks(cb))
+		assert.NoError(t, err)
+		assert.Equal(t, "input done", result)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []int{1, 2}, backoffs)
+		assert.Equal(t, 3, starts)
+		assert.Equal(t, 2, errs)
+
+		// the input stream is replayed to each attempt
+		attempts = 0
+		sr, err := r.Stream(ctx, "input")
+		assert.NoError(t, err)
+		result, err = concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "input done", result)
+	})
+
+	t.Run("giving up returns the last error", func(t *testing.T) {
+		attempts := 0
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("broken", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			attempts++
+			return "", fmt.Errorf("attempt %d failed", attempts)
+		}), WithNodeRetry(2, nil)))
+		assert.NoError(t, g.AddEdge(START, "broken"))
+		assert.NoError(t, g.AddEdge("broken", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "input")
+		assert.ErrorContains(t, err, "attempt 2 failed")
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("the backoff respects the cancellation of the context", func(t *testing.T) {
+		attempts := 0
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("broken", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			attempts++
+			return "", fmt.Errorf("attempt %d failed", attempts)
+		}), WithNodeRetry(3, func(int) time.Duration { return time.Hour })))
+		assert.NoError(t, g.AddEdge(START, "broken"))
+		assert.NoError(t, g.AddEdge("broken", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err = r.Invoke(cancelCtx, "input")
+		assert.ErrorContains(t, err, "attempt 1 failed")
+		assert.Equal(t, 1, attempts)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/uuid"
 
@@ -269,6 +270,96 @@ func init() {
 	schema.RegisterName[*InterruptInfo]("_eino_compose_interrupt_info")
 }
 
+// GetRerunExtra returns the extra info the rerun node of the given key interrupted with, as found in
+// InterruptInfo.RerunNodesExtra, asserted to T.
+// It returns the zero value and false if the node has no extra info, or it's not a T.
+// The extra info keeps its type through checkpoints as long as it's registered by schema.RegisterName.
+// e.g.
+//
+//	extra, ok := compose.GetRerunExtra[*MyExtra](info, "approval_node")
+func GetRerunExtra[T any](info *InterruptInfo, nodeKey string) (T, bool) {
+	var zero T
+	if info == nil {
+		return zero, false
+	}
+	extra, ok := info.RerunNodesExtra[nodeKey].(T)
+	if !ok {
+		return zero, false
+	}
+	return extra, true
+}
+
+// PendingNode describes the nodes of a single graph that are awaiting resumption.
+type PendingNode struct {
+	// Address is the address of the graph owning the nodes.
+	// For subgraphs, it is the address of the root graph followed by the keys of the subgraph nodes.
+	Address Address
+	// NodePath is the path of subgraph node keys from the root graph, empty for the root graph itself.
+	NodePath []string
+
+	BeforeNodes     []string
+	AfterNodes      []string
+	RerunNodes      []string
+	RerunNodesExtra map[string]any
+	// State is the state of the graph owning the nodes, if any.
+	State any
+}
+
+// PendingNodes flattens the interrupt info and its nested SubGraphs into a list with an entry for each graph
+// that has nodes awaiting resumption, in depth-first order, with subgraphs ordered by node key.
+func (i *InterruptInfo) PendingNodes() []PendingNode {
+	if i == nil {
+		return nil
+	}
+
+	var rootAddr Address
+	if len(i.InterruptContexts) > 0 {
+		// the top-most parent of any interrupt context is the root graph
+		ic := i.InterruptContexts[0]
+		for ic.Parent != nil {
+			ic = ic.Parent
+		}
+		rootAddr = ic.Address
+	}
+
+	var result []PendingNode
+	i.appendPendingNodes(&result, rootAddr, nil)
+	return result
+}
+
+func (i *InterruptInfo) appendPendingNodes(result *[]PendingNode, addr Address, path []string) {
+	if len(i.BeforeNodes) > 0 || len(i.AfterNodes) > 0 || len(i.RerunNodes) > 0 {
+		*result = append(*result, PendingNode{
+			Address:         addr,
+			NodePath:        path,
+			BeforeNodes:     i.BeforeNodes,
+			AfterNodes:      i.AfterNodes,
+			RerunNodes:      i.RerunNodes,
+			RerunNodesExtra: i.RerunNodesExtra,
+			State:           i.State,
+		})
+	}
+
+	keys := make([]string, 0, len(i.SubGraphs))
+	for k := range i.SubGraphs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sub := i.SubGraphs[k]
+		if sub == nil {
+			continue
+		}
+		subAddr := make(Address, len(addr), len(addr)+1)
+		copy(subAddr, addr)
+		subAddr = append(subAddr, AddressSegment{Type: AddressSegmentNode, ID: k})
+		subPath := make([]string, len(path), len(path)+1)
+		copy(subPath, path)
+		subPath = append(subPath, k)
+		sub.appendPendingNodes(result, subAddr, subPath)
+	}
+}
+
 // AddressSegmentType defines the type of a segment in an execution address.
 type AddressSegmentType = core.AddressSegmentType
 
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/cloudwego/eino/internal/core"
+)
+
+// InterruptLogger receives structured events about when a graph is interrupted and resumed,
+// to make human-in-the-loop flows easier to debug.
+// Only the root graph of a run reports events, the interrupts of its subgraphs are part of the root's event.
+type InterruptLogger interface {
+	// LogInterrupt is called when the graph run is interrupted, after its checkpoint is saved.
+	LogInterrupt(ctx context.Context, event *InterruptLogEvent)
+	// LogResume is called when the graph run is resumed from a checkpoint, before any node runs.
+	LogResume(ctx context.Context, event *ResumeLogEvent)
+}
+
+// InterruptLogEvent describes an interrupt of a graph run.
+type InterruptLogEvent struct {
+	// CheckPointID is the ID the checkpoint is saved to, empty if the run has no checkpoint ID.
+	CheckPointID string
+	// InterruptContexts are the interrupt points, with their IDs, addresses and user-facing info.
+	InterruptContexts []*InterruptCtx
+}
+
+// ResumeLogEvent describes a graph run resuming from a checkpoint.
+type ResumeLogEvent struct {
+	// CheckPointID is the ID the checkpoint is loaded from.
+	CheckPointID string
+	// Points are the interrupt points saved in the checkpoint, sorted by interrupt ID.
+	Points []*ResumePoint
+}
+
+// ResumePoint is an interrupt point of a resumed checkpoint.
+type ResumePoint struct {
+	// InterruptID is the ID of the interrupt point.
+	InterruptID string
+	// Address is the address of the interrupt point.
+	Address Address
+	// Targeted reports whether the interrupt point is explicitly resumed, by Resume, ResumeWithData or BatchResumeWithData.
+	Targeted bool
+	// HasData reports whether resume data is provided for the interrupt point.
+	HasData bool
+	// Data is the resume data, only set when InterruptLogConfig.LogResumeData is true.
+	Data any
+}
+
+// InterruptLogConfig configures the interrupt and resume logging of a graph run.
+type InterruptLogConfig struct {
+	// Logger receives the events.
+	// required
+	Logger InterruptLogger
+	// LogResumeData sets ResumePoint.Data to the resume data.
+	// Resume data often carries user input, so it's left out unless explicitly enabled.
+	// optional, false by default
+	LogResumeData bool
+}
+
+// WithInterruptLogging reports the interrupt and resume lifecycle of the graph run to config.Logger.
+// e.g.
+//
+//	out, err := graph.Invoke(ctx, input, compose.WithCheckPointID("1"),
+//		compose.WithInterruptLogging(&compose.InterruptLogConfig{Logger: compose.NewStdInterruptLogger()}))
+func WithInterruptLogging(config *InterruptLogConfig) Option {
+	return Option{
+		interruptLog: config,
+	}
+}
+
+// NewStdInterruptLogger returns an InterruptLogger printing the events with the standard log package.
+func NewStdInterruptLogger() InterruptLogger {
+	return stdInterruptLogger{}
+}
+
+type stdInterruptLogger struct{}
+
+func (stdInterruptLogger) LogInterrupt(_ context.Context, event *InterruptLogEvent) {
+	for _, ic := range event.InterruptContexts {
+		if !ic.IsRootCause {
+			continue
+		}
+		log.Printf("graph interrupted: checkpoint_id=%q interrupt_id=%q address=%q info=%v",
+			event.CheckPointID, ic.ID, ic.Address.String(), ic.Info)
+	}
+}
+
+func (stdInterruptLogger) LogResume(_ context.Context, event *ResumeLogEvent) {
+	for _, p := range event.Points {
+		if p.Data != nil {
+			log.Printf("graph resumed: checkpoint_id=%q interrupt_id=%q address=%q targeted=%t has_data=%t data=%v",
+				event.CheckPointID, p.InterruptID, p.Address.String(), p.Targeted, p.HasData, p.Data)
+			continue
+		}
+		log.Printf("graph resumed: checkpoint_id=%q interrupt_id=%q address=%q targeted=%t has_data=%t",
+			event.CheckPointID, p.InterruptID, p.Address.String(), p.Targeted, p.HasData)
+	}
+}
+
+func getInterruptLogConfig(opts ...Option) *InterruptLogConfig {
+	var config *InterruptLogConfig
+	for _, opt := range opts {
+		if opt.interruptLog != nil {
+			config = opt.interruptLog
+		}
+	}
+	if config == nil || config.Logger == nil {
+		return nil
+	}
+	return config
+}
+
+func (c *InterruptLogConfig) logInterrupt(ctx context.Context, checkPointID *string, err error) {
+	info, ok := ExtractInterruptInfo(err)
+	if !ok {
+		return
+	}
+	event := &InterruptLogEvent{InterruptContexts: info.InterruptContexts}
+	if checkPointID != nil {
+		event.CheckPointID = *checkPointID
+	}
+	c.Logger.LogInterrupt(ctx, event)
+}
+
+func (c *InterruptLogConfig) logResume(ctx context.Context, checkPointID string, cp *checkpoint) {
+	resumeData := core.GetResumeData(ctx)
+	event := &ResumeLogEvent{CheckPointID: checkPointID}
+	for id, addr := range cp.InterruptID2Addr {
+		data, targeted := resumeData[id]
+		p := &ResumePoint{
+			InterruptID: id,
+			Address:     addr,
+			Targeted:    targeted,
+			HasData:     data != nil,
+		}
+		if c.LogResumeData {
+			p.Data = data
+		}
+		event.Points = append(event.Points, p)
+	}
+	sort.Slice(event.Points, func(i, j int) bool {
+		return event.Points[i].InterruptID < event.Points[j].InterruptID
+	})
+	c.Logger.LogResume(ctx, event)
+}
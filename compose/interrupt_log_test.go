@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type captureInterruptLogger struct {
+	interrupts []*InterruptLogEvent
+	resumes    []*ResumeLogEvent
+}
+
+func (c *captureInterruptLogger) LogInterrupt(_ context.Context, event *InterruptLogEvent) {
+	c.interrupts = append(c.interrupts, event)
+}
+
+func (c *captureInterruptLogger) LogResume(_ context.Context, event *ResumeLogEvent) {
+	c.resumes = append(c.resumes, event)
+}
+
+func TestWithInterruptLogging(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("ask", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		isResume, hasData, answer := GetResumeContext[string](ctx)
+		if !isResume {
+			return "", Interrupt(ctx, "need answer")
+		}
+		if !hasData {
+			return input, nil
+		}
+		return answer, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "ask"))
+	assert.NoError(t, g.AddEdge("ask", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithGraphName("root"))
+	assert.NoError(t, err)
+
+	run := func(ctx context.Context, logResumeData bool) (*captureInterruptLogger, string, error) {
+		logger := &captureInterruptLogger{}
+		out, err := r.Invoke(ctx, "start", WithCheckPointID("cp"),
+			WithInterruptLogging(&InterruptLogConfig{Logger: logger, LogResumeData: logResumeData}))
+		return logger, out, err
+	}
+
+	logger, _, err := run(ctx, false)
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Len(t, logger.interrupts, 1)
+	assert.Empty(t, logger.resumes)
+	assert.Equal(t, "cp", logger.interrupts[0].CheckPointID)
+	assert.Equal(t, info.InterruptContexts, logger.interrupts[0].InterruptContexts)
+	interruptID := info.InterruptContexts[0].ID
+	assert.Equal(t, "need answer", logger.interrupts[0].InterruptContexts[0].Info)
+
+	// resume data is redacted by default, and a resume that interrupts again is logged as both
+	logger, _, err = run(ResumeWithData(ctx, "unknown", "x"), false)
+	assert.Error(t, err)
+	assert.Len(t, logger.resumes, 1)
+	assert.Len(t, logger.interrupts, 1)
+	// the checkpoint holds the interrupted node and the graph wrapping it
+	assert.Len(t, logger.resumes[0].Points, 2)
+	assert.Equal(t, &ResumePoint{
+		InterruptID: interruptID,
+		Address:     info.InterruptContexts[0].Address,
+	}, findResumePoint(logger.resumes[0], interruptID))
+	interruptID = logger.interrupts[0].InterruptContexts[0].ID
+
+	logger, out, err := run(ResumeWithData(ctx, interruptID, "secret"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", out)
+	assert.Empty(t, logger.interrupts)
+	assert.Len(t, logger.resumes, 1)
+	assert.Equal(t, "cp", logger.resumes[0].CheckPointID)
+	p := findResumePoint(logger.resumes[0], interruptID)
+	assert.True(t, p.Targeted)
+	assert.True(t, p.HasData)
+	assert.Nil(t, p.Data)
+
+	t.Run("log resume data", func(t *testing.T) {
+		_, _, err := run(ctx, true)
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		logger, _, err := run(ResumeWithData(ctx, info.InterruptContexts[0].ID, "secret"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", findResumePoint(logger.resumes[0], info.InterruptContexts[0].ID).Data)
+	})
+}
+
+func findResumePoint(event *ResumeLogEvent, interruptID string) *ResumePoint {
+	for _, p := range event.Points {
+		if p.InterruptID == interruptID {
+			return p
+		}
+	}
+	return nil
+}
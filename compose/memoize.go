@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/cloudwego/eino/internal/serialization"
+)
+
+// MemoCache stores the outputs of a memoized lambda, keyed by a hash of their inputs.
+// The values are the serialized outputs, as []byte, so they aren't shared with the callers.
+// Implementations must be safe for concurrent use.
+type MemoCache interface {
+	Get(ctx context.Context, key string) (value any, ok bool)
+	Set(ctx context.Context, key string, value any)
+}
+
+// NewInMemoryMemoCache returns a MemoCache backed by an in-process map without eviction.
+func NewInMemoryMemoCache() MemoCache {
+	return &inMemoryMemoCache{}
+}
+
+type inMemoryMemoCache struct {
+	m sync.Map
+}
+
+func (c *inMemoryMemoCache) Get(_ context.Context, key string) (any, bool) {
+	return c.m.Load(key)
+}
+
+func (c *inMemoryMemoCache) Set(_ context.Context, key string, value any) {
+	c.m.Store(key, value)
+}
+
+// Memoize creates an invokable Lambda that caches the outputs of the pure function i, keyed by a hash of the input.
+// Unlike checkpoints, which restore the outputs of nodes of an interrupted run, the cache is keyed on the input value,
+// so identical inputs reuse the cached output within a run as well as across runs sharing the cache.
+// If cache is nil, an in-memory cache owned by the returned Lambda is used, which lives as long as the Lambda,
+// e.g. the compiled graph it's added to. Pass a fresh cache per compiled graph to scope the reuse to it.
+//
+// The input must be JSON serializable to be hashed, and the output serializable like the state of a graph to be cached,
+// otherwise i is called without caching. Each call returns a copy of the cached output, which the caller may modify.
+// Errors are not cached, and concurrent calls with the same uncached input may each call i.
+// A cache must not be shared between different functions, as the keys only depend on the input.
+// e.g.
+//
+//	embed := compose.Memoize(func(ctx context.Context, text string) ([][]float64, error) {
+//		return embedder.EmbedStrings(ctx, []string{text})
+//	}, nil)
+func Memoize[I, O any](i InvokeWOOpt[I, O], cache MemoCache, opts ...LambdaOpt) *Lambda {
+	if cache == nil {
+		cache = NewInMemoryMemoCache()
+	}
+
+	return InvokableLambda(func(ctx context.Context, input I) (output O, err error) {
+		key, ok := memoKey(input)
+		if !ok {
+			return i(ctx, input)
+		}
+		if v, ok := cache.Get(ctx, key); ok {
+			if data, ok := v.([]byte); ok {
+				var o O
+				if err = memoSerializer.Unmarshal(data, &o); err == nil {
+					return o, nil
+				}
+			}
+		}
+
+		output, err = i(ctx, input)
+		if err != nil {
+			return output, err
+		}
+		if data, err := memoSerializer.Marshal(output); err == nil {
+			cache.Set(ctx, key, data)
+		}
+		return output, nil
+	}, opts...)
+}
+
+// memoSerializer serializes the cached outputs, so that each call gets its own copy.
+var memoSerializer = &serialization.InternalSerializer{}
+
+func memoKey(input any) (string, bool) {
+	// encoding/json sorts map keys, so equal inputs are encoded identically
+	b, err := json.Marshal(input)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("identical and distinct inputs", func(t *testing.T) {
+		var calls int32
+		upper := Memoize(func(ctx context.Context, input string) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			return strings.ToUpper(input), nil
+		}, nil)
+
+		r, err := NewChain[string, string]().AppendLambda(upper).Compile(ctx)
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			out, err := r.Invoke(ctx, "a")
+			assert.NoError(t, err)
+			assert.Equal(t, "A", out)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		out, err := r.Invoke(ctx, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "B", out)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("shared cache across graphs", func(t *testing.T) {
+		var calls int32
+		cache := NewInMemoryMemoCache()
+		newRunnable := func() Runnable[map[string]int, int] {
+			sum := Memoize(func(ctx context.Context, input map[string]int) (int, error) {
+				atomic.AddInt32(&calls, 1)
+				total := 0
+				for _, v := range input {
+					total += v
+				}
+				return total, nil
+			}, cache)
+			r, err := NewChain[map[string]int, int]().AppendLambda(sum).Compile(ctx)
+			assert.NoError(t, err)
+			return r
+		}
+
+		out, err := newRunnable().Invoke(ctx, map[string]int{"x": 1, "y": 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, out)
+		out, err = newRunnable().Invoke(ctx, map[string]int{"y": 2, "x": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, out)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		var calls int32
+		failing := Memoize(func(ctx context.Context, input string) (string, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return "", errors.New("transient")
+			}
+			return input, nil
+		}, nil)
+
+		r, err := NewChain[string, string]().AppendLambda(failing).Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "a")
+		assert.Error(t, err)
+		out, err := r.Invoke(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, "a", out)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+	t.Run("outputs are not shared", func(t *testing.T) {
+		var calls int32
+		embed := Memoize(func(ctx context.Context, input string) ([][]float64, error) {
+			atomic.AddInt32(&calls, 1)
+			return [][]float64{{1, 2}, {3}}, nil
+		}, nil)
+
+		r, err := NewChain[string, [][]float64]().AppendLambda(embed).Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "a")
+		assert.NoError(t, err)
+		out[0][0] = 100
+		out, err = r.Invoke(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 2}, {3}}, out)
+		out[1][0] = 100
+		out, err = r.Invoke(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1, 2}, {3}}, out)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// NodeOverride replaces the implementation of a node for a run, see WithNodeOverride.
+// input is the input of the node, after its pre handler, and output must be of the output type of the node.
+type NodeOverride func(ctx context.Context, input any) (output any, err error)
+
+// WithNodeOverride replaces the implementation of the node nodeKey with override for this run,
+// e.g. to stub out expensive nodes, so that tests exercise the topology and branching of a graph deterministically.
+// Unlike changing the graph itself, the override only applies to the run it's passed to, and needs no recompiling.
+// The state handlers of the node still run, but its callbacks don't.
+// When the graph is streamed, the input stream is concatenated before calling override, and its output is sent as a single chunk.
+// It applies to the nodes of the graph it's passed to, an overridden subgraph node being replaced as a whole.
+// e.g.
+//
+//	out, err := runnable.Invoke(ctx, "input", compose.WithNodeOverride("retriever", func(ctx context.Context, in any) (any, error) {
+//		return []*schema.Document{{Content: "stub"}}, nil
+//	}))
+func WithNodeOverride(nodeKey string, override NodeOverride) Option {
+	return Option{
+		nodeOverrides: map[string]NodeOverride{nodeKey: override},
+	}
+}
+
+func runNodeOverride(ctx context.Context, nodeKey string, action *composableRunnable, override NodeOverride, input any) (any, error) {
+	sr, isStream := input.(streamReader)
+	if isStream {
+		var err error
+		input, err = action.inputStreamConvertPair.concatStream(sr)
+		if err != nil {
+			return nil, fmt.Errorf("concat input stream of overridden node[%s] fail: %w", nodeKey, err)
+		}
+	}
+
+	output, err := override(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeOutput(nodeKey, action, output, isStream)
+}
+
+// toNodeOutput checks output is of the output type of the node, a nil output standing for its zero value,
+// and makes a stream of it for a streaming run.
+func toNodeOutput(nodeKey string, action *composableRunnable, output any, isStream bool) (any, error) {
+	if output != nil && action.outputType != nil && !reflect.TypeOf(output).AssignableTo(action.outputType) {
+		return nil, fmt.Errorf("output %T replacing node[%s] isn't assignable to the node's output type %s",
+			output, nodeKey, action.outputType)
+	}
+	if output == nil {
+		output = action.outputZeroValue()
+	}
+	if isStream {
+		return action.outputStreamConvertPair.restoreStream(output)
+	}
+	return output, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithNodeOverride(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		panic("expensive node must not run")
+	})))
+	assert.NoError(t, g.AddLambdaNode("3", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "3", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", "3"))
+	assert.NoError(t, g.AddEdge("3", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	var received any
+	stub := WithNodeOverride("2", func(ctx context.Context, in any) (any, error) {
+		received = in
+		return "stub", nil
+	})
+
+	out, err := r.Invoke(ctx, "start", stub)
+	assert.NoError(t, err)
+	assert.Equal(t, "stub3", out)
+	assert.Equal(t, "start1", received)
+
+	sr, err := r.Stream(ctx, "start", stub)
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "stub3", out)
+
+	_, err = r.Invoke(ctx, "start", WithNodeOverride("2", func(ctx context.Context, in any) (any, error) {
+		return 1, nil
+	}))
+	assert.ErrorContains(t, err, "isn't assignable to the node's output type")
+
+	_, err = r.Invoke(ctx, "start", WithNodeOverride("unknown", func(ctx context.Context, in any) (any, error) {
+		return "", nil
+	}))
+	assert.ErrorContains(t, err, "node override designated an unknown node: unknown")
+}
@@ -16,7 +16,10 @@
 
 package compose
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func pregelChannelBuilder(_ []string, _ []string, _ func() any, _ func() streamReader) channel {
 	return &pregelChannel{Values: make(map[string]any)}
@@ -52,7 +55,7 @@ func (ch *pregelChannel) reportValues(ins map[string]any) error {
 	return nil
 }
 
-func (ch *pregelChannel) get(isStream bool, name string, edgeHandler *edgeHandlerManager) (
+func (ch *pregelChannel) get(ctx context.Context, isStream bool, name string, edgeHandler *edgeHandlerManager) (
 	any, bool, error) {
 	if len(ch.Values) == 0 {
 		return nil, false, nil
@@ -62,7 +65,7 @@ func (ch *pregelChannel) get(isStream bool, name string, edgeHandler *edgeHandle
 	names := make([]string, len(ch.Values))
 	i := 0
 	for k, v := range ch.Values {
-		resolvedV, err := edgeHandler.handle(k, name, v, isStream)
+		resolvedV, err := edgeHandler.handle(ctx, k, name, v, isStream)
 		if err != nil {
 			return nil, false, err
 		}
@@ -116,10 +116,82 @@ func ResumeWithData(ctx context.Context, interruptID string, data any) context.C
 //
 // This function is the foundation for the "Explicit Targeted Resume" strategy. Components whose interrupt IDs
 // are present as keys in the map will receive `isResumeFlow = true` when they call `GetResumeContext`.
+//
+// It resumes all the interrupt contexts of a run at once, e.g. those of parallel nodes interrupted together.
+// The interrupt points left out of the map interrupt again, keeping their interrupt IDs, so they can be resumed
+// by a later run with the same IDs, whereas a resumed point interrupting again gets a new interrupt ID.
+// e.g.
+//
+//	ctx = compose.BatchResumeWithData(ctx, map[string]any{approvalID1: "approved", approvalID2: "rejected"})
 func BatchResumeWithData(ctx context.Context, resumeData map[string]any) context.Context {
 	return core.BatchResumeWithData(ctx, resumeData)
 }
 
+type subGraphAbortsKey struct{}
+
+// AbortSubGraph prepares a context to resume an interrupted run without the interrupted subgraph node at addr,
+// whose work is no longer needed: instead of resuming the subgraph, the node outputs output to its successors,
+// and the run carries on with the rest of the graph. The checkpoint of the subgraph is dropped along with its
+// pending interrupts, while the other interrupted components are resumed or interrupt again as usual.
+// addr is the address of the subgraph node, e.g. the Address of the Parent of an interrupt context raised in the
+// subgraph, and may point to a subgraph nested in other subgraphs. output must be assignable to the output type of
+// the node, a nil output standing for its zero value.
+// e.g.
+//
+//	info, _ := compose.ExtractInterruptInfo(err)
+//	ctx = compose.AbortSubGraph(ctx, info.InterruptContexts[0].Parent.Address, "skipped")
+//	out, err := runnable.Invoke(ctx, input, compose.WithCheckPointID("1"))
+func AbortSubGraph(ctx context.Context, addr Address, output any) context.Context {
+	old, _ := ctx.Value(subGraphAbortsKey{}).(map[string]any)
+	aborts := make(map[string]any, len(old)+1)
+	for k, v := range old {
+		aborts[k] = v
+	}
+	aborts[addr.String()] = output
+	return context.WithValue(ctx, subGraphAbortsKey{}, aborts)
+}
+
+// getSubGraphAbort returns the output replacing the node at the current address if it's aborted by AbortSubGraph.
+func getSubGraphAbort(ctx context.Context) (output any, aborted bool) {
+	aborts, _ := ctx.Value(subGraphAbortsKey{}).(map[string]any)
+	if len(aborts) == 0 {
+		return nil, false
+	}
+	output, aborted = aborts[GetCurrentAddress(ctx).String()]
+	return output, aborted
+}
+
+// ResumeFromCheckPoint resumes the run of r which was interrupted and saved under checkPointID,
+// without the caller having to supply the input of the interrupted run again: the checkpoint holds everything
+// needed to continue, so r is invoked with the zero value of I, which is also what the graph's callbacks receive as input.
+// resumeData maps the interrupt IDs of the components to resume to their resume data, as in BatchResumeWithData,
+// and may be empty.
+// An error is returned if no checkpoint is found for checkPointID, instead of starting a new run.
+// e.g.
+//
+//	_, err := r.Invoke(ctx, input, compose.WithCheckPointID("1"))
+//	info, _ := compose.ExtractInterruptInfo(err)
+//	// later, maybe in another process
+//	out, err := compose.ResumeFromCheckPoint(ctx, r, "1", map[string]any{info.InterruptContexts[0].ID: data})
+func ResumeFromCheckPoint[I, O any](ctx context.Context, r Runnable[I, O], checkPointID string,
+	resumeData map[string]any, opts ...Option) (O, error) {
+
+	if len(resumeData) > 0 {
+		ctx = BatchResumeWithData(ctx, resumeData)
+	}
+	var input I
+	return r.Invoke(ctx, input, append(opts, WithCheckPointID(checkPointID), Option{resumeOnly: true})...)
+}
+
+func isResumeOnly(opts ...Option) bool {
+	for _, opt := range opts {
+		if opt.resumeOnly {
+			return true
+		}
+	}
+	return false
+}
+
 func getNodePath(ctx context.Context) (*NodePath, bool) {
 	currentAddress := GetCurrentAddress(ctx)
 	if len(currentAddress) == 0 {
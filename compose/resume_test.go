@@ -1206,3 +1206,197 @@ func TestToolCompositeInterruptWithNestedGraphInterrupt(t *testing.T) {
 	assert.Len(t, wrapperTool.isResumeTargetLog, 2)
 	assert.True(t, wrapperTool.isResumeTargetLog[1], "second invocation: wrapper tool should be resume target because its child is targeted")
 }
+
+func TestResumeFromCheckPoint(t *testing.T) {
+	ctx := context.Background()
+	store := newInMemoryStore()
+
+	g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) (state *testStruct) {
+		return &testStruct{A: ""}
+	}))
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (output string, err error) {
+		return input + "2", nil
+	}), WithStatePreHandler(func(ctx context.Context, in string, state *testStruct) (string, error) {
+		return in + state.A, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(store), WithInterruptBeforeNodes([]string{"2"}), WithGraphName("root"))
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "start", WithCheckPointID("1"))
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+
+	result, err := ResumeFromCheckPoint(ctx, r, "1", map[string]any{info.InterruptContexts[0].ID: &testStruct{A: "state"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "start1state2", result)
+
+	_, err = ResumeFromCheckPoint(ctx, r, "unknown", nil)
+	assert.ErrorContains(t, err, "checkpoint to resume from not found")
+}
+
+func TestBatchResumeParallelInterrupts(t *testing.T) {
+	ctx := context.Background()
+	approval := func(name string) *Lambda {
+		return InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			isResume, hasData, data := GetResumeContext[string](ctx)
+			if !isResume {
+				return "", Interrupt(ctx, name+" needs approval")
+			}
+			assert.True(t, hasData)
+			return name + ":" + data, nil
+		})
+	}
+
+	g := NewGraph[string, map[string]any]()
+	assert.NoError(t, g.AddLambdaNode("a", approval("a"), WithOutputKey("a")))
+	assert.NoError(t, g.AddLambdaNode("b", approval("b"), WithOutputKey("b")))
+	assert.NoError(t, g.AddEdge(START, "a"))
+	assert.NoError(t, g.AddEdge(START, "b"))
+	assert.NoError(t, g.AddEdge("a", END))
+	assert.NoError(t, g.AddEdge("b", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithGraphName("root"))
+	assert.NoError(t, err)
+
+	interruptIDs := func(err error) map[string]string {
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		ids := make(map[string]string)
+		for _, iCtx := range info.InterruptContexts {
+			ids[iCtx.Info.(string)] = iCtx.ID
+		}
+		return ids
+	}
+
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("1"))
+	ids := interruptIDs(err)
+	assert.Len(t, ids, 2)
+
+	// answering only a, b interrupts again with its context intact
+	_, err = r.Invoke(BatchResumeWithData(ctx, map[string]any{ids["a needs approval"]: "yes"}), "input", WithCheckPointID("1"))
+	remaining := interruptIDs(err)
+	assert.Equal(t, map[string]string{"b needs approval": ids["b needs approval"]}, remaining)
+
+	result, err := r.Invoke(BatchResumeWithData(ctx, map[string]any{ids["b needs approval"]: "ok"}), "input", WithCheckPointID("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": "a:yes", "b": "b:ok"}, result)
+
+	// both at once in a single Invoke
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("2"))
+	ids = interruptIDs(err)
+	result, err = r.Invoke(BatchResumeWithData(ctx, map[string]any{
+		ids["a needs approval"]: "yes",
+		ids["b needs approval"]: "ok",
+	}), "input", WithCheckPointID("2"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": "a:yes", "b": "b:ok"}, result)
+}
+
+func TestAbortSubGraph(t *testing.T) {
+	ctx := context.Background()
+
+	var innerRuns int
+	inner := NewGraph[string, string]()
+	assert.NoError(t, inner.AddLambdaNode("approve", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		innerRuns++
+		if wasInterrupted, _, _ := GetInterruptState[any](ctx); !wasInterrupted {
+			return "", Interrupt(ctx, "needs approval")
+		}
+		return input + " approved", nil
+	})))
+	assert.NoError(t, inner.AddEdge(START, "approve"))
+	assert.NoError(t, inner.AddEdge("approve", END))
+
+	middle := NewGraph[string, string]()
+	assert.NoError(t, middle.AddGraphNode("inner", inner))
+	assert.NoError(t, middle.AddLambdaNode("after_inner", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + " then middle", nil
+	})))
+	assert.NoError(t, middle.AddEdge(START, "inner"))
+	assert.NoError(t, middle.AddEdge("inner", "after_inner"))
+	assert.NoError(t, middle.AddEdge("after_inner", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddGraphNode("middle", middle))
+	assert.NoError(t, g.AddLambdaNode("after_middle", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + " then root", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "middle"))
+	assert.NoError(t, g.AddEdge("middle", "after_middle"))
+	assert.NoError(t, g.AddEdge("after_middle", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithGraphName("root"))
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("1"))
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Len(t, info.InterruptContexts, 1)
+	innerAddr := info.InterruptContexts[0].Parent.Address
+	assert.Equal(t, "runnable:root;node:middle;node:inner", innerAddr.String())
+
+	// the inner subgraph is dropped, the middle one and the root graph carry on with the default output
+	result, err := r.Invoke(AbortSubGraph(ctx, innerAddr, "default"), "input", WithCheckPointID("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "default then middle then root", result)
+	assert.Equal(t, 1, innerRuns)
+
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("2"))
+	_, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	sr, err := r.Stream(AbortSubGraph(ctx, innerAddr, nil), "input", WithCheckPointID("2"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	result, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, " then middle then root", result)
+	assert.Equal(t, 2, innerRuns)
+}
+
+func TestReinterruptAfterResume(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("approval", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		isResume, _, data := GetResumeContext[string](ctx)
+		if !isResume {
+			return "", Interrupt(ctx, "first approval")
+		}
+		if data != "final" {
+			// resumed, the point asks for another approval
+			return "", Interrupt(ctx, "second approval")
+		}
+		return data, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "approval"))
+	assert.NoError(t, g.AddEdge("approval", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithGraphName("root"))
+	assert.NoError(t, err)
+
+	interruptID := func(err error) string {
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Len(t, info.InterruptContexts, 1)
+		return info.InterruptContexts[0].ID
+	}
+
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("1"))
+	firstID := interruptID(err)
+
+	// the resumed point interrupting again raises a new interrupt
+	_, err = r.Invoke(ResumeWithData(ctx, firstID, "yes"), "input", WithCheckPointID("1"))
+	secondID := interruptID(err)
+	assert.NotEqual(t, firstID, secondID)
+
+	// resuming without answering it keeps its ID
+	_, err = r.Invoke(ctx, "input", WithCheckPointID("1"))
+	assert.Equal(t, secondID, interruptID(err))
+
+	result, err := r.Invoke(ResumeWithData(ctx, secondID, "final"), "input", WithCheckPointID("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "final", result)
+}
@@ -36,6 +36,25 @@ type Runnable[I, O any] interface {
 	Transform(ctx context.Context, input *schema.StreamReader[I], opts ...Option) (output *schema.StreamReader[O], err error)
 }
 
+// IOTyper reports the declared input and output types of a Runnable, e.g. for tooling to decode requests for arbitrary compiled graphs.
+// The Runnables compiled by Graph, Chain and Workflow implement it.
+// It's apart from Runnable, so that the implementations of Runnable outside this package don't have to implement it.
+type IOTyper interface {
+	// InputType returns the declared input type I.
+	InputType() reflect.Type
+	// OutputType returns the declared output type O.
+	OutputType() reflect.Type
+}
+
+// GetIOTypes returns the declared input and output types of a Runnable that implements IOTyper.
+func GetIOTypes(runnable any) (input, output reflect.Type, ok bool) {
+	if typer, ok := runnable.(IOTyper); ok {
+		return typer.InputType(), typer.OutputType(), true
+	}
+
+	return nil, nil, false
+}
+
 type invoke func(ctx context.Context, input any, opts ...any) (output any, err error)
 type transform func(ctx context.Context, input streamReader, opts ...any) (output streamReader, err error)
 
@@ -179,6 +198,16 @@ func (rp *runnablePacker[I, O, TOption]) Transform(ctx context.Context,
 	return rp.t(ctx, input, opts...)
 }
 
+// InputType returns the type I.
+func (rp *runnablePacker[I, O, TOption]) InputType() reflect.Type {
+	return generic.TypeOf[I]()
+}
+
+// OutputType returns the type O.
+func (rp *runnablePacker[I, O, TOption]) OutputType() reflect.Type {
+	return generic.TypeOf[O]()
+}
+
 func defaultImplConcatStreamReader[T any](
 	sr *schema.StreamReader[T]) (T, error) {
 
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -208,3 +209,56 @@ func TestRunnableLambda(t *testing.T) {
 		assert.Equal(t, "10+100", out)
 	})
 }
+
+func TestRunnableTypes(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[string, []*schema.Message]()
+	assert.NoError(t, g.AddLambdaNode("to_messages", InvokableLambda(func(ctx context.Context, in string) ([]*schema.Message, error) {
+		return []*schema.Message{schema.UserMessage(in)}, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "to_messages"))
+	assert.NoError(t, g.AddEdge("to_messages", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	in, out, ok := GetIOTypes(r)
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(""), in)
+	assert.Equal(t, reflect.TypeOf([]*schema.Message{}), out)
+
+	passthrough := NewGraph[[]*schema.Message, []*schema.Message]()
+	assert.NoError(t, passthrough.AddPassthroughNode("passthrough"))
+	assert.NoError(t, passthrough.AddEdge(START, "passthrough"))
+	assert.NoError(t, passthrough.AddEdge("passthrough", END))
+	pr, err := passthrough.Compile(ctx)
+	assert.NoError(t, err)
+	seq, err := Sequence(ctx, r, pr)
+	assert.NoError(t, err)
+	in, out, ok = GetIOTypes(seq)
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(""), in)
+	assert.Equal(t, reflect.TypeOf([]*schema.Message{}), out)
+
+	// a Runnable implemented outside this package needn't report its types
+	_, _, ok = GetIOTypes(Runnable[string, string](&externalRunnable{}))
+	assert.False(t, ok)
+}
+
+type externalRunnable struct{}
+
+func (e *externalRunnable) Invoke(ctx context.Context, input string, opts ...Option) (string, error) {
+	return input, nil
+}
+
+func (e *externalRunnable) Stream(ctx context.Context, input string, opts ...Option) (*schema.StreamReader[string], error) {
+	return schema.StreamReaderFromArray([]string{input}), nil
+}
+
+func (e *externalRunnable) Collect(ctx context.Context, input *schema.StreamReader[string], opts ...Option) (string, error) {
+	return concatStreamReader(input)
+}
+
+func (e *externalRunnable) Transform(ctx context.Context, input *schema.StreamReader[string], opts ...Option) (*schema.StreamReader[string], error) {
+	return input, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/cloudwego/eino/internal/generic"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	sequenceFirstNode  = "first"
+	sequenceSecondNode = "second"
+)
+
+// Sequence composes two independently compiled Runnables into one, which runs first and feeds its output to second,
+// without rebuilding them into a single graph.
+// opts are used to compile the graph running the two Runnables, e.g. WithCheckPointStore to make the sequence resumable.
+//
+// An interrupt in either Runnable interrupts the sequence, and is resumed by running the sequence again with
+// the same checkpoint ID, just like a graph. A Runnable that already completed isn't run again on resume.
+// When the sequence runs with a checkpoint ID, first and second run with the checkpoint IDs "<id>:first" and
+// "<id>:second", so they must be compiled with a checkpoint store to be interruptible themselves.
+// Other call options apply to the graph running the sequence, not to first and second.
+// e.g.
+//
+//	seq, err := compose.Sequence(ctx, retrieveGraph, answerGraph, compose.WithCheckPointStore(store))
+//	out, err := seq.Invoke(ctx, query, compose.WithCheckPointID("session_1"))
+func Sequence[I, M, O any](ctx context.Context, first Runnable[I, M], second Runnable[M, O],
+	opts ...GraphCompileOption) (Runnable[I, O], error) {
+
+	g := NewGraph[I, O]()
+	if err := g.AddLambdaNode(sequenceFirstNode, sequenceStage(sequenceFirstNode, first)); err != nil {
+		return nil, err
+	}
+	if err := g.AddLambdaNode(sequenceSecondNode, sequenceStage(sequenceSecondNode, second)); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(START, sequenceFirstNode); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(sequenceFirstNode, sequenceSecondNode); err != nil {
+		return nil, err
+	}
+	if err := g.AddEdge(sequenceSecondNode, END); err != nil {
+		return nil, err
+	}
+
+	r, err := g.Compile(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &sequence[I, O]{r: r}, nil
+}
+
+type sequenceCheckPointIDKey struct{}
+
+// sequence passes the checkpoint ID of the run down to its stages.
+type sequence[I, O any] struct {
+	r Runnable[I, O]
+}
+
+func (s *sequence[I, O]) Invoke(ctx context.Context, input I, opts ...Option) (O, error) {
+	return s.r.Invoke(withSequenceCheckPointID(ctx, opts), input, opts...)
+}
+
+func (s *sequence[I, O]) Stream(ctx context.Context, input I, opts ...Option) (*schema.StreamReader[O], error) {
+	return s.r.Stream(withSequenceCheckPointID(ctx, opts), input, opts...)
+}
+
+func (s *sequence[I, O]) Collect(ctx context.Context, input *schema.StreamReader[I], opts ...Option) (O, error) {
+	return s.r.Collect(withSequenceCheckPointID(ctx, opts), input, opts...)
+}
+
+func (s *sequence[I, O]) Transform(ctx context.Context, input *schema.StreamReader[I], opts ...Option) (
+	*schema.StreamReader[O], error) {
+
+	return s.r.Transform(withSequenceCheckPointID(ctx, opts), input, opts...)
+}
+
+func (s *sequence[I, O]) InputType() reflect.Type {
+	return generic.TypeOf[I]()
+}
+
+func (s *sequence[I, O]) OutputType() reflect.Type {
+	return generic.TypeOf[O]()
+}
+
+func withSequenceCheckPointID(ctx context.Context, opts []Option) context.Context {
+	checkPointID, _, _, _ := getCheckPointInfo(opts...)
+	if checkPointID == nil {
+		// don't let the stages of an outer sequence leak into this one
+		return context.WithValue(ctx, sequenceCheckPointIDKey{}, "")
+	}
+	return context.WithValue(ctx, sequenceCheckPointIDKey{}, *checkPointID)
+}
+
+func sequenceStage[I, O any](name string, r Runnable[I, O]) *Lambda {
+	stageOpts := func(ctx context.Context) []Option {
+		if id, _ := ctx.Value(sequenceCheckPointIDKey{}).(string); id != "" {
+			return []Option{WithCheckPointID(id + ":" + name)}
+		}
+		return nil
+	}
+
+	return anyLambda[I, O, struct{}](
+		func(ctx context.Context, input I, _ ...struct{}) (O, error) {
+			out, err := r.Invoke(ctx, input, stageOpts(ctx)...)
+			return out, sequenceStageError(ctx, err)
+		},
+		func(ctx context.Context, input I, _ ...struct{}) (*schema.StreamReader[O], error) {
+			out, err := r.Stream(ctx, input, stageOpts(ctx)...)
+			return out, sequenceStageError(ctx, err)
+		},
+		func(ctx context.Context, input *schema.StreamReader[I], _ ...struct{}) (O, error) {
+			out, err := r.Collect(ctx, input, stageOpts(ctx)...)
+			return out, sequenceStageError(ctx, err)
+		},
+		func(ctx context.Context, input *schema.StreamReader[I], _ ...struct{}) (*schema.StreamReader[O], error) {
+			out, err := r.Transform(ctx, input, stageOpts(ctx)...)
+			return out, sequenceStageError(ctx, err)
+		},
+	)
+}
+
+// sequenceStageError turns the interrupt of a stage into an interrupt of its node, so that it's checkpointed.
+func sequenceStageError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := ExtractInterruptInfo(err); ok {
+		return CompositeInterrupt(ctx, nil, nil, err)
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestSequence(t *testing.T) {
+	ctx := context.Background()
+
+	firstRuns := 0
+	g1 := NewGraph[string, string]()
+	assert.NoError(t, g1.AddLambdaNode("upper", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		firstRuns++
+		return strings.ToUpper(input), nil
+	})))
+	assert.NoError(t, g1.AddEdge(START, "upper"))
+	assert.NoError(t, g1.AddEdge("upper", END))
+	first, err := g1.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	g2 := NewGraph[string, int]()
+	assert.NoError(t, g2.AddLambdaNode("approve", InvokableLambda(func(ctx context.Context, input string) (int, error) {
+		// an interrupted node reruns with a zero input, so it keeps its input in the interrupt state
+		wasInterrupted, _, savedInput := GetInterruptState[string](ctx)
+		if !wasInterrupted && strings.HasPrefix(input, "ASK") {
+			return 0, StatefulInterrupt(ctx, "need approval", input)
+		}
+		if wasInterrupted {
+			input = savedInput
+		}
+		isResume, hasData, bonus := GetResumeContext[int](ctx)
+		if isResume && hasData {
+			return len(input) + bonus, nil
+		}
+		return len(input), nil
+	})))
+	assert.NoError(t, g2.AddEdge(START, "approve"))
+	assert.NoError(t, g2.AddEdge("approve", END))
+	second, err := g2.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	seq, err := Sequence(ctx, first, second, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	t.Run("invoke and stream", func(t *testing.T) {
+		out, err := seq.Invoke(ctx, "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, out)
+
+		sr, err := seq.Stream(ctx, "hi")
+		assert.NoError(t, err)
+		out, err = sr.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, out)
+		_, err = sr.Recv()
+		assert.ErrorIs(t, err, io.EOF)
+
+		out, err = seq.Collect(ctx, schema.StreamReaderFromArray([]string{"a", "bc"}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, out)
+	})
+
+	t.Run("interrupt in second resumes without rerunning first", func(t *testing.T) {
+		firstRuns = 0
+		_, err := seq.Invoke(ctx, "ask", WithCheckPointID("seq_1"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Len(t, info.InterruptContexts, 1)
+		assert.Equal(t, "need approval", info.InterruptContexts[0].Info)
+		assert.Equal(t, 1, firstRuns)
+
+		resumeCtx := ResumeWithData(ctx, info.InterruptContexts[0].ID, 10)
+		out, err := seq.Invoke(resumeCtx, "ignored on resume", WithCheckPointID("seq_1"))
+		assert.NoError(t, err)
+		assert.Equal(t, 13, out)
+		assert.Equal(t, 1, firstRuns)
+	})
+
+	t.Run("nested sequence", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddPassthroughNode("identity"))
+		assert.NoError(t, g.AddEdge(START, "identity"))
+		assert.NoError(t, g.AddEdge("identity", END))
+		identity, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+		assert.NoError(t, err)
+
+		upper, err := Sequence(ctx, first, identity, WithCheckPointStore(newInMemoryStore()))
+		assert.NoError(t, err)
+		nested, err := Sequence(ctx, upper, second, WithCheckPointStore(newInMemoryStore()))
+		assert.NoError(t, err)
+
+		_, err = nested.Invoke(ctx, "ask me", WithCheckPointID("seq_2"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		out, err := nested.Invoke(Resume(ctx, info.InterruptContexts[0].ID), "", WithCheckPointID("seq_2"))
+		assert.NoError(t, err)
+		assert.Equal(t, 6, out)
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/cloudwego/eino/callbacks"
+	icb "github.com/cloudwego/eino/internal/callbacks"
+)
+
+// snapshotState returns the JSON representation of the nearest graph state in ctx,
+// or nil if there is no state or it can't be encoded.
+func snapshotState(ctx context.Context) any {
+	s, ok := ctx.Value(stateKey{}).(*internalState)
+	if !ok || s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	b, err := json.Marshal(s.state)
+	s.mu.Unlock()
+	if err != nil {
+		return nil
+	}
+
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func (t *taskManager) emitStateSnapshot(ta *task) {
+	if !t.stateSnapshots {
+		return
+	}
+	after := snapshotState(ta.ctx)
+	if ta.stateBefore == nil && after == nil {
+		return
+	}
+
+	ctx := initNodeCallbacks(ta.ctx, ta.nodeKey, ta.call.action.nodeInfo, ta.call.action.meta, t.opts...)
+	icb.OnStateSnapshot(ctx, &callbacks.StateSnapshot{
+		Before:  ta.stateBefore,
+		After:   after,
+		Changes: diffState("", ta.stateBefore, after, nil),
+	})
+	ta.stateBefore = nil
+}
+
+// diffState appends the changes from before to after to changes, descending into JSON objects
+// so that a change is reported at the path of the innermost field that differs.
+func diffState(path string, before, after any, changes []callbacks.StateChange) []callbacks.StateChange {
+	bm, ok1 := before.(map[string]any)
+	am, ok2 := after.(map[string]any)
+	if !ok1 || !ok2 {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, callbacks.StateChange{Path: path, Before: before, After: after})
+		}
+		return changes
+	}
+
+	keys := make([]string, 0, len(bm)+len(am))
+	for k := range bm {
+		keys = append(keys, k)
+	}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		changes = diffState(p, bm[k], am[k], changes)
+	}
+	return changes
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+)
+
+func TestWithStateSnapshots(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) *testStruct {
+		return &testStruct{}
+	}))
+	assert.NoError(t, g.AddLambdaNode("node1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "_1", nil
+	}), WithStatePreHandler(func(ctx context.Context, in string, state *testStruct) (string, error) {
+		state.A += in
+		return in, nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("node2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "_2", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "node1"))
+	assert.NoError(t, g.AddEdge("node1", "node2"))
+	assert.NoError(t, g.AddEdge("node2", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	snapshots := func(opts ...Option) map[string]*callbacks.StateSnapshot {
+		sink := &sliceTraceSink{}
+		out, err := r.Invoke(ctx, "in", append(opts, WithTraceSink(sink))...)
+		assert.NoError(t, err)
+		assert.Equal(t, "in_1_2", out)
+
+		ret := make(map[string]*callbacks.StateSnapshot)
+		for _, e := range sink.events {
+			if e.Type == TraceEventStateSnapshot {
+				ret[e.Address.String()] = e.StateSnapshot
+			}
+		}
+		return ret
+	}
+
+	assert.Empty(t, snapshots())
+
+	ss := snapshots(WithStateSnapshots())
+	assert.Len(t, ss, 2)
+	assert.Equal(t, &callbacks.StateSnapshot{
+		Before:  map[string]any{"A": ""},
+		After:   map[string]any{"A": "in"},
+		Changes: []callbacks.StateChange{{Path: "A", Before: "", After: "in"}},
+	}, ss["runnable:;node:node1"])
+	assert.Equal(t, &callbacks.StateSnapshot{
+		Before: map[string]any{"A": "in"},
+		After:  map[string]any{"A": "in"},
+	}, ss["runnable:;node:node2"])
+}
+
+func TestDiffState(t *testing.T) {
+	before := map[string]any{"A": "a", "Inner": map[string]any{"B": 1.0, "C": "c"}, "D": []any{"x"}}
+	after := map[string]any{"A": "a", "Inner": map[string]any{"B": 2.0, "C": "c"}, "D": []any{"x", "y"}, "E": true}
+	assert.Equal(t, []callbacks.StateChange{
+		{Path: "D", Before: []any{"x"}, After: []any{"x", "y"}},
+		{Path: "E", Before: nil, After: true},
+		{Path: "Inner.B", Before: 1.0, After: 2.0},
+	}, diffState("", before, after, nil))
+}
@@ -266,6 +266,56 @@ func TestStateChain(t *testing.T) {
 	}
 }
 
+func TestStateInitHook(t *testing.T) {
+	ctx := context.Background()
+	store := map[string]string{"A": "persisted"}
+	genState := WithGenLocalState(func(ctx context.Context) *testStruct {
+		return &testStruct{}
+	})
+
+	newGraph := func(hook NewGraphOption) *Graph[string, string] {
+		g := NewGraph[string, string](genState, hook)
+		assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input, nil
+		}), WithStatePreHandler(func(ctx context.Context, in string, state *testStruct) (string, error) {
+			return in + ":" + state.A, nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", "2"))
+		assert.NoError(t, g.AddEdge("2", END))
+		return g
+	}
+
+	r, err := newGraph(WithStateInitHook(func(ctx context.Context, state *testStruct) error {
+		state.A = store["A"]
+		return nil
+	})).Compile(ctx)
+	assert.NoError(t, err)
+	out, err := r.Invoke(ctx, "start")
+	assert.NoError(t, err)
+	assert.Equal(t, "start:persisted", out)
+
+	r, err = newGraph(WithStateInitHook(func(ctx context.Context, state *testStruct) error {
+		return fmt.Errorf("store unavailable")
+	})).Compile(ctx)
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, "start")
+	assert.ErrorContains(t, err, "store unavailable")
+
+	_, err = NewGraph[string, string](genState, WithStateInitHook(func(ctx context.Context, state *midStr) error {
+		return nil
+	})).Compile(ctx)
+	assert.ErrorContains(t, err, "state init hook's state type")
+
+	_, err = NewGraph[string, string](WithStateInitHook(func(ctx context.Context, state *testStruct) error {
+		return nil
+	})).Compile(ctx)
+	assert.ErrorContains(t, err, "graph state, which is not enabled")
+}
+
 func TestStreamState(t *testing.T) {
 	type testState struct {
 		Field1 string
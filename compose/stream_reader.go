@@ -17,6 +17,7 @@
 package compose
 
 import (
+	"io"
 	"reflect"
 
 	"github.com/cloudwego/eino/internal/generic"
@@ -32,6 +33,7 @@ type streamReader interface {
 	close()
 	toAnyStreamReader() *schema.StreamReader[any]
 	mergeWithNames([]streamReader, []string) streamReader
+	withFinish(func()) streamReader
 }
 
 type streamReaderPacker[T any] struct {
@@ -102,6 +104,28 @@ func (srp streamReaderPacker[T]) withKey(key string) streamReader {
 	return packStreamReader(ret)
 }
 
+// withFinish returns a stream forwarding the chunks of srp, calling fn once srp is read to the end or the returned stream is closed.
+func (srp streamReaderPacker[T]) withFinish(fn func()) streamReader {
+	sr, sw := schema.Pipe[T](0)
+	go func() {
+		defer func() {
+			srp.sr.Close()
+			sw.Close()
+			fn()
+		}()
+		for {
+			chunk, err := srp.sr.Recv()
+			if err == io.EOF {
+				return
+			}
+			if closed := sw.Send(chunk, err); closed {
+				return
+			}
+		}
+	}()
+	return packStreamReader(sr)
+}
+
 func (srp streamReaderPacker[T]) toAnyStreamReader() *schema.StreamReader[any] {
 	return schema.StreamReaderWithConvert(srp.sr, func(t T) (any, error) {
 		return t, nil
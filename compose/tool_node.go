@@ -64,7 +64,9 @@ type ToolsNode struct {
 	tuple                     *toolsTuple
 	unknownToolHandler        func(ctx context.Context, name, input string) (string, error)
 	executeSequentially       bool
+	maxParallelToolCalls      int
 	toolArgumentsHandler      func(ctx context.Context, name, input string) (string, error)
+	toolAliases               map[string]string
 	toolCallMiddlewares       []InvokableToolMiddleware
 	streamToolCallMiddlewares []StreamableToolMiddleware
 }
@@ -76,6 +78,7 @@ type ToolInput struct {
 	// Arguments contains the arguments for the tool call.
 	Arguments string
 	// CallID is the unique identifier for this tool call.
+	// Every tool middleware sees the same CallID, which also equals GetToolCallID(ctx), even if an outer middleware modifies it.
 	CallID string
 	// CallOptions contains tool options for the execution.
 	CallOptions []tool.Option
@@ -141,6 +144,12 @@ type ToolsNodeConfig struct {
 	// When set to false (default), tool calls will be executed in parallel.
 	ExecuteSequentially bool
 
+	// MaxParallelToolCalls limits how many tool calls of one input message are executed in parallel.
+	// When set to a positive number, the tool calls are executed in batches of at most this size, one batch after another.
+	// The order of the output messages always follows the order of the tool calls in the input message.
+	// This field is optional and ignored when ExecuteSequentially is true. When 0 (default), there is no limit.
+	MaxParallelToolCalls int
+
 	// ToolArgumentsHandler allows handling of tool arguments before execution.
 	// When provided, this function will be called for each tool call to process the arguments.
 	// Parameters:
@@ -152,6 +161,13 @@ type ToolsNodeConfig struct {
 	//   - error: Any error that occurred during preprocessing
 	ToolArgumentsHandler func(ctx context.Context, name, arguments string) (string, error)
 
+	// ToolAliases maps alternative tool names to the names of the tools in Tools,
+	// so that a tool call with a slightly wrong name the LLM is known to use still reaches the intended tool,
+	// e.g. {"list_files": "ls"}. The tool call is then executed as if it were made with the canonical name.
+	// An alias is only resolved when no tool has the called name, and takes effect before UnknownToolsHandler.
+	// This field is optional.
+	ToolAliases map[string]string
+
 	// ToolCallMiddlewares configures middleware for tool calls.
 	// Each element can contain Invokable and/or Streamable middleware.
 	// Invokable middleware only applies to tools implementing InvokableTool interface.
@@ -183,11 +199,22 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 		return nil, err
 	}
 
+	for alias, name := range conf.ToolAliases {
+		if _, ok := tuple.indexes[alias]; ok {
+			return nil, fmt.Errorf("tool alias %s conflicts with the name of a tool", alias)
+		}
+		if _, ok := tuple.indexes[name]; !ok {
+			return nil, fmt.Errorf("tool alias %s refers to unknown tool %s", alias, name)
+		}
+	}
+
 	return &ToolsNode{
 		tuple:                     tuple,
 		unknownToolHandler:        conf.UnknownToolsHandler,
 		executeSequentially:       conf.ExecuteSequentially,
+		maxParallelToolCalls:      conf.MaxParallelToolCalls,
 		toolArgumentsHandler:      conf.ToolArgumentsHandler,
+		toolAliases:               conf.ToolAliases,
 		toolCallMiddlewares:       middlewares,
 		streamToolCallMiddlewares: streamMiddlewares,
 	}, nil
@@ -276,7 +303,7 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 func wrapToolCall(it tool.InvokableTool, middlewares []InvokableToolMiddleware, needCallback bool) InvokableToolEndpoint {
 	middleware := func(next InvokableToolEndpoint) InvokableToolEndpoint {
 		for i := len(middlewares) - 1; i >= 0; i-- {
-			next = middlewares[i](next)
+			next = middlewares[i](pinToolCallID(next))
 		}
 		return next
 	}
@@ -295,7 +322,7 @@ func wrapToolCall(it tool.InvokableTool, middlewares []InvokableToolMiddleware,
 func wrapStreamToolCall(st tool.StreamableTool, middlewares []StreamableToolMiddleware, needCallback bool) StreamableToolEndpoint {
 	middleware := func(next StreamableToolEndpoint) StreamableToolEndpoint {
 		for i := len(middlewares) - 1; i >= 0; i-- {
-			next = middlewares[i](next)
+			next = middlewares[i](pinStreamToolCallID(next))
 		}
 		return next
 	}
@@ -311,6 +338,29 @@ func wrapStreamToolCall(st tool.StreamableTool, middlewares []StreamableToolMidd
 	})
 }
 
+// pinToolCallID makes sure next sees the call id of the current tool call, whatever the outer middleware did to input.
+func pinToolCallID(next InvokableToolEndpoint) InvokableToolEndpoint {
+	return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+		return next(ctx, withToolCallID(ctx, input))
+	}
+}
+
+func pinStreamToolCallID(next StreamableToolEndpoint) StreamableToolEndpoint {
+	return func(ctx context.Context, input *ToolInput) (*StreamToolOutput, error) {
+		return next(ctx, withToolCallID(ctx, input))
+	}
+}
+
+func withToolCallID(ctx context.Context, input *ToolInput) *ToolInput {
+	callID := GetToolCallID(ctx)
+	if input == nil || callID == "" || input.CallID == callID {
+		return input
+	}
+	cp := *input
+	cp.CallID = callID
+	return &cp
+}
+
 type invokableToolWithCallback struct {
 	it tool.InvokableTool
 }
@@ -403,7 +453,14 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			}
 			continue
 		}
-		index, ok := tuple.indexes[toolCall.Function.Name]
+		name := toolCall.Function.Name
+		index, ok := tuple.indexes[name]
+		if !ok {
+			if canonical, isAlias := tn.toolAliases[name]; isAlias {
+				name = canonical
+				index, ok = tuple.indexes[name]
+			}
+		}
 		if !ok {
 			if tn.unknownToolHandler == nil {
 				return nil, fmt.Errorf("tool %s not found in toolsNode indexes", toolCall.Function.Name)
@@ -413,12 +470,12 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			toolCallTasks[i].endpoint = tuple.endpoints[index]
 			toolCallTasks[i].streamEndpoint = tuple.streamEndpoints[index]
 			toolCallTasks[i].meta = tuple.meta[index]
-			toolCallTasks[i].name = toolCall.Function.Name
+			toolCallTasks[i].name = name
 			toolCallTasks[i].callID = toolCall.ID
 			if tn.toolArgumentsHandler != nil {
-				arg, err := tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
+				arg, err := tn.toolArgumentsHandler(ctx, name, toolCall.Function.Arguments)
 				if err != nil {
-					return nil, fmt.Errorf("failed to executed tool[name:%s arguments:%s] arguments handler: %w", toolCall.Function.Name, toolCall.Function.Arguments, err)
+					return nil, fmt.Errorf("failed to executed tool[name:%s arguments:%s] arguments handler: %w", name, toolCall.Function.Arguments, err)
 				}
 				toolCallTasks[i].arg = arg
 			} else {
@@ -549,6 +606,25 @@ func parallelRunToolCall(ctx context.Context,
 	wg.Wait()
 }
 
+// batchedRunToolCall runs tasks in parallel, at most batchSize at a time. A non-positive batchSize means no limit.
+func batchedRunToolCall(ctx context.Context,
+	run func(ctx2 context.Context, callTask *toolCallTask, opts ...tool.Option),
+	tasks []toolCallTask, batchSize int, opts ...tool.Option) {
+
+	if batchSize <= 0 || batchSize >= len(tasks) {
+		parallelRunToolCall(ctx, run, tasks, opts...)
+		return
+	}
+
+	for start := 0; start < len(tasks); start += batchSize {
+		end := start + batchSize
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+		parallelRunToolCall(ctx, run, tasks[start:end], opts...)
+	}
+}
+
 // Invoke calls the tools and collects the results of invokable tools.
 // it's parallel if there are multiple tool calls in the input message.
 func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
@@ -580,7 +656,7 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
+		batchedRunToolCall(ctx, runToolCallTaskByInvoke, tasks, tn.maxParallelToolCalls, opt.ToolOptions...)
 	}
 
 	n := len(tasks)
@@ -661,7 +737,7 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
+		batchedRunToolCall(ctx, runToolCallTaskByStream, tasks, tn.maxParallelToolCalls, opt.ToolOptions...)
 	}
 
 	n := len(tasks)
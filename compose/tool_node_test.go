@@ -22,7 +22,9 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
@@ -1019,3 +1021,173 @@ func (f *streamableTool[I, O]) StreamableRun(ctx context.Context, argumentsInJSO
 		return sonic.MarshalString(o)
 	}), nil
 }
+
+func TestToolMiddlewareCallID(t *testing.T) {
+	ctx := context.Background()
+
+	var seen []string
+	record := func(name string) ToolMiddleware {
+		return ToolMiddleware{
+			Invokable: func(endpoint InvokableToolEndpoint) InvokableToolEndpoint {
+				return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+					seen = append(seen, name+":"+input.CallID+":"+GetToolCallID(ctx))
+					// a misbehaving middleware must not change the call id seen by inner layers
+					input.CallID = "tampered"
+					return endpoint(ctx, input)
+				}
+			},
+		}
+	}
+
+	tl := &callIDTool{seen: &seen}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:               []tool.BaseTool{tl},
+		ToolCallMiddlewares: []ToolMiddleware{record("outer"), record("inner")},
+	})
+	assert.NoError(t, err)
+
+	_, err = tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "call-1", Function: schema.FunctionCall{Name: "echo", Arguments: `"hi"`}},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer:call-1:call-1", "inner:call-1:call-1", "tool:call-1"}, seen)
+}
+
+type callIDTool struct {
+	seen *[]string
+}
+
+func (c *callIDTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo"}, nil
+}
+
+func (c *callIDTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	*c.seen = append(*c.seen, "tool:"+GetToolCallID(ctx))
+	return argumentsInJSON, nil
+}
+
+func TestToolsNodeMaxParallelToolCalls(t *testing.T) {
+	ctx := context.Background()
+	bt := &batchTool{}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:                []tool.BaseTool{bt},
+		MaxParallelToolCalls: 2,
+	})
+	assert.NoError(t, err)
+
+	var toolCalls []schema.ToolCall
+	for i := 0; i < 5; i++ {
+		toolCalls = append(toolCalls, schema.ToolCall{
+			ID:       strconv.Itoa(i),
+			Function: schema.FunctionCall{Name: "batch", Arguments: strconv.Itoa(i)},
+		})
+	}
+
+	messages, err := tn.Invoke(ctx, schema.AssistantMessage("", toolCalls))
+	assert.NoError(t, err)
+	assert.Len(t, messages, 5)
+	for i, msg := range messages {
+		assert.Equal(t, strconv.Itoa(i), msg.ToolCallID)
+		assert.Equal(t, "result "+strconv.Itoa(i), msg.Content)
+	}
+	assert.Equal(t, 2, bt.maxRunning)
+
+	bt.maxRunning = 0
+	sr, err := tn.Stream(ctx, schema.AssistantMessage("", toolCalls))
+	assert.NoError(t, err)
+	var chunks [][]*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	messages, err = schema.ConcatMessageArray(chunks)
+	assert.NoError(t, err)
+	for i, msg := range messages {
+		assert.Equal(t, "result "+strconv.Itoa(i), msg.Content)
+	}
+	assert.Equal(t, 2, bt.maxRunning)
+}
+
+type batchTool struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (b *batchTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "batch"}, nil
+}
+
+func (b *batchTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	b.mu.Lock()
+	b.running++
+	if b.running > b.maxRunning {
+		b.maxRunning = b.running
+	}
+	b.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	b.mu.Lock()
+	b.running--
+	b.mu.Unlock()
+	return "result " + argumentsInJSON, nil
+}
+
+func TestToolAliases(t *testing.T) {
+	ctx := context.Background()
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:       []tool.BaseTool{&batchTool{}},
+		ToolAliases: map[string]string{"run_batch": "batch"},
+		UnknownToolsHandler: func(ctx context.Context, name, input string) (string, error) {
+			return "unknown " + name, nil
+		},
+	})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "run_batch", Arguments: "1"}},
+		{ID: "2", Function: schema.FunctionCall{Name: "batch", Arguments: "2"}},
+		{ID: "3", Function: schema.FunctionCall{Name: "batch_run", Arguments: "3"}},
+	})
+	expected := []*schema.Message{
+		schema.ToolMessage("result 1", "1", schema.WithToolName("batch")),
+		schema.ToolMessage("result 2", "2", schema.WithToolName("batch")),
+		schema.ToolMessage("unknown batch_run", "3", schema.WithToolName("batch_run")),
+	}
+
+	result, err := tn.Invoke(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	sr, err := tn.Stream(ctx, input)
+	assert.NoError(t, err)
+	var chunks [][]*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	result, err = schema.ConcatMessageArray(chunks)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	_, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:       []tool.BaseTool{&batchTool{}},
+		ToolAliases: map[string]string{"run_batch": "ls"},
+	})
+	assert.ErrorContains(t, err, "tool alias run_batch refers to unknown tool ls")
+
+	_, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:       []tool.BaseTool{&batchTool{}},
+		ToolAliases: map[string]string{"batch": "batch"},
+	})
+	assert.ErrorContains(t, err, "tool alias batch conflicts with the name of a tool")
+}
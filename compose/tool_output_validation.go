@@ -0,0 +1,218 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// ErrInvalidToolOutput is returned by the middleware of NewToolOutputValidationMiddleware
+// when the result of a tool doesn't conform to the OutputSchema declared in its ToolInfo.
+var ErrInvalidToolOutput = errors.New("invalid tool output")
+
+// NewToolOutputValidationMiddleware creates a ToolMiddleware checking that the results of the tools declaring
+// an OutputSchema in their ToolInfo, e.g. by utils.WithOutputSchema, are JSON conforming to it.
+// A result violating the schema fails the tool call with an error wrapping ErrInvalidToolOutput,
+// to catch tool bugs early instead of passing malformed results to the model.
+// The keywords checked are type, enum, const, required, properties, additionalProperties and items.
+// Only invokable tool calls are validated, as the result of a streamable tool is only known once it's consumed.
+// e.g.
+//
+//	m, err := compose.NewToolOutputValidationMiddleware(ctx, tools)
+//	toolsNode, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{
+//		Tools:               tools,
+//		ToolCallMiddlewares: []compose.ToolMiddleware{m},
+//	})
+func NewToolOutputValidationMiddleware(ctx context.Context, tools []tool.BaseTool) (ToolMiddleware, error) {
+	schemas := make(map[string]*jsonschema.Schema)
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return ToolMiddleware{}, fmt.Errorf("failed to get tool info: %w", err)
+		}
+		if info.OutputSchema != nil {
+			schemas[info.Name] = info.OutputSchema
+		}
+	}
+
+	return ToolMiddleware{
+		Invokable: func(next InvokableToolEndpoint) InvokableToolEndpoint {
+			return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+				output, err := next(ctx, input)
+				if err != nil {
+					return nil, err
+				}
+				s, ok := schemas[input.Name]
+				if !ok {
+					return output, nil
+				}
+				if err = validateJSONOutput(s, output.Result); err != nil {
+					return nil, fmt.Errorf("%w of tool '%s': %v", ErrInvalidToolOutput, input.Name, err)
+				}
+				return output, nil
+			}
+		},
+	}, nil
+}
+
+func validateJSONOutput(s *jsonschema.Schema, result string) error {
+	d := json.NewDecoder(bytes.NewReader([]byte(result)))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("not valid JSON: %v", err)
+	}
+	if d.More() {
+		return fmt.Errorf("not valid JSON: unexpected content after the value")
+	}
+	return validateJSONValue(s, v, "$")
+}
+
+func validateJSONValue(s *jsonschema.Schema, v any, path string) error {
+	if s == nil || s == jsonschema.TrueSchema {
+		return nil
+	}
+	if s == jsonschema.FalseSchema {
+		return fmt.Errorf("%s: not allowed", path)
+	}
+
+	types := s.TypeEnhanced
+	if s.Type != "" {
+		types = append([]string{s.Type}, types...)
+	}
+	if len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if isJSONType(v, typ) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %v, got %s", path, types, jsonTypeOf(v))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		matched := false
+		for _, e := range s.Enum {
+			if jsonEqual(e, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+		}
+	}
+	if s.Const != nil && !jsonEqual(s.Const, v) {
+		return fmt.Errorf("%s: %v is not %v", path, v, s.Const)
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: missing required property '%s'", path, name)
+			}
+		}
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var ps *jsonschema.Schema
+			ok := false
+			if s.Properties != nil {
+				ps, ok = s.Properties.Get(name)
+			}
+			if !ok {
+				ps = s.AdditionalProperties
+			}
+			if err := validateJSONValue(ps, val[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, item := range val {
+			if err := validateJSONValue(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isJSONType(v any, typ string) bool {
+	switch typ {
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	default:
+		return jsonTypeOf(v) == typ
+	}
+}
+
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual compares values of a schema and of the decoded output by their JSON encodings,
+// as the schema values aren't decoded with json.Number.
+func jsonEqual(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var va, vb any
+	if json.Unmarshal(ja, &va) != nil || json.Unmarshal(jb, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fixedResultTool struct {
+	info   *schema.ToolInfo
+	result string
+}
+
+func (f *fixedResultTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return f.info, nil
+}
+
+func (f *fixedResultTool) InvokableRun(_ context.Context, _ string, _ ...tool.Option) (string, error) {
+	return f.result, nil
+}
+
+func TestToolOutputValidationMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	props := orderedmap.New[string, *jsonschema.Schema]()
+	props.Set("city", &jsonschema.Schema{Type: "string"})
+	props.Set("temperature", &jsonschema.Schema{Type: "integer"})
+	props.Set("unit", &jsonschema.Schema{Type: "string", Enum: []any{"C", "F"}})
+	outputSchema := &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           props,
+		Required:             []string{"city", "temperature"},
+		AdditionalProperties: jsonschema.FalseSchema,
+	}
+
+	run := func(result string) (*schema.Message, error) {
+		tools := []tool.BaseTool{
+			&fixedResultTool{info: &schema.ToolInfo{Name: "weather", OutputSchema: outputSchema}, result: result},
+			&fixedResultTool{info: &schema.ToolInfo{Name: "echo"}, result: "free-form text"},
+		}
+		m, err := NewToolOutputValidationMiddleware(ctx, tools)
+		assert.NoError(t, err)
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: tools, ToolCallMiddlewares: []ToolMiddleware{m}})
+		assert.NoError(t, err)
+
+		msgs, err := tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: "{}"}},
+			{ID: "2", Function: schema.FunctionCall{Name: "echo", Arguments: "{}"}},
+		}))
+		if err != nil {
+			return nil, err
+		}
+		assert.Equal(t, "free-form text", msgs[1].Content)
+		return msgs[0], nil
+	}
+
+	msg, err := run(`{"city": "Paris", "temperature": 21, "unit": "C"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"city": "Paris", "temperature": 21, "unit": "C"}`, msg.Content)
+
+	for result, reason := range map[string]string{
+		`{"city": "Paris", "temperature": 21`:                   "not valid JSON",
+		`the weather is fine`:                                   "not valid JSON",
+		`{"city": "Paris"}`:                                     "missing required property 'temperature'",
+		`{"city": "Paris", "temperature": 21.5}`:                "$.temperature: expected [integer], got number",
+		`{"city": "Paris", "temperature": 21, "unit": "K"}`:     "$.unit: K is not one of [C F]",
+		`{"city": "Paris", "temperature": 21, "humidity": 0.4}`: "$.humidity: not allowed",
+		`["Paris", 21]`:                                         "$: expected [object], got array",
+	} {
+		_, err = run(result)
+		assert.ErrorIs(t, err, ErrInvalidToolOutput, result)
+		assert.ErrorContains(t, err, reason, result)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TraceEventType is the type of a TraceEvent.
+type TraceEventType string
+
+const (
+	// TraceEventStart is emitted when a graph, node or component starts running.
+	TraceEventStart TraceEventType = "start"
+	// TraceEventEnd is emitted when a graph, node or component finishes successfully.
+	TraceEventEnd TraceEventType = "end"
+	// TraceEventError is emitted when a graph, node or component fails.
+	TraceEventError TraceEventType = "error"
+	// TraceEventInterrupt is emitted when a graph, node or component is interrupted.
+	TraceEventInterrupt TraceEventType = "interrupt"
+	// TraceEventStateSnapshot is emitted when a node finishes, with the graph state before and after it,
+	// if enabled by WithStateSnapshots.
+	TraceEventStateSnapshot TraceEventType = "state_snapshot"
+)
+
+// TraceEvent is a structured, serializable record of a single step of a run.
+type TraceEvent struct {
+	Type TraceEventType `json:"type"`
+	// Address is the address of the running graph, node or component.
+	// Events of a subgraph's nodes have addresses prefixed by the subgraph node's address.
+	Address Address `json:"address"`
+	// Name is the display name set through WithNodeName or WithGraphName, if any.
+	Name      string               `json:"name,omitempty"`
+	Component components.Component `json:"component,omitempty"`
+	// Time is when the step started for start events, and when it finished otherwise.
+	Time time.Time `json:"time"`
+	// Duration is how long the step took. Empty for start events.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Input is the input of the step for start events.
+	// A streaming input is collected into a slice of chunks, and set on the event ending the step instead,
+	// as the start event is emitted before the step consumes its input.
+	Input any `json:"input,omitempty"`
+	// Output is the output of the step for end events. A streaming output is collected into a slice of chunks.
+	Output any `json:"output,omitempty"`
+	// Error is the error message for error events.
+	Error string `json:"error,omitempty"`
+	// InterruptInfo is the interrupt info for interrupt events raised by graphs.
+	InterruptInfo *InterruptInfo `json:"interrupt_info,omitempty"`
+	// StateSnapshot is the graph state before and after the node, and the changes made, for state snapshot events.
+	StateSnapshot *callbacks.StateSnapshot `json:"state_snapshot,omitempty"`
+}
+
+// TraceSink receives trace events during a run.
+// Events may be emitted concurrently, e.g. by nodes running in parallel, so implementations must be safe for concurrent use.
+// The start event of a step is always emitted before the event ending it. When the input or output of a step is a stream,
+// the event ending it is emitted asynchronously once the streams are fully consumed, so for streaming runs
+// it may arrive after the caller has read the output to the end.
+type TraceSink interface {
+	Emit(ctx context.Context, event *TraceEvent)
+}
+
+// WithTraceSink streams structured trace events of the run, including its subgraphs, to sink.
+// Unlike plain callbacks, the events carry the execution address, timing and interrupt information,
+// and are designed to be persisted, e.g. by NewJSONTraceSink.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithTraceSink(compose.NewJSONTraceSink(file)))
+func WithTraceSink(sink TraceSink) Option {
+	return WithCallbacks(&traceHandler{sink: sink})
+}
+
+// NewJSONTraceSink returns a TraceSink that writes each event to w as a line of JSON.
+// Events that cannot be serialized are written with their input and output dropped.
+func NewJSONTraceSink(w io.Writer) TraceSink {
+	return &jsonTraceSink{w: w}
+}
+
+type jsonTraceSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (j *jsonTraceSink) Emit(_ context.Context, event *TraceEvent) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		e := *event
+		e.Input, e.Output = nil, nil
+		if b, err = json.Marshal(&e); err != nil {
+			return
+		}
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.w.Write(append(b, '\n'))
+}
+
+type traceStartKey struct {
+	h *traceHandler
+}
+
+type traceInputKey struct {
+	h *traceHandler
+}
+
+// traceStreamInput is the streaming input of a step, being collected.
+type traceStreamInput struct {
+	done   chan struct{}
+	chunks []callbacks.CallbackInput
+}
+
+type traceHandler struct {
+	sink TraceSink
+}
+
+func (t *traceHandler) newEvent(ctx context.Context, typ TraceEventType, info *callbacks.RunInfo) *TraceEvent {
+	e := &TraceEvent{
+		Type:    typ,
+		Address: GetCurrentAddress(ctx),
+		Time:    time.Now(),
+	}
+	if info != nil {
+		e.Name = info.Name
+		e.Component = info.Component
+	}
+	if typ != TraceEventStart {
+		if start, ok := ctx.Value(traceStartKey{h: t}).(time.Time); ok {
+			e.Duration = e.Time.Sub(start)
+		}
+	}
+	return e
+}
+
+// setStreamInput sets the streaming input of the step of ctx on e ending it, once it is collected.
+func (t *traceHandler) setStreamInput(ctx context.Context, e *TraceEvent) {
+	if in, ok := ctx.Value(traceInputKey{h: t}).(*traceStreamInput); ok {
+		<-in.done
+		e.Input = in.chunks
+	}
+}
+
+// emitEnd emits e ending the step of ctx. If the step has a streaming input,
+// e is emitted asynchronously once the input is collected, so that the callback never waits for the input stream.
+func (t *traceHandler) emitEnd(ctx context.Context, e *TraceEvent) {
+	if _, ok := ctx.Value(traceInputKey{h: t}).(*traceStreamInput); !ok {
+		t.sink.Emit(ctx, e)
+		return
+	}
+	go func() {
+		t.setStreamInput(ctx, e)
+		t.sink.Emit(ctx, e)
+	}()
+}
+
+func (t *traceHandler) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	e := t.newEvent(ctx, TraceEventStart, info)
+	e.Input = input
+	t.sink.Emit(ctx, e)
+	return context.WithValue(ctx, traceStartKey{h: t}, e.Time)
+}
+
+func (t *traceHandler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	e := t.newEvent(ctx, TraceEventEnd, info)
+	e.Output = output
+	t.emitEnd(ctx, e)
+	return ctx
+}
+
+func (t *traceHandler) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	typ := TraceEventError
+	if isInterruptError(err) {
+		typ = TraceEventInterrupt
+	}
+	e := t.newEvent(ctx, typ, info)
+	e.Error = err.Error()
+	e.InterruptInfo, _ = ExtractInterruptInfo(err)
+	t.emitEnd(ctx, e)
+	return ctx
+}
+
+func (t *traceHandler) OnStateSnapshot(ctx context.Context, info *callbacks.RunInfo, snapshot *callbacks.StateSnapshot) {
+	e := t.newEvent(ctx, TraceEventStateSnapshot, info)
+	e.StateSnapshot = snapshot
+	t.sink.Emit(ctx, e)
+}
+
+func (t *traceHandler) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+
+	e := t.newEvent(ctx, TraceEventStart, info)
+	t.sink.Emit(ctx, e)
+
+	in := &traceStreamInput{done: make(chan struct{})}
+	go func() {
+		defer close(in.done)
+		in.chunks = collectTraceChunks(input)
+	}()
+	ctx = context.WithValue(ctx, traceInputKey{h: t}, in)
+	return context.WithValue(ctx, traceStartKey{h: t}, e.Time)
+}
+
+func (t *traceHandler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+
+	go func() {
+		chunks := collectTraceChunks(output)
+		// the step finishes when its output stream is fully consumed
+		e := t.newEvent(ctx, TraceEventEnd, info)
+		e.Output = chunks
+		t.setStreamInput(ctx, e)
+		t.sink.Emit(ctx, e)
+	}()
+	return ctx
+}
+
+func collectTraceChunks[T any](sr *schema.StreamReader[T]) []T {
+	defer sr.Close()
+	var chunks []T
+	for {
+		chunk, err := sr.Recv()
+		if err != nil {
+			// io.EOF or a stream error, either way the collected chunks are all there is
+			return chunks
+		}
+		chunks = append(chunks, chunk)
+	}
+}
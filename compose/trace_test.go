@@ -0,0 +1,262 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type sliceTraceSink struct {
+	mu     sync.Mutex
+	events []*TraceEvent
+}
+
+func (s *sliceTraceSink) Emit(_ context.Context, event *TraceEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *sliceTraceSink) summary() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]string, 0, len(s.events))
+	for _, e := range s.events {
+		ret = append(ret, string(e.Type)+" "+e.Address.String())
+	}
+	return ret
+}
+
+func newTraceTestGraph(t *testing.T) *Graph[string, string] {
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("node1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "_1", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("node2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "_2", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "node1"))
+	assert.NoError(t, g.AddEdge("node1", "node2"))
+	assert.NoError(t, g.AddEdge("node2", END))
+	return g
+}
+
+func TestTraceSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("simple graph", func(t *testing.T) {
+		r, err := newTraceTestGraph(t).Compile(ctx, WithGraphName("g"))
+		assert.NoError(t, err)
+
+		sink := &sliceTraceSink{}
+		out, err := r.Invoke(ctx, "in", WithTraceSink(sink))
+		assert.NoError(t, err)
+		assert.Equal(t, "in_1_2", out)
+
+		assert.Equal(t, []string{
+			"start runnable:g",
+			"start runnable:g;node:node1",
+			"end runnable:g;node:node1",
+			"start runnable:g;node:node2",
+			"end runnable:g;node:node2",
+			"end runnable:g",
+		}, sink.summary())
+
+		assert.Equal(t, "in", sink.events[1].Input)
+		assert.Equal(t, "in_1", sink.events[2].Output)
+		assert.Equal(t, "in_1_2", sink.events[5].Output)
+	})
+
+	t.Run("subgraph", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddGraphNode("sub", newTraceTestGraph(t)))
+		assert.NoError(t, g.AddEdge(START, "sub"))
+		assert.NoError(t, g.AddEdge("sub", END))
+		r, err := g.Compile(ctx, WithGraphName("root"))
+		assert.NoError(t, err)
+
+		sink := &sliceTraceSink{}
+		_, err = r.Invoke(ctx, "in", WithTraceSink(sink))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"start runnable:root",
+			"start runnable:root;node:sub",
+			"start runnable:root;node:sub;node:node1",
+			"end runnable:root;node:sub;node:node1",
+			"start runnable:root;node:sub;node:node2",
+			"end runnable:root;node:sub;node:node2",
+			"end runnable:root;node:sub",
+			"end runnable:root",
+		}, sink.summary())
+	})
+
+	t.Run("interrupt", func(t *testing.T) {
+		r, err := newTraceTestGraph(t).Compile(ctx, WithGraphName("g"),
+			WithCheckPointStore(newInMemoryStore()), WithInterruptBeforeNodes([]string{"node2"}))
+		assert.NoError(t, err)
+
+		sink := &sliceTraceSink{}
+		_, err = r.Invoke(ctx, "in", WithTraceSink(sink), WithCheckPointID("1"))
+		assert.Error(t, err)
+
+		summary := sink.summary()
+		assert.Equal(t, "interrupt runnable:g", summary[len(summary)-1])
+		last := sink.events[len(sink.events)-1]
+		assert.NotNil(t, last.InterruptInfo)
+		assert.Equal(t, []string{"node2"}, last.InterruptInfo.BeforeNodes)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		r, err := newTraceTestGraph(t).Compile(ctx, WithGraphName("g"))
+		assert.NoError(t, err)
+
+		sink := &sliceTraceSink{}
+		sr, err := r.Stream(ctx, "in", WithTraceSink(sink))
+		assert.NoError(t, err)
+		out, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "in_1_2", out)
+
+		// end events are emitted once the streams are consumed, always after the start events
+		assert.Eventually(t, func() bool {
+			return len(sink.summary()) == 6
+		}, time.Second, 10*time.Millisecond)
+		index := make(map[string]int)
+		for i, s := range sink.summary() {
+			index[s] = i
+		}
+		assert.Len(t, index, 6)
+		for _, addr := range []string{"runnable:g", "runnable:g;node:node1", "runnable:g;node:node2"} {
+			assert.Less(t, index["start "+addr], index["end "+addr])
+		}
+		assert.Less(t, index["start runnable:g"], index["start runnable:g;node:node1"])
+		assert.Less(t, index["start runnable:g;node:node1"], index["start runnable:g;node:node2"])
+	})
+
+	t.Run("stream input", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("node1", TransformableLambda(
+			func(ctx context.Context, input *schema.StreamReader[string]) (*schema.StreamReader[string], error) {
+				return schema.StreamReaderWithConvert(input, func(s string) (string, error) {
+					return s + "_1", nil
+				}), nil
+			})))
+		assert.NoError(t, g.AddEdge(START, "node1"))
+		assert.NoError(t, g.AddEdge("node1", END))
+		r, err := g.Compile(ctx, WithGraphName("g"))
+		assert.NoError(t, err)
+
+		sink := &sliceTraceSink{}
+		sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]string{"a", "b"}), WithTraceSink(sink))
+		assert.NoError(t, err)
+		// the start events are emitted before the input is consumed
+		assert.Equal(t, []string{"start runnable:g", "start runnable:g;node:node1"}, sink.summary())
+		out, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "a_1b_1", out)
+
+		assert.Eventually(t, func() bool {
+			return len(sink.summary()) == 4
+		}, time.Second, 10*time.Millisecond)
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		for _, e := range sink.events {
+			if e.Type == TraceEventStart {
+				assert.Nil(t, e.Input)
+			} else if e.Address.String() == "runnable:g;node:node1" {
+				assert.Equal(t, TraceEventEnd, e.Type)
+				assert.Len(t, e.Input, 2)
+				assert.Len(t, e.Output, 2)
+			}
+		}
+	})
+
+	t.Run("stream input not waited by callbacks", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("node1", CollectableLambda(
+			func(ctx context.Context, input *schema.StreamReader[string]) (string, error) {
+				defer input.Close()
+				return input.Recv()
+			})))
+		assert.NoError(t, g.AddEdge(START, "node1"))
+		assert.NoError(t, g.AddEdge("node1", END))
+		r, err := g.Compile(ctx, WithGraphName("g"))
+		assert.NoError(t, err)
+
+		inSR, inSW := schema.Pipe[string](2)
+		inSW.Send("a", nil)
+
+		sink := &sliceTraceSink{}
+		outCh := make(chan string, 1)
+		go func() {
+			sr, err := r.Transform(ctx, inSR, WithTraceSink(sink))
+			assert.NoError(t, err)
+			out, err := concatStreamReader(sr)
+			assert.NoError(t, err)
+			outCh <- out
+		}()
+
+		// the node finishes with its input stream still open
+		select {
+		case out := <-outCh:
+			assert.Equal(t, "a", out)
+		case <-time.After(time.Second):
+			t.Fatal("callbacks waited for the input stream")
+		}
+
+		inSW.Send("b", nil)
+		inSW.Close()
+		assert.Eventually(t, func() bool {
+			return len(sink.summary()) == 4
+		}, time.Second, 10*time.Millisecond)
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		for _, e := range sink.events {
+			if e.Type == TraceEventEnd && e.Address.String() == "runnable:g;node:node1" {
+				assert.Len(t, e.Input, 2)
+			}
+		}
+	})
+
+	t.Run("json sink", func(t *testing.T) {
+		r, err := newTraceTestGraph(t).Compile(ctx, WithGraphName("g"))
+		assert.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		_, err = r.Invoke(ctx, "in", WithTraceSink(NewJSONTraceSink(buf)))
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 6)
+		e := &TraceEvent{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), e))
+		assert.Equal(t, TraceEventStart, e.Type)
+		assert.Equal(t, "runnable:g;node:node1", e.Address.String())
+		assert.Equal(t, "in", e.Input)
+	})
+}
@@ -67,8 +67,7 @@ func NewWorkflow[I, O any](opts ...NewGraphOption) *Workflow[I, O] {
 	wf := &Workflow[I, O]{
 		g: newGraphFromGeneric[I, O](
 			ComponentOfWorkflow,
-			options.withState,
-			options.stateType,
+			options,
 			opts,
 		),
 		workflowNodes: make(map[string]*WorkflowNode),
@@ -18,6 +18,7 @@ package callbacks
 
 import (
 	"context"
+	"time"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/internal/generic"
@@ -25,7 +26,7 @@ import (
 )
 
 func InitCallbacks(ctx context.Context, info *RunInfo, handlers ...Handler) context.Context {
-	mgr, ok := newManager(info, handlers...)
+	mgr, ok := newManager(withTraceID(ctx, info), handlers...)
 	if ok {
 		return ctxWithManager(ctx, mgr)
 	}
@@ -55,7 +56,34 @@ func ReuseHandlers(ctx context.Context, info *RunInfo) context.Context {
 	if !ok {
 		return InitCallbacks(ctx, info)
 	}
-	return ctxWithManager(ctx, cbm.withRunInfo(info))
+	return ctxWithManager(ctx, cbm.withRunInfo(withTraceID(ctx, info)))
+}
+
+type ctxTraceIDKey struct{}
+
+// WithTraceID returns a child context carrying traceID, which is filled into the RunInfo of the callbacks initialized with it.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, ctxTraceIDKey{}, traceID)
+}
+
+// GetTraceID returns the trace ID carried by ctx, or "" if there is none.
+func GetTraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(ctxTraceIDKey{}).(string)
+	return traceID
+}
+
+// withTraceID returns a copy of info with the trace ID carried by ctx, leaving info untouched as it may be shared.
+func withTraceID(ctx context.Context, info *RunInfo) *RunInfo {
+	if info == nil || info.TraceID != "" {
+		return info
+	}
+	traceID := GetTraceID(ctx)
+	if traceID == "" {
+		return info
+	}
+	n := *info
+	n.TraceID = traceID
+	return &n
 }
 
 func AppendHandlers(ctx context.Context, info *RunInfo, handlers ...Handler) context.Context {
@@ -69,6 +97,38 @@ func AppendHandlers(ctx context.Context, info *RunInfo, handlers ...Handler) con
 	return InitCallbacks(ctx, info, nh...)
 }
 
+// OnHeartbeat calls the handlers in ctx implementing HeartbeatHandler.
+func OnHeartbeat(ctx context.Context, elapsed time.Duration) {
+	mgr, ok := managerFromCtx(ctx)
+	if !ok {
+		return
+	}
+
+	for _, hs := range [][]Handler{mgr.handlers, mgr.globalHandlers} {
+		for _, handler := range hs {
+			if hh, ok_ := handler.(HeartbeatHandler); ok_ {
+				hh.OnHeartbeat(ctx, mgr.runInfo, elapsed)
+			}
+		}
+	}
+}
+
+// OnStateSnapshot calls the handlers in ctx implementing StateSnapshotHandler.
+func OnStateSnapshot(ctx context.Context, snapshot *StateSnapshot) {
+	mgr, ok := managerFromCtx(ctx)
+	if !ok {
+		return
+	}
+
+	for _, hs := range [][]Handler{mgr.handlers, mgr.globalHandlers} {
+		for _, handler := range hs {
+			if sh, ok_ := handler.(StateSnapshotHandler); ok_ {
+				sh.OnStateSnapshot(ctx, mgr.runInfo, snapshot)
+			}
+		}
+	}
+}
+
 type Handle[T any] func(context.Context, T, *RunInfo, []Handler) (context.Context, T)
 
 func On[T any](ctx context.Context, inOut T, handle Handle[T], timing CallbackTiming, start bool) (context.Context, T) {
@@ -18,6 +18,7 @@ package callbacks
 
 import (
 	"context"
+	"time"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/schema"
@@ -29,6 +30,9 @@ type RunInfo struct {
 	Name      string
 	Type      string
 	Component components.Component
+	// TraceID is the trace ID of the run the component is executed in.
+	// Passed from compose.WithTraceID().
+	TraceID string
 }
 
 type CallbackInput any
@@ -52,3 +56,23 @@ type CallbackTiming uint8
 type TimingChecker interface {
 	Needed(ctx context.Context, info *RunInfo, timing CallbackTiming) bool
 }
+
+type HeartbeatHandler interface {
+	OnHeartbeat(ctx context.Context, info *RunInfo, elapsed time.Duration)
+}
+
+type StateSnapshot struct {
+	Before  any           `json:"before"`
+	After   any           `json:"after"`
+	Changes []StateChange `json:"changes,omitempty"`
+}
+
+type StateChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type StateSnapshotHandler interface {
+	OnStateSnapshot(ctx context.Context, info *RunInfo, snapshot *StateSnapshot)
+}
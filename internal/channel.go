@@ -18,11 +18,14 @@ package internal
 
 import "sync"
 
-// UnboundedChan represents a channel with unlimited capacity
+// UnboundedChan represents a channel with unlimited capacity.
+// A capacity limit can be set with NewBoundedChan, in which case Send blocks while the buffer is full.
 type UnboundedChan[T any] struct {
 	buffer   []T        // Internal buffer to store data
 	mutex    sync.Mutex // Mutex to protect buffer access
 	notEmpty *sync.Cond // Condition variable to wait for data
+	notFull  *sync.Cond // Condition variable to wait for free space, only used when capacity > 0
+	capacity int        // Maximum number of buffered items, non-positive means unlimited
 	closed   bool       // Indicates if the channel has been closed
 }
 
@@ -30,14 +33,27 @@ type UnboundedChan[T any] struct {
 func NewUnboundedChan[T any]() *UnboundedChan[T] {
 	ch := &UnboundedChan[T]{}
 	ch.notEmpty = sync.NewCond(&ch.mutex)
+	ch.notFull = sync.NewCond(&ch.mutex)
 	return ch
 }
 
-// Send puts an item into the channel
+// NewBoundedChan initializes and returns an UnboundedChan holding at most capacity items.
+// A non-positive capacity is equivalent to NewUnboundedChan.
+func NewBoundedChan[T any](capacity int) *UnboundedChan[T] {
+	ch := NewUnboundedChan[T]()
+	ch.capacity = capacity
+	return ch
+}
+
+// Send puts an item into the channel, blocking while a bounded channel is full
 func (ch *UnboundedChan[T]) Send(value T) {
 	ch.mutex.Lock()
 	defer ch.mutex.Unlock()
 
+	for ch.capacity > 0 && len(ch.buffer) >= ch.capacity && !ch.closed {
+		ch.notFull.Wait() // Wait until a receiver frees up space
+	}
+
 	if ch.closed {
 		panic("send on closed channel")
 	}
@@ -63,6 +79,7 @@ func (ch *UnboundedChan[T]) Receive() (T, bool) {
 
 	val := ch.buffer[0]
 	ch.buffer = ch.buffer[1:]
+	ch.notFull.Signal() // Wake up one goroutine waiting to send
 	return val, true
 }
 
@@ -74,5 +91,6 @@ func (ch *UnboundedChan[T]) Close() {
 	if !ch.closed {
 		ch.closed = true
 		ch.notEmpty.Broadcast() // Wake up all waiting goroutines
+		ch.notFull.Broadcast()
 	}
 }
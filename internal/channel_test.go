@@ -219,3 +219,61 @@ func TestUnboundedChan_BlockingReceive(t *testing.T) {
 		t.Error("Receive should have unblocked")
 	}
 }
+
+func TestBoundedChan(t *testing.T) {
+	ch := NewBoundedChan[int](2)
+	ch.Send(1)
+	ch.Send(2)
+
+	sendDone := make(chan bool)
+	go func() {
+		ch.Send(3)
+		sendDone <- true
+	}()
+
+	select {
+	case <-sendDone:
+		t.Error("Send should block when the channel is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	val, ok := ch.Receive()
+	if !ok || val != 1 {
+		t.Errorf("expected 1, got %d", val)
+	}
+
+	select {
+	case <-sendDone:
+	case <-time.After(time.Second):
+		t.Error("Send should unblock after Receive")
+	}
+
+	if len(ch.buffer) != 2 {
+		t.Errorf("buffer length should be 2, got %d", len(ch.buffer))
+	}
+}
+
+func TestBoundedChan_CloseUnblocksSender(t *testing.T) {
+	ch := NewBoundedChan[int](1)
+	ch.Send(1)
+
+	panicked := make(chan bool)
+	go func() {
+		defer func() {
+			panicked <- recover() != nil
+		}()
+		ch.Send(2)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	ch.Close()
+
+	select {
+	case p := <-panicked:
+		if !p {
+			t.Error("blocked Send should panic once the channel is closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("Close should unblock a blocked Send")
+	}
+}
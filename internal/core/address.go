@@ -79,7 +79,9 @@ type AddressSegment struct {
 type addrCtxKey struct{}
 
 type addrCtx struct {
-	addr           Address
+	addr Address
+	// interruptID is the ID of the previous interrupt at addr, kept by a new interrupt there.
+	interruptID    string
 	interruptState *InterruptState
 	isResumeTarget bool
 	resumeData     any
@@ -153,6 +155,7 @@ func AppendAddressSegment(ctx context.Context, segType AddressSegmentType, segID
 			rInfo.mu.Lock()
 			if used, ok := rInfo.id2StateUsed[id_]; !ok || !used {
 				runCtx.interruptState = generic.PtrOf(rInfo.id2State[id_])
+				runCtx.interruptID = id_
 				rInfo.id2StateUsed[id_] = true
 				id = id_
 				rInfo.mu.Unlock()
@@ -299,6 +302,7 @@ func PopulateInterruptState(ctx context.Context, id2Addr map[string]Address,
 			if addr.Equals(runCtx.addr) {
 				if used, ok := rInfo.id2StateUsed[id_]; !ok || !used {
 					runCtx.interruptState = generic.PtrOf(rInfo.id2State[id_])
+					runCtx.interruptID = id_
 					rInfo.mu.Lock()
 					rInfo.id2StateUsed[id_] = true
 					rInfo.mu.Unlock()
@@ -320,6 +324,23 @@ func PopulateInterruptState(ctx context.Context, id2Addr map[string]Address,
 	return ctx
 }
 
+// GetResumeData returns a copy of the resume targets injected into the context by BatchResumeWithData,
+// mapping the interrupt IDs to their resume data.
+func GetResumeData(ctx context.Context) map[string]any {
+	rInfo, ok := getResumeInfo(ctx)
+	if !ok {
+		return nil
+	}
+
+	rInfo.mu.Lock()
+	defer rInfo.mu.Unlock()
+	ret := make(map[string]any, len(rInfo.id2ResumeData))
+	for id, data := range rInfo.id2ResumeData {
+		ret[id] = data
+	}
+	return ret
+}
+
 func getResumeInfo(ctx context.Context) (*globalResumeInfo, bool) {
 	info, ok := ctx.Value(globalResumeInfoKey{}).(*globalResumeInfo)
 	return info, ok
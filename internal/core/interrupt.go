@@ -84,10 +84,18 @@ func Interrupt(ctx context.Context, info any, state any, subContexts []*Interrup
 		Info: info,
 	}
 
+	// interrupting again where the previous run was interrupted, without being resumed there, keeps the ID of the
+	// interrupt, so that the interrupts left unanswered by a partial resume keep the IDs the end-user knows them by.
+	// A point that was resumed and interrupts again raises a new interrupt, with a new ID.
+	id := uuid.NewString()
+	if runCtx, ok := getRunCtx(ctx); ok && runCtx.interruptID != "" && !runCtx.isResumeTarget {
+		id = runCtx.interruptID
+	}
+
 	if len(subContexts) == 0 {
 		myPoint.IsRootCause = true
 		return &InterruptSignal{
-			ID:            uuid.NewString(),
+			ID:            id,
 			Address:       addr,
 			InterruptInfo: myPoint,
 			InterruptState: InterruptState{
@@ -98,7 +106,7 @@ func Interrupt(ctx context.Context, info any, state any, subContexts []*Interrup
 	}
 
 	return &InterruptSignal{
-		ID:            uuid.NewString(),
+		ID:            id,
 		Address:       addr,
 		InterruptInfo: myPoint,
 		InterruptState: InterruptState{
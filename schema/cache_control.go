@@ -0,0 +1,60 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+// CacheControlExtraKey is the key of Message.Extra under which SetCacheControl stores the cache control hint.
+const CacheControlExtraKey = "_eino_cache_control"
+
+func init() {
+	RegisterName[CacheControl]("_eino_cache_control")
+}
+
+// CacheControl is a hint that the prompt up to and including the message carrying it can be cached by the provider.
+// It's a hint only, model implementations not supporting prompt caching ignore it.
+type CacheControl struct {
+	// TTL is the requested lifetime of the cache entry, e.g. "5m" or "1h".
+	// Optional. If empty, the provider decides.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// SetCacheControl marks msg as a prompt caching breakpoint.
+// Extra of msg is copied before being written, so messages sharing the map aren't affected.
+// A nil cc removes the marker.
+func SetCacheControl(msg *Message, cc *CacheControl) {
+	if msg == nil {
+		return
+	}
+	extra := make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		extra[k] = v
+	}
+	if cc == nil {
+		delete(extra, CacheControlExtraKey)
+	} else {
+		extra[CacheControlExtraKey] = cc
+	}
+	msg.Extra = extra
+}
+
+// GetCacheControl returns the cache control hint set on msg by SetCacheControl, or nil if msg isn't marked.
+func GetCacheControl(msg *Message) *CacheControl {
+	if msg == nil {
+		return nil
+	}
+	cc, _ := msg.Extra[CacheControlExtraKey].(*CacheControl)
+	return cc
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	extra := map[string]any{"k": "v"}
+	msg := &Message{Role: System, Content: "sys", Extra: extra}
+	assert.Nil(t, GetCacheControl(msg))
+
+	SetCacheControl(msg, &CacheControl{TTL: "1h"})
+	assert.Equal(t, &CacheControl{TTL: "1h"}, GetCacheControl(msg))
+	assert.Equal(t, "v", msg.Extra["k"])
+	// the original map is left untouched
+	assert.NotContains(t, extra, CacheControlExtraKey)
+
+	SetCacheControl(msg, nil)
+	assert.Nil(t, GetCacheControl(msg))
+	assert.Equal(t, "v", msg.Extra["k"])
+
+	assert.Nil(t, GetCacheControl(nil))
+	SetCacheControl(nil, &CacheControl{})
+}
@@ -82,3 +82,44 @@ func (message *Message) GetContent() string {
 	}
 	return message.Content
 }
+
+// RedactMessage returns a copy of message with redactor applied to every piece of free-form text that may
+// carry sensitive data: Content, ReasoningContent, the text of multi-content parts and tool call arguments.
+// The original message is left untouched, so the result can be safely handed to logging or telemetry.
+func RedactMessage(message *Message, redactor func(string) string) *Message {
+	if message == nil {
+		return nil
+	}
+
+	redacted := message.Copy()
+	if redactor == nil {
+		return redacted
+	}
+
+	redacted.Content = redactor(redacted.Content)
+	redacted.ReasoningContent = redactor(redacted.ReasoningContent)
+
+	for i := range redacted.MultiContent {
+		redacted.MultiContent[i].Text = redactor(redacted.MultiContent[i].Text)
+	}
+
+	if message.UserInputMultiContent != nil {
+		redacted.UserInputMultiContent = append([]MessageInputPart(nil), message.UserInputMultiContent...)
+		for i := range redacted.UserInputMultiContent {
+			redacted.UserInputMultiContent[i].Text = redactor(redacted.UserInputMultiContent[i].Text)
+		}
+	}
+
+	if message.AssistantGenMultiContent != nil {
+		redacted.AssistantGenMultiContent = append([]MessageOutputPart(nil), message.AssistantGenMultiContent...)
+		for i := range redacted.AssistantGenMultiContent {
+			redacted.AssistantGenMultiContent[i].Text = redactor(redacted.AssistantGenMultiContent[i].Text)
+		}
+	}
+
+	for i := range redacted.ToolCalls {
+		redacted.ToolCalls[i].Function.Arguments = redactor(redacted.ToolCalls[i].Function.Arguments)
+	}
+
+	return redacted
+}
@@ -18,7 +18,10 @@ package schema
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
 
@@ -1008,3 +1011,110 @@ func TestFormatUserInputMultiContent(t *testing.T) {
 		}
 	})
 }
+
+func TestRedactMessage(t *testing.T) {
+	secret := regexp.MustCompile(`sk-[a-zA-Z0-9]+`)
+	redactor := func(s string) string {
+		return secret.ReplaceAllString(s, "[REDACTED]")
+	}
+
+	msg := &Message{
+		Role:    Assistant,
+		Content: "use key sk-abc123 to call the api",
+		MultiContent: []ChatMessagePart{
+			{Type: ChatMessagePartTypeText, Text: "part sk-def456"},
+		},
+		ToolCalls: []ToolCall{
+			{
+				ID: "call_1",
+				Function: FunctionCall{
+					Name:      "call_api",
+					Arguments: `{"api_key": "sk-ghi789", "query": "weather"}`,
+				},
+			},
+		},
+	}
+
+	redacted := RedactMessage(msg, redactor)
+	assert.Equal(t, "use key [REDACTED] to call the api", redacted.Content)
+	assert.Equal(t, "part [REDACTED]", redacted.MultiContent[0].Text)
+	assert.Equal(t, `{"api_key": "[REDACTED]", "query": "weather"}`, redacted.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "call_api", redacted.ToolCalls[0].Function.Name)
+
+	// the original message is not modified
+	assert.Equal(t, "use key sk-abc123 to call the api", msg.Content)
+	assert.Equal(t, "part sk-def456", msg.MultiContent[0].Text)
+	assert.Equal(t, `{"api_key": "sk-ghi789", "query": "weather"}`, msg.ToolCalls[0].Function.Arguments)
+
+	userMsg := &Message{
+		Role: User,
+		UserInputMultiContent: []MessageInputPart{
+			{Type: ChatMessagePartTypeText, Text: "my key is sk-xyz"},
+		},
+	}
+	redacted = RedactMessage(userMsg, redactor)
+	assert.Equal(t, "my key is [REDACTED]", redacted.UserInputMultiContent[0].Text)
+	assert.Equal(t, "my key is sk-xyz", userMsg.UserInputMultiContent[0].Text)
+
+	assert.Nil(t, RedactMessage(nil, redactor))
+}
+
+func TestTruncateMessage(t *testing.T) {
+	counter := func(m *Message) int {
+		n := len([]rune(m.Content))
+		for _, part := range m.MultiContent {
+			n += len([]rune(part.Text))
+		}
+		return n
+	}
+
+	t.Run("long content", func(t *testing.T) {
+		msg := AssistantMessage(strings.Repeat("a", 200), nil)
+		truncated := TruncateMessage(msg, 50, counter)
+		assert.LessOrEqual(t, counter(truncated), 50)
+		assert.True(t, strings.HasPrefix(truncated.Content, "aaaa"))
+		assert.Contains(t, truncated.Content, "characters truncated]")
+		notice := truncated.Content[strings.Index(truncated.Content, "\n"):]
+		kept := len(truncated.Content) - len(notice)
+		assert.Equal(t, fmt.Sprintf("\n...[%d characters truncated]", 200-kept), notice)
+
+		// the original message is not modified
+		assert.Equal(t, strings.Repeat("a", 200), msg.Content)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		msg := UserMessage("short")
+		truncated := TruncateMessage(msg, 50, counter)
+		assert.Equal(t, msg, truncated)
+		assert.NotSame(t, msg, truncated)
+	})
+
+	t.Run("tool calls preserved", func(t *testing.T) {
+		toolCalls := []ToolCall{{ID: "call_1", Function: FunctionCall{Name: "search", Arguments: strings.Repeat(`{"q": "x"}`, 20)}}}
+		msg := AssistantMessage(strings.Repeat("b", 100), toolCalls)
+		truncated := TruncateMessage(msg, 10, nil)
+		assert.Less(t, len(truncated.Content), 100)
+		assert.Equal(t, toolCalls, truncated.ToolCalls)
+	})
+
+	t.Run("multi content", func(t *testing.T) {
+		msg := &Message{
+			Role:    User,
+			Content: "intro ",
+			MultiContent: []ChatMessagePart{
+				{Type: ChatMessagePartTypeText, Text: strings.Repeat("c", 100)},
+				{Type: ChatMessagePartTypeImageURL, ImageURL: &ChatMessageImageURL{URL: "https://example.com/a.png"}},
+				{Type: ChatMessagePartTypeText, Text: strings.Repeat("d", 100)},
+			},
+		}
+		truncated := TruncateMessage(msg, 60, counter)
+		assert.LessOrEqual(t, counter(truncated), 60)
+		assert.Equal(t, "intro ", truncated.Content)
+		assert.Contains(t, truncated.MultiContent[0].Text, "characters truncated]")
+		assert.Equal(t, msg.MultiContent[1], truncated.MultiContent[1])
+		assert.Empty(t, truncated.MultiContent[2].Text)
+		assert.Equal(t, strings.Repeat("c", 100), msg.MultiContent[0].Text)
+	})
+
+	assert.Nil(t, TruncateMessage(nil, 10, counter))
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import "fmt"
+
+// truncationNotice is appended to the text of a message truncated by TruncateMessage.
+const truncationNotice = "\n...[%d characters truncated]"
+
+// TruncateMessage returns a copy of message whose text, i.e. Content followed by the text parts of MultiContent,
+// is trimmed so that counter reports at most maxTokens for it, a notice with the number of characters removed being
+// appended where the text is cut. Tool calls and the other fields are copied untouched.
+// counter is optional, estimating a token every 4 characters of the text by default.
+// If the message fits the budget even with all of its text removed, the text is emptied down to the notice.
+func TruncateMessage(message *Message, maxTokens int, counter func(*Message) int) *Message {
+	if message == nil {
+		return nil
+	}
+	if counter == nil {
+		counter = estimateTokens
+	}
+
+	truncated := message.Copy()
+	if counter(truncated) <= maxTokens {
+		return truncated
+	}
+
+	texts := []*string{&truncated.Content}
+	for i := range truncated.MultiContent {
+		if truncated.MultiContent[i].Type == ChatMessagePartTypeText {
+			texts = append(texts, &truncated.MultiContent[i].Text)
+		}
+	}
+	originals := make([][]rune, len(texts))
+	total := 0
+	for i, text := range texts {
+		originals[i] = []rune(*text)
+		total += len(originals[i])
+	}
+
+	// keep the first keep characters of the texts in order, cutting the rest
+	build := func(keep int) {
+		removed := total - keep
+		cut := false
+		for i, original := range originals {
+			switch {
+			case cut:
+				*texts[i] = ""
+			case keep >= len(original):
+				*texts[i] = string(original)
+				keep -= len(original)
+			default:
+				*texts[i] = string(original[:keep]) + fmt.Sprintf(truncationNotice, removed)
+				cut = true
+			}
+		}
+	}
+
+	// binary search the most characters kept within the budget
+	lo, hi := 0, total-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		build(mid)
+		if counter(truncated) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	build(lo)
+	return truncated
+}
+
+func estimateTokens(message *Message) int {
+	n := len(message.Content)
+	for _, part := range message.MultiContent {
+		n += len(part.Text)
+	}
+	return (n + 3) / 4
+}
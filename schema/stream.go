@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -951,3 +952,127 @@ func InternalMergeNamedStreamReaders[T any](srs []*StreamReader[T], names []stri
 		msr: msr,
 	}
 }
+
+// StreamSplit re-chunks a stream of strings so that each chunk is a segment of the content bounded by delim,
+// regardless of where the chunks of sr start and end. Like strings.Split, the delimiters are not included in
+// the segments, and consecutive delimiters produce empty segments, but an empty last segment is not emitted.
+// A segment is emitted as soon as its closing delimiter is received; the content after the last delimiter
+// is emitted when sr ends. If delim is empty, the whole content is emitted as a single segment.
+// An error from sr is forwarded as is, dropping the incomplete segment received so far.
+// e.g.
+//
+//	sr := schema.StreamReaderFromArray([]string{"a<", "br>b", "<br>c"})
+//	segments := schema.StreamSplit(sr, "<br>")
+//	defer segments.Close()
+//	// segments yields "a", "b", "c"
+func StreamSplit(sr *StreamReader[string], delim string) *StreamReader[string] {
+	out, sw := Pipe[string](0)
+
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				sw.Send("", safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sw.Close()
+			sr.Close()
+		}()
+
+		var buf strings.Builder
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				if buf.Len() > 0 {
+					sw.Send(buf.String(), nil)
+				}
+				return
+			}
+			if err != nil {
+				sw.Send("", err)
+				return
+			}
+
+			buf.WriteString(chunk)
+			if delim == "" || !strings.Contains(chunk, delim[len(delim)-1:]) {
+				// no delimiter can end within this chunk
+				continue
+			}
+
+			pending := buf.String()
+			for {
+				i := strings.Index(pending, delim)
+				if i < 0 {
+					break
+				}
+				if closed := sw.Send(pending[:i], nil); closed {
+					return
+				}
+				pending = pending[i+len(delim):]
+			}
+			buf.Reset()
+			buf.WriteString(pending)
+		}
+	}()
+
+	return out
+}
+
+// StreamReaderLimit caps the total size of the chunks of sr, e.g. to protect against the unbounded output of a tool.
+// The size of a chunk is given by size, e.g. its length in bytes, or 1 if size is nil, i.e. limit is a number of chunks.
+// Chunks are forwarded as is until the one that would bring the total size above limit: then onExceed is called with it
+// and the size left under limit, to return the last chunk to emit if ok, e.g. the part of the chunk that fits followed
+// by a truncation marker. The returned stream then ends, and sr is closed. If onExceed is nil, no last chunk is emitted.
+// An error from sr is forwarded as is.
+// e.g.
+//
+//	limited := schema.StreamReaderLimit(sr, 1<<20, func(s string) int { return len(s) },
+//		func(s string, remaining int) (string, bool) {
+//			return s[:remaining] + "\n[output truncated]", true
+//		})
+//	defer limited.Close()
+func StreamReaderLimit[T any](sr *StreamReader[T], limit int, size func(T) int,
+	onExceed func(chunk T, remaining int) (T, bool)) *StreamReader[T] {
+	out, sw := Pipe[T](0)
+
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				var zero T
+				sw.Send(zero, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sw.Close()
+			sr.Close()
+		}()
+
+		total := 0
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				sw.Send(chunk, err)
+				return
+			}
+
+			n := 1
+			if size != nil {
+				n = size(chunk)
+			}
+			if total+n > limit {
+				if onExceed != nil {
+					if last, ok := onExceed(chunk, limit-total); ok {
+						sw.Send(last, nil)
+					}
+				}
+				return
+			}
+			total += n
+
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}()
+
+	return out
+}
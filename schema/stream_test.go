@@ -855,3 +855,142 @@ func TestMergeNamedStreamReaders(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamSplit(t *testing.T) {
+	collect := func(sr *StreamReader[string]) ([]string, error) {
+		defer sr.Close()
+		var segments []string
+		for {
+			s, err := sr.Recv()
+			if err == io.EOF {
+				return segments, nil
+			}
+			if err != nil {
+				return segments, err
+			}
+			segments = append(segments, s)
+		}
+	}
+
+	t.Run("delimiter spans chunks", func(t *testing.T) {
+		sr := StreamReaderFromArray([]string{"a<", "br>b", "<b", "r><br>c"})
+		segments, err := collect(StreamSplit(sr, "<br>"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "", "c"}, segments)
+	})
+
+	t.Run("several delimiters in one chunk", func(t *testing.T) {
+		sr := StreamReaderFromArray([]string{"x\ny\nz", "z\n"})
+		segments, err := collect(StreamSplit(sr, "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"x", "y", "zz"}, segments)
+	})
+
+	t.Run("no delimiter", func(t *testing.T) {
+		sr := StreamReaderFromArray([]string{"ab", "cd"})
+		segments, err := collect(StreamSplit(sr, "|"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"abcd"}, segments)
+
+		sr = StreamReaderFromArray([]string{"a|b", "c"})
+		segments, err = collect(StreamSplit(sr, ""))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a|bc"}, segments)
+	})
+
+	t.Run("error is forwarded", func(t *testing.T) {
+		sr, sw := Pipe[string](3)
+		sw.Send("a|b", nil)
+		sw.Send("", errors.New("broken"))
+		sw.Close()
+
+		segments, err := collect(StreamSplit(sr, "|"))
+		assert.EqualError(t, err, "broken")
+		assert.Equal(t, []string{"a"}, segments)
+	})
+
+	t.Run("close early", func(t *testing.T) {
+		sr, sw := Pipe[string](0)
+		go func() {
+			defer sw.Close()
+			for i := 0; i < 100; i++ {
+				if closed := sw.Send("x|", nil); closed {
+					return
+				}
+			}
+		}()
+
+		out := StreamSplit(sr, "|")
+		s, err := out.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "x", s)
+		out.Close()
+	})
+}
+
+func TestStreamReaderLimit(t *testing.T) {
+	collect := func(sr *StreamReader[string]) ([]string, error) {
+		defer sr.Close()
+		var chunks []string
+		for {
+			s, err := sr.Recv()
+			if err == io.EOF {
+				return chunks, nil
+			}
+			if err != nil {
+				return chunks, err
+			}
+			chunks = append(chunks, s)
+		}
+	}
+	byteLen := func(s string) int { return len(s) }
+
+	t.Run("stream exceeding the byte limit is cut off and the source closed", func(t *testing.T) {
+		src, sw := Pipe[string](0)
+		sourceClosed := make(chan bool, 1)
+		go func() {
+			defer sw.Close()
+			for i := 0; ; i++ {
+				if closed := sw.Send(fmt.Sprintf("line%d\n", i), nil); closed {
+					sourceClosed <- true
+					return
+				}
+			}
+		}()
+
+		limited := StreamReaderLimit(src, 16, byteLen, func(chunk string, remaining int) (string, bool) {
+			return chunk[:remaining] + "[truncated]", true
+		})
+		chunks, err := collect(limited)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"line0\n", "line1\n", "line[truncated]"}, chunks)
+
+		select {
+		case <-sourceClosed:
+		case <-time.After(time.Second):
+			t.Fatal("source wasn't closed")
+		}
+	})
+
+	t.Run("stream under the limit", func(t *testing.T) {
+		chunks, err := collect(StreamReaderLimit(StreamReaderFromArray([]string{"a", "b"}), 2, byteLen, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, chunks)
+	})
+
+	t.Run("item limit without marker", func(t *testing.T) {
+		chunks, err := collect(StreamReaderLimit(StreamReaderFromArray([]string{"a", "b", "c"}), 2, nil, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, chunks)
+	})
+
+	t.Run("error is forwarded", func(t *testing.T) {
+		src, sw := Pipe[string](2)
+		sw.Send("a", nil)
+		sw.Send("", errors.New("read failed"))
+		sw.Close()
+		chunks, err := collect(StreamReaderLimit(src, 10, byteLen, nil))
+		assert.EqualError(t, err, "read failed")
+		assert.Equal(t, []string{"a"}, chunks)
+	})
+}
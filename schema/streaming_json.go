@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StreamingJSONAccumulator accumulates the argument fragments of streamed tool calls, keyed by ToolCall.Index,
+// and reports when the arguments of a tool call have become a complete JSON value,
+// so that the execution of the tool call can be prepared before the whole message is received.
+// It is not safe for concurrent use.
+//
+//	acc := schema.NewStreamingJSONAccumulator()
+//	for {
+//		chunk, err := sr.Recv()
+//		// handle err, io.EOF
+//		for _, index := range acc.Add(chunk) {
+//			args, _ := acc.Arguments(index)
+//			// prepare the execution of the tool call at index
+//		}
+//	}
+type StreamingJSONAccumulator struct {
+	calls map[int]*jsonArgsState
+}
+
+type jsonArgsState struct {
+	sb       strings.Builder
+	depth    int
+	started  bool
+	inString bool
+	escaped  bool
+	complete bool
+}
+
+// NewStreamingJSONAccumulator creates an empty StreamingJSONAccumulator.
+func NewStreamingJSONAccumulator() *StreamingJSONAccumulator {
+	return &StreamingJSONAccumulator{calls: make(map[int]*jsonArgsState)}
+}
+
+// Add accumulates the argument fragments of the tool calls of a message chunk,
+// and returns the indexes of the tool calls whose arguments have become complete with this chunk.
+// Tool calls without Index are keyed by their position in the chunk.
+func (a *StreamingJSONAccumulator) Add(chunk *Message) []int {
+	if chunk == nil {
+		return nil
+	}
+	var completed []int
+	for i, tc := range chunk.ToolCalls {
+		index := i
+		if tc.Index != nil {
+			index = *tc.Index
+		}
+		if a.AddFragment(index, tc.Function.Arguments) {
+			completed = append(completed, index)
+		}
+	}
+	return completed
+}
+
+// AddFragment accumulates an argument fragment of the tool call at index,
+// and reports whether its arguments have become complete with this fragment.
+// Fragments added once the arguments are complete are still accumulated, but are never reported again.
+func (a *StreamingJSONAccumulator) AddFragment(index int, fragment string) bool {
+	st, ok := a.calls[index]
+	if !ok {
+		st = &jsonArgsState{}
+		a.calls[index] = st
+	}
+	st.sb.WriteString(fragment)
+	if st.complete {
+		return false
+	}
+
+	for i := 0; i < len(fragment); i++ {
+		c := fragment[i]
+		if st.inString {
+			switch {
+			case st.escaped:
+				st.escaped = false
+			case c == '\\':
+				st.escaped = true
+			case c == '"':
+				st.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			st.inString = true
+		case '{', '[':
+			st.depth++
+			st.started = true
+		case '}', ']':
+			st.depth--
+		}
+	}
+
+	// the cheap scan above only finds candidates, json.Valid confirms them
+	if st.started && st.depth == 0 && !st.inString && json.Valid([]byte(st.sb.String())) {
+		st.complete = true
+		return true
+	}
+	return false
+}
+
+// Arguments returns the arguments accumulated for the tool call at index,
+// and whether they are a complete JSON value.
+func (a *StreamingJSONAccumulator) Arguments(index int) (string, bool) {
+	st, ok := a.calls[index]
+	if !ok {
+		return "", false
+	}
+	return st.sb.String(), st.complete
+}
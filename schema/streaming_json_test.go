@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingJSONAccumulator(t *testing.T) {
+	chunk := func(index int, args string) *Message {
+		i := index
+		return AssistantMessage("", []ToolCall{{Index: &i, Function: FunctionCall{Arguments: args}}})
+	}
+
+	acc := NewStreamingJSONAccumulator()
+	assert.Empty(t, acc.Add(chunk(0, `{"query": "a{`)))
+	assert.Empty(t, acc.Add(chunk(1, `{"path": [1, `)))
+	assert.Empty(t, acc.Add(chunk(0, `b\"}", "n": `)))
+	assert.Equal(t, []int{1}, acc.Add(chunk(1, `2]}`)))
+
+	args, complete := acc.Arguments(0)
+	assert.False(t, complete)
+	assert.Equal(t, `{"query": "a{b\"}", "n": `, args)
+
+	assert.Equal(t, []int{0}, acc.Add(chunk(0, `3}`)))
+	args, complete = acc.Arguments(0)
+	assert.True(t, complete)
+	assert.Equal(t, `{"query": "a{b\"}", "n": 3}`, args)
+
+	args, complete = acc.Arguments(1)
+	assert.True(t, complete)
+	assert.Equal(t, `{"path": [1, 2]}`, args)
+
+	// completion is only reported once
+	assert.Empty(t, acc.Add(chunk(1, ``)))
+
+	_, complete = acc.Arguments(2)
+	assert.False(t, complete)
+	assert.Empty(t, acc.Add(nil))
+}
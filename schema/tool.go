@@ -17,7 +17,9 @@
 package schema
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/eino-contrib/jsonschema"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
@@ -72,6 +74,11 @@ type ToolInfo struct {
 	// If is nil, signals that the tool does not need any input parameter
 	*ParamsOneOf
 
+	// OutputSchema is the JSON schema of the result for tools promising a structured, JSON encoded result.
+	// It isn't sent to the model, but lets the result be checked, see compose.NewToolOutputValidationMiddleware.
+	// If is nil, the result of the tool is free-form.
+	OutputSchema *jsonschema.Schema
+
 	// IsEnabled indicates whether the tool is enabled.
 	IsEnabled bool
 	// IsReadOnly indicates whether the tool is read only.
@@ -91,7 +98,9 @@ type ParameterInfo struct {
 	SubParams map[string]*ParameterInfo
 	// The description of the parameter.
 	Desc string
-	// The enum values of the parameter, only for string.
+	// The enum values of the parameter, for string, integer, number and boolean.
+	// The values of non string types must be the string form of values of the type, e.g. "1" for integer,
+	// and are converted to the type in the JSON schema.
 	Enum []string
 	// Whether the parameter is required.
 	Required bool
@@ -123,6 +132,94 @@ func NewParamsOneOfByJSONSchema(s *jsonschema.Schema) *ParamsOneOf {
 	}
 }
 
+// Validate checks that the parameters described by NewParamsOneOfByParams are consistent,
+// e.g. that the enum values match the type, and that only arrays have ElemInfo and only objects have SubParams.
+// A JSON schema provided by NewParamsOneOfByJSONSchema is not validated.
+// Validate is not called by ToJSONSchema, call it when building tools to catch malformed parameters
+// before they reach the model.
+func (p *ParamsOneOf) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(p.params))
+	for k := range p.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := validateParamInfo(k, p.params[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateParamInfo(path string, paramInfo *ParameterInfo) error {
+	if paramInfo == nil {
+		return fmt.Errorf("parameter '%s' is nil", path)
+	}
+
+	switch paramInfo.Type {
+	case "", Object, Number, Integer, String, Array, Null, Boolean:
+	default:
+		return fmt.Errorf("parameter '%s' has unknown type '%s'", path, paramInfo.Type)
+	}
+
+	for _, enum := range paramInfo.Enum {
+		switch paramInfo.Type {
+		case Object, Array, Null:
+			return fmt.Errorf("parameter '%s' of type '%s' can't have enum values", path, paramInfo.Type)
+		}
+		if _, err := parseEnumValue(paramInfo.Type, enum); err != nil {
+			return fmt.Errorf("enum value '%s' of parameter '%s' is not a valid %s", enum, path, paramInfo.Type)
+		}
+	}
+
+	if paramInfo.ElemInfo != nil {
+		if paramInfo.Type != Array {
+			return fmt.Errorf("parameter '%s' of type '%s' can't have ElemInfo, only array can", path, paramInfo.Type)
+		}
+		if err := validateParamInfo(path+"[]", paramInfo.ElemInfo); err != nil {
+			return err
+		}
+	}
+
+	if len(paramInfo.SubParams) > 0 {
+		if paramInfo.Type != Object {
+			return fmt.Errorf("parameter '%s' of type '%s' can't have SubParams, only object can", path, paramInfo.Type)
+		}
+		keys := make([]string, 0, len(paramInfo.SubParams))
+		for k := range paramInfo.SubParams {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := validateParamInfo(path+"."+k, paramInfo.SubParams[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseEnumValue converts the enum value in its string form to a value of typ,
+// e.g. "1" to the integer 1, so that it is emitted with the declared type in the JSON schema.
+func parseEnumValue(typ DataType, enum string) (any, error) {
+	switch typ {
+	case Integer:
+		return strconv.ParseInt(enum, 10, 64)
+	case Number:
+		return strconv.ParseFloat(enum, 64)
+	case Boolean:
+		return strconv.ParseBool(enum)
+	default:
+		return enum, nil
+	}
+}
+
 // ToJSONSchema parses ParamsOneOf, converts the parameter description that user actually provides, into the format ready to be passed to Model.
 func (p *ParamsOneOf) ToJSONSchema() (*jsonschema.Schema, error) {
 	if p == nil {
@@ -165,7 +262,12 @@ func paramInfoToJSONSchema(paramInfo *ParameterInfo) *jsonschema.Schema {
 	if len(paramInfo.Enum) > 0 {
 		js.Enum = make([]any, len(paramInfo.Enum))
 		for i, enum := range paramInfo.Enum {
-			js.Enum[i] = enum
+			// enum values not matching the type, reported by Validate, are kept as strings
+			v, err := parseEnumValue(paramInfo.Type, enum)
+			if err != nil {
+				v = enum
+			}
+			js.Enum[i] = v
 		}
 	}
 
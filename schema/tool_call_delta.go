@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import "strings"
+
+// ToolCallDelta returns the tool calls of next that are new or changed compared to prev,
+// where prev and next are assistant messages accumulated from the same stream, e.g. by ConcatMessages,
+// next being the later one. Tool calls are matched by ToolCall.Index, or by ToolCall.ID if Index is nil.
+// For a tool call whose arguments grew, the returned ToolCall only carries the new fragment of the arguments,
+// so that concatenating prev and the delta gives back next, while a new tool call is returned as is.
+// Tool calls that didn't change are omitted.
+func ToolCallDelta(prev, next *Message) []ToolCall {
+	if next == nil {
+		return nil
+	}
+
+	byIndex := make(map[int]*ToolCall)
+	byID := make(map[string]*ToolCall)
+	if prev != nil {
+		for i := range prev.ToolCalls {
+			tc := &prev.ToolCalls[i]
+			if tc.Index != nil {
+				byIndex[*tc.Index] = tc
+			} else if tc.ID != "" {
+				byID[tc.ID] = tc
+			}
+		}
+	}
+
+	var delta []ToolCall
+	for _, tc := range next.ToolCalls {
+		var old *ToolCall
+		if tc.Index != nil {
+			old = byIndex[*tc.Index]
+		} else if tc.ID != "" {
+			old = byID[tc.ID]
+		}
+
+		if old == nil || old.ID != tc.ID || old.Type != tc.Type || old.Function.Name != tc.Function.Name ||
+			!strings.HasPrefix(tc.Function.Arguments, old.Function.Arguments) {
+			delta = append(delta, tc)
+			continue
+		}
+		if len(tc.Function.Arguments) == len(old.Function.Arguments) {
+			continue
+		}
+
+		tc.Function.Arguments = tc.Function.Arguments[len(old.Function.Arguments):]
+		delta = append(delta, tc)
+	}
+
+	return delta
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolCallDelta(t *testing.T) {
+	idx0, idx1 := 0, 1
+	call := func(index *int, id, name, args string) ToolCall {
+		return ToolCall{Index: index, ID: id, Type: "function", Function: FunctionCall{Name: name, Arguments: args}}
+	}
+
+	t.Run("arguments grow", func(t *testing.T) {
+		prev := AssistantMessage("", []ToolCall{call(&idx0, "call-1", "search", `{"query":`)})
+		next := AssistantMessage("", []ToolCall{call(&idx0, "call-1", "search", `{"query":"eino"}`)})
+
+		delta := ToolCallDelta(prev, next)
+		assert.Equal(t, []ToolCall{call(&idx0, "call-1", "search", `"eino"}`)}, delta)
+
+		merged, err := ConcatMessages([]*Message{prev, AssistantMessage("", delta)})
+		assert.NoError(t, err)
+		assert.Equal(t, next.ToolCalls, merged.ToolCalls)
+	})
+
+	t.Run("new and unchanged tool calls", func(t *testing.T) {
+		prev := AssistantMessage("", []ToolCall{call(&idx0, "call-1", "search", `{}`)})
+		next := AssistantMessage("", []ToolCall{
+			call(&idx0, "call-1", "search", `{}`),
+			call(&idx1, "call-2", "read", `{"path"`),
+		})
+		assert.Equal(t, []ToolCall{call(&idx1, "call-2", "read", `{"path"`)}, ToolCallDelta(prev, next))
+	})
+
+	t.Run("match by id without index", func(t *testing.T) {
+		prev := AssistantMessage("", []ToolCall{call(nil, "call-1", "search", `{"q`)})
+		next := AssistantMessage("", []ToolCall{call(nil, "call-1", "search", `{"q":1}`)})
+		assert.Equal(t, []ToolCall{call(nil, "call-1", "search", `":1}`)}, ToolCallDelta(prev, next))
+	})
+
+	t.Run("nil messages", func(t *testing.T) {
+		next := AssistantMessage("", []ToolCall{call(&idx0, "call-1", "search", `{}`)})
+		assert.Equal(t, next.ToolCalls, ToolCallDelta(nil, next))
+		assert.Nil(t, ToolCallDelta(next, nil))
+	})
+}
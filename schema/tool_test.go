@@ -133,3 +133,74 @@ func TestParamsOneOfToJSONSchema(t *testing.T) {
 
 	})
 }
+
+func TestParamsOneOfValidate(t *testing.T) {
+	t.Run("valid enums", func(t *testing.T) {
+		p := NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"unit":  {Type: String, Enum: []string{"celsius", "fahrenheit"}},
+			"level": {Type: Integer, Enum: []string{"1", "2", "3"}},
+			"ratio": {Type: Number, Enum: []string{"0.5", "1"}},
+			"flag":  {Type: Boolean, Enum: []string{"true", "false"}},
+			"tags": {Type: Array, ElemInfo: &ParameterInfo{
+				Type: Object,
+				SubParams: map[string]*ParameterInfo{
+					"kind": {Type: String, Enum: []string{"a", "b"}},
+				},
+			}},
+		})
+		assert.NoError(t, p.Validate())
+		sc, err := p.ToJSONSchema()
+		assert.NoError(t, err)
+
+		enum := func(name string) []any {
+			prop, _ := sc.Properties.Get(name)
+			return prop.Enum
+		}
+		assert.Equal(t, []any{"celsius", "fahrenheit"}, enum("unit"))
+		assert.Equal(t, []any{int64(1), int64(2), int64(3)}, enum("level"))
+		assert.Equal(t, []any{0.5, float64(1)}, enum("ratio"))
+		assert.Equal(t, []any{true, false}, enum("flag"))
+		js, err := json.Marshal(sc)
+		assert.NoError(t, err)
+		assert.Contains(t, string(js), `"enum":[1,2,3]`)
+	})
+
+	t.Run("type mismatched enum", func(t *testing.T) {
+		p := NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"level": {Type: Integer, Enum: []string{"1", "high"}},
+		})
+		assert.EqualError(t, p.Validate(), "enum value 'high' of parameter 'level' is not a valid integer")
+		// validation is opt-in, ToJSONSchema keeps converting the parameters as is
+		sc, err := p.ToJSONSchema()
+		assert.NoError(t, err)
+		prop, _ := sc.Properties.Get("level")
+		assert.Equal(t, []any{int64(1), "high"}, prop.Enum)
+	})
+
+	t.Run("nested mismatched enum", func(t *testing.T) {
+		p := NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"items": {Type: Array, ElemInfo: &ParameterInfo{
+				Type: Object,
+				SubParams: map[string]*ParameterInfo{
+					"enabled": {Type: Boolean, Enum: []string{"yes"}},
+				},
+			}},
+		})
+		assert.EqualError(t, p.Validate(), "enum value 'yes' of parameter 'items[].enabled' is not a valid boolean")
+	})
+
+	t.Run("inconsistent fields", func(t *testing.T) {
+		assert.EqualError(t, NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"obj": {Type: Object, Enum: []string{"x"}},
+		}).Validate(), "parameter 'obj' of type 'object' can't have enum values")
+		assert.EqualError(t, NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"name": {Type: String, ElemInfo: &ParameterInfo{Type: String}},
+		}).Validate(), "parameter 'name' of type 'string' can't have ElemInfo, only array can")
+		assert.EqualError(t, NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"list": {Type: Array, SubParams: map[string]*ParameterInfo{"x": {Type: String}}},
+		}).Validate(), "parameter 'list' of type 'array' can't have SubParams, only object can")
+		assert.EqualError(t, NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"x": {Type: "int"},
+		}).Validate(), "parameter 'x' has unknown type 'int'")
+	})
+}